@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,10 +15,22 @@ import (
 func main() {
 	rootCtx := context.Background()
 
+	checkPolicy := flag.Bool("check-policy", false, "check the access policy file and exit")
+
 	cfg := config.MustLoad()
 
 	log := logger.Setup(cfg.Logger.Type, cfg.Logger.Level)
 
+	if *checkPolicy {
+		if err := app.CheckPolicy(cfg.Auth.PolicyFile); err != nil {
+			log.Error("access policy is invalid", "file", cfg.Auth.PolicyFile, "error", err)
+			os.Exit(1)
+		}
+
+		log.Info("access policy is valid", "file", cfg.Auth.PolicyFile)
+		return
+	}
+
 	grpcServer, err := app.NewGRPCServer(rootCtx, cfg, log)
 	if err != nil {
 		log.Error("failed to start server", "error", err)
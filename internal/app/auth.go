@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/Geriler/effective-mobile/internal/config"
+	"github.com/Geriler/effective-mobile/internal/middleware"
+	"github.com/Geriler/effective-mobile/internal/subscription/service"
+	"github.com/Geriler/effective-mobile/pkg/lib/jwt"
+	"github.com/MicahParks/keyfunc/v3"
+	"golang.org/x/time/rate"
+)
+
+// newAuthenticator создает проверку bearer-токенов по конфигурации: API-ключи проверяет apiKeys, остальные токены
+// проверяются как JWT. Если ключи проверки подписи JWT не настроены, аутентификация отключена и возвращается nil.
+// Ключи по URL перечитываются в фоне до отмены ctx
+func newAuthenticator(ctx context.Context, cfg config.Auth, apiKeys *service.APIKeyService, log *slog.Logger) (middleware.Authenticator, error) {
+	var keys keyfunc.Keyfunc
+	switch {
+	case cfg.JWKSFile != "" && cfg.JWKSURL != "":
+		return nil, errors.New("only one of auth jwks file and jwks url can be set")
+	case cfg.JWKSFile != "":
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if keys, err = keyfunc.NewJWKSetJSON(data); err != nil {
+			return nil, err
+		}
+	case cfg.JWKSURL != "":
+		var err error
+		keys, err = keyfunc.NewDefaultOverrideCtx(ctx, []string{cfg.JWKSURL}, keyfunc.Override{
+			Client:          &http.Client{Timeout: cfg.JWKSTimeout},
+			HTTPTimeout:     cfg.JWKSTimeout,
+			RefreshInterval: cfg.JWKSRefreshInterval,
+			// токен с неизвестным kid перечитывает набор не чаще раза в JWKSMinRefreshInterval, а остальные
+			// такие токены в это время отклоняются, не дожидаясь следующего перечитывания
+			RefreshUnknownKID: rate.NewLimiter(rate.Every(cfg.JWKSMinRefreshInterval), 1),
+			RateLimitWaitMax:  cfg.JWKSTimeout,
+			RefreshErrorHandlerFunc: func(url string) func(context.Context, error) {
+				return func(_ context.Context, err error) {
+					log.Error("failed to refresh jwks", "url", url, "error", err)
+				}
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		log.Warn("authentication is disabled, all subscriptions are available to every client")
+		return nil, nil
+	}
+
+	verifier := jwt.NewVerifier(keys, cfg.Issuer, cfg.Audience, cfg.Leeway)
+
+	return middleware.NewAPIKeyAuthenticator(
+		apiKeys,
+		service.APIKeyPrefix,
+		middleware.NewJWTAuthenticator(verifier, cfg.UserIDClaim, cfg.RolesClaim, cfg.TenantClaim, cfg.AdminScope),
+	), nil
+}
@@ -2,53 +2,258 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
+	"sync"
+	"time"
 
 	"github.com/Geriler/effective-mobile/internal/config"
 	"github.com/Geriler/effective-mobile/internal/middleware"
 	"github.com/Geriler/effective-mobile/internal/subscription/handler"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/internal/subscription/publisher"
 	"github.com/Geriler/effective-mobile/internal/subscription/repository"
 	"github.com/Geriler/effective-mobile/internal/subscription/service"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
 	"github.com/Geriler/effective-mobile/pkg/infra/postgres"
+	"github.com/Geriler/effective-mobile/pkg/lib/pagetoken"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+// eventBrokerRestartDelay - пауза перед повторным подключением к уведомлениям Postgres после потери соединения
+const eventBrokerRestartDelay = time.Second
+
 type GRPCServer struct {
-	cfg    config.Config
-	log    *slog.Logger
-	server *grpc.Server
+	cfg      config.Config
+	log      *slog.Logger
+	server   *grpc.Server
+	stopJobs context.CancelFunc
+	jobs     *sync.WaitGroup
+	closers  []io.Closer
 }
 
 func NewGRPCServer(ctx context.Context, cfg config.Config, log *slog.Logger) (*GRPCServer, error) {
 	conn, err := postgres.Connect(ctx, fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s",
 		cfg.Database.User, cfg.Database.Password, cfg.Database.Host, cfg.Database.Port, cfg.Database.Name),
+		// политики защиты строк отбирают строки арендатора запроса по параметру сеанса app.tenant_id
+		postgres.WithSessionSetting("app.tenant_id", tenant.SessionSetting),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	exchangeRateRepo := repository.NewPostgresExchangeRateRepository(conn, log)
+	if cfg.ExchangeRates.File != "" {
+		count, err := service.NewExchangeRateService(exchangeRateRepo).ImportFile(ctx, cfg.ExchangeRates.File)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("exchange rates imported", "file", cfg.ExchangeRates.File, "count", count)
+	}
+
+	overlapPolicy, err := model.ParseOverlapPolicy(cfg.Overlaps.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := tenantSettings(cfg.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
 	subscriptionRepo := repository.NewPostgresSubscriptionRepository(conn, log)
-	subscriptionService := service.NewSubscriptionService(subscriptionRepo)
-	subscriptionHandler := handler.NewSubscriptionHandler(log, subscriptionService)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, exchangeRateRepo, cfg.ExchangeRates.BaseCurrency, tenants, overlapPolicy)
+	pageTokenSecret := []byte(cfg.Pagination.TokenSecret)
+	if len(pageTokenSecret) == 0 {
+		log.Warn("pagination token secret is not configured, generating a random one")
+
+		pageTokenSecret = make([]byte, 32)
+		if _, err = rand.Read(pageTokenSecret); err != nil {
+			return nil, err
+		}
+	}
+
+	webhookService := service.NewWebhookService(
+		repository.NewPostgresWebhookRepository(conn, log),
+		publisher.NewWebhookSender(cfg.Webhooks.Timeout),
+		service.WebhookPolicy{
+			MaxAttempts:       cfg.Webhooks.MaxAttempts,
+			InitialBackoff:    cfg.Webhooks.InitialBackoff,
+			MaxBackoff:        cfg.Webhooks.MaxBackoff,
+			SecretGracePeriod: cfg.Webhooks.SecretGracePeriod,
+			BatchSize:         cfg.Webhooks.BatchSize,
+			Lease:             cfg.Webhooks.Lease,
+		},
+	)
+
+	outboxRepo := repository.NewPostgresOutboxRepository(conn, log)
+	eventBroker := service.NewEventBroker(outboxRepo)
+
+	apiKeyService := service.NewAPIKeyService(repository.NewPostgresAPIKeyRepository(conn, log))
+
+	subscriptionHandler := handler.NewSubscriptionHandler(log, subscriptionService, webhookService, apiKeyService, eventBroker, pagetoken.NewSigner(pageTokenSecret))
+
+	idempotencyService := service.NewIdempotencyService(
+		repository.NewPostgresIdempotencyRepository(conn, log),
+		cfg.Idempotency.TTL,
+		cfg.Idempotency.Lease,
+	)
+
+	eventPublisher, publisherCloser, err := newEventPublisher(cfg.Outbox, log)
+	if err != nil {
+		return nil, err
+	}
+	// события публикуются в настроенный публикатор и ставятся в очередь доставки вебхукам
+	outboxRelay := service.NewOutboxRelay(
+		outboxRepo,
+		publisher.NewFanoutPublisher(eventPublisher, webhookService),
+		cfg.Outbox.BatchSize,
+		cfg.Outbox.MaxAttempts,
+	)
+
+	authenticator, err := newAuthenticator(ctx, cfg.Auth, apiKeyService, log)
+	if err != nil {
+		return nil, err
+	}
+
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		middleware.RequestMeta,
+		middleware.Logger,
+		middleware.ErrorTranslator(handler.ErrorMappings...),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		middleware.StreamRequestMeta,
+		middleware.StreamLogger,
+		middleware.StreamErrorTranslator(handler.ErrorMappings...),
+	}
+	if authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, middleware.Auth(authenticator), middleware.Scopes(handler.MethodScopes))
+		streamInterceptors = append(streamInterceptors, middleware.StreamAuth(authenticator), middleware.StreamScopes(handler.MethodScopes))
+	}
+	if cfg.Auth.PolicyFile != "" {
+		policy, err := loadPolicy(cfg.Auth.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if authenticator == nil {
+			log.Warn("access policy is ignored because authentication is disabled", "file", cfg.Auth.PolicyFile)
+		} else {
+			unaryInterceptors = append(unaryInterceptors, middleware.RBAC(policy))
+			streamInterceptors = append(streamInterceptors, middleware.StreamRBAC(policy))
+		}
+	}
+	// арендатор берется из учетных данных клиента, поэтому определяется после аутентификации
+	if cfg.Auth.TrustTenantHeader && authenticator == nil {
+		log.Warn("tenant header is trusted without authentication, any client can choose a tenant")
+	}
+	unaryInterceptors = append(unaryInterceptors, middleware.Tenant(cfg.Auth.TrustTenantHeader))
+	streamInterceptors = append(streamInterceptors, middleware.StreamTenant(cfg.Auth.TrustTenantHeader))
+	// ключи идемпотентности разделяются по арендаторам и клиентам, поэтому проверка идет после аутентификации
+	unaryInterceptors = append(unaryInterceptors, middleware.Idempotency(idempotencyService, handler.ReplayHeaders, handler.IdempotentMethods...))
 
 	server := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			middleware.Logger,
-		),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
 	)
 
 	pbSubscription.RegisterSubscriptionsServer(server, subscriptionHandler)
 	reflection.Register(server)
 
+	// фоновые задачи обрабатывают данные всех арендаторов
+	jobsCtx, stopJobs := context.WithCancel(tenant.WithAll(ctx))
+	jobs := &sync.WaitGroup{}
+	jobs.Go(func() {
+		runPeriodically(jobsCtx, log, "purge expired idempotency keys", cfg.Idempotency.CleanupInterval, func(ctx context.Context) error {
+			count, err := idempotencyService.PurgeExpired(ctx)
+			if err == nil && count > 0 {
+				log.Info("expired idempotency keys purged", "count", count)
+			}
+			return err
+		})
+	})
+	jobs.Go(func() {
+		runPeriodically(jobsCtx, log, "update subscription statuses", cfg.Lifecycle.StatusInterval, func(ctx context.Context) error {
+			activated, expired, err := subscriptionService.UpdateStatuses(ctx)
+			if err == nil && activated+expired > 0 {
+				log.Info("subscription statuses updated", "activated", activated, "expired", expired)
+			}
+			return err
+		})
+	})
+	jobs.Go(func() {
+		runPeriodically(jobsCtx, log, "purge deleted subscriptions", cfg.Deletion.PurgeInterval, func(ctx context.Context) error {
+			count, err := subscriptionService.PurgeDeletedSubscriptions(ctx, cfg.Deletion.Retention)
+			if err == nil && count > 0 {
+				log.Info("deleted subscriptions purged", "count", count)
+			}
+			return err
+		})
+	})
+	jobs.Go(func() {
+		runPeriodically(jobsCtx, log, "publish outbox events", cfg.Outbox.Interval, func(ctx context.Context) error {
+			published, failed, deadLettered, err := outboxRelay.Relay(ctx)
+			if err == nil && published > 0 {
+				log.Debug("outbox events published", "count", published)
+			}
+			if failed > 0 {
+				log.Warn("outbox events failed to publish", "count", failed)
+			}
+			if deadLettered > 0 {
+				log.Error("outbox events are dead-lettered after max attempts", "count", deadLettered)
+			}
+			return err
+		})
+	})
+	jobs.Go(func() {
+		runPeriodically(jobsCtx, log, "purge published outbox events", cfg.Outbox.CleanupInterval, func(ctx context.Context) error {
+			count, err := outboxRelay.PurgePublished(ctx, cfg.Outbox.Retention)
+			if err == nil && count > 0 {
+				log.Info("published outbox events purged", "count", count)
+			}
+			return err
+		})
+	})
+	// остановка задач завершает и наблюдения за подписками, иначе GracefulStop ждал бы их бесконечно
+	jobs.Go(func() {
+		runRestarting(jobsCtx, log, "dispatch subscription events", eventBrokerRestartDelay, eventBroker.Run)
+	})
+	jobs.Go(func() {
+		runPeriodically(jobsCtx, log, "deliver webhooks", cfg.Webhooks.Interval, func(ctx context.Context) error {
+			delivered, failed, err := webhookService.Deliver(ctx)
+			if delivered > 0 {
+				log.Debug("webhooks delivered", "count", delivered)
+			}
+			if failed > 0 {
+				log.Warn("webhook deliveries failed", "count", failed)
+			}
+			return err
+		})
+	})
+	jobs.Go(func() {
+		runPeriodically(jobsCtx, log, "purge webhook deliveries", cfg.Webhooks.CleanupInterval, func(ctx context.Context) error {
+			count, err := webhookService.PurgeDeliveries(ctx, cfg.Webhooks.Retention)
+			if err == nil && count > 0 {
+				log.Info("webhook deliveries purged", "count", count)
+			}
+			return err
+		})
+	})
+
 	return &GRPCServer{
-		cfg:    cfg,
-		log:    log,
-		server: server,
+		cfg:      cfg,
+		log:      log,
+		server:   server,
+		stopJobs: stopJobs,
+		jobs:     jobs,
+		closers:  []io.Closer{publisherCloser},
 	}, nil
 }
 
@@ -66,5 +271,13 @@ func (a *GRPCServer) ListenAndServe() error {
 }
 
 func (a *GRPCServer) Shutdown() {
+	a.stopJobs()
 	a.server.GracefulStop()
+	a.jobs.Wait()
+
+	for _, closer := range a.closers {
+		if err := closer.Close(); err != nil {
+			a.log.Error("failed to close resource", "error", err)
+		}
+	}
 }
@@ -8,6 +8,8 @@ import (
 
 	"github.com/Geriler/effective-mobile/internal/config"
 	"github.com/Geriler/effective-mobile/internal/middleware"
+	"github.com/Geriler/effective-mobile/internal/subscription/gateway"
+	"github.com/Geriler/effective-mobile/internal/subscription/handler"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
@@ -22,14 +24,18 @@ type HTTPGW struct {
 }
 
 func NewHTTPGW(cfg config.Config, log *slog.Logger) *HTTPGW {
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(middleware.NewHTTPErrorHandler(handler.ErrorMappings...)),
+		runtime.WithIncomingHeaderMatcher(middleware.HTTPHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(middleware.HTTPOutgoingHeaderMatcher),
+	)
 
 	return &HTTPGW{
 		cfg: cfg,
 		log: log,
 		server: &http.Server{
 			Addr:    fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port),
-			Handler: middleware.NewLogWrapperHandler(mux, log),
+			Handler: middleware.NewLogWrapperHandler(middleware.NewConditionalGetHandler(mux), log),
 		},
 		mux: mux,
 	}
@@ -55,6 +61,12 @@ func (a *HTTPGW) ListenAndServe() error {
 		return err
 	}
 
+	// регистрируется после сгенерированных обработчиков, чтобы отдавать поток как Server-Sent Events
+	err = a.mux.HandlePath("GET", gateway.WatchSubscriptionsPath, gateway.WatchSubscriptionsSSE(a.mux, pbSubscription.NewSubscriptionsClient(conn)))
+	if err != nil {
+		return err
+	}
+
 	if err = a.server.ListenAndServe(); err != nil {
 		return err
 	}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runPeriodically выполняет job каждые interval до отмены ctx. Ошибки job логируются и не прерывают расписание
+func runPeriodically(ctx context.Context, log *slog.Logger, name string, interval time.Duration, job func(ctx context.Context) error) {
+	log = log.With("job", name)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.Error("job failed", "error", err)
+			}
+		}
+	}
+}
+
+// runRestarting выполняет долгоживущий job до отмены ctx, перезапуская его через delay после каждого завершения
+func runRestarting(ctx context.Context, log *slog.Logger, name string, delay time.Duration, job func(ctx context.Context) error) {
+	log = log.With("job", name)
+
+	for {
+		err := job(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error("job stopped, restarting", "error", err, "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/rbac"
+)
+
+// CheckPolicy читает политику доступа и проверяет ее на ошибки, не запуская сервис
+func CheckPolicy(path string) error {
+	if path == "" {
+		return errors.New("auth policy file is not configured")
+	}
+
+	_, err := loadPolicy(path)
+
+	return err
+}
+
+// loadPolicy читает политику доступа и отклоняет ее, если в ней есть ошибки
+func loadPolicy(path string) (*rbac.Policy, error) {
+	policy, err := rbac.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if problems := policy.Lint(knownMethods()); len(problems) > 0 {
+		return nil, fmt.Errorf("policy %s: %w", path, errors.Join(problems...))
+	}
+
+	return policy, nil
+}
+
+// knownMethods возвращает полные имена методов сервиса подписок
+func knownMethods() []string {
+	desc := pbSubscription.Subscriptions_ServiceDesc
+
+	methods := make([]string, 0, len(desc.Methods)+len(desc.Streams))
+	for _, method := range desc.Methods {
+		methods = append(methods, "/"+desc.ServiceName+"/"+method.MethodName)
+	}
+	for _, stream := range desc.Streams {
+		methods = append(methods, "/"+desc.ServiceName+"/"+stream.StreamName)
+	}
+
+	return methods
+}
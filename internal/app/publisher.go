@@ -0,0 +1,46 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/Geriler/effective-mobile/internal/config"
+	"github.com/Geriler/effective-mobile/internal/subscription/publisher"
+	"github.com/Geriler/effective-mobile/internal/subscription/service"
+)
+
+// newEventPublisher создает публикатор событий outbox по конфигурации. Возвращаемый io.Closer освобождает
+// ресурсы публикатора и вызывается после остановки фоновых задач
+func newEventPublisher(cfg config.Outbox, log *slog.Logger) (service.EventPublisher, io.Closer, error) {
+	switch cfg.Publisher {
+	case "log":
+		return publisher.NewLogPublisher(log), nopCloser{}, nil
+	case "file":
+		if cfg.File == "" {
+			return nil, nil, errors.New("outbox file is required for the file publisher")
+		}
+
+		filePublisher, err := publisher.NewFilePublisher(cfg.File)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return filePublisher, filePublisher, nil
+	case "http":
+		if cfg.URL == "" {
+			return nil, nil, errors.New("outbox url is required for the http publisher")
+		}
+
+		return publisher.NewHTTPPublisher(cfg.URL, cfg.Timeout), nopCloser{}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+	}
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Geriler/effective-mobile/internal/config"
+	"github.com/Geriler/effective-mobile/internal/subscription/service"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+)
+
+// tenantSettings переводит настройки арендаторов из конфигурации в настройки сервиса подписок
+func tenantSettings(tenants map[string]config.Tenant) (map[string]service.TenantSettings, error) {
+	settings := make(map[string]service.TenantSettings, len(tenants))
+	for tenantID, tenantConfig := range tenants {
+		if err := tenant.Validate(tenantID); err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", tenantID, err)
+		}
+
+		settings[tenantID] = service.TenantSettings{
+			DefaultCurrency:   tenantConfig.DefaultCurrency,
+			DeletionRetention: tenantConfig.DeletionRetention,
+		}
+	}
+
+	return settings, nil
+}
@@ -8,11 +8,22 @@ import (
 )
 
 type Config struct {
-	Logger      Logger        `yaml:"logger" env-prefix:"LOGGER_"`
-	TimeoutStop time.Duration `env:"TIMEOUT_STOP" yaml:"timeout_stop" env-default:"10s"`
-	HTTP        Address       `yaml:"http" env-prefix:"HTTP_"`
-	GRPC        Address       `yaml:"grpc" env-prefix:"GRPC_"`
-	Database    Database      `yaml:"database" env-prefix:"DATABASE_"`
+	Logger        Logger        `yaml:"logger" env-prefix:"LOGGER_"`
+	TimeoutStop   time.Duration `env:"TIMEOUT_STOP" yaml:"timeout_stop" env-default:"10s"`
+	HTTP          Address       `yaml:"http" env-prefix:"HTTP_"`
+	GRPC          Address       `yaml:"grpc" env-prefix:"GRPC_"`
+	Database      Database      `yaml:"database" env-prefix:"DATABASE_"`
+	ExchangeRates ExchangeRates `yaml:"exchange_rates" env-prefix:"EXCHANGE_RATES_"`
+	Pagination    Pagination    `yaml:"pagination" env-prefix:"PAGINATION_"`
+	Idempotency   Idempotency   `yaml:"idempotency" env-prefix:"IDEMPOTENCY_"`
+	Overlaps      Overlaps      `yaml:"overlaps" env-prefix:"OVERLAPS_"`
+	Lifecycle     Lifecycle     `yaml:"lifecycle" env-prefix:"LIFECYCLE_"`
+	Deletion      Deletion      `yaml:"deletion" env-prefix:"DELETION_"`
+	Outbox        Outbox        `yaml:"outbox" env-prefix:"OUTBOX_"`
+	Webhooks      Webhooks      `yaml:"webhooks" env-prefix:"WEBHOOKS_"`
+	Auth          Auth          `yaml:"auth" env-prefix:"AUTH_"`
+	// Tenants - настройки арендаторов, заменяющие общие. Задаются только в YAML
+	Tenants map[string]Tenant `yaml:"tenants"`
 }
 
 type Address struct {
@@ -28,6 +39,122 @@ type Database struct {
 	Name     string `env:"NAME" yaml:"name" env-required:"true"`
 }
 
+type ExchangeRates struct {
+	BaseCurrency string `env:"BASE_CURRENCY" yaml:"base_currency" env-default:"RUB"`
+	File         string `env:"FILE" yaml:"file"`
+}
+
+type Pagination struct {
+	// TokenSecret - ключ подписи токенов страниц. Если не задан, генерируется при старте
+	// и токены, выданные до перезапуска, перестают приниматься
+	TokenSecret string `env:"TOKEN_SECRET" yaml:"token_secret"`
+}
+
+type Overlaps struct {
+	// Policy - что делать с подпиской, пересекающейся с другой подпиской того же пользователя на тот же сервис:
+	// reject, warn или allow
+	Policy string `env:"POLICY" yaml:"policy" env-default:"reject"`
+}
+
+type Lifecycle struct {
+	// StatusInterval - периодичность обновления статусов: перевода подписок с закончившимся пробным периодом
+	// в статус active и закончившихся подписок в статус expired
+	StatusInterval time.Duration `env:"STATUS_INTERVAL" yaml:"status_interval" env-default:"1h"`
+}
+
+type Deletion struct {
+	// Retention - сколько хранятся удаленные подписки, которые еще можно восстановить
+	Retention time.Duration `env:"RETENTION" yaml:"retention" env-default:"720h"`
+	// PurgeInterval - периодичность окончательного удаления подписок с истекшим сроком хранения
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL" yaml:"purge_interval" env-default:"1h"`
+}
+
+type Outbox struct {
+	// Publisher - куда публикуются события об изменении подписок: log, file (NDJSON-файл File) или http (POST на URL)
+	Publisher string `env:"PUBLISHER" yaml:"publisher" env-default:"log"`
+	File      string `env:"FILE" yaml:"file"`
+	URL       string `env:"URL" yaml:"url"`
+	// Timeout - таймаут доставки события по HTTP
+	Timeout time.Duration `env:"TIMEOUT" yaml:"timeout" env-default:"5s"`
+	// Interval - периодичность публикации, BatchSize - сколько событий публикуется за раз
+	Interval  time.Duration `env:"INTERVAL" yaml:"interval" env-default:"1s"`
+	BatchSize int           `env:"BATCH_SIZE" yaml:"batch_size" env-default:"100"`
+	// MaxAttempts - сколько раз публикуется событие, прежде чем оно откладывается и перестает задерживать
+	// следующие события подписки
+	MaxAttempts int `env:"MAX_ATTEMPTS" yaml:"max_attempts" env-default:"10"`
+	// Retention - сколько хранятся опубликованные события
+	Retention       time.Duration `env:"RETENTION" yaml:"retention" env-default:"168h"`
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" yaml:"cleanup_interval" env-default:"1h"`
+}
+
+type Webhooks struct {
+	// Timeout - таймаут запроса к получателю вебхука
+	Timeout time.Duration `env:"TIMEOUT" yaml:"timeout" env-default:"10s"`
+	// MaxAttempts - сколько раз доставка повторяется, прежде чем считается неудачной. Пауза между попытками
+	// начинается с InitialBackoff и удваивается до MaxBackoff
+	MaxAttempts    int           `env:"MAX_ATTEMPTS" yaml:"max_attempts" env-default:"10"`
+	InitialBackoff time.Duration `env:"INITIAL_BACKOFF" yaml:"initial_backoff" env-default:"30s"`
+	MaxBackoff     time.Duration `env:"MAX_BACKOFF" yaml:"max_backoff" env-default:"1h"`
+	// SecretGracePeriod - сколько после ротации запросы подписываются и прежним секретом
+	SecretGracePeriod time.Duration `env:"SECRET_GRACE_PERIOD" yaml:"secret_grace_period" env-default:"24h"`
+	// Interval - периодичность отправки, BatchSize - сколько доставок отправляется за раз
+	Interval  time.Duration `env:"INTERVAL" yaml:"interval" env-default:"1s"`
+	BatchSize int           `env:"BATCH_SIZE" yaml:"batch_size" env-default:"50"`
+	// Lease - через сколько доставка, отправку которой не завершил упавший экземпляр, отправляется повторно
+	Lease time.Duration `env:"LEASE" yaml:"lease" env-default:"1m"`
+	// Retention - сколько хранится журнал завершенных доставок
+	Retention       time.Duration `env:"RETENTION" yaml:"retention" env-default:"720h"`
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" yaml:"cleanup_interval" env-default:"1h"`
+}
+
+// Tenant - настройки арендатора. Незаданная настройка берется из общей
+type Tenant struct {
+	// DefaultCurrency - валюта новых подписок без валюты и расчета расходов без параметра currency
+	DefaultCurrency string `yaml:"default_currency"`
+	// DeletionRetention - сколько хранятся удаленные подписки арендатора
+	DeletionRetention time.Duration `yaml:"deletion_retention"`
+}
+
+type Auth struct {
+	// JWKSFile и JWKSURL - откуда читаются ключи проверки подписи токенов в формате JWKS. Если не задано ни то,
+	// ни другое, аутентификация отключена
+	JWKSFile string `env:"JWKS_FILE" yaml:"jwks_file"`
+	JWKSURL  string `env:"JWKS_URL" yaml:"jwks_url"`
+	// JWKSRefreshInterval - периодичность перечитывания ключей по JWKSURL. Токен с неизвестным kid вызывает
+	// внеочередное перечитывание, но не чаще раза в JWKSMinRefreshInterval
+	JWKSRefreshInterval    time.Duration `env:"JWKS_REFRESH_INTERVAL" yaml:"jwks_refresh_interval" env-default:"1h"`
+	JWKSMinRefreshInterval time.Duration `env:"JWKS_MIN_REFRESH_INTERVAL" yaml:"jwks_min_refresh_interval" env-default:"1m"`
+	JWKSTimeout            time.Duration `env:"JWKS_TIMEOUT" yaml:"jwks_timeout" env-default:"5s"`
+	// Issuer и Audience - ожидаемые утверждения iss и aud токена. Пустые не проверяются
+	Issuer   string `env:"ISSUER" yaml:"issuer"`
+	Audience string `env:"AUDIENCE" yaml:"audience"`
+	// Leeway - допустимое расхождение часов с издателем токенов
+	Leeway time.Duration `env:"LEEWAY" yaml:"leeway" env-default:"1m"`
+	// UserIDClaim - утверждение токена с ID пользователя, подписки которого доступны клиенту
+	UserIDClaim string `env:"USER_ID_CLAIM" yaml:"user_id_claim" env-default:"sub"`
+	// RolesClaim - утверждение токена с ролями клиента (строка или список строк)
+	RolesClaim string `env:"ROLES_CLAIM" yaml:"roles_claim" env-default:"roles"`
+	// TenantClaim - утверждение токена с арендатором клиента. Клиенту без арендатора достается арендатор по умолчанию
+	TenantClaim string `env:"TENANT_CLAIM" yaml:"tenant_claim" env-default:"tenant_id"`
+	// TrustTenantHeader - принимать заголовок X-Tenant-Id от запросов без аутентификации. Включается только для
+	// сервиса, доступного лишь доверенным клиентам: иначе любой клиент может выбрать чужого арендатора
+	TrustTenantHeader bool `env:"TRUST_TENANT_HEADER" yaml:"trust_tenant_header" env-default:"false"`
+	// AdminScope - область доступа, дающая доступ к подпискам всех пользователей и управлению вебхуками
+	AdminScope string `env:"ADMIN_SCOPE" yaml:"admin_scope" env-default:"subscriptions:admin"`
+	// PolicyFile - политика доступа по ролям в формате YAML. Если не задана, клиенту доступны все методы
+	// для своих подписок
+	PolicyFile string `env:"POLICY_FILE" yaml:"policy_file"`
+}
+
+type Idempotency struct {
+	// TTL - срок хранения ключей идемпотентности и ответов на запросы
+	TTL time.Duration `env:"TTL" yaml:"ttl" env-default:"24h"`
+	// Lease - через сколько запрос, не завершившийся из-за сбоя, можно повторить с тем же ключом
+	Lease time.Duration `env:"LEASE" yaml:"lease" env-default:"1m"`
+	// CleanupInterval - периодичность удаления ключей с истекшим сроком хранения
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" yaml:"cleanup_interval" env-default:"1h"`
+}
+
 type Logger struct {
 	Type  string `env:"TYPE" yaml:"type" env-default:"json"`
 	Level string `env:"LEVEL" yaml:"level" env-default:"info"`
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+)
+
+// APIKeyStore проверяет API-ключи. ok ложно, если ключ не выпускался, отозван или истек
+type APIKeyStore interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (caller principal.Principal, ok bool, err error)
+}
+
+// APIKeyAuthenticator принимает API-ключи - bearer-токены с префиксом prefix, а остальные токены передает next
+type APIKeyAuthenticator struct {
+	keys   APIKeyStore
+	prefix string
+	next   Authenticator
+}
+
+func NewAPIKeyAuthenticator(keys APIKeyStore, prefix string, next Authenticator) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		keys:   keys,
+		prefix: prefix,
+		next:   next,
+	}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, token string) (principal.Principal, error) {
+	if !strings.HasPrefix(token, a.prefix) {
+		return a.next.Authenticate(ctx, token)
+	}
+
+	caller, ok, err := a.keys.AuthenticateAPIKey(ctx, token)
+	if err != nil {
+		return principal.Principal{}, err
+	}
+	if !ok {
+		return principal.Principal{}, ErrInvalidCredentials
+	}
+
+	return caller, nil
+}
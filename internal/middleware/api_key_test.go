@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAPIKeyStore map[string]principal.Principal
+
+func (s stubAPIKeyStore) AuthenticateAPIKey(_ context.Context, key string) (principal.Principal, bool, error) {
+	if key == "emk_db-down" {
+		return principal.Principal{}, false, errors.New("connection refused")
+	}
+
+	caller, ok := s[key]
+	return caller, ok, nil
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticator := NewAPIKeyAuthenticator(stubAPIKeyStore{
+		"emk_reports": {Subject: "api-key:reports", Scopes: []string{"reports:read"}, ScopeLimited: true},
+	}, "emk_", NewJWTAuthenticator(stubTokenVerifier{
+		"admin": {Subject: "ops", Scopes: []string{"subscriptions:admin"}, Raw: map[string]any{"sub": "ops"}},
+	}, "sub", "roles", "tenant_id", "subscriptions:admin"))
+
+	caller, err := authenticator.Authenticate(context.Background(), "emk_reports")
+	require.NoError(t, err)
+	assert.Equal(t, "api-key:reports", caller.Subject)
+	assert.True(t, caller.ScopeLimited)
+
+	// токены без префикса проверяются как JWT
+	caller, err = authenticator.Authenticate(context.Background(), "admin")
+	require.NoError(t, err)
+	assert.True(t, caller.Admin)
+
+	_, err = authenticator.Authenticate(context.Background(), "emk_revoked")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(context.Background(), "emk_db-down")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(context.Background(), "forged")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.ErrorIs(t, err, errTestInvalidSignature)
+}
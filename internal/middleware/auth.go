@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/jwt"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AuthorizationHeader - заголовок с bearer-токеном. HTTP Gateway передает его в gRPC как метаданные authorization
+	AuthorizationHeader = "Authorization"
+
+	ReasonUnauthenticated = "UNAUTHENTICATED"
+
+	bearerScheme = "bearer"
+)
+
+// ErrInvalidCredentials - клиент передал недействительные учетные данные
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Authenticator определяет клиента по bearer-токену. Ошибка ErrInvalidCredentials означает, что токен
+// недействителен, остальные ошибки - что проверить токен не удалось
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (principal.Principal, error)
+}
+
+// Auth возвращает интерсептор, который пропускает только запросы с действительным bearer-токеном в метаданных
+// authorization и сохраняет клиента в контексте. Автором изменений в журнале аудита становится клиент из токена
+func Auth(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth - Auth для потоковых методов
+func StreamAuth(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, authenticator Authenticator) (context.Context, error) {
+	scheme, token, _ := strings.Cut(firstMetadataValue(ctx, AuthorizationHeader), " ")
+	if !strings.EqualFold(scheme, bearerScheme) || strings.TrimSpace(token) == "" {
+		return nil, unauthenticated("bearer token is required")
+	}
+
+	caller, err := authenticator.Authenticate(ctx, strings.TrimSpace(token))
+	if errors.Is(err, ErrInvalidCredentials) {
+		return nil, unauthenticated(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if caller.Subject != "" {
+		meta := requestmeta.From(ctx)
+		meta.Actor = caller.Subject
+		ctx = requestmeta.With(ctx, meta)
+	}
+
+	return principal.With(ctx, caller), nil
+}
+
+func unauthenticated(message string) error {
+	return withDetails(status.New(codes.Unauthenticated, message), &errdetails.ErrorInfo{
+		Reason: ReasonUnauthenticated,
+		Domain: ErrorDomain,
+	})
+}
+
+// TokenVerifier проверяет подпись и сроки действия JWT
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (jwt.Claims, error)
+}
+
+// JWTAuthenticator определяет клиента по JWT: ID пользователя берется из утверждения userIDClaim, роли -
+// из rolesClaim, арендатор - из tenantClaim, а клиент с областью доступа adminScope получает доступ к подпискам
+// всех пользователей
+type JWTAuthenticator struct {
+	verifier    TokenVerifier
+	userIDClaim string
+	rolesClaim  string
+	tenantClaim string
+	adminScope  string
+}
+
+func NewJWTAuthenticator(verifier TokenVerifier, userIDClaim, rolesClaim, tenantClaim, adminScope string) *JWTAuthenticator {
+	return &JWTAuthenticator{
+		verifier:    verifier,
+		userIDClaim: userIDClaim,
+		rolesClaim:  rolesClaim,
+		tenantClaim: tenantClaim,
+		adminScope:  adminScope,
+	}
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (principal.Principal, error) {
+	claims, err := a.verifier.Verify(ctx, token)
+	if errors.Is(err, jwt.ErrKeysUnavailable) {
+		return principal.Principal{}, status.Error(codes.Unavailable, "token signing keys are unavailable")
+	}
+	if err != nil {
+		return principal.Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	caller := principal.Principal{
+		Subject:  claims.Subject,
+		TenantID: claims.String(a.tenantClaim),
+		Scopes:   claims.Scopes,
+		Roles:    claims.Strings(a.rolesClaim),
+		Admin:    claims.HasScope(a.adminScope),
+	}
+	if caller.TenantID != "" && tenant.Validate(caller.TenantID) != nil {
+		return principal.Principal{}, fmt.Errorf("%w: token claim %s must be a tenant id", ErrInvalidCredentials, a.tenantClaim)
+	}
+
+	userID, err := uuid.Parse(claims.String(a.userIDClaim))
+	if err != nil && !caller.Admin {
+		return principal.Principal{}, fmt.Errorf("%w: token claim %s must be a user id", ErrInvalidCredentials, a.userIDClaim)
+	}
+	if err == nil {
+		caller.UserID = userID
+	}
+
+	return caller, nil
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/jwt"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errTestInvalidSignature - ошибка проверки токена, неизвестного заглушке
+var errTestInvalidSignature = errors.New("token signature is invalid")
+
+type stubTokenVerifier map[string]jwt.Claims
+
+func (v stubTokenVerifier) Verify(_ context.Context, token string) (jwt.Claims, error) {
+	if token == "keys-down" {
+		return jwt.Claims{}, jwt.ErrKeysUnavailable
+	}
+
+	claims, ok := v[token]
+	if !ok {
+		return jwt.Claims{}, errTestInvalidSignature
+	}
+
+	return claims, nil
+}
+
+func TestAuth(t *testing.T) {
+	userID := uuid.New()
+	authenticator := NewJWTAuthenticator(stubTokenVerifier{
+		"user":       {Subject: "alice", Raw: map[string]any{"sub": "alice", "uid": userID.String(), "roles": []any{"support", "finance"}, "tenant": "acme"}},
+		"admin":      {Subject: "ops", Scopes: []string{"subscriptions:admin"}, Raw: map[string]any{"sub": "ops"}},
+		"no-userid":  {Subject: "bob", Raw: map[string]any{"sub": "bob"}},
+		"bad-tenant": {Subject: "eve", Raw: map[string]any{"sub": "eve", "uid": userID.String(), "tenant": "Acme Inc"}},
+	}, "uid", "roles", "tenant", "subscriptions:admin")
+	interceptor := Auth(authenticator)
+
+	var caller principal.Principal
+	var actor string
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		caller, _ = principal.From(ctx)
+		actor = requestmeta.From(ctx).Actor
+		return "ok", nil
+	}
+
+	withAuthorization := func(value string) context.Context {
+		ctx := requestmeta.With(context.Background(), requestmeta.Meta{Actor: requestmeta.UnauthenticatedActor, ClaimedActor: "spoofed"})
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", value))
+	}
+
+	t.Run("Пользователь", func(t *testing.T) {
+		_, err := interceptor(withAuthorization("Bearer user"), nil, &grpc.UnaryServerInfo{}, handler)
+		require.NoError(t, err)
+		assert.Equal(t, userID, caller.UserID)
+		assert.False(t, caller.Admin)
+		assert.Equal(t, []string{"support", "finance"}, caller.Roles)
+		assert.Equal(t, "acme", caller.TenantID)
+		// автор изменений - клиент из токена, а не заголовок X-Actor
+		assert.Equal(t, "alice", actor)
+	})
+
+	t.Run("Администратор без ID пользователя", func(t *testing.T) {
+		_, err := interceptor(withAuthorization("bearer admin"), nil, &grpc.UnaryServerInfo{}, handler)
+		require.NoError(t, err)
+		assert.True(t, caller.Admin)
+		assert.Equal(t, uuid.Nil, caller.UserID)
+		assert.Empty(t, caller.TenantID)
+	})
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expected      codes.Code
+	}{
+		{name: "Без токена", expected: codes.Unauthenticated},
+		{name: "Другая схема", authorization: "Basic dXNlcjpwYXNz", expected: codes.Unauthenticated},
+		{name: "Недействительный токен", authorization: "Bearer forged", expected: codes.Unauthenticated},
+		{name: "Токен без ID пользователя", authorization: "Bearer no-userid", expected: codes.Unauthenticated},
+		{name: "Некорректный арендатор", authorization: "Bearer bad-tenant", expected: codes.Unauthenticated},
+		{name: "Ключи недоступны", authorization: "Bearer keys-down", expected: codes.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.authorization != "" {
+				ctx = withAuthorization(tc.authorization)
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			assert.Equal(t, tc.expected, status.Code(err))
+		})
+	}
+
+	t.Run("Поток", func(t *testing.T) {
+		stream := &stubServerStream{ctx: withAuthorization("Bearer user")}
+		err := StreamAuth(authenticator)(nil, stream, &grpc.StreamServerInfo{}, func(_ interface{}, ss grpc.ServerStream) error {
+			caller, _ = principal.From(ss.Context())
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, userID, caller.UserID)
+
+		stream = &stubServerStream{ctx: context.Background()}
+		err = StreamAuth(authenticator)(nil, stream, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+const (
+	// IfMatchHeader - заголовок с ETag ресурса, который клиент видел перед изменением
+	IfMatchHeader = "If-Match"
+	// IfNoneMatchHeader - заголовок условного GET с ETag закэшированного у клиента ресурса
+	IfNoneMatchHeader = "If-None-Match"
+	// ETagHeader - заголовок ответа с текущим ETag ресурса
+	ETagHeader = "ETag"
+)
+
+// ConditionalGetHandler отвечает 304 Not Modified без тела на GET-запрос с If-None-Match, если ETag ответа
+// совпадает с одним из переданных клиентом. ETag сравниваются без учета признака слабого ETag (W/)
+type ConditionalGetHandler struct {
+	wrap http.Handler
+}
+
+func NewConditionalGetHandler(wrap http.Handler) *ConditionalGetHandler {
+	return &ConditionalGetHandler{wrap: wrap}
+}
+
+func (h ConditionalGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ifNoneMatch := r.Header.Get(IfNoneMatchHeader)
+	// поток событий не завершается, поэтому буферизовать его ответ нельзя
+	eventStream := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	if (r.Method != http.MethodGet && r.Method != http.MethodHead) || ifNoneMatch == "" || eventStream {
+		h.wrap.ServeHTTP(w, r)
+		return
+	}
+
+	recorder := &bufferedResponseWriter{header: w.Header(), status: http.StatusOK}
+	h.wrap.ServeHTTP(recorder, r)
+
+	if recorder.status == http.StatusOK && etagMatches(ifNoneMatch, w.Header().Get(ETagHeader)) {
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(recorder.status)
+	_, _ = w.Write(recorder.body.Bytes())
+}
+
+// etagMatches сообщает, есть ли etag в списке ETag заголовка If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bufferedResponseWriter откладывает отправку ответа, пока не станет известен его ETag
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
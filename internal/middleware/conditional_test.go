@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalGetHandler(t *testing.T) {
+	handler := NewConditionalGetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ETagHeader, `"3"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version":"3"}`))
+	}))
+
+	testCases := []struct {
+		name           string
+		method         string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Без If-None-Match", method: http.MethodGet, expectedStatus: http.StatusOK, expectedBody: `{"version":"3"}`},
+		{name: "ETag совпадает", method: http.MethodGet, ifNoneMatch: `"3"`, expectedStatus: http.StatusNotModified},
+		{name: "Слабый ETag в списке", method: http.MethodGet, ifNoneMatch: `"1", W/"3"`, expectedStatus: http.StatusNotModified},
+		{name: "ETag изменился", method: http.MethodGet, ifNoneMatch: `"2"`, expectedStatus: http.StatusOK, expectedBody: `{"version":"3"}`},
+		{name: "Не GET", method: http.MethodPatch, ifNoneMatch: `"3"`, expectedStatus: http.StatusOK, expectedBody: `{"version":"3"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(tc.method, "/api/v1/subscriptions/1", nil)
+			if tc.ifNoneMatch != "" {
+				request.Header.Set(IfNoneMatchHeader, tc.ifNoneMatch)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			assert.Equal(t, tc.expectedBody, recorder.Body.String())
+			assert.Equal(t, `"3"`, recorder.Header().Get(ETagHeader))
+		})
+	}
+
+	t.Run("Ошибка не заменяется на 304", func(t *testing.T) {
+		handler := NewConditionalGetHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set(IfNoneMatchHeader, "*")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Поток событий не буферизуется", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/watch", nil)
+		request.Header.Set(IfNoneMatchHeader, `"3"`)
+		request.Header.Set("Accept", "text/event-stream")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"version":"3"}`, recorder.Body.String())
+	})
+}
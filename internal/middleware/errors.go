@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const (
+	ErrorDomain = "subscriptions.effective-mobile"
+
+	ReasonInvalidArgument = "INVALID_ARGUMENT"
+	ReasonInternal        = "INTERNAL"
+)
+
+// ErrorMapping - соответствие доменной ошибки gRPC-коду и машиночитаемой причине. HTTPStatus задает
+// HTTP-статус ответа Gateway, если он отличается от стандартного для Code
+type ErrorMapping struct {
+	Err        error
+	Code       codes.Code
+	Reason     string
+	HTTPStatus int
+}
+
+// ErrorTranslator переводит ошибки обработчиков в gRPC-статусы:
+// доменные ошибки - по таблице mappings, ошибки валидации - в INVALID_ARGUMENT с google.rpc.BadRequest,
+// остальные ошибки - в INTERNAL без подробностей. Уже сформированные статусы не изменяются
+func ErrorTranslator(mappings ...ErrorMapping) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, translateError(err, info.FullMethod, mappings)
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamErrorTranslator - ErrorTranslator для потоковых методов
+func StreamErrorTranslator(mappings ...ErrorMapping) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return translateError(err, info.FullMethod, mappings)
+		}
+
+		return nil
+	}
+}
+
+func translateError(err error, method string, mappings []ErrorMapping) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var protoValidationErr *protovalidate.ValidationError
+	if errors.As(err, &protoValidationErr) {
+		violations := make([]apperror.FieldViolation, 0, len(protoValidationErr.Violations))
+		for _, violation := range protoValidationErr.Violations {
+			violations = append(violations, apperror.FieldViolation{
+				Field:       protovalidate.FieldPathString(violation.Proto.GetField()),
+				Description: violation.Proto.GetMessage(),
+			})
+		}
+
+		return validationStatus(&apperror.ValidationError{Violations: violations})
+	}
+
+	var validationErr *apperror.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationStatus(validationErr)
+	}
+
+	for _, mapping := range mappings {
+		if errors.Is(err, mapping.Err) {
+			return withDetails(status.New(mapping.Code, err.Error()), &errdetails.ErrorInfo{
+				Reason: mapping.Reason,
+				Domain: ErrorDomain,
+			})
+		}
+	}
+
+	logger.GetLogger().Error("unexpected error", "method", method, "error", err)
+
+	return withDetails(status.New(codes.Internal, "internal error"), &errdetails.ErrorInfo{
+		Reason: ReasonInternal,
+		Domain: ErrorDomain,
+	})
+}
+
+func validationStatus(err *apperror.ValidationError) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range err.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+
+	return withDetails(status.New(codes.InvalidArgument, err.Error()), &errdetails.ErrorInfo{
+		Reason: ReasonInvalidArgument,
+		Domain: ErrorDomain,
+	}, badRequest)
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
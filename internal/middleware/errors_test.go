@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errTestNotFound = errors.New("not found")
+	errTestModified = errors.New("modified")
+)
+
+func TestTranslateError(t *testing.T) {
+	logger.Setup(string(logger.Text), "error")
+
+	mappings := []ErrorMapping{
+		{Err: errTestNotFound, Code: codes.NotFound, Reason: "TEST_NOT_FOUND"},
+		{Err: errTestModified, Code: codes.Aborted, Reason: "TEST_MODIFIED", HTTPStatus: http.StatusPreconditionFailed},
+	}
+	httpErrorHandler := NewHTTPErrorHandler(mappings...)
+
+	testCases := []struct {
+		name           string
+		err            error
+		expectedCode   codes.Code
+		expectedStatus int
+		expectedBody   HTTPError
+	}{
+		{
+			name:           "Доменная ошибка из таблицы",
+			err:            fmt.Errorf("get: %w", errTestNotFound),
+			expectedCode:   codes.NotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody: HTTPError{
+				Code:    int32(codes.NotFound),
+				Status:  "NOT_FOUND",
+				Message: "get: not found",
+				Reason:  "TEST_NOT_FOUND",
+			},
+		},
+		{
+			name:           "Доменная ошибка со своим HTTP-статусом",
+			err:            errTestModified,
+			expectedCode:   codes.Aborted,
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody: HTTPError{
+				Code:    int32(codes.Aborted),
+				Status:  "ABORTED",
+				Message: "modified",
+				Reason:  "TEST_MODIFIED",
+			},
+		},
+		{
+			name:           "Ошибка валидации",
+			err:            apperror.NewValidationError("start_date", "invalid month"),
+			expectedCode:   codes.InvalidArgument,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: HTTPError{
+				Code:    int32(codes.InvalidArgument),
+				Status:  "INVALID_ARGUMENT",
+				Message: "validation error: start_date: invalid month",
+				Reason:  ReasonInvalidArgument,
+				FieldViolations: []HTTPFieldViolation{
+					{Field: "start_date", Description: "invalid month"},
+				},
+			},
+		},
+		{
+			name:           "Непредвиденная ошибка скрывается",
+			err:            errors.New("connection refused"),
+			expectedCode:   codes.Internal,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: HTTPError{
+				Code:    int32(codes.Internal),
+				Status:  "INTERNAL",
+				Message: "internal error",
+				Reason:  ReasonInternal,
+			},
+		},
+		{
+			name:           "Готовый статус не изменяется",
+			err:            status.Error(codes.PermissionDenied, "denied"),
+			expectedCode:   codes.PermissionDenied,
+			expectedStatus: http.StatusForbidden,
+			expectedBody: HTTPError{
+				Code:    int32(codes.PermissionDenied),
+				Status:  "PERMISSION_DENIED",
+				Message: "denied",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := translateError(tc.err, "/test.Service/Method", mappings)
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+
+			recorder := httptest.NewRecorder()
+			httpErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, recorder, httptest.NewRequest(http.MethodGet, "/", nil), err)
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+
+			var body HTTPError
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+			assert.Equal(t, tc.expectedBody, body)
+		})
+	}
+}
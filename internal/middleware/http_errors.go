@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPError - тело ответа HTTP Gateway при ошибке
+type HTTPError struct {
+	Code            int32                `json:"code"`
+	Status          string               `json:"status"`
+	Message         string               `json:"message"`
+	Reason          string               `json:"reason,omitempty"`
+	FieldViolations []HTTPFieldViolation `json:"field_violations,omitempty"`
+}
+
+type HTTPFieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// NewHTTPErrorHandler возвращает обработчик ошибок Gateway, который отдает ошибки gRPC в виде стабильного JSON
+// с машиночитаемой причиной (reason) и списком некорректных полей. HTTP-статус определяется по gRPC-коду,
+// если для причины ошибки в mappings не задан свой HTTPStatus
+func NewHTTPErrorHandler(mappings ...ErrorMapping) runtime.ErrorHandlerFunc {
+	statuses := make(map[string]int)
+	for _, mapping := range mappings {
+		if mapping.HTTPStatus != 0 {
+			statuses[mapping.Reason] = mapping.HTTPStatus
+		}
+	}
+
+	return func(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+		writeHTTPError(w, err, statuses)
+	}
+}
+
+func writeHTTPError(w http.ResponseWriter, err error, statuses map[string]int) {
+	body := NewHTTPErrorBody(err)
+
+	httpStatus, ok := statuses[body.Reason]
+	if !ok {
+		httpStatus = runtime.HTTPStatusFromCode(codes.Code(body.Code))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// NewHTTPErrorBody формирует тело ответа Gateway для ошибки gRPC
+func NewHTTPErrorBody(err error) HTTPError {
+	st := status.Convert(err)
+
+	body := HTTPError{
+		Code:    int32(st.Code()),
+		Status:  code.Code(st.Code()).String(),
+		Message: st.Message(),
+	}
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			body.Reason = detail.GetReason()
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				body.FieldViolations = append(body.FieldViolations, HTTPFieldViolation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	return body
+}
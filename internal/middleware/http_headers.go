@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/textproto"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// HTTPHeaderMatcher передает в gRPC, помимо стандартных заголовков, заголовки Idempotency-Key, X-Request-Id, X-Actor,
+// X-Tenant-Id и If-Match без префикса grpcgateway-. Authorization Gateway всегда передает как метаданные authorization,
+// поэтому его копия с префиксом не передается
+func HTTPHeaderMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case IdempotencyKeyHeader, RequestIDHeader, ActorHeader, TenantHeader, IfMatchHeader:
+		return textproto.CanonicalMIMEHeaderKey(key), true
+	case AuthorizationHeader:
+		return "", false
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// HTTPOutgoingHeaderMatcher отдает клиенту заголовки Idempotent-Replayed, X-Request-Id и ETag без префикса Grpc-Metadata-
+func HTTPOutgoingHeaderMatcher(key string) (string, bool) {
+	if key == IdempotentReplayedHeader || key == strings.ToLower(RequestIDHeader) {
+		return textproto.CanonicalMIMEHeaderKey(key), true
+	}
+	if key == strings.ToLower(ETagHeader) {
+		return ETagHeader, true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
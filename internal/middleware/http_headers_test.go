@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHTTPHeaderMatcher(t *testing.T) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(HTTPHeaderMatcher))
+
+	request := httptest.NewRequest("GET", "/api/v1/subscriptions", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	request.Header.Set("X-Actor", "alice")
+	request.Header.Set("X-Tenant-ID", "acme")
+
+	ctx, err := runtime.AnnotateContext(context.Background(), mux, request, "/api.Subscriptions/GetSubscriptions")
+	require.NoError(t, err)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	assert.Equal(t, []string{"Bearer token"}, md.Get("authorization"))
+	assert.Empty(t, md.Get("grpcgateway-authorization"))
+	assert.Equal(t, []string{"alice"}, md.Get("x-actor"))
+	assert.Equal(t, []string{"acme"}, md.Get("x-tenant-id"))
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// IdempotencyKeyHeader - заголовок с ключом идемпотентности. В метаданных gRPC передается в нижнем регистре
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader - заголовок ответа, повторно выданного по ключу идемпотентности
+	IdempotentReplayedHeader = "idempotent-replayed"
+	// idempotencyKeyField - поле запроса, которым можно передать ключ вместо заголовка
+	idempotencyKeyField     = "request_id"
+	maxIdempotencyKeyLength = 255
+)
+
+var errNotProtoMessage = errors.New("response is not a proto message")
+
+// ReplayHeaders возвращает заголовки, которые обработчик отдает вместе с ответом resp. Сохраненный ответ
+// выдается повторно без вызова обработчика, поэтому такие заголовки восстанавливаются по самому ответу
+type ReplayHeaders func(resp interface{}) metadata.MD
+
+// IdempotencyStore хранит ответы на запросы с ключами идемпотентности
+type IdempotencyStore interface {
+	// Begin возвращает сохраненный ответ на запрос или nil, если ключ занят для нового запроса
+	Begin(ctx context.Context, key, method string, requestHash []byte) ([]byte, error)
+	Complete(ctx context.Context, key string, response []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency возвращает интерсептор, который для методов methods выполняет запрос с ключом идемпотентности
+// не более одного раза: повторный запрос с тем же ключом получает сохраненный ответ. Ключ берется из заголовка
+// Idempotency-Key или поля request_id запроса. Ответы с ошибкой не сохраняются, такой запрос можно повторить.
+// Заголовки повторно выданного ответа восстанавливает replayHeaders, если он задан
+func Idempotency(store IdempotencyStore, replayHeaders ReplayHeaders, methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		const op = "middleware.Idempotency"
+
+		message, ok := req.(proto.Message)
+		if !ok || !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		key, err := idempotencyKey(ctx, message)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return handler(ctx, req)
+		}
+		// ключи разных клиентов не пересекаются: иначе чужой клиент с тем же ключом получил бы сохраненный ответ
+		if caller, ok := principal.From(ctx); ok {
+			key = caller.Subject + "/" + key
+		}
+
+		log := logger.GetLogger().With(
+			slog.String("op", op),
+			slog.String("method", info.FullMethod),
+			slog.String("idempotency_key", key),
+		)
+
+		requestHash, err := idempotencyRequestHash(message)
+		if err != nil {
+			return nil, err
+		}
+
+		saved, err := store.Begin(ctx, key, info.FullMethod, requestHash)
+		if err != nil {
+			return nil, err
+		}
+		if saved != nil {
+			log.Info("replaying saved response")
+			return replayResponse(ctx, saved, replayHeaders)
+		}
+
+		// ключ освобождается и ответ сохраняется, даже если клиент уже отключился
+		storeCtx := context.WithoutCancel(ctx)
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if releaseErr := store.Release(storeCtx, key); releaseErr != nil {
+				log.Error("failed to release idempotency key", "error", releaseErr)
+			}
+			return nil, err
+		}
+
+		response, err := marshalResponse(resp)
+		if err != nil {
+			log.Error("failed to marshal response", "error", err)
+			return resp, nil
+		}
+
+		if err = store.Complete(storeCtx, key, response); err != nil {
+			log.Error("failed to save response", "error", err)
+		}
+
+		return resp, nil
+	}
+}
+
+// idempotencyKey возвращает ключ из заголовка или поля request_id. Если заданы оба, они должны совпадать
+func idempotencyKey(ctx context.Context, message proto.Message) (string, error) {
+	var key string
+	if values := metadata.ValueFromIncomingContext(ctx, IdempotencyKeyHeader); len(values) > 0 {
+		key = values[0]
+	}
+
+	reflect := message.ProtoReflect()
+	if field := reflect.Descriptor().Fields().ByName(idempotencyKeyField); field != nil && field.Kind() == protoreflect.StringKind {
+		requestID := reflect.Get(field).String()
+		if key != "" && requestID != "" && key != requestID {
+			return "", apperror.NewValidationError(idempotencyKeyField, "does not match "+IdempotencyKeyHeader+" header")
+		}
+		if key == "" {
+			key = requestID
+		}
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		return "", apperror.NewValidationError(idempotencyKeyField, "idempotency key is too long")
+	}
+
+	return key, nil
+}
+
+// idempotencyRequestHash возвращает хэш запроса без поля request_id, чтобы ключ из заголовка и из тела
+// давали одинаковый хэш
+func idempotencyRequestHash(message proto.Message) ([]byte, error) {
+	message = proto.Clone(message)
+
+	reflect := message.ProtoReflect()
+	if field := reflect.Descriptor().Fields().ByName(idempotencyKeyField); field != nil {
+		reflect.Clear(field)
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+
+	return hash[:], nil
+}
+
+func marshalResponse(resp interface{}) ([]byte, error) {
+	message, ok := resp.(proto.Message)
+	if !ok {
+		return nil, errNotProtoMessage
+	}
+
+	wrapped, err := anypb.New(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(wrapped)
+}
+
+func replayResponse(ctx context.Context, saved []byte, replayHeaders ReplayHeaders) (interface{}, error) {
+	var wrapped anypb.Any
+	if err := proto.Unmarshal(saved, &wrapped); err != nil {
+		return nil, err
+	}
+
+	resp, err := wrapped.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+
+	header := metadata.Pairs(IdempotentReplayedHeader, "true")
+	if replayHeaders != nil {
+		header = metadata.Join(header, replayHeaders(resp))
+	}
+	_ = grpc.SetHeader(ctx, header)
+
+	return resp, nil
+}
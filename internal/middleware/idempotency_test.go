@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+var errTestKeyReused = errors.New("key reused")
+
+type memoryIdempotencyStore struct {
+	hashes    map[string][]byte
+	responses map[string][]byte
+}
+
+func (s *memoryIdempotencyStore) Begin(_ context.Context, key, _ string, requestHash []byte) ([]byte, error) {
+	if hash, ok := s.hashes[key]; ok {
+		if !bytes.Equal(hash, requestHash) {
+			return nil, errTestKeyReused
+		}
+		return s.responses[key], nil
+	}
+
+	s.hashes[key] = requestHash
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key string, response []byte) error {
+	s.responses[key] = response
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key string) error {
+	delete(s.hashes, key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	logger.Setup(string(logger.Text), "error")
+
+	store := &memoryIdempotencyStore{hashes: map[string][]byte{}, responses: map[string][]byte{}}
+	info := &grpc.UnaryServerInfo{FullMethod: pbSubscription.Subscriptions_ChangeSubscriptionPlan_FullMethodName}
+	interceptor := Idempotency(store, nil, info.FullMethod)
+
+	calls := 0
+	var handlerErr error
+	handler := func(_ context.Context, req interface{}) (interface{}, error) {
+		calls++
+		if handlerErr != nil {
+			return nil, handlerErr
+		}
+
+		return &pbSubscription.ChangeSubscriptionPlanResponse{
+			Subscription: &pbSubscription.Subscription{SubscriptionId: req.(*pbSubscription.ChangeSubscriptionPlanRequest).GetSubscriptionId()},
+		}, nil
+	}
+
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", key))
+	}
+	request := &pbSubscription.ChangeSubscriptionPlanRequest{SubscriptionId: "first", Price: 500}
+
+	t.Run("Повтор с тем же ключом возвращает сохраненный ответ", func(t *testing.T) {
+		first, err := interceptor(withKey("key-1"), request, info, handler)
+		assert.NoError(t, err)
+
+		replayed, err := interceptor(withKey("key-1"), request, info, handler)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(first.(proto.Message), replayed.(proto.Message)))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Ключ в поле request_id равнозначен заголовку", func(t *testing.T) {
+		withRequestID := proto.Clone(request).(*pbSubscription.ChangeSubscriptionPlanRequest)
+		withRequestID.RequestId = "key-1"
+
+		_, err := interceptor(context.Background(), withRequestID, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+
+		withRequestID.RequestId = "key-2"
+		_, err = interceptor(withKey("key-1"), withRequestID, info, handler)
+		var validationErr *apperror.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("Повтор с другим телом запроса", func(t *testing.T) {
+		_, err := interceptor(withKey("key-1"), &pbSubscription.ChangeSubscriptionPlanRequest{SubscriptionId: "first", Price: 600}, info, handler)
+		assert.ErrorIs(t, err, errTestKeyReused)
+	})
+
+	t.Run("Ответ с ошибкой не сохраняется", func(t *testing.T) {
+		handlerErr = errors.New("unavailable")
+		_, err := interceptor(withKey("key-3"), request, info, handler)
+		assert.Error(t, err)
+
+		handlerErr = nil
+		_, err = interceptor(withKey("key-3"), request, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Без ключа и для других методов запрос выполняется каждый раз", func(t *testing.T) {
+		_, _ = interceptor(context.Background(), request, info, handler)
+		_, _ = interceptor(withKey("key-1"), request, &grpc.UnaryServerInfo{FullMethod: "/other"}, handler)
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("Ключи разных клиентов не пересекаются", func(t *testing.T) {
+		alice := principal.With(withKey("key-4"), principal.Principal{Subject: "alice"})
+		bob := principal.With(withKey("key-4"), principal.Principal{Subject: "bob"})
+
+		_, err := interceptor(alice, request, info, handler)
+		assert.NoError(t, err)
+		_, err = interceptor(bob, request, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, calls)
+		assert.Contains(t, store.hashes, "alice/key-4")
+	})
+}
+
+type headerTransportStream struct {
+	header metadata.MD
+}
+
+func (s *headerTransportStream) Method() string {
+	return ""
+}
+
+func (s *headerTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *headerTransportStream) SetTrailer(metadata.MD) error {
+	return nil
+}
+
+func TestIdempotencyReplayHeaders(t *testing.T) {
+	logger.Setup(string(logger.Text), "error")
+
+	store := &memoryIdempotencyStore{hashes: map[string][]byte{}, responses: map[string][]byte{}}
+	info := &grpc.UnaryServerInfo{FullMethod: pbSubscription.Subscriptions_ChangeSubscriptionPlan_FullMethodName}
+	replayHeaders := func(resp interface{}) metadata.MD {
+		return metadata.Pairs(ETagHeader, resp.(*pbSubscription.ChangeSubscriptionPlanResponse).GetSubscription().GetEtag())
+	}
+	interceptor := Idempotency(store, replayHeaders, info.FullMethod)
+
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(ETagHeader, `"2"`))
+		return &pbSubscription.ChangeSubscriptionPlanResponse{
+			Subscription: &pbSubscription.Subscription{SubscriptionId: "first", Etag: `"2"`},
+		}, nil
+	}
+	call := func() metadata.MD {
+		stream := &headerTransportStream{}
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", "key-1"))
+		_, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), &pbSubscription.ChangeSubscriptionPlanRequest{SubscriptionId: "first"}, info, handler)
+		assert.NoError(t, err)
+		return stream.header
+	}
+
+	first := call()
+	assert.Equal(t, []string{`"2"`}, first.Get(ETagHeader))
+	assert.Empty(t, first.Get(IdempotentReplayedHeader))
+
+	// повторно выданный ответ получает тот же ETag, хотя обработчик не вызывается
+	replayed := call()
+	assert.Equal(t, []string{`"2"`}, replayed.Get(ETagHeader))
+	assert.Equal(t, []string{"true"}, replayed.Get(IdempotentReplayedHeader))
+}
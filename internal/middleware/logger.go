@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
 	"google.golang.org/grpc"
 )
 
@@ -41,6 +42,7 @@ func Logger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, ha
 	log := logger.GetLogger().With(
 		slog.String("op", op),
 		slog.String("method", info.FullMethod),
+		slog.String("request_id", requestmeta.From(ctx).RequestID),
 	)
 
 	log.Info("request received")
@@ -53,3 +55,23 @@ func Logger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, ha
 
 	return resp, err
 }
+
+// StreamLogger - Logger для потоковых методов. Поток логируется при открытии и при завершении с ошибкой
+func StreamLogger(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	const op = "middleware.StreamLogger"
+
+	log := logger.GetLogger().With(
+		slog.String("op", op),
+		slog.String("method", info.FullMethod),
+		slog.String("request_id", requestmeta.From(ss.Context()).RequestID),
+	)
+
+	log.Info("stream opened")
+
+	if err := handler(srv, ss); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/rbac"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ReasonAccessDenied = "ACCESS_DENIED"
+
+// RBAC возвращает интерсептор, который пропускает клиента только к методам, разрешенным его ролям в policy.
+// Правило с условием all открывает клиенту подписки всех пользователей. Администратора и клиентов с API-ключами,
+// ограниченных областями доступа, политика не проверяет
+func RBAC(policy *rbac.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, info.FullMethod, policy)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRBAC - RBAC для потоковых методов
+func StreamRBAC(policy *rbac.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), info.FullMethod, policy)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authorize(ctx context.Context, method string, policy *rbac.Policy) (context.Context, error) {
+	caller, ok := principal.From(ctx)
+	if !ok || caller.Admin || caller.ScopeLimited {
+		return ctx, nil
+	}
+
+	roles := caller.Roles
+	if len(roles) == 0 {
+		roles = policy.DefaultRoles
+	}
+
+	decision := policy.Authorize(roles, method)
+	if !decision.Allowed {
+		return nil, withDetails(status.New(codes.PermissionDenied, "method is not allowed for the caller roles"), &errdetails.ErrorInfo{
+			Reason: ReasonAccessDenied,
+			Domain: ErrorDomain,
+		})
+	}
+
+	if decision.Resources == rbac.ResourcesAll {
+		// клиент без пользователя не ограничен подписками одного пользователя
+		caller.UserID = uuid.Nil
+		ctx = principal.With(ctx, caller)
+	}
+
+	return ctx, nil
+}
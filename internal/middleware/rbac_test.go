@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/rbac"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRBAC(t *testing.T) {
+	policy := &rbac.Policy{
+		DefaultRoles: []string{"user"},
+		Roles: map[string]rbac.Role{
+			"user": {Rules: []rbac.Rule{{Methods: []string{"/api.Subscriptions/*"}}}},
+			"support": {Rules: []rbac.Rule{{
+				Methods:   []string{"/api.Subscriptions/Get*", pbSubscription.Subscriptions_WatchSubscriptions_FullMethodName},
+				Resources: rbac.ResourcesAll,
+			}}},
+			"finance": {Rules: []rbac.Rule{{
+				Methods:   []string{pbSubscription.Subscriptions_GetSumSubscriptions_FullMethodName},
+				Resources: rbac.ResourcesAll,
+			}}},
+		},
+	}
+	interceptor := RBAC(policy)
+
+	userID := uuid.New()
+	withRoles := func(roles ...string) context.Context {
+		return principal.With(context.Background(), principal.Principal{Subject: "alice", UserID: userID, Roles: roles})
+	}
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		expected codes.Code
+		userID   uuid.UUID
+	}{
+		{name: "Поддержка читает подписки всех пользователей", ctx: withRoles("support"), method: pbSubscription.Subscriptions_GetSubscriptions_FullMethodName, expected: codes.OK, userID: uuid.Nil},
+		{name: "Поддержка не удаляет подписки", ctx: withRoles("support"), method: pbSubscription.Subscriptions_DeleteSubscription_FullMethodName, expected: codes.PermissionDenied},
+		{name: "Финансы получают сумму", ctx: withRoles("finance"), method: pbSubscription.Subscriptions_GetSumSubscriptions_FullMethodName, expected: codes.OK, userID: uuid.Nil},
+		{name: "Финансы не читают подписки", ctx: withRoles("finance"), method: pbSubscription.Subscriptions_GetSubscriptions_FullMethodName, expected: codes.PermissionDenied},
+		{name: "Роли по умолчанию", ctx: withRoles(), method: pbSubscription.Subscriptions_DeleteSubscription_FullMethodName, expected: codes.OK, userID: userID},
+		{name: "Неизвестная роль", ctx: withRoles("guest"), method: pbSubscription.Subscriptions_GetSubscriptions_FullMethodName, expected: codes.PermissionDenied},
+		{
+			name:     "Администратор",
+			ctx:      principal.With(context.Background(), principal.Principal{Subject: "root", Admin: true, Roles: []string{"finance"}}),
+			method:   pbSubscription.Subscriptions_CreateWebhook_FullMethodName,
+			expected: codes.OK,
+		},
+		{
+			name:     "API-ключ",
+			ctx:      principal.With(context.Background(), principal.Principal{Subject: "api-key:1", UserID: userID, ScopeLimited: true}),
+			method:   pbSubscription.Subscriptions_CreateWebhook_FullMethodName,
+			expected: codes.OK,
+			userID:   userID,
+		},
+		{name: "Без аутентификации", ctx: context.Background(), method: pbSubscription.Subscriptions_DeleteSubscription_FullMethodName, expected: codes.OK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var caller principal.Principal
+			handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+				caller, _ = principal.From(ctx)
+				return "ok", nil
+			}
+
+			_, err := interceptor(tc.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			assert.Equal(t, tc.expected, status.Code(err))
+
+			if tc.expected != codes.OK {
+				details := status.Convert(err).Details()
+				if assert.Len(t, details, 1) {
+					assert.Equal(t, ReasonAccessDenied, details[0].(*errdetails.ErrorInfo).GetReason())
+				}
+				return
+			}
+			assert.Equal(t, tc.userID, caller.UserID)
+		})
+	}
+
+	t.Run("Поток", func(t *testing.T) {
+		var caller principal.Principal
+		stream := &stubServerStream{ctx: withRoles("support")}
+		err := StreamRBAC(policy)(nil, stream, &grpc.StreamServerInfo{FullMethod: pbSubscription.Subscriptions_WatchSubscriptions_FullMethodName}, func(_ interface{}, ss grpc.ServerStream) error {
+			caller, _ = principal.From(ss.Context())
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Nil, caller.UserID)
+
+		stream = &stubServerStream{ctx: withRoles("finance")}
+		err = StreamRBAC(policy)(nil, stream, &grpc.StreamServerInfo{FullMethod: pbSubscription.Subscriptions_WatchSubscriptions_FullMethodName}, func(interface{}, grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
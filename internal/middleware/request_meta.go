@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// RequestIDHeader - заголовок с ID запроса. Если клиент его не передал, ID генерируется и возвращается в ответе
+	RequestIDHeader = "X-Request-Id"
+	// ActorHeader - заголовок, в котором клиент может назвать автора изменений. Значение не проверяется
+	// и попадает в журнал аудита отдельно от автора, подтвержденного аутентификацией
+	ActorHeader = "X-Actor"
+)
+
+// RequestMeta сохраняет в контексте ID запроса из метаданных x-request-id и заявленного автора изменений из x-actor.
+// Автор изменений - requestmeta.UnauthenticatedActor, пока его не заменит субъект клиента при аутентификации
+func RequestMeta(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	meta := newRequestMeta(ctx)
+
+	// ошибка возможна, только если заголовки ответа уже отправлены, ID запроса при этом остается в логах
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, meta.RequestID))
+
+	return handler(requestmeta.With(ctx, meta), req)
+}
+
+// StreamRequestMeta - RequestMeta для потоковых методов
+func StreamRequestMeta(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	meta := newRequestMeta(ss.Context())
+	_ = ss.SetHeader(metadata.Pairs(RequestIDHeader, meta.RequestID))
+
+	return handler(srv, &contextServerStream{ServerStream: ss, ctx: requestmeta.With(ss.Context(), meta)})
+}
+
+func newRequestMeta(ctx context.Context) requestmeta.Meta {
+	meta := requestmeta.Meta{
+		RequestID:    firstMetadataValue(ctx, RequestIDHeader),
+		Actor:        requestmeta.UnauthenticatedActor,
+		ClaimedActor: firstMetadataValue(ctx, ActorHeader),
+	}
+	if meta.RequestID == "" {
+		meta.RequestID = uuid.NewString()
+	}
+
+	return meta
+}
+
+func firstMetadataValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
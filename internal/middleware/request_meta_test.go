@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestMeta(t *testing.T) {
+	var meta requestmeta.Meta
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		meta = requestmeta.From(ctx)
+		return nil, nil
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1", "x-actor", "alice"))
+
+		_, err := RequestMeta(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, requestmeta.Meta{RequestID: "req-1", Actor: requestmeta.UnauthenticatedActor, ClaimedActor: "alice"}, meta)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		_, err := RequestMeta(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, meta.RequestID)
+		assert.Equal(t, requestmeta.UnauthenticatedActor, meta.Actor)
+		assert.Empty(t, meta.ClaimedActor)
+	})
+}
+
+type stubServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *stubServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *stubServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestStreamRequestMeta(t *testing.T) {
+	ss := &stubServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1", "x-actor", "alice")),
+	}
+
+	var meta requestmeta.Meta
+	err := StreamRequestMeta(nil, ss, &grpc.StreamServerInfo{}, func(_ interface{}, stream grpc.ServerStream) error {
+		meta = requestmeta.From(stream.Context())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, requestmeta.Meta{RequestID: "req-1", Actor: requestmeta.UnauthenticatedActor, ClaimedActor: "alice"}, meta)
+	assert.Equal(t, []string{"req-1"}, ss.header.Get(RequestIDHeader))
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ReasonInsufficientScope = "INSUFFICIENT_SCOPE"
+
+// Scopes возвращает интерсептор, который пропускает клиента с ограниченными областями доступа только к методам,
+// область доступа которых из таблицы methodScopes у него есть. Методы, которых нет в таблице, такому клиенту
+// недоступны. Доступ остальных клиентов не проверяется
+func Scopes(methodScopes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkScope(ctx, info.FullMethod, methodScopes); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamScopes - Scopes для потоковых методов
+func StreamScopes(methodScopes map[string]string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkScope(ss.Context(), info.FullMethod, methodScopes); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func checkScope(ctx context.Context, method string, methodScopes map[string]string) error {
+	caller, ok := principal.From(ctx)
+	if !ok || !caller.ScopeLimited {
+		return nil
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok {
+		return insufficientScope("method is not available to clients with limited scopes")
+	}
+	if !caller.HasScope(scope) {
+		return insufficientScope("scope " + scope + " is required")
+	}
+
+	return nil
+}
+
+func insufficientScope(message string) error {
+	return withDetails(status.New(codes.PermissionDenied, message), &errdetails.ErrorInfo{
+		Reason: ReasonInsufficientScope,
+		Domain: ErrorDomain,
+	})
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestScopes(t *testing.T) {
+	methodScopes := map[string]string{
+		pbSubscription.Subscriptions_GetSubscriptions_FullMethodName:    "subscriptions:read",
+		pbSubscription.Subscriptions_GetSumSubscriptions_FullMethodName: "reports:read",
+	}
+	interceptor := Scopes(methodScopes)
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	reports := principal.With(context.Background(), principal.Principal{Scopes: []string{"reports:read"}, ScopeLimited: true})
+	user := principal.With(context.Background(), principal.Principal{Subject: "alice"})
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		expected codes.Code
+	}{
+		{name: "Есть область доступа", ctx: reports, method: pbSubscription.Subscriptions_GetSumSubscriptions_FullMethodName, expected: codes.OK},
+		{name: "Нет области доступа", ctx: reports, method: pbSubscription.Subscriptions_GetSubscriptions_FullMethodName, expected: codes.PermissionDenied},
+		{name: "Метода нет в таблице", ctx: reports, method: pbSubscription.Subscriptions_CreateWebhook_FullMethodName, expected: codes.PermissionDenied},
+		{name: "Клиент без ограничения областями", ctx: user, method: pbSubscription.Subscriptions_GetSubscriptions_FullMethodName, expected: codes.OK},
+		{name: "Без аутентификации", ctx: context.Background(), method: pbSubscription.Subscriptions_CreateWebhook_FullMethodName, expected: codes.OK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := interceptor(tc.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			assert.Equal(t, tc.expected, status.Code(err))
+
+			if tc.expected != codes.OK {
+				details := status.Convert(err).Details()
+				if assert.Len(t, details, 1) {
+					assert.Equal(t, ReasonInsufficientScope, details[0].(*errdetails.ErrorInfo).GetReason())
+				}
+			}
+		})
+	}
+
+	t.Run("Поток", func(t *testing.T) {
+		stream := &stubServerStream{ctx: reports}
+		err := StreamScopes(methodScopes)(nil, stream, &grpc.StreamServerInfo{FullMethod: pbSubscription.Subscriptions_WatchSubscriptions_FullMethodName}, func(interface{}, grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
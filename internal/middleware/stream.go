@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// contextServerStream подменяет контекст потока, чтобы stream-перехватчики могли передать обработчику
+// значения в контексте, как unary-перехватчики
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
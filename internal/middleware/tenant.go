@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// TenantHeader - заголовок с арендатором запроса
+	TenantHeader = "X-Tenant-Id"
+
+	ReasonTenantDenied = "TENANT_DENIED"
+)
+
+// Tenant определяет арендатора запроса и сохраняет его в контексте. Арендатор клиента берется из его учетных данных,
+// а заголовок X-Tenant-Id выбирает арендатора для администратора без арендатора. Запросу без аутентификации заголовок
+// доступен, только если trustHeader: иначе любой клиент мог бы выбрать чужого арендатора. Клиенту без арендатора
+// и запросу без заголовка достается арендатор по умолчанию
+func Tenant(trustHeader bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		tenantID, err := resolveTenant(ctx, trustHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(tenant.With(ctx, tenantID), req)
+	}
+}
+
+// StreamTenant - Tenant для потоковых методов
+func StreamTenant(trustHeader bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tenantID, err := resolveTenant(ss.Context(), trustHeader)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: tenant.With(ss.Context(), tenantID)})
+	}
+}
+
+func resolveTenant(ctx context.Context, trustHeader bool) (string, error) {
+	requested := firstMetadataValue(ctx, TenantHeader)
+	if requested != "" {
+		if err := tenant.Validate(requested); err != nil {
+			return "", apperror.NewValidationError(TenantHeader, err.Error())
+		}
+	}
+
+	caller, ok := principal.From(ctx)
+	if !ok && requested != "" && !trustHeader {
+		return "", tenantDenied("tenant header requires authentication")
+	}
+	if !ok || (caller.Admin && caller.TenantID == "") {
+		if requested == "" {
+			return tenant.Default, nil
+		}
+		return requested, nil
+	}
+
+	tenantID := caller.TenantID
+	if tenantID == "" {
+		tenantID = tenant.Default
+	}
+	if requested != "" && requested != tenantID {
+		return "", tenantDenied("tenant is not available to the caller")
+	}
+
+	return tenantID, nil
+}
+
+func tenantDenied(message string) error {
+	return withDetails(status.New(codes.PermissionDenied, message), &errdetails.ErrorInfo{
+		Reason: ReasonTenantDenied,
+		Domain: ErrorDomain,
+	})
+}
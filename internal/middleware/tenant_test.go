@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenant(t *testing.T) {
+	var tenantID string
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		tenantID = tenant.From(ctx)
+		return nil, nil
+	}
+
+	withTenant := func(ctx context.Context, header string) context.Context {
+		if header == "" {
+			return ctx
+		}
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("x-tenant-id", header))
+	}
+	user := principal.With(context.Background(), principal.Principal{Subject: "alice", TenantID: "acme"})
+	userWithoutTenant := principal.With(context.Background(), principal.Principal{Subject: "bob"})
+	admin := principal.With(context.Background(), principal.Principal{Subject: "ops", Admin: true})
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		header      string
+		trustHeader bool
+		expected    string
+	}{
+		{name: "Без аутентификации и заголовка", ctx: context.Background(), expected: tenant.Default},
+		{name: "Без аутентификации с доверенным заголовком", ctx: context.Background(), header: "acme", trustHeader: true, expected: "acme"},
+		{name: "Арендатор клиента", ctx: user, expected: "acme"},
+		{name: "Заголовок совпадает с арендатором клиента", ctx: user, header: "acme", expected: "acme"},
+		{name: "Клиент без арендатора", ctx: userWithoutTenant, expected: tenant.Default},
+		{name: "Администратор выбирает арендатора", ctx: admin, header: "globex", expected: "globex"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Tenant(tc.trustHeader)(withTenant(tc.ctx, tc.header), nil, &grpc.UnaryServerInfo{}, handler)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, tenantID)
+		})
+	}
+
+	t.Run("Чужой арендатор", func(t *testing.T) {
+		// без аутентификации заголовок принимается, только если ему доверяют
+		for _, ctx := range []context.Context{user, userWithoutTenant, context.Background()} {
+			_, err := Tenant(false)(withTenant(ctx, "globex"), nil, &grpc.UnaryServerInfo{}, handler)
+
+			st := status.Convert(err)
+			assert.Equal(t, codes.PermissionDenied, st.Code())
+			if assert.Len(t, st.Details(), 1) {
+				assert.Equal(t, ReasonTenantDenied, st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+			}
+		}
+	})
+
+	t.Run("Некорректный арендатор", func(t *testing.T) {
+		_, err := Tenant(true)(withTenant(context.Background(), "Acme Inc"), nil, &grpc.UnaryServerInfo{}, handler)
+
+		var validationErr *apperror.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("Поток", func(t *testing.T) {
+		stream := &stubServerStream{ctx: withTenant(admin, "globex")}
+		err := StreamTenant(false)(nil, stream, &grpc.StreamServerInfo{}, func(_ interface{}, ss grpc.ServerStream) error {
+			tenantID = tenant.From(ss.Context())
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "globex", tenantID)
+	})
+}
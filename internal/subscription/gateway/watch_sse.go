@@ -0,0 +1,183 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/middleware"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// WatchSubscriptionsPath - путь HTTP Gateway для наблюдения за изменениями подписок
+	WatchSubscriptionsPath = "/api/v1/subscriptions/watch"
+	// LastEventIDHeader - заголовок, с которым EventSource переподключается после обрыва соединения
+	LastEventIDHeader = "Last-Event-ID"
+
+	// heartbeatInterval - период комментариев-пингов, не дающих прокси закрыть простаивающее соединение
+	heartbeatInterval = 15 * time.Second
+)
+
+// WatchSubscriptionsSSE возвращает обработчик Gateway, который отдает поток WatchSubscriptions как Server-Sent Events.
+// Каждое событие передается с id, равным event_id, поэтому после обрыва EventSource продолжает поток с заголовком
+// Last-Event-ID. Ошибки до начала потока возвращаются обычным JSON-ответом, после начала - событием error
+func WatchSubscriptionsSSE(mux *runtime.ServeMux, client pbSubscription.SubscriptionsClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, pbSubscription.Subscriptions_WatchSubscriptions_FullMethodName,
+			runtime.WithHTTPPathPattern(WatchSubscriptionsPath))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		req, err := watchRequest(r)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		stream, err := client.WatchSubscriptions(ctx, req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		// заголовки приходят, когда сервер подписался на события; без них поток уже завершился
+		header, err := stream.Header()
+		if err == nil && header == nil {
+			_, err = stream.Recv()
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		if requestID := header.Get(middleware.RequestIDHeader); len(requestID) > 0 {
+			w.Header().Set(middleware.RequestIDHeader, requestID[0])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		controller := http.NewResponseController(w)
+		_ = controller.Flush()
+		if header == nil {
+			return
+		}
+
+		type received struct {
+			event *pbSubscription.SubscriptionEvent
+			err   error
+		}
+
+		events := make(chan received)
+		go func() {
+			for {
+				event, err := stream.Recv()
+				select {
+				case events <- received{event: event, err: err}:
+				case <-ctx.Done():
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+				_, err = io.WriteString(w, ": ping\n\n")
+			case next := <-events:
+				switch {
+				case errors.Is(next.err, io.EOF):
+					return
+				case next.err != nil:
+					writeErrorEvent(w, next.err)
+					_ = controller.Flush()
+					return
+				}
+
+				err = writeEvent(w, outboundMarshaler, next.event)
+			}
+			if err != nil {
+				return
+			}
+
+			_ = controller.Flush()
+		}
+	}
+}
+
+// watchRequest собирает запрос из параметров URL. Last-Event-ID важнее afterEventId: при переподключении
+// EventSource повторяет исходный URL, а продолжать нужно с последнего полученного события
+func watchRequest(r *http.Request) (*pbSubscription.WatchSubscriptionsRequest, error) {
+	req := &pbSubscription.WatchSubscriptionsRequest{}
+	if err := runtime.PopulateQueryParameters(req, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if lastEventID := r.Header.Get(LastEventIDHeader); lastEventID != "" {
+		afterEventID, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s header: %q", LastEventIDHeader, lastEventID)
+		}
+		req.AfterEventId = &afterEventID
+	}
+
+	return req, nil
+}
+
+func writeEvent(w io.Writer, marshaler runtime.Marshaler, event *pbSubscription.SubscriptionEvent) error {
+	data, err := marshaler.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "id: %d\nevent: %s\n", event.GetEventId(), event.GetType())
+	writeData(&buf, data)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func writeErrorEvent(w io.Writer, err error) {
+	data, _ := json.Marshal(middleware.NewHTTPErrorBody(err))
+
+	var buf bytes.Buffer
+	buf.WriteString("event: error\n")
+	writeData(&buf, data)
+
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeData записывает данные события: каждая строка - отдельное поле data
+func writeData(buf *bytes.Buffer, data []byte) {
+	for _, line := range strings.Split(string(data), "\n") {
+		buf.WriteString("data: ")
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+}
@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Geriler/effective-mobile/internal/middleware"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type stubSubscriptionsClient struct {
+	pbSubscription.SubscriptionsClient
+	stream  *stubEventStream
+	request *pbSubscription.WatchSubscriptionsRequest
+}
+
+func (c *stubSubscriptionsClient) WatchSubscriptions(_ context.Context, in *pbSubscription.WatchSubscriptionsRequest, _ ...grpc.CallOption) (grpc.ServerStreamingClient[pbSubscription.SubscriptionEvent], error) {
+	c.request = in
+	return c.stream, nil
+}
+
+type stubEventStream struct {
+	grpc.ClientStream
+	header metadata.MD
+	events []*pbSubscription.SubscriptionEvent
+	err    error
+}
+
+func (s *stubEventStream) Header() (metadata.MD, error) {
+	return s.header, nil
+}
+
+func (s *stubEventStream) Recv() (*pbSubscription.SubscriptionEvent, error) {
+	if len(s.events) == 0 {
+		return nil, s.err
+	}
+
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func newTestMux() *runtime.ServeMux {
+	return runtime.NewServeMux(runtime.WithErrorHandler(middleware.NewHTTPErrorHandler(middleware.ErrorMapping{
+		Reason:     "WATCH_RESUME_EXPIRED",
+		HTTPStatus: http.StatusGone,
+	})))
+}
+
+func TestWatchSubscriptionsSSE(t *testing.T) {
+	t.Run("События и обрыв потока", func(t *testing.T) {
+		client := &stubSubscriptionsClient{stream: &stubEventStream{
+			header: metadata.Pairs("x-request-id", "req-1"),
+			events: []*pbSubscription.SubscriptionEvent{
+				{EventId: 42, Type: "SubscriptionCreated", SubscriptionId: "5b1c"},
+			},
+			err: status.Error(codes.Unavailable, "subscription event stream was interrupted"),
+		}}
+
+		request := httptest.NewRequest(http.MethodGet, WatchSubscriptionsPath+"?userId=60601fee-2bf1-4721-ae6f-7636e79a0cba&afterEventId=7", nil)
+		request.Header.Set(LastEventIDHeader, "41")
+		recorder := httptest.NewRecorder()
+		WatchSubscriptionsSSE(newTestMux(), client)(recorder, request, nil)
+
+		// при переподключении EventSource продолжает с Last-Event-ID, а не с исходного afterEventId
+		require.NotNil(t, client.request)
+		assert.Equal(t, int64(41), client.request.GetAfterEventId())
+		assert.Equal(t, "60601fee-2bf1-4721-ae6f-7636e79a0cba", client.request.GetUserId())
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "req-1", recorder.Header().Get(middleware.RequestIDHeader))
+		blocks := strings.Split(strings.TrimSuffix(recorder.Body.String(), "\n\n"), "\n\n")
+		require.Len(t, blocks, 2)
+
+		lines := strings.Split(blocks[0], "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "id: 42", lines[0])
+		assert.Equal(t, "event: SubscriptionCreated", lines[1])
+		var event map[string]any
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event))
+		assert.Equal(t, "5b1c", event["subscriptionId"])
+
+		assert.Equal(t, "event: error\n"+
+			`data: {"code":14,"status":"UNAVAILABLE","message":"subscription event stream was interrupted"}`, blocks[1])
+	})
+
+	t.Run("Ошибка до начала потока", func(t *testing.T) {
+		st, err := status.New(codes.OutOfRange, "events are no longer available").WithDetails(&errdetails.ErrorInfo{Reason: "WATCH_RESUME_EXPIRED"})
+		require.NoError(t, err)
+		client := &stubSubscriptionsClient{stream: &stubEventStream{err: st.Err()}}
+
+		request := httptest.NewRequest(http.MethodGet, WatchSubscriptionsPath+"?afterEventId=3", nil)
+		recorder := httptest.NewRecorder()
+		WatchSubscriptionsSSE(newTestMux(), client)(recorder, request, nil)
+
+		assert.Equal(t, http.StatusGone, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	})
+
+	t.Run("Поток завершился без событий", func(t *testing.T) {
+		client := &stubSubscriptionsClient{stream: &stubEventStream{err: io.EOF}}
+
+		recorder := httptest.NewRecorder()
+		WatchSubscriptionsSSE(newTestMux(), client)(recorder, httptest.NewRequest(http.MethodGet, WatchSubscriptionsPath, nil), nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("Некорректный Last-Event-ID", func(t *testing.T) {
+		client := &stubSubscriptionsClient{stream: &stubEventStream{}}
+
+		request := httptest.NewRequest(http.MethodGet, WatchSubscriptionsPath, nil)
+		request.Header.Set(LastEventIDHeader, "abc")
+		recorder := httptest.NewRecorder()
+		WatchSubscriptionsSSE(newTestMux(), client)(recorder, request, nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Nil(t, client.request)
+	})
+}
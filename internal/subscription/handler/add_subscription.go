@@ -2,14 +2,12 @@ package handler
 
 import (
 	"context"
-	"time"
 
 	"buf.build/go/protovalidate"
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (s *SubscriptionHandler) AddSubscription(ctx context.Context, request *pbSubscription.AddSubscriptionRequest) (*pbSubscription.AddSubscriptionResponse, error) {
@@ -19,58 +17,125 @@ func (s *SubscriptionHandler) AddSubscription(ctx context.Context, request *pbSu
 	err := protovalidate.Validate(request)
 	if err != nil {
 		logger.Error("failed validate request", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 
-	userID, err := uuid.Parse(request.GetUserId())
+	subscription, err := subscriptionFromPb(request, request.BillingIntervalMonths)
 	if err != nil {
-		logger.Error("failed parse user id", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		logger.Error("failed parse subscription", "error", err)
+		return nil, err
+	}
+
+	subscription.Price = int64(request.GetPrice())
+	subscription.Currency = request.GetCurrency()
+	subscription.PriceInMajorUnits = true
+	for _, phase := range request.GetPromoPhases() {
+		subscription.PromoPhases = append(subscription.PromoPhases, model.PromoPhase{
+			Months: phase.GetMonths(),
+			Price:  int64(phase.GetPrice()),
+		})
 	}
 
-	startDate, err := time.Parse("01-2006", request.GetStartDate())
+	resultSubscription, overlaps, err := s.service.AddSubscription(ctx, subscription)
 	if err != nil {
-		logger.Error("failed parse start date", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		logger.Error("failed add subscription", "error", err)
+		return nil, err
+	}
+	if len(overlaps) > 0 {
+		logger.Warn("subscription overlaps with existing subscriptions", "count", len(overlaps))
 	}
+	setETag(ctx, resultSubscription)
 
-	subscription := model.Subscription{
-		UserID:      userID,
-		ServiceName: request.GetServiceName(),
-		Price:       request.GetPrice(),
-		StartDate:   startDate,
+	return &pbSubscription.AddSubscriptionResponse{
+		Subscription: subscriptionToPb(resultSubscription),
+		Overlaps:     subscriptionsToPb(overlaps),
+	}, nil
+}
+
+func (s *SubscriptionHandler) AddSubscriptionV2(ctx context.Context, request *pbSubscription.AddSubscriptionV2Request) (*pbSubscription.AddSubscriptionV2Response, error) {
+	const op = "SubscriptionHandler.AddSubscriptionV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
 	}
 
-	if request.GetEndDate() != "" {
-		endDate, err := time.Parse("01-2006", request.GetEndDate())
-		if err != nil {
-			logger.Error("failed parse end date", "error", err)
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
+	subscription, err := subscriptionFromPb(request, request.BillingIntervalMonths)
+	if err != nil {
+		logger.Error("failed parse subscription", "error", err)
+		return nil, err
+	}
 
-		subscription.EndDate = endDate
+	subscription.Price = request.GetPrice().GetAmountMinor()
+	subscription.Currency = request.GetPrice().GetCurrency()
+	for _, phase := range request.GetPromoPhases() {
+		subscription.PromoPhases = append(subscription.PromoPhases, model.PromoPhase{
+			Months:   phase.GetMonths(),
+			Price:    phase.GetPrice().GetAmountMinor(),
+			Currency: phase.GetPrice().GetCurrency(),
+		})
 	}
 
-	resultSubscription, err := s.service.AddSubscription(ctx, subscription)
+	resultSubscription, overlaps, err := s.service.AddSubscription(ctx, subscription)
 	if err != nil {
 		logger.Error("failed add subscription", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	if len(overlaps) > 0 {
+		logger.Warn("subscription overlaps with existing subscriptions", "count", len(overlaps))
 	}
+	setETag(ctx, resultSubscription)
 
-	response := &pbSubscription.AddSubscriptionResponse{
-		Subscription: &pbSubscription.Subscription{
-			SubscriptionId: resultSubscription.ID.String(),
-			UserId:         resultSubscription.UserID.String(),
-			ServiceName:    resultSubscription.ServiceName,
-			Price:          resultSubscription.Price,
-			StartDate:      resultSubscription.StartDate.Format("01-2006"),
-		},
+	return &pbSubscription.AddSubscriptionV2Response{
+		Subscription: subscriptionToPbV2(resultSubscription),
+		Overlaps:     subscriptionsToPbV2(overlaps),
+	}, nil
+}
+
+// addSubscriptionRequest - поля запросов AddSubscription и AddSubscriptionV2, не связанные с ценой
+type addSubscriptionRequest interface {
+	GetUserId() string
+	GetServiceName() string
+	GetStartDate() string
+	GetEndDate() string
+	GetBillingPeriod() pbSubscription.BillingPeriod
+	GetTrialMonths() int32
+}
+
+// subscriptionFromPb возвращает подписку без цены и льготных фаз: они задаются в разных версиях API по-разному
+func subscriptionFromPb(request addSubscriptionRequest, intervalMonths *int32) (model.Subscription, error) {
+	subscription := model.Subscription{
+		ServiceName: request.GetServiceName(),
+		TrialMonths: request.GetTrialMonths(),
+	}
+
+	userID, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return subscription, apperror.NewValidationError("user_id", err.Error())
 	}
+	subscription.UserID = userID
 
-	if !subscription.EndDate.IsZero() {
-		endDate := subscription.EndDate.Format("01-2006")
-		response.Subscription.EndDate = &endDate
+	startDate, err := model.ParseYearMonth(request.GetStartDate())
+	if err != nil {
+		return subscription, apperror.NewValidationError("start_date", err.Error())
+	}
+	subscription.StartDate = startDate
+
+	billingPeriod, err := billingPeriodFromPb(request.GetBillingPeriod(), intervalMonths)
+	if err != nil {
+		return subscription, apperror.NewValidationError("billing_period", err.Error())
+	}
+	subscription.BillingPeriod = billingPeriod
+
+	if request.GetEndDate() != "" {
+		endDate, err := model.ParseYearMonth(request.GetEndDate())
+		if err != nil {
+			return subscription, apperror.NewValidationError("end_date", err.Error())
+		}
+		subscription.EndDate = endDate
 	}
 
-	return response, nil
+	return subscription, nil
 }
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) ChangeSubscriptionPlan(ctx context.Context, request *pbSubscription.ChangeSubscriptionPlanRequest) (*pbSubscription.ChangeSubscriptionPlanResponse, error) {
+	const op = "SubscriptionHandler.ChangeSubscriptionPlan"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	plan, err := subscriptionPlanFromPb(request)
+	if err != nil {
+		logger.Error("failed parse plan", "error", err)
+		return nil, err
+	}
+	plan.Price = int64(request.GetPrice())
+	plan.PriceInMajorUnits = true
+
+	subscription, err := s.service.ChangeSubscriptionPlan(ctx, plan)
+	if err != nil {
+		logger.Error("failed change subscription plan", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.ChangeSubscriptionPlanResponse{
+		Subscription: subscriptionToPb(subscription),
+	}, nil
+}
+
+func (s *SubscriptionHandler) ChangeSubscriptionPlanV2(ctx context.Context, request *pbSubscription.ChangeSubscriptionPlanV2Request) (*pbSubscription.ChangeSubscriptionPlanV2Response, error) {
+	const op = "SubscriptionHandler.ChangeSubscriptionPlanV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	plan, err := subscriptionPlanFromPb(request)
+	if err != nil {
+		logger.Error("failed parse plan", "error", err)
+		return nil, err
+	}
+	plan.Price = request.GetPrice().GetAmountMinor()
+	plan.Currency = request.GetPrice().GetCurrency()
+
+	subscription, err := s.service.ChangeSubscriptionPlan(ctx, plan)
+	if err != nil {
+		logger.Error("failed change subscription plan", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.ChangeSubscriptionPlanV2Response{
+		Subscription: subscriptionToPbV2(subscription),
+	}, nil
+}
+
+// changePlanRequest - поля запросов ChangeSubscriptionPlan и ChangeSubscriptionPlanV2, не связанные с ценой
+type changePlanRequest interface {
+	GetSubscriptionId() string
+	GetEffectiveDate() string
+	GetServiceName() string
+}
+
+func subscriptionPlanFromPb(request changePlanRequest) (model.SubscriptionPlan, error) {
+	plan := model.SubscriptionPlan{
+		ServiceName: request.GetServiceName(),
+	}
+
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		return plan, apperror.NewValidationError("subscription_id", err.Error())
+	}
+	plan.SubscriptionID = subscriptionID
+
+	effectiveDate, err := model.ParseYearMonth(request.GetEffectiveDate())
+	if err != nil {
+		return plan, apperror.NewValidationError("effective_date", err.Error())
+	}
+	plan.ValidFrom = effectiveDate
+
+	return plan, nil
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) CreateAPIKey(ctx context.Context, request *pbSubscription.CreateAPIKeyRequest) (*pbSubscription.CreateAPIKeyResponse, error) {
+	const op = "SubscriptionHandler.CreateAPIKey"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	key := model.APIKey{
+		Name:   request.GetName(),
+		Scopes: request.GetScopes(),
+	}
+	if request.GetUserId() != "" {
+		key.UserID, err = uuid.Parse(request.GetUserId())
+		if err != nil {
+			logger.Error("failed parse user id", "error", err)
+			return nil, apperror.NewValidationError("user_id", err.Error())
+		}
+	}
+	if request.GetExpiresAt() != "" {
+		key.ExpiresAt, err = time.Parse(time.RFC3339, request.GetExpiresAt())
+		if err != nil {
+			logger.Error("failed parse expires at", "error", err)
+			return nil, apperror.NewValidationError("expires_at", "must be an RFC 3339 timestamp")
+		}
+	}
+
+	created, err := s.apiKeys.CreateAPIKey(ctx, key)
+	if err != nil {
+		logger.Error("failed create api key", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.CreateAPIKeyResponse{
+		ApiKey: apiKeyToPb(created),
+		Key:    created.Key,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+)
+
+func (s *SubscriptionHandler) CreateWebhook(ctx context.Context, request *pbSubscription.CreateWebhookRequest) (*pbSubscription.CreateWebhookResponse, error) {
+	const op = "SubscriptionHandler.CreateWebhook"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	eventTypes := make([]model.EventType, 0, len(request.GetEventTypes()))
+	for _, eventType := range request.GetEventTypes() {
+		eventTypes = append(eventTypes, model.EventType(eventType))
+	}
+
+	webhook, err := s.webhooks.CreateWebhook(ctx, model.Webhook{
+		URL:         request.GetUrl(),
+		Description: request.GetDescription(),
+		EventTypes:  eventTypes,
+	})
+	if err != nil {
+		logger.Error("failed create webhook", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.CreateWebhookResponse{
+		Webhook: webhookToPb(webhook),
+		Secret:  webhook.Secret,
+	}, nil
+}
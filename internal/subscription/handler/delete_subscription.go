@@ -5,9 +5,8 @@ import (
 
 	"buf.build/go/protovalidate"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (s *SubscriptionHandler) DeleteSubscription(ctx context.Context, request *pbSubscription.DeleteSubscriptionRequest) (*pbSubscription.DeleteSubscriptionResponse, error) {
@@ -17,19 +16,25 @@ func (s *SubscriptionHandler) DeleteSubscription(ctx context.Context, request *p
 	err := protovalidate.Validate(request)
 	if err != nil {
 		logger.Error("failed validate request", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 
 	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
 	if err != nil {
 		logger.Error("failed parse subscription id", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
 	}
 
-	err = s.service.DeleteSubscription(ctx, subscriptionID)
+	version, err := expectedVersion(ctx, request.GetEtag())
+	if err != nil {
+		logger.Error("failed parse etag", "error", err)
+		return nil, err
+	}
+
+	err = s.service.DeleteSubscription(ctx, subscriptionID, version)
 	if err != nil {
 		logger.Error("failed delete subscription", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	return &pbSubscription.DeleteSubscriptionResponse{}, nil
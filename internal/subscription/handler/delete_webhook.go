@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) DeleteWebhook(ctx context.Context, request *pbSubscription.DeleteWebhookRequest) (*pbSubscription.DeleteWebhookResponse, error) {
+	const op = "SubscriptionHandler.DeleteWebhook"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	webhookID, err := uuid.Parse(request.GetWebhookId())
+	if err != nil {
+		logger.Error("failed parse webhook id", "error", err)
+		return nil, apperror.NewValidationError("webhook_id", err.Error())
+	}
+
+	err = s.webhooks.DeleteWebhook(ctx, webhookID)
+	if err != nil {
+		logger.Error("failed delete webhook", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.DeleteWebhookResponse{}, nil
+}
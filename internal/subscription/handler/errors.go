@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Geriler/effective-mobile/internal/middleware"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorMappings - соответствие доменных ошибок сервиса подписок gRPC-кодам и причинам (reason) для клиентов
+var ErrorMappings = []middleware.ErrorMapping{
+	{Err: model.ErrSubscriptionNotFound, Code: codes.NotFound, Reason: "SUBSCRIPTION_NOT_FOUND"},
+	{Err: model.ErrSubscriptionAlreadyExists, Code: codes.AlreadyExists, Reason: "SUBSCRIPTION_ALREADY_EXISTS"},
+	{Err: model.ErrExchangeRateNotFound, Code: codes.FailedPrecondition, Reason: "EXCHANGE_RATE_NOT_FOUND"},
+	{Err: model.ErrPlanOutsideSubscription, Code: codes.InvalidArgument, Reason: "PLAN_OUTSIDE_SUBSCRIPTION"},
+	{Err: model.ErrSubscriptionsNotMergeable, Code: codes.InvalidArgument, Reason: "SUBSCRIPTIONS_NOT_MERGEABLE"},
+	{Err: model.ErrInvalidStatusTransition, Code: codes.FailedPrecondition, Reason: "INVALID_STATUS_TRANSITION"},
+	{Err: model.ErrSubscriptionClosed, Code: codes.FailedPrecondition, Reason: "SUBSCRIPTION_CLOSED"},
+	{Err: model.ErrSubscriptionTermsFixed, Code: codes.FailedPrecondition, Reason: "SUBSCRIPTION_TERMS_FIXED"},
+	{Err: model.ErrTotalSumOutOfRange, Code: codes.OutOfRange, Reason: "TOTAL_SUM_OUT_OF_RANGE"},
+	{Err: model.ErrVersionMismatch, Code: codes.Aborted, Reason: "SUBSCRIPTION_VERSION_MISMATCH", HTTPStatus: http.StatusPreconditionFailed},
+	{Err: model.ErrWebhookNotFound, Code: codes.NotFound, Reason: "WEBHOOK_NOT_FOUND"},
+	{Err: model.ErrWebhookDeliveryNotFound, Code: codes.NotFound, Reason: "WEBHOOK_DELIVERY_NOT_FOUND"},
+	{Err: model.ErrAPIKeyNotFound, Code: codes.NotFound, Reason: "API_KEY_NOT_FOUND"},
+	{Err: model.ErrWatchInterrupted, Code: codes.Unavailable, Reason: "WATCH_INTERRUPTED"},
+	{Err: model.ErrWatchResumeExpired, Code: codes.OutOfRange, Reason: "WATCH_RESUME_EXPIRED", HTTPStatus: http.StatusGone},
+	{Err: model.ErrPermissionDenied, Code: codes.PermissionDenied, Reason: "PERMISSION_DENIED"},
+	{Err: model.ErrIdempotencyKeyReused, Code: codes.AlreadyExists, Reason: "IDEMPOTENCY_KEY_REUSED"},
+	{Err: model.ErrIdempotencyKeyInProgress, Code: codes.Aborted, Reason: "IDEMPOTENCY_KEY_IN_PROGRESS"},
+}
+
+// IdempotentMethods - изменяющие методы, которые можно безопасно повторять с ключом идемпотентности
+var IdempotentMethods = []string{
+	pbSubscription.Subscriptions_AddSubscription_FullMethodName,
+	pbSubscription.Subscriptions_UpdateSubscription_FullMethodName,
+	pbSubscription.Subscriptions_DeleteSubscription_FullMethodName,
+	pbSubscription.Subscriptions_UndeleteSubscription_FullMethodName,
+	pbSubscription.Subscriptions_ChangeSubscriptionPlan_FullMethodName,
+	pbSubscription.Subscriptions_MergeSubscriptions_FullMethodName,
+	pbSubscription.Subscriptions_CancelSubscription_FullMethodName,
+	pbSubscription.Subscriptions_PauseSubscription_FullMethodName,
+	pbSubscription.Subscriptions_ResumeSubscription_FullMethodName,
+	pbSubscription.Subscriptions_AddSubscriptionV2_FullMethodName,
+	pbSubscription.Subscriptions_UpdateSubscriptionV2_FullMethodName,
+	pbSubscription.Subscriptions_ChangeSubscriptionPlanV2_FullMethodName,
+}
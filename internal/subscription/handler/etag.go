@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Geriler/effective-mobile/internal/middleware"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// expectedVersion возвращает версию подписки, которую видел клиент: из поля etag запроса или, если оно пустое,
+// из заголовка If-Match (в gRPC - метаданные if-match). 0 - версия не проверяется
+func expectedVersion(ctx context.Context, etag string) (int64, error) {
+	if etag == "" {
+		if values := metadata.ValueFromIncomingContext(ctx, middleware.IfMatchHeader); len(values) > 0 {
+			etag = values[0]
+		}
+	}
+
+	version, err := model.ParseETag(etag)
+	if err != nil {
+		return 0, apperror.NewValidationError("etag", err.Error())
+	}
+
+	return version, nil
+}
+
+// setETag отдает ETag подписки в заголовке ответа ETag (в gRPC - метаданные etag)
+func setETag(ctx context.Context, subscription *model.Subscription) {
+	// ошибка возможна, только если заголовки ответа уже отправлены: ETag при этом остается в теле ответа
+	_ = grpc.SetHeader(ctx, metadata.Pairs(middleware.ETagHeader, subscription.ETag()))
+}
+
+// ReplayHeaders восстанавливает заголовок ETag ответа, повторно выданного по ключу идемпотентности
+func ReplayHeaders(resp interface{}) metadata.MD {
+	var etag string
+	switch resp := resp.(type) {
+	case *pbSubscription.AddSubscriptionResponse:
+		etag = resp.GetSubscription().GetEtag()
+	case *pbSubscription.UpdateSubscriptionResponse:
+		etag = resp.GetSubscription().GetEtag()
+	case *pbSubscription.AddSubscriptionV2Response:
+		etag = resp.GetSubscription().GetEtag()
+	case *pbSubscription.UpdateSubscriptionV2Response:
+		etag = resp.GetSubscription().GetEtag()
+	}
+	if etag == "" {
+		return nil
+	}
+
+	return metadata.Pairs(middleware.ETagHeader, etag)
+}
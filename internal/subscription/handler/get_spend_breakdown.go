@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+)
+
+func (s *SubscriptionHandler) GetSpendBreakdown(ctx context.Context, request *pbSubscription.GetSpendBreakdownRequest) (*pbSubscription.GetSpendBreakdownResponse, error) {
+	const op = "SubscriptionHandler.GetSpendBreakdown"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filters, err := spendFiltersFromPb(request)
+	if err != nil {
+		logger.Error("failed parse filters", "error", err)
+		return nil, err
+	}
+
+	breakdown, err := s.service.GetSpendBreakdown(ctx, filters, breakdownDimensionFromPb(request.GetGroupBy()), int(request.GetLimit()))
+	if err != nil {
+		logger.Error("failed get spend breakdown", "error", err)
+		return nil, err
+	}
+
+	rows := make([]*pbSubscription.SpendBreakdownRow, 0, len(breakdown.Rows))
+	for _, row := range breakdown.Rows {
+		rows = append(rows, &pbSubscription.SpendBreakdownRow{
+			Key:           row.Key,
+			Amount:        model.ToMajorUnits(row.Amount, breakdown.Currency),
+			Share:         row.Share,
+			Subscriptions: row.Subscriptions,
+			Other:         row.Other,
+		})
+	}
+
+	return &pbSubscription.GetSpendBreakdownResponse{
+		Rows:     rows,
+		TotalSum: model.ToMajorUnits(breakdown.Total, breakdown.Currency),
+		Currency: breakdown.Currency,
+		Rates:    exchangeRatesToPb(breakdown.Rates),
+	}, nil
+}
+
+func (s *SubscriptionHandler) GetSpendBreakdownV2(ctx context.Context, request *pbSubscription.GetSpendBreakdownV2Request) (*pbSubscription.GetSpendBreakdownV2Response, error) {
+	const op = "SubscriptionHandler.GetSpendBreakdownV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filters, err := spendFiltersFromPb(request)
+	if err != nil {
+		logger.Error("failed parse filters", "error", err)
+		return nil, err
+	}
+
+	breakdown, err := s.service.GetSpendBreakdown(ctx, filters, breakdownDimensionFromPb(request.GetGroupBy()), int(request.GetLimit()))
+	if err != nil {
+		logger.Error("failed get spend breakdown", "error", err)
+		return nil, err
+	}
+
+	rows := make([]*pbSubscription.SpendBreakdownRowV2, 0, len(breakdown.Rows))
+	for _, row := range breakdown.Rows {
+		rows = append(rows, &pbSubscription.SpendBreakdownRowV2{
+			Key:           row.Key,
+			Amount:        moneyToPb(row.Amount, breakdown.Currency),
+			Share:         row.Share,
+			Subscriptions: row.Subscriptions,
+			Other:         row.Other,
+		})
+	}
+
+	return &pbSubscription.GetSpendBreakdownV2Response{
+		Rows:  rows,
+		Total: moneyToPb(breakdown.Total, breakdown.Currency),
+		Rates: exchangeRatesToPb(breakdown.Rates),
+	}, nil
+}
+
+func breakdownDimensionFromPb(dimension pbSubscription.BreakdownDimension) model.BreakdownDimension {
+	if dimension == pbSubscription.BreakdownDimension_BREAKDOWN_DIMENSION_USER_ID {
+		return model.BreakdownByUserID
+	}
+
+	return model.BreakdownByServiceName
+}
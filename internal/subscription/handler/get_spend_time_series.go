@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+)
+
+func (s *SubscriptionHandler) GetSpendTimeSeries(ctx context.Context, request *pbSubscription.GetSpendTimeSeriesRequest) (*pbSubscription.GetSpendTimeSeriesResponse, error) {
+	const op = "SubscriptionHandler.GetSpendTimeSeries"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filters, err := spendFiltersFromPb(request)
+	if err != nil {
+		logger.Error("failed parse filters", "error", err)
+		return nil, err
+	}
+
+	series, err := s.service.GetSpendTimeSeries(ctx, filters, granularityFromPb(request.GetGranularity()))
+	if err != nil {
+		logger.Error("failed get spend time series", "error", err)
+		return nil, err
+	}
+
+	buckets := make([]*pbSubscription.SpendBucket, 0, len(series.Buckets))
+	for _, bucket := range series.Buckets {
+		buckets = append(buckets, &pbSubscription.SpendBucket{
+			StartDate:           bucket.StartDate.String(),
+			EndDate:             bucket.EndDate.String(),
+			Amount:              model.ToMajorUnits(bucket.Amount, series.Currency),
+			ActiveSubscriptions: bucket.ActiveSubscriptions,
+		})
+	}
+
+	return &pbSubscription.GetSpendTimeSeriesResponse{
+		Buckets:  buckets,
+		TotalSum: model.ToMajorUnits(series.Total, series.Currency),
+		Currency: series.Currency,
+		Rates:    exchangeRatesToPb(series.Rates),
+	}, nil
+}
+
+func (s *SubscriptionHandler) GetSpendTimeSeriesV2(ctx context.Context, request *pbSubscription.GetSpendTimeSeriesV2Request) (*pbSubscription.GetSpendTimeSeriesV2Response, error) {
+	const op = "SubscriptionHandler.GetSpendTimeSeriesV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filters, err := spendFiltersFromPb(request)
+	if err != nil {
+		logger.Error("failed parse filters", "error", err)
+		return nil, err
+	}
+
+	series, err := s.service.GetSpendTimeSeries(ctx, filters, granularityFromPb(request.GetGranularity()))
+	if err != nil {
+		logger.Error("failed get spend time series", "error", err)
+		return nil, err
+	}
+
+	buckets := make([]*pbSubscription.SpendBucketV2, 0, len(series.Buckets))
+	for _, bucket := range series.Buckets {
+		buckets = append(buckets, &pbSubscription.SpendBucketV2{
+			StartDate:           bucket.StartDate.String(),
+			EndDate:             bucket.EndDate.String(),
+			Amount:              moneyToPb(bucket.Amount, series.Currency),
+			ActiveSubscriptions: bucket.ActiveSubscriptions,
+		})
+	}
+
+	return &pbSubscription.GetSpendTimeSeriesV2Response{
+		Buckets: buckets,
+		Total:   moneyToPb(series.Total, series.Currency),
+		Rates:   exchangeRatesToPb(series.Rates),
+	}, nil
+}
+
+func granularityFromPb(granularity pbSubscription.Granularity) model.Granularity {
+	switch granularity {
+	case pbSubscription.Granularity_GRANULARITY_QUARTER:
+		return model.GranularityQuarter
+	case pbSubscription.Granularity_GRANULARITY_YEAR:
+		return model.GranularityYear
+	default:
+		return model.GranularityMonth
+	}
+}
@@ -2,51 +2,65 @@ package handler
 
 import (
 	"context"
-	"errors"
 
-	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"buf.build/go/protovalidate"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (s *SubscriptionHandler) GetSubscription(ctx context.Context, request *pbSubscription.GetSubscriptionRequest) (*pbSubscription.GetSubscriptionResponse, error) {
 	const op = "SubscriptionHandler.GetSubscription"
 	logger := s.logger.With("op", op).With("request", request)
 
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
 	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
 	if err != nil {
 		logger.Error("failed to parse subscription id", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
 	}
 
 	subscription, err := s.service.GetSubscription(ctx, subscriptionID)
 	if err != nil {
-		if errors.Is(err, model.ErrSubscriptionNotFound) {
-			return &pbSubscription.GetSubscriptionResponse{
-				Subscription: nil,
-			}, status.Error(codes.NotFound, err.Error())
-		}
-
 		logger.Error("failed to get subscription", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
+	setETag(ctx, subscription)
 
-	response := &pbSubscription.GetSubscriptionResponse{
-		Subscription: &pbSubscription.Subscription{
-			SubscriptionId: subscription.ID.String(),
-			UserId:         subscription.UserID.String(),
-			ServiceName:    subscription.ServiceName,
-			Price:          subscription.Price,
-			StartDate:      subscription.StartDate.Format("01-2006"),
-		},
+	return &pbSubscription.GetSubscriptionResponse{
+		Subscription: subscriptionToPb(subscription),
+	}, nil
+}
+
+func (s *SubscriptionHandler) GetSubscriptionV2(ctx context.Context, request *pbSubscription.GetSubscriptionV2Request) (*pbSubscription.GetSubscriptionV2Response, error) {
+	const op = "SubscriptionHandler.GetSubscriptionV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
 	}
 
-	if !subscription.EndDate.IsZero() {
-		endDate := subscription.EndDate.Format("01-2006")
-		response.Subscription.EndDate = &endDate
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		logger.Error("failed to parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	subscription, err := s.service.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		logger.Error("failed to get subscription", "error", err)
+		return nil, err
 	}
+	setETag(ctx, subscription)
 
-	return response, nil
+	return &pbSubscription.GetSubscriptionV2Response{
+		Subscription: subscriptionToPbV2(subscription),
+	}, nil
 }
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) GetSubscriptionPlans(ctx context.Context, request *pbSubscription.GetSubscriptionPlansRequest) (*pbSubscription.GetSubscriptionPlansResponse, error) {
+	const op = "SubscriptionHandler.GetSubscriptionPlans"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		logger.Error("failed parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	plans, err := s.service.ListSubscriptionPlans(ctx, subscriptionID)
+	if err != nil {
+		logger.Error("failed list subscription plans", "error", err)
+		return nil, err
+	}
+
+	plansResponse := make([]*pbSubscription.SubscriptionPlan, 0, len(plans))
+	for _, plan := range plans {
+		plansResponse = append(plansResponse, subscriptionPlanToPb(plan))
+	}
+
+	return &pbSubscription.GetSubscriptionPlansResponse{
+		Plans: plansResponse,
+	}, nil
+}
+
+func (s *SubscriptionHandler) GetSubscriptionPlansV2(ctx context.Context, request *pbSubscription.GetSubscriptionPlansV2Request) (*pbSubscription.GetSubscriptionPlansV2Response, error) {
+	const op = "SubscriptionHandler.GetSubscriptionPlansV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		logger.Error("failed parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	plans, err := s.service.ListSubscriptionPlans(ctx, subscriptionID)
+	if err != nil {
+		logger.Error("failed list subscription plans", "error", err)
+		return nil, err
+	}
+
+	plansResponse := make([]*pbSubscription.SubscriptionPlanV2, 0, len(plans))
+	for _, plan := range plans {
+		plansResponse = append(plansResponse, subscriptionPlanToPbV2(plan))
+	}
+
+	return &pbSubscription.GetSubscriptionPlansV2Response{
+		Plans: plansResponse,
+	}, nil
+}
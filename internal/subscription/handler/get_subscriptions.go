@@ -2,62 +2,271 @@ package handler
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
 
+	"buf.build/go/protovalidate"
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
 )
 
 func (s *SubscriptionHandler) GetSubscriptions(ctx context.Context, request *pbSubscription.GetSubscriptionsRequest) (*pbSubscription.GetSubscriptionsResponse, error) {
 	const op = "SubscriptionHandler.GetSubscriptions"
 	logger := s.logger.With("op", op).With("request", request)
 
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filter, err := subscriptionFilterFromPb(request)
+	if err != nil {
+		logger.Error("failed parse filter", "error", err)
+		return nil, err
+	}
+	if request.MinPrice != nil || request.MaxPrice != nil {
+		filter.MinPrice = int32PtrToInt64(request.MinPrice)
+		filter.MaxPrice = int32PtrToInt64(request.MaxPrice)
+		filter.PriceInMajorUnits = true
+	}
+
+	result, nextPageToken, err := s.listSubscriptions(ctx, request, filter)
+	if err != nil {
+		logger.Error("failed to list subscriptions", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.GetSubscriptionsResponse{
+		Subscriptions: subscriptionsToPb(result.Subscriptions),
+		NextPageToken: nextPageToken,
+		TotalSize:     result.TotalSize,
+	}, nil
+}
+
+func (s *SubscriptionHandler) GetSubscriptionsV2(ctx context.Context, request *pbSubscription.GetSubscriptionsV2Request) (*pbSubscription.GetSubscriptionsV2Response, error) {
+	const op = "SubscriptionHandler.GetSubscriptionsV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filter, err := subscriptionFilterFromPb(request)
+	if err != nil {
+		logger.Error("failed parse filter", "error", err)
+		return nil, err
+	}
+	if minPrice := request.GetMinPrice(); minPrice != nil {
+		filter.MinPrice = &minPrice.AmountMinor
+		filter.PriceCurrency = minPrice.GetCurrency()
+	}
+	if maxPrice := request.GetMaxPrice(); maxPrice != nil {
+		filter.MaxPrice = &maxPrice.AmountMinor
+		filter.PriceCurrency = maxPrice.GetCurrency()
+	}
+
+	result, nextPageToken, err := s.listSubscriptions(ctx, request, filter)
+	if err != nil {
+		logger.Error("failed to list subscriptions", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.GetSubscriptionsV2Response{
+		Subscriptions: subscriptionsToPbV2(result.Subscriptions),
+		NextPageToken: nextPageToken,
+		TotalSize:     result.TotalSize,
+	}, nil
+}
+
+// subscriptionsRequest - поля запросов GetSubscriptions и GetSubscriptionsV2, не связанные с ценой
+type subscriptionsRequest interface {
+	GetCount() int32
+	GetPage() int32
+	GetPageSize() int32
+	GetPageToken() string
+	GetIncludeTotalSize() bool
+	GetOrderBy() string
+	GetUserId() string
+	GetServiceName() string
+	GetServiceNameMatch() pbSubscription.ServiceNameMatch
+	GetActiveAt() string
+	GetOpenEndedOnly() bool
+	GetDeleted() pbSubscription.DeletedFilter
+}
+
+// listSubscriptions возвращает страницу подписок по фильтру и токен следующей страницы
+func (s *SubscriptionHandler) listSubscriptions(ctx context.Context, request subscriptionsRequest, filter model.SubscriptionFilter) (*model.SubscriptionPage, string, error) {
 	var count int32 = 10
-	if request.GetCount() != 0 {
-		count = *request.Count
+	switch {
+	case request.GetPageSize() != 0:
+		count = request.GetPageSize()
+	case request.GetCount() != 0:
+		count = request.GetCount()
 	}
 
 	var page int32 = 0
 	if request.GetPage() != 0 {
-		page = *request.Page - 1
+		page = request.GetPage() - 1
 	}
 
-	subscriptions, err := s.service.ListSubscriptions(ctx, model.Pagination{
-		Page:  page,
-		Count: count,
-	})
+	query := model.SubscriptionQuery{
+		Filter: filter,
+		Order:  subscriptionOrderFromPb(request.GetOrderBy()),
+		Pagination: model.Pagination{
+			Page:  page,
+			Count: count,
+		},
+		WithTotalSize: request.GetIncludeTotalSize(),
+	}
+
+	if request.GetPageToken() != "" {
+		after, err := s.decodePageToken(request.GetPageToken(), query)
+		if err != nil {
+			return nil, "", err
+		}
+		query.After = after
+	}
+
+	result, err := s.service.ListSubscriptions(ctx, query)
 	if err != nil {
-		if errors.Is(err, model.ErrSubscriptionNotFound) {
-			return &pbSubscription.GetSubscriptionsResponse{
-				Subscriptions: []*pbSubscription.Subscription{},
-			}, nil
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if result.Next != nil {
+		nextPageToken, err = s.encodePageToken(*result.Next, query)
+		if err != nil {
+			return nil, "", err
 		}
+	}
 
-		logger.Error("failed to list subscriptions", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+	return result, nextPageToken, nil
+}
+
+// subscriptionsPageToken - содержимое page_token: позиция последней выданной подписки и отпечаток условий выборки.
+// Токен нельзя использовать с другим фильтром или сортировкой
+type subscriptionsPageToken struct {
+	Query string                   `json:"q"`
+	After model.SubscriptionCursor `json:"after"`
+}
+
+func (s *SubscriptionHandler) encodePageToken(after model.SubscriptionCursor, query model.SubscriptionQuery) (string, error) {
+	fingerprint, err := queryFingerprint(query)
+	if err != nil {
+		return "", err
+	}
+
+	return s.pageTokens.Encode(subscriptionsPageToken{
+		Query: fingerprint,
+		After: after,
+	})
+}
+
+func (s *SubscriptionHandler) decodePageToken(token string, query model.SubscriptionQuery) (*model.SubscriptionCursor, error) {
+	var pageToken subscriptionsPageToken
+	if err := s.pageTokens.Decode(token, &pageToken); err != nil {
+		return nil, apperror.NewValidationError("page_token", err.Error())
+	}
+
+	fingerprint, err := queryFingerprint(query)
+	if err != nil {
+		return nil, err
+	}
+	if pageToken.Query != fingerprint {
+		return nil, apperror.NewValidationError("page_token", "page token does not match filter or order_by")
+	}
+
+	return &pageToken.After, nil
+}
+
+func queryFingerprint(query model.SubscriptionQuery) (string, error) {
+	data, err := json.Marshal(struct {
+		Filter model.SubscriptionFilter `json:"filter"`
+		Order  model.SubscriptionOrder  `json:"order"`
+	}{query.Filter, query.Order})
+	if err != nil {
+		return "", err
 	}
 
-	subscriptionsResponse := make([]*pbSubscription.Subscription, 0, len(subscriptions))
-	for _, subscription := range subscriptions {
-		subscriptionResponse := pbSubscription.Subscription{
-			SubscriptionId: subscription.ID.String(),
-			UserId:         subscription.UserID.String(),
-			ServiceName:    subscription.ServiceName,
-			Price:          subscription.Price,
-			StartDate:      subscription.StartDate.Format("01-2006"),
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+// subscriptionFilterFromPb возвращает фильтр без границ цены: они задаются в разных версиях API по-разному
+func subscriptionFilterFromPb(request subscriptionsRequest) (model.SubscriptionFilter, error) {
+	filter := model.SubscriptionFilter{
+		ServiceName:      request.GetServiceName(),
+		ServiceNameMatch: serviceNameMatchFromPb(request.GetServiceNameMatch()),
+		OpenEndedOnly:    request.GetOpenEndedOnly(),
+		Deleted:          deletedFilterFromPb(request.GetDeleted()),
+	}
+
+	if request.GetUserId() != "" {
+		userID, err := uuid.Parse(request.GetUserId())
+		if err != nil {
+			return filter, apperror.NewValidationError("user_id", err.Error())
 		}
+		filter.UserID = userID
+	}
 
-		if !subscription.EndDate.IsZero() {
-			endDate := subscription.EndDate.Format("01-2006")
-			subscriptionResponse.EndDate = &endDate
+	if request.GetActiveAt() != "" {
+		activeAt, err := model.ParseYearMonth(request.GetActiveAt())
+		if err != nil {
+			return filter, apperror.NewValidationError("active_at", err.Error())
 		}
+		filter.ActiveAt = activeAt
+	}
 
-		subscriptionsResponse = append(subscriptionsResponse, &subscriptionResponse)
+	return filter, nil
+}
+
+func int32PtrToInt64(value *int32) *int64 {
+	if value == nil {
+		return nil
 	}
+	converted := int64(*value)
+	return &converted
+}
 
-	return &pbSubscription.GetSubscriptionsResponse{
-		Subscriptions: subscriptionsResponse,
-	}, nil
+func serviceNameMatchFromPb(match pbSubscription.ServiceNameMatch) model.ServiceNameMatch {
+	switch match {
+	case pbSubscription.ServiceNameMatch_SERVICE_NAME_MATCH_PREFIX:
+		return model.ServiceNameMatchPrefix
+	case pbSubscription.ServiceNameMatch_SERVICE_NAME_MATCH_SIMILAR:
+		return model.ServiceNameMatchSimilar
+	default:
+		return model.ServiceNameMatchExact
+	}
+}
+
+func deletedFilterFromPb(deleted pbSubscription.DeletedFilter) model.DeletedFilter {
+	switch deleted {
+	case pbSubscription.DeletedFilter_DELETED_FILTER_INCLUDE:
+		return model.DeletedFilterInclude
+	case pbSubscription.DeletedFilter_DELETED_FILTER_ONLY:
+		return model.DeletedFilterOnly
+	default:
+		return model.DeletedFilterExclude
+	}
+}
+
+// subscriptionOrderFromPb разбирает order_by вида "price desc", формат проверен protovalidate
+func subscriptionOrderFromPb(orderBy string) model.SubscriptionOrder {
+	if orderBy == "" {
+		return model.SubscriptionOrder{By: model.SubscriptionOrderByStartDate}
+	}
+
+	field, direction, _ := strings.Cut(orderBy, " ")
+
+	return model.SubscriptionOrder{
+		By:   model.SubscriptionOrderBy(field),
+		Desc: direction == "desc",
+	}
 }
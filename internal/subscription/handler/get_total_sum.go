@@ -2,14 +2,12 @@ package handler
 
 import (
 	"context"
-	"time"
 
 	"buf.build/go/protovalidate"
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (s *SubscriptionHandler) GetSumSubscriptions(ctx context.Context, request *pbSubscription.GetSumSubscriptionsRequest) (*pbSubscription.GetSumSubscriptionsResponse, error) {
@@ -19,42 +17,96 @@ func (s *SubscriptionHandler) GetSumSubscriptions(ctx context.Context, request *
 	err := protovalidate.Validate(request)
 	if err != nil {
 		logger.Error("failed validate request", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 
-	startDate, err := time.Parse("01-2006", request.GetStartDate())
+	filters, err := spendFiltersFromPb(request)
 	if err != nil {
-		logger.Error("failed parse start date", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		logger.Error("failed parse filters", "error", err)
+		return nil, err
 	}
 
-	endDate, err := time.Parse("01-2006", request.GetEndDate())
+	sum, err := s.service.GetTotalSum(ctx, filters)
 	if err != nil {
-		logger.Error("failed parse start date", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		logger.Error("failed get total sum", "error", err)
+		return nil, err
 	}
 
-	var userID uuid.UUID
-	if request.GetUserId() != "" {
-		userID, err = uuid.Parse(request.GetUserId())
-		if err != nil {
-			logger.Error("failed parse user id", "error", err)
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
+	totalSum, err := totalSumToPb(sum.Sum, sum.Currency)
+	if err != nil {
+		logger.Error("failed convert total sum", "error", err, "sum", sum.Sum)
+		return nil, err
 	}
 
-	sum, err := s.service.GetTotalSum(ctx, model.Filters{
-		StartDate:   startDate,
-		EndDate:     endDate,
-		UserID:      userID,
-		ServiceName: request.GetServiceName(),
-	})
+	return &pbSubscription.GetSumSubscriptionsResponse{
+		TotalSum: totalSum,
+		Currency: sum.Currency,
+		Rates:    exchangeRatesToPb(sum.Rates),
+	}, nil
+}
+
+func (s *SubscriptionHandler) GetSumSubscriptionsV2(ctx context.Context, request *pbSubscription.GetSumSubscriptionsV2Request) (*pbSubscription.GetSumSubscriptionsV2Response, error) {
+	const op = "SubscriptionHandler.GetSumSubscriptionsV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filters, err := spendFiltersFromPb(request)
+	if err != nil {
+		logger.Error("failed parse filters", "error", err)
+		return nil, err
+	}
+
+	sum, err := s.service.GetTotalSum(ctx, filters)
 	if err != nil {
 		logger.Error("failed get total sum", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
-	return &pbSubscription.GetSumSubscriptionsResponse{
-		TotalSum: sum,
+	return &pbSubscription.GetSumSubscriptionsV2Response{
+		Total: moneyToPb(sum.Sum, sum.Currency),
+		Rates: exchangeRatesToPb(sum.Rates),
 	}, nil
 }
+
+// spendRequest - общие фильтры запросов аналитики расходов
+type spendRequest interface {
+	GetStartDate() string
+	GetEndDate() string
+	GetUserId() string
+	GetServiceName() string
+	GetCurrency() string
+}
+
+func spendFiltersFromPb(request spendRequest) (model.Filters, error) {
+	filters := model.Filters{
+		ServiceName: request.GetServiceName(),
+		Currency:    request.GetCurrency(),
+	}
+
+	startDate, err := model.ParseYearMonth(request.GetStartDate())
+	if err != nil {
+		return filters, apperror.NewValidationError("start_date", err.Error())
+	}
+	filters.StartDate = startDate
+
+	endDate, err := model.ParseYearMonth(request.GetEndDate())
+	if err != nil {
+		return filters, apperror.NewValidationError("end_date", err.Error())
+	}
+	filters.EndDate = endDate
+
+	if request.GetUserId() != "" {
+		userID, err := uuid.Parse(request.GetUserId())
+		if err != nil {
+			return filters, apperror.NewValidationError("user_id", err.Error())
+		}
+		filters.UserID = userID
+	}
+
+	return filters, nil
+}
@@ -6,27 +6,69 @@ import (
 
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/pagetoken"
 	"github.com/google/uuid"
 )
 
 type SubscriptionService interface {
-	AddSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error)
+	AddSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, []model.Subscription, error)
 	GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
-	ListSubscriptions(ctx context.Context, pagination model.Pagination) ([]model.Subscription, error)
-	UpdateSubscription(ctx context.Context, id uuid.UUID, subscription model.Subscription) (*model.Subscription, error)
-	DeleteSubscription(ctx context.Context, id uuid.UUID) error
-	GetTotalSum(ctx context.Context, filters model.Filters) (int32, error)
+	ListSubscriptions(ctx context.Context, query model.SubscriptionQuery) (*model.SubscriptionPage, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, update model.SubscriptionUpdate) (*model.Subscription, []model.Subscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID, expectedVersion int64) error
+	UndeleteSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	ChangeSubscriptionPlan(ctx context.Context, plan model.SubscriptionPlan) (*model.Subscription, error)
+	ListSubscriptionPlans(ctx context.Context, id uuid.UUID) ([]model.SubscriptionPlan, error)
+	GetTotalSum(ctx context.Context, filters model.Filters) (*model.TotalSum, error)
+	GetSpendTimeSeries(ctx context.Context, filters model.Filters, granularity model.Granularity) (*model.SpendTimeSeries, error)
+	GetSpendBreakdown(ctx context.Context, filters model.Filters, groupBy model.BreakdownDimension, limit int) (*model.SpendBreakdown, error)
+	ListSubscriptionOverlaps(ctx context.Context, filter model.OverlapFilter) ([]model.SubscriptionOverlap, error)
+	MergeSubscriptions(ctx context.Context, id uuid.UUID, mergedIDs []uuid.UUID) (*model.Subscription, error)
+	CancelSubscription(ctx context.Context, id uuid.UUID, effective model.YearMonth) (*model.Subscription, error)
+	PauseSubscription(ctx context.Context, id uuid.UUID, effective model.YearMonth) (*model.Subscription, error)
+	ResumeSubscription(ctx context.Context, id uuid.UUID, effective model.YearMonth) (*model.Subscription, error)
+	ListEndingTrials(ctx context.Context, withinDays int, userID uuid.UUID) ([]model.EndingTrial, error)
+	ListSubscriptionHistory(ctx context.Context, id uuid.UUID) ([]model.AuditRecord, error)
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	RotateWebhookSecret(ctx context.Context, id uuid.UUID, revokePrevious bool) (*model.Webhook, error)
+	ListWebhookDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]model.WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (*model.WebhookDelivery, error)
+}
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) (*model.APIKey, error)
+}
+
+// SubscriptionWatcher отправляет в send изменения подписок, пока клиент не отключится. ready вызывается, когда
+// наблюдение началось
+type SubscriptionWatcher interface {
+	Watch(ctx context.Context, userID uuid.UUID, afterEventID int64, ready func() error, send func(model.Event) error) error
 }
 
 type SubscriptionHandler struct {
 	pbSubscription.UnimplementedSubscriptionsServer
-	logger  *slog.Logger
-	service SubscriptionService
+	logger     *slog.Logger
+	service    SubscriptionService
+	webhooks   WebhookService
+	apiKeys    APIKeyService
+	watcher    SubscriptionWatcher
+	pageTokens *pagetoken.Signer
 }
 
-func NewSubscriptionHandler(logger *slog.Logger, subscriptionService SubscriptionService) *SubscriptionHandler {
+func NewSubscriptionHandler(logger *slog.Logger, subscriptionService SubscriptionService, webhookService WebhookService, apiKeyService APIKeyService, watcher SubscriptionWatcher, pageTokens *pagetoken.Signer) *SubscriptionHandler {
 	return &SubscriptionHandler{
-		logger:  logger,
-		service: subscriptionService,
+		logger:     logger,
+		service:    subscriptionService,
+		webhooks:   webhookService,
+		apiKeys:    apiKeyService,
+		watcher:    watcher,
+		pageTokens: pageTokens,
 	}
 }
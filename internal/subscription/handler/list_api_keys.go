@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+)
+
+func (s *SubscriptionHandler) ListAPIKeys(ctx context.Context, _ *pbSubscription.ListAPIKeysRequest) (*pbSubscription.ListAPIKeysResponse, error) {
+	const op = "SubscriptionHandler.ListAPIKeys"
+	logger := s.logger.With("op", op)
+
+	keys, err := s.apiKeys.ListAPIKeys(ctx)
+	if err != nil {
+		logger.Error("failed list api keys", "error", err)
+		return nil, err
+	}
+
+	response := make([]*pbSubscription.APIKey, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, apiKeyToPb(&key))
+	}
+
+	return &pbSubscription.ListAPIKeysResponse{
+		ApiKeys: response,
+	}, nil
+}
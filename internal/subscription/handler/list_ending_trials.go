@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+// defaultTrialWindowDays - за сколько дней до окончания пробного периода подписка попадает в выборку по умолчанию
+const defaultTrialWindowDays = 7
+
+func (s *SubscriptionHandler) ListEndingTrials(ctx context.Context, request *pbSubscription.ListEndingTrialsRequest) (*pbSubscription.ListEndingTrialsResponse, error) {
+	const op = "SubscriptionHandler.ListEndingTrials"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	withinDays := defaultTrialWindowDays
+	if request.WithinDays != nil {
+		withinDays = int(request.GetWithinDays())
+	}
+
+	var userID uuid.UUID
+	if request.UserId != nil {
+		userID, err = uuid.Parse(request.GetUserId())
+		if err != nil {
+			logger.Error("failed parse user id", "error", err)
+			return nil, apperror.NewValidationError("user_id", err.Error())
+		}
+	}
+
+	trials, err := s.service.ListEndingTrials(ctx, withinDays, userID)
+	if err != nil {
+		logger.Error("failed list ending trials", "error", err)
+		return nil, err
+	}
+
+	trialsResponse := make([]*pbSubscription.EndingTrial, 0, len(trials))
+	for _, trial := range trials {
+		trialsResponse = append(trialsResponse, &pbSubscription.EndingTrial{
+			Subscription: subscriptionToPb(&trial.Subscription),
+			TrialEndsOn:  trial.EndsOn.Format(time.DateOnly),
+			DaysLeft:     trial.DaysLeft,
+		})
+	}
+
+	return &pbSubscription.ListEndingTrialsResponse{
+		Trials: trialsResponse,
+	}, nil
+}
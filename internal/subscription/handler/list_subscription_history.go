@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) ListSubscriptionHistory(ctx context.Context, request *pbSubscription.ListSubscriptionHistoryRequest) (*pbSubscription.ListSubscriptionHistoryResponse, error) {
+	const op = "SubscriptionHandler.ListSubscriptionHistory"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		logger.Error("failed parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	records, err := s.service.ListSubscriptionHistory(ctx, subscriptionID)
+	if err != nil {
+		logger.Error("failed list subscription history", "error", err)
+		return nil, err
+	}
+
+	entries := make([]*pbSubscription.SubscriptionHistoryEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, auditRecordToPb(record))
+	}
+
+	return &pbSubscription.ListSubscriptionHistoryResponse{
+		Entries: entries,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) ListSubscriptionOverlaps(ctx context.Context, request *pbSubscription.ListSubscriptionOverlapsRequest) (*pbSubscription.ListSubscriptionOverlapsResponse, error) {
+	const op = "SubscriptionHandler.ListSubscriptionOverlaps"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	filter := model.OverlapFilter{
+		ServiceName: request.GetServiceName(),
+	}
+
+	if request.UserId != nil {
+		filter.UserID, err = uuid.Parse(request.GetUserId())
+		if err != nil {
+			logger.Error("failed parse user id", "error", err)
+			return nil, apperror.NewValidationError("user_id", err.Error())
+		}
+	}
+
+	overlaps, err := s.service.ListSubscriptionOverlaps(ctx, filter)
+	if err != nil {
+		logger.Error("failed list subscription overlaps", "error", err)
+		return nil, err
+	}
+
+	overlapsResponse := make([]*pbSubscription.SubscriptionOverlap, 0, len(overlaps))
+	for _, overlap := range overlaps {
+		overlapResponse := &pbSubscription.SubscriptionOverlap{
+			Subscription: subscriptionToPb(&overlap.Subscription),
+			Overlapping:  subscriptionToPb(&overlap.Overlapping),
+			OverlapStart: overlap.Start.String(),
+		}
+
+		if !overlap.End.IsZero() {
+			overlapEnd := overlap.End.String()
+			overlapResponse.OverlapEnd = &overlapEnd
+		}
+
+		overlapsResponse = append(overlapsResponse, overlapResponse)
+	}
+
+	return &pbSubscription.ListSubscriptionOverlapsResponse{
+		Overlaps: overlapsResponse,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+const defaultWebhookDeliveriesPageSize = 50
+
+func (s *SubscriptionHandler) ListWebhookDeliveries(ctx context.Context, request *pbSubscription.ListWebhookDeliveriesRequest) (*pbSubscription.ListWebhookDeliveriesResponse, error) {
+	const op = "SubscriptionHandler.ListWebhookDeliveries"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	webhookID, err := uuid.Parse(request.GetWebhookId())
+	if err != nil {
+		logger.Error("failed parse webhook id", "error", err)
+		return nil, apperror.NewValidationError("webhook_id", err.Error())
+	}
+
+	filter := model.WebhookDeliveryFilter{
+		WebhookID: webhookID,
+		Status:    webhookDeliveryStatusFromPb(request.GetStatus()),
+		PageSize:  defaultWebhookDeliveriesPageSize,
+	}
+	if request.PageSize != nil {
+		filter.PageSize = int(request.GetPageSize())
+	}
+
+	deliveries, err := s.webhooks.ListWebhookDeliveries(ctx, filter)
+	if err != nil {
+		logger.Error("failed list webhook deliveries", "error", err)
+		return nil, err
+	}
+
+	response := make([]*pbSubscription.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, webhookDeliveryToPb(&delivery))
+	}
+
+	return &pbSubscription.ListWebhookDeliveriesResponse{
+		Deliveries: response,
+	}, nil
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+)
+
+func (s *SubscriptionHandler) ListWebhooks(ctx context.Context, _ *pbSubscription.ListWebhooksRequest) (*pbSubscription.ListWebhooksResponse, error) {
+	const op = "SubscriptionHandler.ListWebhooks"
+	logger := s.logger.With("op", op)
+
+	webhooks, err := s.webhooks.ListWebhooks(ctx)
+	if err != nil {
+		logger.Error("failed list webhooks", "error", err)
+		return nil, err
+	}
+
+	response := make([]*pbSubscription.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		response = append(response, webhookToPb(&webhook))
+	}
+
+	return &pbSubscription.ListWebhooksResponse{
+		Webhooks: response,
+	}, nil
+}
@@ -0,0 +1,441 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/google/uuid"
+)
+
+func subscriptionToPb(subscription *model.Subscription) *pbSubscription.Subscription {
+	response := &pbSubscription.Subscription{
+		SubscriptionId: subscription.ID.String(),
+		UserId:         subscription.UserID.String(),
+		ServiceName:    subscription.ServiceName,
+		Price:          priceToPb(subscription.Price, subscription.Currency),
+		Currency:       subscription.Currency,
+		StartDate:      subscription.StartDate.String(),
+		Status:         subscriptionStatusToPb(subscription.Status),
+		Version:        subscription.Version,
+		Etag:           subscription.ETag(),
+	}
+
+	response.BillingPeriod, response.BillingIntervalMonths = billingPeriodToPb(subscription.BillingPeriod)
+
+	if !subscription.EndDate.IsZero() {
+		endDate := subscription.EndDate.String()
+		response.EndDate = &endDate
+	}
+
+	if !subscription.TrialEndDate.IsZero() {
+		trialEndDate := subscription.TrialEndDate.String()
+		response.TrialEndDate = &trialEndDate
+	}
+
+	if !subscription.DeletedAt.IsZero() {
+		deletedAt := subscription.DeletedAt.UTC().Format(time.RFC3339)
+		response.DeletedAt = &deletedAt
+	}
+
+	return response
+}
+
+func subscriptionsToPb(subscriptions []model.Subscription) []*pbSubscription.Subscription {
+	response := make([]*pbSubscription.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, subscriptionToPb(&subscription))
+	}
+
+	return response
+}
+
+func subscriptionPlanToPb(plan model.SubscriptionPlan) *pbSubscription.SubscriptionPlan {
+	response := &pbSubscription.SubscriptionPlan{
+		ValidFrom:   plan.ValidFrom.String(),
+		ServiceName: plan.ServiceName,
+		Price:       priceToPb(plan.Price, plan.Currency),
+		Phase:       planPhaseToPb(plan.Phase),
+	}
+
+	if !plan.ValidTo.IsZero() {
+		validTo := plan.ValidTo.String()
+		response.ValidTo = &validTo
+	}
+
+	return response
+}
+
+// priceToPb возвращает цену в целых единицах валюты для API v1. Сервис не принимает цены больше
+// model.MaxPrice, поэтому цена помещается в int32
+func priceToPb(amount int64, currency string) int32 {
+	return int32(model.ToMajorUnits(amount, currency))
+}
+
+// totalSumToPb возвращает сумму в целых единицах валюты для API v1. Сумма, которая не помещается в int32,
+// доступна только в API v2
+func totalSumToPb(amount int64, currency string) (int32, error) {
+	sum := model.ToMajorUnits(amount, currency)
+	if sum > math.MaxInt32 {
+		return 0, model.ErrTotalSumOutOfRange
+	}
+
+	return int32(sum), nil
+}
+
+func moneyToPb(amount int64, currency string) *pbSubscription.Money {
+	return &pbSubscription.Money{
+		Currency:    currency,
+		AmountMinor: amount,
+	}
+}
+
+func subscriptionToPbV2(subscription *model.Subscription) *pbSubscription.SubscriptionV2 {
+	response := &pbSubscription.SubscriptionV2{
+		SubscriptionId: subscription.ID.String(),
+		UserId:         subscription.UserID.String(),
+		ServiceName:    subscription.ServiceName,
+		Price:          moneyToPb(subscription.Price, subscription.Currency),
+		StartDate:      subscription.StartDate.String(),
+		Status:         subscriptionStatusToPb(subscription.Status),
+		Version:        subscription.Version,
+		Etag:           subscription.ETag(),
+	}
+
+	response.BillingPeriod, response.BillingIntervalMonths = billingPeriodToPb(subscription.BillingPeriod)
+
+	if !subscription.EndDate.IsZero() {
+		endDate := subscription.EndDate.String()
+		response.EndDate = &endDate
+	}
+
+	if !subscription.TrialEndDate.IsZero() {
+		trialEndDate := subscription.TrialEndDate.String()
+		response.TrialEndDate = &trialEndDate
+	}
+
+	if !subscription.DeletedAt.IsZero() {
+		deletedAt := subscription.DeletedAt.UTC().Format(time.RFC3339)
+		response.DeletedAt = &deletedAt
+	}
+
+	return response
+}
+
+func subscriptionsToPbV2(subscriptions []model.Subscription) []*pbSubscription.SubscriptionV2 {
+	response := make([]*pbSubscription.SubscriptionV2, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, subscriptionToPbV2(&subscription))
+	}
+
+	return response
+}
+
+func subscriptionPlanToPbV2(plan model.SubscriptionPlan) *pbSubscription.SubscriptionPlanV2 {
+	response := &pbSubscription.SubscriptionPlanV2{
+		ValidFrom:   plan.ValidFrom.String(),
+		ServiceName: plan.ServiceName,
+		Price:       moneyToPb(plan.Price, plan.Currency),
+		Phase:       planPhaseToPb(plan.Phase),
+	}
+
+	if !plan.ValidTo.IsZero() {
+		validTo := plan.ValidTo.String()
+		response.ValidTo = &validTo
+	}
+
+	return response
+}
+
+func exchangeRatesToPb(rates []model.ExchangeRate) []*pbSubscription.ExchangeRate {
+	response := make([]*pbSubscription.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		response = append(response, &pbSubscription.ExchangeRate{
+			Currency:  rate.Currency,
+			ValidFrom: rate.ValidFrom.Format("2006-01-02"),
+			Rate:      rate.Rate.FloatString(10),
+		})
+	}
+
+	return response
+}
+
+// billingPeriodFromPb возвращает нулевой период, если периодичность в запросе не указана
+func billingPeriodFromPb(period pbSubscription.BillingPeriod, intervalMonths *int32) (model.BillingPeriod, error) {
+	if period != pbSubscription.BillingPeriod_BILLING_PERIOD_CUSTOM && intervalMonths != nil {
+		return model.BillingPeriod{}, errors.New("billing_interval_months is allowed only for BILLING_PERIOD_CUSTOM")
+	}
+
+	switch period {
+	case pbSubscription.BillingPeriod_BILLING_PERIOD_WEEKLY:
+		return model.BillingPeriodWeekly, nil
+	case pbSubscription.BillingPeriod_BILLING_PERIOD_MONTHLY:
+		return model.BillingPeriodMonthly, nil
+	case pbSubscription.BillingPeriod_BILLING_PERIOD_QUARTERLY:
+		return model.BillingPeriodQuarterly, nil
+	case pbSubscription.BillingPeriod_BILLING_PERIOD_YEARLY:
+		return model.BillingPeriodYearly, nil
+	case pbSubscription.BillingPeriod_BILLING_PERIOD_CUSTOM:
+		if intervalMonths == nil {
+			return model.BillingPeriod{}, errors.New("billing_interval_months is required for BILLING_PERIOD_CUSTOM")
+		}
+		return model.BillingPeriod{Unit: model.BillingUnitMonth, Interval: *intervalMonths}, nil
+	default:
+		return model.BillingPeriod{}, nil
+	}
+}
+
+func subscriptionStatusToPb(status model.SubscriptionStatus) pbSubscription.SubscriptionStatus {
+	switch status {
+	case model.SubscriptionStatusTrial:
+		return pbSubscription.SubscriptionStatus_SUBSCRIPTION_STATUS_TRIAL
+	case model.SubscriptionStatusActive:
+		return pbSubscription.SubscriptionStatus_SUBSCRIPTION_STATUS_ACTIVE
+	case model.SubscriptionStatusPaused:
+		return pbSubscription.SubscriptionStatus_SUBSCRIPTION_STATUS_PAUSED
+	case model.SubscriptionStatusCancelled:
+		return pbSubscription.SubscriptionStatus_SUBSCRIPTION_STATUS_CANCELLED
+	case model.SubscriptionStatusExpired:
+		return pbSubscription.SubscriptionStatus_SUBSCRIPTION_STATUS_EXPIRED
+	default:
+		return pbSubscription.SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED
+	}
+}
+
+func planPhaseToPb(phase model.PlanPhase) pbSubscription.PlanPhase {
+	switch phase {
+	case model.PlanPhaseTrial:
+		return pbSubscription.PlanPhase_PLAN_PHASE_TRIAL
+	case model.PlanPhasePromo:
+		return pbSubscription.PlanPhase_PLAN_PHASE_PROMO
+	case model.PlanPhaseRegular:
+		return pbSubscription.PlanPhase_PLAN_PHASE_REGULAR
+	default:
+		return pbSubscription.PlanPhase_PLAN_PHASE_UNSPECIFIED
+	}
+}
+
+func auditRecordToPb(record model.AuditRecord) *pbSubscription.SubscriptionHistoryEntry {
+	response := &pbSubscription.SubscriptionHistoryEntry{
+		Id:        record.ID,
+		Action:    historyActionToPb(record.Action),
+		Actor:     record.Actor,
+		ChangedAt: record.CreatedAt.Format(time.RFC3339),
+		Changes:   make([]*pbSubscription.FieldChange, 0, len(record.Changes)),
+	}
+
+	if record.RequestID != "" {
+		response.RequestId = &record.RequestID
+	}
+	if record.ClaimedActor != "" {
+		response.ClaimedActor = &record.ClaimedActor
+	}
+	for _, change := range record.Changes {
+		response.Changes = append(response.Changes, &pbSubscription.FieldChange{
+			Field:  change.Field,
+			Before: string(change.Before),
+			After:  string(change.After),
+		})
+	}
+	if record.Before != nil {
+		response.Before = subscriptionToPb(record.Before)
+	}
+	if record.After != nil {
+		response.After = subscriptionToPb(record.After)
+	}
+
+	return response
+}
+
+func historyActionToPb(action model.AuditAction) pbSubscription.HistoryAction {
+	switch action {
+	case model.AuditActionCreate:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_CREATE
+	case model.AuditActionUpdate:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_UPDATE
+	case model.AuditActionDelete:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_DELETE
+	case model.AuditActionUndelete:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_UNDELETE
+	case model.AuditActionChangePlan:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_CHANGE_PLAN
+	case model.AuditActionMerge:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_MERGE
+	case model.AuditActionCancel:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_CANCEL
+	case model.AuditActionPause:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_PAUSE
+	case model.AuditActionResume:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_RESUME
+	case model.AuditActionActivate:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_ACTIVATE
+	case model.AuditActionExpire:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_EXPIRE
+	case model.AuditActionPurge:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_PURGE
+	default:
+		return pbSubscription.HistoryAction_HISTORY_ACTION_UNSPECIFIED
+	}
+}
+
+func billingPeriodToPb(period model.BillingPeriod) (pbSubscription.BillingPeriod, *int32) {
+	switch period {
+	case model.BillingPeriodWeekly:
+		return pbSubscription.BillingPeriod_BILLING_PERIOD_WEEKLY, nil
+	case model.BillingPeriodMonthly:
+		return pbSubscription.BillingPeriod_BILLING_PERIOD_MONTHLY, nil
+	case model.BillingPeriodQuarterly:
+		return pbSubscription.BillingPeriod_BILLING_PERIOD_QUARTERLY, nil
+	case model.BillingPeriodYearly:
+		return pbSubscription.BillingPeriod_BILLING_PERIOD_YEARLY, nil
+	}
+
+	if period.Unit == model.BillingUnitMonth {
+		interval := period.Interval
+		return pbSubscription.BillingPeriod_BILLING_PERIOD_CUSTOM, &interval
+	}
+
+	return pbSubscription.BillingPeriod_BILLING_PERIOD_UNSPECIFIED, nil
+}
+
+func webhookToPb(webhook *model.Webhook) *pbSubscription.Webhook {
+	response := &pbSubscription.Webhook{
+		WebhookId:       webhook.ID.String(),
+		Url:             webhook.URL,
+		Description:     webhook.Description,
+		EventTypes:      make([]string, 0, len(webhook.EventTypes)),
+		CreatedAt:       webhook.CreatedAt.UTC().Format(time.RFC3339),
+		SecretRotatedAt: webhook.SecretRotatedAt.UTC().Format(time.RFC3339),
+	}
+
+	for _, eventType := range webhook.EventTypes {
+		response.EventTypes = append(response.EventTypes, string(eventType))
+	}
+	if !webhook.PreviousSecretExpiresAt.IsZero() {
+		expiresAt := webhook.PreviousSecretExpiresAt.UTC().Format(time.RFC3339)
+		response.PreviousSecretExpiresAt = &expiresAt
+	}
+
+	return response
+}
+
+func apiKeyToPb(key *model.APIKey) *pbSubscription.APIKey {
+	response := &pbSubscription.APIKey{
+		ApiKeyId:  key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if key.UserID != uuid.Nil {
+		userID := key.UserID.String()
+		response.UserId = &userID
+	}
+	if !key.ExpiresAt.IsZero() {
+		expiresAt := key.ExpiresAt.UTC().Format(time.RFC3339)
+		response.ExpiresAt = &expiresAt
+	}
+	if !key.LastUsedAt.IsZero() {
+		lastUsedAt := key.LastUsedAt.UTC().Format(time.RFC3339)
+		response.LastUsedAt = &lastUsedAt
+	}
+	if !key.RevokedAt.IsZero() {
+		revokedAt := key.RevokedAt.UTC().Format(time.RFC3339)
+		response.RevokedAt = &revokedAt
+	}
+
+	return response
+}
+
+func webhookDeliveryToPb(delivery *model.WebhookDelivery) *pbSubscription.WebhookDelivery {
+	response := &pbSubscription.WebhookDelivery{
+		DeliveryId: delivery.ID,
+		WebhookId:  delivery.WebhookID.String(),
+		EventId:    delivery.EventID,
+		EventType:  string(delivery.EventType),
+		Status:     webhookDeliveryStatusToPb(delivery.Status),
+		Attempts:   delivery.Attempts,
+		Payload:    string(delivery.Payload),
+		CreatedAt:  delivery.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if delivery.Status == model.WebhookDeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt.UTC().Format(time.RFC3339)
+		response.NextAttemptAt = &nextAttemptAt
+	}
+	if !delivery.LastAttemptAt.IsZero() {
+		lastAttemptAt := delivery.LastAttemptAt.UTC().Format(time.RFC3339)
+		response.LastAttemptAt = &lastAttemptAt
+	}
+	if delivery.ResponseStatus != 0 {
+		response.ResponseStatus = &delivery.ResponseStatus
+		response.ResponseBody = &delivery.ResponseBody
+	}
+	if delivery.LastError != "" {
+		response.LastError = &delivery.LastError
+	}
+	if !delivery.DeliveredAt.IsZero() {
+		deliveredAt := delivery.DeliveredAt.UTC().Format(time.RFC3339)
+		response.DeliveredAt = &deliveredAt
+	}
+
+	return response
+}
+
+func webhookDeliveryStatusToPb(status model.WebhookDeliveryStatus) pbSubscription.WebhookDeliveryStatus {
+	switch status {
+	case model.WebhookDeliveryPending:
+		return pbSubscription.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_PENDING
+	case model.WebhookDeliverySucceeded:
+		return pbSubscription.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_SUCCEEDED
+	case model.WebhookDeliveryFailed:
+		return pbSubscription.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_FAILED
+	default:
+		return pbSubscription.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_UNSPECIFIED
+	}
+}
+
+// webhookDeliveryStatusFromPb возвращает пустой статус, если статус в запросе не указан
+func webhookDeliveryStatusFromPb(status pbSubscription.WebhookDeliveryStatus) model.WebhookDeliveryStatus {
+	switch status {
+	case pbSubscription.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_PENDING:
+		return model.WebhookDeliveryPending
+	case pbSubscription.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_SUCCEEDED:
+		return model.WebhookDeliverySucceeded
+	case pbSubscription.WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_FAILED:
+		return model.WebhookDeliveryFailed
+	default:
+		return ""
+	}
+}
+
+func eventToPb(event model.Event) *pbSubscription.SubscriptionEvent {
+	response := &pbSubscription.SubscriptionEvent{
+		EventId:        event.ID,
+		Type:           string(event.Type),
+		SubscriptionId: event.SubscriptionID.String(),
+		Action:         historyActionToPb(event.Action),
+		Changes:        make([]*pbSubscription.FieldChange, 0, len(event.Changes)),
+		Actor:          event.Actor,
+		OccurredAt:     event.OccurredAt.UTC().Format(time.RFC3339),
+	}
+
+	if event.Subscription != nil {
+		response.Subscription = subscriptionToPb(event.Subscription)
+	}
+	if event.RequestID != "" {
+		response.RequestId = &event.RequestID
+	}
+	for _, change := range event.Changes {
+		response.Changes = append(response.Changes, &pbSubscription.FieldChange{
+			Field:  change.Field,
+			Before: string(change.Before),
+			After:  string(change.After),
+		})
+	}
+
+	return response
+}
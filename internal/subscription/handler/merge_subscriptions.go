@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) MergeSubscriptions(ctx context.Context, request *pbSubscription.MergeSubscriptionsRequest) (*pbSubscription.MergeSubscriptionsResponse, error) {
+	const op = "SubscriptionHandler.MergeSubscriptions"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		logger.Error("failed parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	mergedIDs := make([]uuid.UUID, 0, len(request.GetMergedSubscriptionIds()))
+	for _, mergedID := range request.GetMergedSubscriptionIds() {
+		id, err := uuid.Parse(mergedID)
+		if err != nil {
+			logger.Error("failed parse merged subscription id", "error", err)
+			return nil, apperror.NewValidationError("merged_subscription_ids", err.Error())
+		}
+
+		mergedIDs = append(mergedIDs, id)
+	}
+
+	subscription, err := s.service.MergeSubscriptions(ctx, subscriptionID, mergedIDs)
+	if err != nil {
+		logger.Error("failed merge subscriptions", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.MergeSubscriptionsResponse{
+		Subscription: subscriptionToPb(subscription),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) ReplayWebhookDelivery(ctx context.Context, request *pbSubscription.ReplayWebhookDeliveryRequest) (*pbSubscription.ReplayWebhookDeliveryResponse, error) {
+	const op = "SubscriptionHandler.ReplayWebhookDelivery"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	webhookID, err := uuid.Parse(request.GetWebhookId())
+	if err != nil {
+		logger.Error("failed parse webhook id", "error", err)
+		return nil, apperror.NewValidationError("webhook_id", err.Error())
+	}
+
+	delivery, err := s.webhooks.ReplayWebhookDelivery(ctx, webhookID, request.GetDeliveryId())
+	if err != nil {
+		logger.Error("failed replay webhook delivery", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.ReplayWebhookDeliveryResponse{
+		Delivery: webhookDeliveryToPb(delivery),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) ResumeSubscription(ctx context.Context, request *pbSubscription.ResumeSubscriptionRequest) (*pbSubscription.ResumeSubscriptionResponse, error) {
+	const op = "SubscriptionHandler.ResumeSubscription"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		logger.Error("failed parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	var effectiveDate model.YearMonth
+	if request.EffectiveDate != nil {
+		effectiveDate, err = model.ParseYearMonth(request.GetEffectiveDate())
+		if err != nil {
+			logger.Error("failed parse effective date", "error", err)
+			return nil, apperror.NewValidationError("effective_date", err.Error())
+		}
+	}
+
+	subscription, err := s.service.ResumeSubscription(ctx, subscriptionID, effectiveDate)
+	if err != nil {
+		logger.Error("failed resume subscription", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.ResumeSubscriptionResponse{
+		Subscription: subscriptionToPb(subscription),
+	}, nil
+}
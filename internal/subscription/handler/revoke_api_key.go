@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) RevokeAPIKey(ctx context.Context, request *pbSubscription.RevokeAPIKeyRequest) (*pbSubscription.RevokeAPIKeyResponse, error) {
+	const op = "SubscriptionHandler.RevokeAPIKey"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	keyID, err := uuid.Parse(request.GetApiKeyId())
+	if err != nil {
+		logger.Error("failed parse api key id", "error", err)
+		return nil, apperror.NewValidationError("api_key_id", err.Error())
+	}
+
+	key, err := s.apiKeys.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		logger.Error("failed revoke api key", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.RevokeAPIKeyResponse{
+		ApiKey: apiKeyToPb(key),
+	}, nil
+}
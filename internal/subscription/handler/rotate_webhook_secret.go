@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) RotateWebhookSecret(ctx context.Context, request *pbSubscription.RotateWebhookSecretRequest) (*pbSubscription.RotateWebhookSecretResponse, error) {
+	const op = "SubscriptionHandler.RotateWebhookSecret"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	webhookID, err := uuid.Parse(request.GetWebhookId())
+	if err != nil {
+		logger.Error("failed parse webhook id", "error", err)
+		return nil, apperror.NewValidationError("webhook_id", err.Error())
+	}
+
+	webhook, err := s.webhooks.RotateWebhookSecret(ctx, webhookID, request.GetRevokePrevious())
+	if err != nil {
+		logger.Error("failed rotate webhook secret", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.RotateWebhookSecretResponse{
+		Webhook: webhookToPb(webhook),
+		Secret:  webhook.Secret,
+	}, nil
+}
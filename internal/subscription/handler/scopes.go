@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+)
+
+// MethodScopes - области доступа, необходимые для вызова методов клиентам с ограниченными областями доступа
+// (API-ключам). Методов управления вебхуками и API-ключами в таблице нет: они доступны только администратору
+var MethodScopes = map[string]string{
+	pbSubscription.Subscriptions_GetSubscription_FullMethodName:          model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_GetSubscriptions_FullMethodName:         model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_GetSubscriptionPlans_FullMethodName:     model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_ListSubscriptionOverlaps_FullMethodName: model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_ListEndingTrials_FullMethodName:         model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_ListSubscriptionHistory_FullMethodName:  model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_WatchSubscriptions_FullMethodName:       model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_GetSubscriptionV2_FullMethodName:        model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_GetSubscriptionsV2_FullMethodName:       model.ScopeSubscriptionsRead,
+	pbSubscription.Subscriptions_GetSubscriptionPlansV2_FullMethodName:   model.ScopeSubscriptionsRead,
+
+	pbSubscription.Subscriptions_AddSubscription_FullMethodName:          model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_UpdateSubscription_FullMethodName:       model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_DeleteSubscription_FullMethodName:       model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_UndeleteSubscription_FullMethodName:     model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_ChangeSubscriptionPlan_FullMethodName:   model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_MergeSubscriptions_FullMethodName:       model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_CancelSubscription_FullMethodName:       model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_PauseSubscription_FullMethodName:        model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_ResumeSubscription_FullMethodName:       model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_AddSubscriptionV2_FullMethodName:        model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_UpdateSubscriptionV2_FullMethodName:     model.ScopeSubscriptionsWrite,
+	pbSubscription.Subscriptions_ChangeSubscriptionPlanV2_FullMethodName: model.ScopeSubscriptionsWrite,
+
+	pbSubscription.Subscriptions_GetSumSubscriptions_FullMethodName:   model.ScopeReportsRead,
+	pbSubscription.Subscriptions_GetSpendTimeSeries_FullMethodName:    model.ScopeReportsRead,
+	pbSubscription.Subscriptions_GetSpendBreakdown_FullMethodName:     model.ScopeReportsRead,
+	pbSubscription.Subscriptions_GetSumSubscriptionsV2_FullMethodName: model.ScopeReportsRead,
+	pbSubscription.Subscriptions_GetSpendTimeSeriesV2_FullMethodName:  model.ScopeReportsRead,
+	pbSubscription.Subscriptions_GetSpendBreakdownV2_FullMethodName:   model.ScopeReportsRead,
+}
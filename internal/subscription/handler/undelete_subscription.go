@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+
+	"buf.build/go/protovalidate"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+func (s *SubscriptionHandler) UndeleteSubscription(ctx context.Context, request *pbSubscription.UndeleteSubscriptionRequest) (*pbSubscription.UndeleteSubscriptionResponse, error) {
+	const op = "SubscriptionHandler.UndeleteSubscription"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
+	}
+
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
+	if err != nil {
+		logger.Error("failed parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	subscription, err := s.service.UndeleteSubscription(ctx, subscriptionID)
+	if err != nil {
+		logger.Error("failed undelete subscription", "error", err)
+		return nil, err
+	}
+
+	return &pbSubscription.UndeleteSubscriptionResponse{
+		Subscription: subscriptionToPb(subscription),
+	}, nil
+}
@@ -2,17 +2,40 @@ package handler
 
 import (
 	"context"
-	"errors"
-	"time"
+	"fmt"
+	"slices"
 
 	"buf.build/go/protovalidate"
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
 	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
+// updatableFields - поля UpdateSubscriptionRequest, которые можно указывать в update_mask
+var updatableFields = []string{
+	"user_id",
+	"service_name",
+	"price",
+	"currency",
+	"billing_period",
+	"billing_interval_months",
+	"start_date",
+	"end_date",
+}
+
+// updatableFieldsV2 - поля UpdateSubscriptionV2Request, которые можно указывать в update_mask
+var updatableFieldsV2 = []string{
+	"user_id",
+	"service_name",
+	"price",
+	"start_date",
+	"end_date",
+}
+
 func (s *SubscriptionHandler) UpdateSubscription(ctx context.Context, request *pbSubscription.UpdateSubscriptionRequest) (*pbSubscription.UpdateSubscriptionResponse, error) {
 	const op = "SubscriptionHandler.UpdateSubscription"
 	logger := s.logger.With("op", op).With("request", request)
@@ -20,74 +43,227 @@ func (s *SubscriptionHandler) UpdateSubscription(ctx context.Context, request *p
 	err := protovalidate.Validate(request)
 	if err != nil {
 		logger.Error("failed validate request", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 
 	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
 	if err != nil {
 		logger.Error("failed parse subscription id", "error", err)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
 	}
 
-	var userID uuid.UUID
-	if request.GetUserId() != "" {
-		userID, err = uuid.Parse(request.GetUserId())
-		if err != nil {
-			logger.Error("failed parse user id", "error", err)
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+	update, err := subscriptionUpdateFromPb(request, updatableFields, func(update *model.SubscriptionUpdate, path string) error {
+		switch path {
+		case "price":
+			if request.Price == nil {
+				return apperror.NewValidationError("price", "cannot be cleared")
+			}
+
+			price := int64(request.GetPrice())
+			update.Price = &price
+			update.PriceInMajorUnits = true
+		case "currency":
+			if request.Currency == nil {
+				return apperror.NewValidationError("currency", "cannot be cleared")
+			}
+			update.Currency = request.Currency
+		case "billing_period", "billing_interval_months":
+			billingPeriod, err := billingPeriodFromPb(request.GetBillingPeriod(), request.BillingIntervalMonths)
+			if err != nil {
+				return apperror.NewValidationError(path, err.Error())
+			}
+			if billingPeriod.IsZero() {
+				return apperror.NewValidationError("billing_period", "cannot be cleared")
+			}
+			update.BillingPeriod = &billingPeriod
 		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed parse update", "error", err)
+		return nil, err
 	}
 
-	var startDate time.Time
-	if request.GetEndDate() != "" {
-		startDate, err = time.Parse("01-2006", request.GetStartDate())
-		if err != nil {
-			logger.Error("failed parse start date", "error", err)
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
+	update.ExpectedVersion, err = expectedVersion(ctx, request.GetEtag())
+	if err != nil {
+		logger.Error("failed parse etag", "error", err)
+		return nil, err
 	}
 
-	var endDate time.Time
-	if request.GetEndDate() != "" {
-		endDate, err = time.Parse("01-2006", request.GetEndDate())
-		if err != nil {
-			logger.Error("failed parse end date", "error", err)
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
+	resultSubscription, overlaps, err := s.service.UpdateSubscription(ctx, subscriptionID, update)
+	if err != nil {
+		logger.Error("failed update subscription", "error", err)
+		return nil, err
 	}
+	if len(overlaps) > 0 {
+		logger.Warn("subscription overlaps with existing subscriptions", "count", len(overlaps))
+	}
+	setETag(ctx, resultSubscription)
+
+	return &pbSubscription.UpdateSubscriptionResponse{
+		Subscription: subscriptionToPb(resultSubscription),
+		Overlaps:     subscriptionsToPb(overlaps),
+	}, nil
+}
 
-	subscription := model.Subscription{
-		UserID:      userID,
-		ServiceName: request.GetServiceName(),
-		Price:       request.GetPrice(),
-		StartDate:   startDate,
-		EndDate:     endDate,
+func (s *SubscriptionHandler) UpdateSubscriptionV2(ctx context.Context, request *pbSubscription.UpdateSubscriptionV2Request) (*pbSubscription.UpdateSubscriptionV2Response, error) {
+	const op = "SubscriptionHandler.UpdateSubscriptionV2"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return nil, err
 	}
 
-	resultSubscription, err := s.service.UpdateSubscription(ctx, subscriptionID, subscription)
+	subscriptionID, err := uuid.Parse(request.GetSubscriptionId())
 	if err != nil {
-		if errors.Is(err, model.ErrSubscriptionNotFound) {
-			return nil, status.Error(codes.NotFound, err.Error())
+		logger.Error("failed parse subscription id", "error", err)
+		return nil, apperror.NewValidationError("subscription_id", err.Error())
+	}
+
+	update, err := subscriptionUpdateFromPb(request, updatableFieldsV2, func(update *model.SubscriptionUpdate, path string) error {
+		if path == "price" {
+			if request.Price == nil {
+				return apperror.NewValidationError("price", "cannot be cleared")
+			}
+
+			price := request.GetPrice().GetAmountMinor()
+			update.Price = &price
+			update.PriceCurrency = request.GetPrice().GetCurrency()
 		}
 
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed parse update", "error", err)
+		return nil, err
+	}
+
+	update.ExpectedVersion, err = expectedVersion(ctx, request.GetEtag())
+	if err != nil {
+		logger.Error("failed parse etag", "error", err)
+		return nil, err
+	}
+
+	resultSubscription, overlaps, err := s.service.UpdateSubscription(ctx, subscriptionID, update)
+	if err != nil {
 		logger.Error("failed update subscription", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+	if len(overlaps) > 0 {
+		logger.Warn("subscription overlaps with existing subscriptions", "count", len(overlaps))
 	}
+	setETag(ctx, resultSubscription)
+
+	return &pbSubscription.UpdateSubscriptionV2Response{
+		Subscription: subscriptionToPbV2(resultSubscription),
+		Overlaps:     subscriptionsToPbV2(overlaps),
+	}, nil
+}
+
+// updateRequest - поля запросов UpdateSubscription и UpdateSubscriptionV2, одинаковые в обеих версиях API
+type updateRequest interface {
+	proto.Message
+	GetUpdateMask() *fieldmaskpb.FieldMask
+	GetUserId() string
+	GetServiceName() string
+	GetStartDate() string
+	GetEndDate() string
+}
 
-	response := &pbSubscription.UpdateSubscriptionResponse{
-		Subscription: &pbSubscription.Subscription{
-			SubscriptionId: resultSubscription.ID.String(),
-			UserId:         resultSubscription.UserID.String(),
-			ServiceName:    resultSubscription.ServiceName,
-			Price:          resultSubscription.Price,
-			StartDate:      resultSubscription.StartDate.Format("01-2006"),
-		},
+// updatePaths возвращает поля, которые нужно изменить. Без update_mask изменяются только переданные поля
+func updatePaths(request updateRequest, updatable []string) ([]string, error) {
+	if request.GetUpdateMask() == nil {
+		message := request.ProtoReflect()
+		fields := message.Descriptor().Fields()
+
+		var paths []string
+		for _, field := range updatable {
+			if message.Has(fields.ByName(protoreflect.Name(field))) {
+				paths = append(paths, field)
+			}
+		}
+
+		return paths, nil
 	}
 
-	if !subscription.EndDate.IsZero() {
-		endDate := subscription.EndDate.Format("01-2006")
-		response.Subscription.EndDate = &endDate
+	paths := request.GetUpdateMask().GetPaths()
+	if len(paths) == 1 && paths[0] == "*" {
+		return updatable, nil
+	}
+
+	for _, path := range paths {
+		if !slices.Contains(updatable, path) {
+			return nil, apperror.NewValidationError("update_mask", fmt.Sprintf("field %q cannot be updated", path))
+		}
+	}
+
+	return paths, nil
+}
+
+// subscriptionUpdateFromPb разбирает поля, одинаковые в обеих версиях API. Остальные поля из маски,
+// в том числе цену, разбирает parseField
+func subscriptionUpdateFromPb(request updateRequest, updatable []string, parseField func(update *model.SubscriptionUpdate, path string) error) (model.SubscriptionUpdate, error) {
+	var update model.SubscriptionUpdate
+
+	paths, err := updatePaths(request, updatable)
+	if err != nil {
+		return update, err
+	}
+
+	message := request.ProtoReflect()
+	fields := message.Descriptor().Fields()
+
+	for _, path := range paths {
+		set := message.Has(fields.ByName(protoreflect.Name(path)))
+
+		switch path {
+		case "user_id":
+			if !set {
+				return update, apperror.NewValidationError("user_id", "cannot be cleared")
+			}
+
+			userID, err := uuid.Parse(request.GetUserId())
+			if err != nil {
+				return update, apperror.NewValidationError("user_id", err.Error())
+			}
+			update.UserID = &userID
+		case "service_name":
+			if !set {
+				return update, apperror.NewValidationError("service_name", "cannot be cleared")
+			}
+
+			serviceName := request.GetServiceName()
+			update.ServiceName = &serviceName
+		case "start_date":
+			if !set {
+				return update, apperror.NewValidationError("start_date", "cannot be cleared")
+			}
+
+			startDate, err := model.ParseYearMonth(request.GetStartDate())
+			if err != nil {
+				return update, apperror.NewValidationError("start_date", err.Error())
+			}
+			update.StartDate = &startDate
+		case "end_date":
+			if !set {
+				update.ClearEndDate = true
+				continue
+			}
+
+			endDate, err := model.ParseYearMonth(request.GetEndDate())
+			if err != nil {
+				return update, apperror.NewValidationError("end_date", err.Error())
+			}
+			update.EndDate = &endDate
+		default:
+			if err := parseField(&update, path); err != nil {
+				return update, err
+			}
+		}
 	}
 
-	return response, nil
+	return update, nil
 }
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"buf.build/go/protovalidate"
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	pbSubscription "github.com/Geriler/effective-mobile/pb/api"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func (s *SubscriptionHandler) WatchSubscriptions(request *pbSubscription.WatchSubscriptionsRequest, stream grpc.ServerStreamingServer[pbSubscription.SubscriptionEvent]) error {
+	const op = "SubscriptionHandler.WatchSubscriptions"
+	logger := s.logger.With("op", op).With("request", request)
+
+	err := protovalidate.Validate(request)
+	if err != nil {
+		logger.Error("failed validate request", "error", err)
+		return err
+	}
+
+	var userID uuid.UUID
+	if request.UserId != nil {
+		userID, err = uuid.Parse(request.GetUserId())
+		if err != nil {
+			logger.Error("failed parse user id", "error", err)
+			return apperror.NewValidationError("user_id", err.Error())
+		}
+	}
+
+	// заголовки ответа отправляются сразу, чтобы клиент знал, что наблюдение началось, еще до первого события
+	ready := func() error {
+		return stream.SendHeader(metadata.MD{})
+	}
+	send := func(event model.Event) error {
+		return stream.Send(eventToPb(event))
+	}
+
+	err = s.watcher.Watch(stream.Context(), userID, request.GetAfterEventId(), ready, send)
+	if err != nil {
+		logger.Error("failed watch subscriptions", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Области доступа API-ключей
+const (
+	ScopeSubscriptionsRead  = "subscriptions:read"
+	ScopeSubscriptionsWrite = "subscriptions:write"
+	ScopeReportsRead        = "reports:read"
+)
+
+// APIKeyScopes - области доступа, которые можно выдать API-ключу
+var APIKeyScopes = []string{ScopeSubscriptionsRead, ScopeSubscriptionsWrite, ScopeReportsRead}
+
+// APIKey - долгоживущие учетные данные машинного клиента. Key заполняется только при выпуске ключа: сервис хранит
+// лишь хеш ключа, и получить ключ позже нельзя
+type APIKey struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Prefix - начало ключа, по которому его можно узнать в списке
+	Prefix string   `json:"prefix"`
+	Key    string   `json:"-"`
+	Hash   []byte   `json:"-"`
+	Scopes []string `json:"scopes"`
+	// UserID - пользователь, подписками которого ограничен ключ. Нулевой - подписки всех пользователей
+	UserID uuid.UUID `json:"user_id,omitzero"`
+	// TenantID - арендатор, от имени которого работает ключ: арендатор, в котором ключ выпущен
+	TenantID   string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero"`
+	RevokedAt  time.Time `json:"revoked_at,omitzero"`
+}
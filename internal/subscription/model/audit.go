@@ -0,0 +1,115 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction - вид изменения подписки в журнале аудита
+type AuditAction string
+
+const (
+	AuditActionCreate     AuditAction = "create"
+	AuditActionUpdate     AuditAction = "update"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionUndelete   AuditAction = "undelete"
+	AuditActionChangePlan AuditAction = "change_plan"
+	AuditActionMerge      AuditAction = "merge"
+	AuditActionCancel     AuditAction = "cancel"
+	AuditActionPause      AuditAction = "pause"
+	AuditActionResume     AuditAction = "resume"
+	// AuditActionActivate - окончание пробного периода
+	AuditActionActivate AuditAction = "activate"
+	// AuditActionExpire - окончание подписки
+	AuditActionExpire AuditAction = "expire"
+	// AuditActionPurge - окончательное удаление по истечении срока хранения
+	AuditActionPurge AuditAction = "purge"
+)
+
+// FieldChange - изменение поля подписки. Значения хранятся в JSON-представлении Subscription,
+// отсутствующее значение - null
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditRecord - запись журнала изменений подписки. Before равен nil при создании, After - при окончательном удалении
+type AuditRecord struct {
+	ID             int64         `json:"id"`
+	SubscriptionID uuid.UUID     `json:"subscription_id"`
+	Action         AuditAction   `json:"action"`
+	Actor          string        `json:"actor"`
+	ClaimedActor   string        `json:"claimed_actor,omitempty"`
+	RequestID      string        `json:"request_id,omitempty"`
+	Before         *Subscription `json:"before,omitempty"`
+	After          *Subscription `json:"after,omitempty"`
+	Changes        []FieldChange `json:"changes"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+var jsonNull = json.RawMessage("null")
+
+// DiffSubscriptions возвращает поля, значения которых различаются у подписок before и after, в порядке имен полей.
+// Отсутствующая подписка (nil) сравнивается как подписка без полей. Версия не сравнивается: она меняется при любом изменении
+func DiffSubscriptions(before, after *Subscription) ([]FieldChange, error) {
+	beforeFields, err := subscriptionFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := subscriptionFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	changes := make([]FieldChange, 0)
+	for _, name := range names {
+		beforeValue, ok := beforeFields[name]
+		if !ok {
+			beforeValue = jsonNull
+		}
+		afterValue, ok := afterFields[name]
+		if !ok {
+			afterValue = jsonNull
+		}
+
+		if !bytes.Equal(beforeValue, afterValue) {
+			changes = append(changes, FieldChange{Field: name, Before: beforeValue, After: afterValue})
+		}
+	}
+
+	return changes, nil
+}
+
+func subscriptionFields(subscription *Subscription) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if subscription == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "version")
+
+	return fields, nil
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSubscriptions(t *testing.T) {
+	before := &Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   NewYearMonth(2025, 1),
+		Status:      SubscriptionStatusActive,
+	}
+	after := *before
+	after.Price = 50000
+	after.EndDate = NewYearMonth(2025, 12)
+
+	changes, err := DiffSubscriptions(before, &after)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldChange{
+		{Field: "end_date", Before: json.RawMessage("null"), After: json.RawMessage(`"12-2025"`)},
+		{Field: "price", Before: json.RawMessage("40000"), After: json.RawMessage("50000")},
+	}, changes)
+
+	changes, err = DiffSubscriptions(before, before)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = DiffSubscriptions(nil, before)
+	assert.NoError(t, err)
+	for _, change := range changes {
+		assert.Equal(t, json.RawMessage("null"), change.Before)
+	}
+	assert.Contains(t, changes, FieldChange{Field: "price", Before: json.RawMessage("null"), After: json.RawMessage("40000")})
+}
@@ -0,0 +1,25 @@
+package model
+
+type BillingUnit string
+
+const (
+	BillingUnitWeek  BillingUnit = "week"
+	BillingUnitMonth BillingUnit = "month"
+)
+
+// BillingPeriod - периодичность списаний: одно списание раз в Interval недель или месяцев
+type BillingPeriod struct {
+	Unit     BillingUnit `json:"unit"`
+	Interval int32       `json:"interval"`
+}
+
+var (
+	BillingPeriodWeekly    = BillingPeriod{Unit: BillingUnitWeek, Interval: 1}
+	BillingPeriodMonthly   = BillingPeriod{Unit: BillingUnitMonth, Interval: 1}
+	BillingPeriodQuarterly = BillingPeriod{Unit: BillingUnitMonth, Interval: 3}
+	BillingPeriodYearly    = BillingPeriod{Unit: BillingUnitMonth, Interval: 12}
+)
+
+func (p BillingPeriod) IsZero() bool {
+	return p == BillingPeriod{}
+}
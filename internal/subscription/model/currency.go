@@ -0,0 +1,40 @@
+package model
+
+import "math"
+
+// currencyExponents - количество знаков после запятой у валют, отличное от двух (ISO 4217)
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent возвращает количество знаков после запятой у валюты: 2 для рубля (копейки), 0 для иены
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+
+	return 2
+}
+
+// MinorUnitsFactor возвращает количество минимальных единиц в единице валюты
+func MinorUnitsFactor(currency string) int64 {
+	return int64(math.Pow10(CurrencyExponent(currency)))
+}
+
+// ToMinorUnits переводит сумму в целых единицах валюты в минимальные единицы
+func ToMinorUnits(amount int64, currency string) int64 {
+	return amount * MinorUnitsFactor(currency)
+}
+
+// ToMajorUnits переводит сумму в минимальных единицах в целые единицы валюты, дробная часть отбрасывается
+func ToMajorUnits(amount int64, currency string) int64 {
+	return amount / MinorUnitsFactor(currency)
+}
+
+// MaxPrice возвращает наибольшую цену подписки в минимальных единицах валюты: цена в целых единицах должна
+// помещаться в int32 полей price API v1
+func MaxPrice(currency string) int64 {
+	return ToMinorUnits(math.MaxInt32, currency)
+}
@@ -5,4 +5,22 @@ import "errors"
 var (
 	ErrSubscriptionNotFound      = errors.New("subscription not found")
 	ErrSubscriptionAlreadyExists = errors.New("subscription already exists")
+	ErrExchangeRateNotFound      = errors.New("exchange rate not found")
+	ErrPlanOutsideSubscription   = errors.New("plan start is outside of subscription period")
+	ErrSubscriptionsNotMergeable = errors.New("only subscriptions of the same user and service can be merged")
+	ErrIdempotencyKeyReused      = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress  = errors.New("request with this idempotency key is still in progress")
+	ErrInvalidYearMonth          = errors.New("month must be in MM-YYYY format")
+	ErrInvalidStatusTransition   = errors.New("invalid subscription status transition")
+	ErrSubscriptionClosed        = errors.New("end date of a cancelled or expired subscription cannot be changed")
+	ErrSubscriptionTermsFixed    = errors.New("currency and billing period of a subscription cannot be changed")
+	ErrTotalSumOutOfRange        = errors.New("total sum does not fit in int32, use GetSumSubscriptionsV2")
+	ErrVersionMismatch           = errors.New("subscription was modified by another request")
+	ErrInvalidETag               = errors.New("etag must be a subscription version in quotes")
+	ErrWebhookNotFound           = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound   = errors.New("webhook delivery not found")
+	ErrWatchInterrupted          = errors.New("subscription event stream was interrupted, resume from the last received event")
+	ErrWatchResumeExpired        = errors.New("events after the requested event are no longer available")
+	ErrPermissionDenied          = errors.New("permission denied")
+	ErrAPIKeyNotFound            = errors.New("api key not found")
 )
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType - тип события об изменении подписки
+type EventType string
+
+const (
+	EventSubscriptionCreated EventType = "SubscriptionCreated"
+	// EventSubscriptionUpdated публикуется при любом изменении, в том числе при восстановлении удаленной подписки:
+	// событие содержит подписку целиком, и получателю достаточно сохранить ее
+	EventSubscriptionUpdated EventType = "SubscriptionUpdated"
+	EventSubscriptionDeleted EventType = "SubscriptionDeleted"
+)
+
+// Event - событие об изменении подписки. Subscription - состояние подписки после изменения,
+// для SubscriptionDeleted - удаленная подписка с временем удаления.
+// События публикуются не менее одного раза: получатель должен отбрасывать повторы по ID
+type Event struct {
+	ID             int64         `json:"id"`
+	TenantID       string        `json:"tenant_id"`
+	Type           EventType     `json:"type"`
+	SubscriptionID uuid.UUID     `json:"subscription_id"`
+	Action         AuditAction   `json:"action"`
+	Subscription   *Subscription `json:"subscription"`
+	Changes        []FieldChange `json:"changes,omitempty"`
+	Actor          string        `json:"actor"`
+	RequestID      string        `json:"request_id,omitempty"`
+	OccurredAt     time.Time     `json:"occurred_at"`
+	// Attempts - количество неудачных попыток публикации
+	Attempts int32 `json:"-"`
+}
+
+// EventTypeOf возвращает тип события для изменения подписки before -> after. Окончательное удаление
+// уже удаленной подписки (after равен nil) событием не сопровождается: о нем сообщило SubscriptionDeleted
+func EventTypeOf(before, after *Subscription) (EventType, bool) {
+	switch {
+	case after == nil:
+		return "", false
+	case before == nil:
+		return EventSubscriptionCreated, true
+	case before.DeletedAt.IsZero() && !after.DeletedAt.IsZero():
+		return EventSubscriptionDeleted, true
+	default:
+		return EventSubscriptionUpdated, true
+	}
+}
+
+// EventNotification - уведомление о записи события. UserID - пользователь подписки после изменения
+type EventNotification struct {
+	ID       int64
+	TenantID string
+	UserID   uuid.UUID
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"math/big"
+	"time"
+)
+
+// ExchangeRate - курс валюты к базовой валюте сервиса, действующий с даты ValidFrom
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	ValidFrom time.Time `json:"valid_from"`
+	Rate      *big.Rat  `json:"rate"`
+}
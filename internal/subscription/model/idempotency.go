@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// IdempotencyRecord - запрос, выполненный с ключом идемпотентности. Response равен nil, пока запрос выполняется
+type IdempotencyRecord struct {
+	Key         string    `json:"key"`
+	Method      string    `json:"method"`
+	RequestHash []byte    `json:"request_hash"`
+	Response    []byte    `json:"response,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// OverlapPolicy - что делать с подпиской, период которой пересекается с другой подпиской
+// того же пользователя на тот же сервис
+type OverlapPolicy string
+
+const (
+	// OverlapPolicyReject - подписка не сохраняется
+	OverlapPolicyReject OverlapPolicy = "reject"
+	// OverlapPolicyWarn - подписка сохраняется, пересекающиеся подписки возвращаются клиенту
+	OverlapPolicyWarn OverlapPolicy = "warn"
+	// OverlapPolicyAllow - пересечения не проверяются
+	OverlapPolicyAllow OverlapPolicy = "allow"
+)
+
+func ParseOverlapPolicy(policy string) (OverlapPolicy, error) {
+	switch OverlapPolicy(policy) {
+	case OverlapPolicyReject, OverlapPolicyWarn, OverlapPolicyAllow:
+		return OverlapPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown overlap policy %q", policy)
+	}
+}
+
+// NormalizeServiceName приводит наименование к виду, в котором сравниваются подписки:
+// без учета регистра и лишних пробелов. Совпадает с SQL-функцией normalize_service_name
+func NormalizeServiceName(serviceName string) string {
+	return strings.ToLower(strings.Join(strings.Fields(serviceName), " "))
+}
+
+// SubscriptionOverlap - пара подписок одного пользователя на один сервис с пересекающимися периодами.
+// End равен нулю, если пересечение бессрочное
+type SubscriptionOverlap struct {
+	Subscription Subscription `json:"subscription"`
+	Overlapping  Subscription `json:"overlapping"`
+	Start        YearMonth    `json:"start"`
+	End          YearMonth    `json:"end,omitzero"`
+}
+
+type OverlapFilter struct {
+	UserID      uuid.UUID `json:"user_id,omitempty"`
+	ServiceName string    `json:"service_name,omitempty"`
+}
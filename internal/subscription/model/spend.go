@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// Granularity - размер интервала временного ряда расходов
+type Granularity string
+
+const (
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+	GranularityYear    Granularity = "year"
+)
+
+// BucketStart возвращает первый месяц календарного интервала, в который попадает month
+func (g Granularity) BucketStart(month YearMonth) YearMonth {
+	switch g {
+	case GranularityQuarter:
+		return NewYearMonth(month.Year(), (month.Month()-1)/3*3+1)
+	case GranularityYear:
+		return NewYearMonth(month.Year(), time.January)
+	default:
+		return month
+	}
+}
+
+// SpendBucket - расходы за один интервал. StartDate и EndDate - первый и последний месяцы интервала,
+// обрезанные по границам запрошенного периода
+type SpendBucket struct {
+	StartDate           YearMonth `json:"start_date"`
+	EndDate             YearMonth `json:"end_date"`
+	Amount              int64     `json:"amount"`
+	ActiveSubscriptions int32     `json:"active_subscriptions"`
+}
+
+// SpendTimeSeries - расходы за период в разрезе интервалов. Total равен сумме Amount всех интервалов
+type SpendTimeSeries struct {
+	Buckets  []SpendBucket  `json:"buckets"`
+	Total    int64          `json:"total"`
+	Currency string         `json:"currency"`
+	Rates    []ExchangeRate `json:"rates"`
+}
+
+// BreakdownDimension - признак, по которому группируются расходы
+type BreakdownDimension string
+
+const (
+	BreakdownByServiceName BreakdownDimension = "service_name"
+	BreakdownByUserID      BreakdownDimension = "user_id"
+)
+
+// SpendBreakdownRow - расходы одной группы. Other отмечает строку, объединяющую группы за пределами top-N
+type SpendBreakdownRow struct {
+	Key           string  `json:"key"`
+	Amount        int64   `json:"amount"`
+	Share         float64 `json:"share"`
+	Subscriptions int32   `json:"subscriptions"`
+	Other         bool    `json:"other,omitempty"`
+}
+
+// SpendBreakdown - расходы за период в разрезе групп, строки упорядочены по убыванию суммы.
+// Total равен сумме Amount всех строк
+type SpendBreakdown struct {
+	Rows     []SpendBreakdownRow `json:"rows"`
+	Total    int64               `json:"total"`
+	Currency string              `json:"currency"`
+	Rates    []ExchangeRate      `json:"rates"`
+}
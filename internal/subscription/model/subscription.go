@@ -6,23 +6,183 @@ import (
 	"github.com/google/uuid"
 )
 
+// Subscription - подписка пользователя. Price хранится в минимальных единицах валюты Currency (копейках, центах)
 type Subscription struct {
-	ID          uuid.UUID `json:"id"`
-	UserID      uuid.UUID `json:"user_id"`
-	ServiceName string    `json:"service_name"`
-	Price       int32     `json:"price"`
-	StartDate   time.Time `json:"start_date"`
-	EndDate     time.Time `json:"end_date,omitempty"`
+	ID            uuid.UUID     `json:"id"`
+	UserID        uuid.UUID     `json:"user_id"`
+	ServiceName   string        `json:"service_name"`
+	Price         int64         `json:"price"`
+	Currency      string        `json:"currency"`
+	BillingPeriod BillingPeriod `json:"billing_period"`
+	StartDate     YearMonth     `json:"start_date"`
+	EndDate       YearMonth     `json:"end_date,omitzero"`
+	// Status - статус жизненного цикла. Меняется только переходами CancelSubscription, PauseSubscription,
+	// ResumeSubscription и по истечении подписки
+	Status SubscriptionStatus `json:"status"`
+	// TrialEndDate - последний месяц бесплатного пробного периода, равен нулю, если пробного периода нет
+	TrialEndDate YearMonth `json:"trial_end_date,omitzero"`
+	// TrialMonths и PromoPhases задают пробный период и льготные фазы при создании подписки.
+	// Они сохраняются как тарифы подписки, начинающиеся с StartDate, после них действует цена Price
+	TrialMonths int32        `json:"-"`
+	PromoPhases []PromoPhase `json:"-"`
+	// OverlapAllowed - подписка сохранена, несмотря на пересечение с другой подпиской того же пользователя на тот же сервис
+	OverlapAllowed bool `json:"overlap_allowed,omitempty"`
+	// DeletedAt - время удаления, равно нулю у неудаленной подписки. Удаленная подписка видна только в списке
+	// с DeletedFilterInclude или DeletedFilterOnly и может быть восстановлена до окончательного удаления
+	DeletedAt time.Time `json:"deleted_at,omitzero"`
+	// Version увеличивается при каждом изменении подписки и служит ее ETag для оптимистичной блокировки
+	Version int64 `json:"version,omitzero"`
+	// TenantID - арендатор подписки. Не входит в журнал изменений: подписка не переходит к другому арендатору
+	TenantID string `json:"-"`
+	// PriceInMajorUnits - Price и цены PromoPhases заданы в целых единицах валюты (API v1), сервис переводит их
+	// в минимальные единицы
+	PriceInMajorUnits bool `json:"-"`
+}
+
+// Overlaps сообщает, пересекаются ли периоды подписок одного пользователя на один сервис
+func (s Subscription) Overlaps(other Subscription) bool {
+	if s.UserID != other.UserID || NormalizeServiceName(s.ServiceName) != NormalizeServiceName(other.ServiceName) {
+		return false
+	}
+
+	return (s.EndDate.IsZero() || !s.EndDate.Before(other.StartDate)) &&
+		(other.EndDate.IsZero() || !other.EndDate.Before(s.StartDate))
+}
+
+// InitialPlans возвращает тарифы новой подписки: пробный период, льготные фазы и обычную цену Price после них
+func (s Subscription) InitialPlans() []SubscriptionPlan {
+	plans := make([]SubscriptionPlan, 0, len(s.PromoPhases)+2)
+	validFrom := s.StartDate
+
+	if s.TrialMonths > 0 {
+		plans = append(plans, SubscriptionPlan{
+			SubscriptionID: s.ID,
+			ValidFrom:      validFrom,
+			ServiceName:    s.ServiceName,
+			Price:          0,
+			Phase:          PlanPhaseTrial,
+			Currency:       s.Currency,
+		})
+		validFrom = validFrom.AddMonths(int(s.TrialMonths))
+	}
+
+	for _, phase := range s.PromoPhases {
+		plans = append(plans, SubscriptionPlan{
+			SubscriptionID: s.ID,
+			ValidFrom:      validFrom,
+			ServiceName:    s.ServiceName,
+			Price:          phase.Price,
+			Phase:          PlanPhasePromo,
+			Currency:       s.Currency,
+		})
+		validFrom = validFrom.AddMonths(int(phase.Months))
+	}
+
+	return append(plans, SubscriptionPlan{
+		SubscriptionID: s.ID,
+		ValidFrom:      validFrom,
+		ServiceName:    s.ServiceName,
+		Price:          s.Price,
+		Phase:          PlanPhaseRegular,
+		Currency:       s.Currency,
+	})
+}
+
+// Apply возвращает подписку после применения update
+func (s Subscription) Apply(update SubscriptionUpdate) Subscription {
+	if update.UserID != nil {
+		s.UserID = *update.UserID
+	}
+	if update.ServiceName != nil {
+		s.ServiceName = *update.ServiceName
+	}
+	if update.Price != nil {
+		s.Price = *update.Price
+	}
+	if update.Currency != nil {
+		s.Currency = *update.Currency
+	}
+	if update.BillingPeriod != nil {
+		s.BillingPeriod = *update.BillingPeriod
+	}
+	if update.StartDate != nil {
+		s.StartDate = *update.StartDate
+	}
+	if update.EndDate != nil {
+		s.EndDate = *update.EndDate
+	}
+	if update.ClearEndDate {
+		s.EndDate = YearMonth{}
+	}
+	if update.OverlapAllowed != nil {
+		s.OverlapAllowed = *update.OverlapAllowed
+	}
+
+	return s
+}
+
+// SubscriptionUpdate - частичное обновление подписки: изменяются только поля, отличные от nil.
+// ClearEndDate сбрасывает дату окончания, делая подписку бессрочной
+type SubscriptionUpdate struct {
+	UserID        *uuid.UUID     `json:"user_id,omitempty"`
+	ServiceName   *string        `json:"service_name,omitempty"`
+	Price         *int64         `json:"price,omitempty"`
+	Currency      *string        `json:"currency,omitempty"`
+	BillingPeriod *BillingPeriod `json:"billing_period,omitempty"`
+	StartDate     *YearMonth     `json:"start_date,omitempty"`
+	EndDate       *YearMonth     `json:"end_date,omitempty"`
+	ClearEndDate  bool           `json:"clear_end_date,omitempty"`
+	// PriceInMajorUnits - Price задана в целых единицах валюты (API v1), сервис переводит ее в минимальные единицы
+	PriceInMajorUnits bool `json:"-"`
+	// PriceCurrency - валюта, в которой задана Price, если клиент ее указал. Должна совпадать с валютой подписки
+	PriceCurrency string `json:"-"`
+	// OverlapAllowed задается сервисом по политике пересечений, если изменяются пользователь, сервис или период
+	OverlapAllowed *bool `json:"-"`
+	// PlanFrom задается сервисом, если изменяются цена или наименование: новые значения действуют с этого месяца,
+	// а суммы за прошлые месяцы остаются прежними
+	PlanFrom YearMonth `json:"-"`
+	// ExpectedVersion - версия подписки, которую видел клиент. Если она изменилась, изменение отклоняется
+	// с ErrVersionMismatch; 0 - версия не проверяется
+	ExpectedVersion int64 `json:"-"`
+}
+
+// ChangesPeriodOrOwner сообщает, меняет ли update пользователя, наименование или период подписки
+func (u SubscriptionUpdate) ChangesPeriodOrOwner() bool {
+	return u.UserID != nil || u.ServiceName != nil || u.StartDate != nil || u.EndDate != nil || u.ClearEndDate
 }
 
 type Filters struct {
-	StartDate   time.Time `json:"start_date"`
-	EndDate     time.Time `json:"end_date"`
+	StartDate   YearMonth `json:"start_date"`
+	EndDate     YearMonth `json:"end_date"`
 	UserID      uuid.UUID `json:"user_id,omitempty"`
 	ServiceName string    `json:"service_name,omitempty"`
+	Currency    string    `json:"currency,omitempty"`
 }
 
 type Pagination struct {
 	Page  int32 `json:"page"`
 	Count int32 `json:"count"`
 }
+
+// MonthlyCharge - списания по одной подписке за один месяц в валюте подписки
+type MonthlyCharge struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	ServiceName    string    `json:"service_name"`
+	Month          YearMonth `json:"month"`
+	Price          int64     `json:"price"`
+	Currency       string    `json:"currency"`
+	Charges        int32     `json:"charges"`
+}
+
+// Amount возвращает сумму списаний за месяц в минимальных единицах валюты
+func (c MonthlyCharge) Amount() int64 {
+	return c.Price * int64(c.Charges)
+}
+
+// TotalSum - сумма расходов за период в минимальных единицах валюты Currency
+type TotalSum struct {
+	Sum      int64          `json:"sum"`
+	Currency string         `json:"currency"`
+	Rates    []ExchangeRate `json:"rates"`
+}
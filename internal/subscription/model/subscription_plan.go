@@ -0,0 +1,37 @@
+package model
+
+import "github.com/google/uuid"
+
+// PlanPhase - фаза тарифа подписки
+type PlanPhase string
+
+const (
+	// PlanPhaseTrial - бесплатный пробный период
+	PlanPhaseTrial PlanPhase = "trial"
+	// PlanPhasePromo - льготная цена на первые месяцы подписки
+	PlanPhasePromo PlanPhase = "promo"
+	// PlanPhaseRegular - обычная цена
+	PlanPhaseRegular PlanPhase = "regular"
+)
+
+// PromoPhase - льготная фаза при создании подписки: Months месяцев по цене Price в минимальных единицах валюты.
+// Currency может быть пустой, иначе должна совпадать с валютой подписки
+type PromoPhase struct {
+	Months   int32  `json:"months"`
+	Price    int64  `json:"price"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// SubscriptionPlan - тариф подписки, действующий с месяца ValidFrom до начала следующего тарифа
+type SubscriptionPlan struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ValidFrom      YearMonth `json:"valid_from"`
+	ValidTo        YearMonth `json:"valid_to,omitzero"`
+	ServiceName    string    `json:"service_name"`
+	Price          int64     `json:"price"`
+	Phase          PlanPhase `json:"phase"`
+	// Currency - валюта подписки. При смене тарифа может быть пустой, иначе должна совпадать с валютой подписки
+	Currency string `json:"currency"`
+	// PriceInMajorUnits - Price задана в целых единицах валюты (API v1), сервис переводит ее в минимальные единицы
+	PriceInMajorUnits bool `json:"-"`
+}
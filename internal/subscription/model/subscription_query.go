@@ -0,0 +1,92 @@
+package model
+
+import "github.com/google/uuid"
+
+// ServiceNameMatch - способ сравнения наименования подписки при поиске
+type ServiceNameMatch string
+
+const (
+	ServiceNameMatchExact   ServiceNameMatch = "exact"
+	ServiceNameMatchPrefix  ServiceNameMatch = "prefix"
+	ServiceNameMatchSimilar ServiceNameMatch = "similar"
+)
+
+// SubscriptionOrderBy - поле сортировки списка подписок
+type SubscriptionOrderBy string
+
+const (
+	SubscriptionOrderByStartDate   SubscriptionOrderBy = "start_date"
+	SubscriptionOrderByPrice       SubscriptionOrderBy = "price"
+	SubscriptionOrderByServiceName SubscriptionOrderBy = "service_name"
+)
+
+// DeletedFilter - отбор подписок по признаку удаления
+type DeletedFilter string
+
+const (
+	// DeletedFilterExclude - только неудаленные подписки (по умолчанию)
+	DeletedFilterExclude DeletedFilter = "exclude"
+	// DeletedFilterInclude - удаленные и неудаленные подписки
+	DeletedFilterInclude DeletedFilter = "include"
+	// DeletedFilterOnly - только удаленные подписки
+	DeletedFilterOnly DeletedFilter = "only"
+)
+
+// SubscriptionFilter - условия отбора подписок, нулевые значения полей не ограничивают выборку
+type SubscriptionFilter struct {
+	UserID           uuid.UUID        `json:"user_id,omitempty"`
+	ServiceName      string           `json:"service_name,omitempty"`
+	ServiceNameMatch ServiceNameMatch `json:"service_name_match,omitempty"`
+	ActiveAt         YearMonth        `json:"active_at,omitzero"`
+	MinPrice         *int64           `json:"min_price,omitempty"`
+	MaxPrice         *int64           `json:"max_price,omitempty"`
+	OpenEndedOnly    bool             `json:"open_ended_only,omitempty"`
+	Deleted          DeletedFilter    `json:"deleted,omitempty"`
+
+	// PriceInMajorUnits - MinPrice и MaxPrice заданы в целых единицах валюты подписки (API v1), иначе в минимальных
+	PriceInMajorUnits bool `json:"price_in_major_units,omitempty"`
+	// PriceCurrency - валюта, в которой заданы MinPrice и MaxPrice: подписки в других валютах не попадают в выборку
+	PriceCurrency string `json:"price_currency,omitempty"`
+}
+
+// SubscriptionOrder - сортировка списка подписок. При равенстве значений подписки упорядочиваются по ID
+type SubscriptionOrder struct {
+	By   SubscriptionOrderBy `json:"by"`
+	Desc bool                `json:"desc,omitempty"`
+}
+
+// SubscriptionCursor - позиция в отсортированном списке подписок: значения ключей сортировки последней
+// выданной подписки. Следующая страница начинается строго после нее
+type SubscriptionCursor struct {
+	ID          uuid.UUID `json:"id"`
+	StartDate   YearMonth `json:"start_date"`
+	Price       int64     `json:"price"`
+	ServiceName string    `json:"service_name"`
+}
+
+func CursorOf(subscription Subscription) SubscriptionCursor {
+	return SubscriptionCursor{
+		ID:          subscription.ID,
+		StartDate:   subscription.StartDate,
+		Price:       subscription.Price,
+		ServiceName: subscription.ServiceName,
+	}
+}
+
+// SubscriptionQuery - запрос списка подписок: фильтр, сортировка и страница.
+// Если задан After, страница выбирается по курсору (keyset), иначе - по номеру страницы
+type SubscriptionQuery struct {
+	Filter        SubscriptionFilter  `json:"filter"`
+	Order         SubscriptionOrder   `json:"order"`
+	Pagination    Pagination          `json:"pagination"`
+	After         *SubscriptionCursor `json:"after,omitempty"`
+	WithTotalSize bool                `json:"with_total_size,omitempty"`
+}
+
+// SubscriptionPage - страница списка подписок. Next равен nil на последней странице,
+// TotalSize заполняется только по запросу
+type SubscriptionPage struct {
+	Subscriptions []Subscription      `json:"subscriptions"`
+	Next          *SubscriptionCursor `json:"next,omitempty"`
+	TotalSize     *int64              `json:"total_size,omitempty"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionStatus - статус жизненного цикла подписки
+type SubscriptionStatus string
+
+const (
+	// SubscriptionStatusTrial - пробный период
+	SubscriptionStatusTrial SubscriptionStatus = "trial"
+	// SubscriptionStatusActive - подписка оплачивается
+	SubscriptionStatusActive SubscriptionStatus = "active"
+	// SubscriptionStatusPaused - подписка приостановлена, месяцы приостановки не оплачиваются
+	SubscriptionStatusPaused SubscriptionStatus = "paused"
+	// SubscriptionStatusCancelled - подписка отменена и действует до месяца окончания включительно
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+	// SubscriptionStatusExpired - месяц окончания подписки прошел
+	SubscriptionStatusExpired SubscriptionStatus = "expired"
+)
+
+// subscriptionTransitions - допустимые переходы между статусами: trial → active → paused → cancelled → expired.
+// Приостановленная подписка может быть возобновлена, отменить можно любую подписку, кроме истекшей
+var subscriptionTransitions = map[SubscriptionStatus][]SubscriptionStatus{
+	SubscriptionStatusTrial:     {SubscriptionStatusActive, SubscriptionStatusCancelled, SubscriptionStatusExpired},
+	SubscriptionStatusActive:    {SubscriptionStatusPaused, SubscriptionStatusCancelled, SubscriptionStatusExpired},
+	SubscriptionStatusPaused:    {SubscriptionStatusActive, SubscriptionStatusCancelled, SubscriptionStatusExpired},
+	SubscriptionStatusCancelled: {SubscriptionStatusExpired},
+}
+
+// CanTransitionTo сообщает, допустим ли переход из статуса s в статус to
+func (s SubscriptionStatus) CanTransitionTo(to SubscriptionStatus) bool {
+	return slices.Contains(subscriptionTransitions[s], to)
+}
+
+// TransitionTo возвращает ErrInvalidStatusTransition, если переход из статуса s в статус to недопустим
+func (s SubscriptionStatus) TransitionTo(to SubscriptionStatus) error {
+	if !s.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, s, to)
+	}
+
+	return nil
+}
+
+// SubscriptionPause - приостановка подписки: месяцы с StartDate по EndDate включительно не оплачиваются.
+// EndDate равен нулю, пока подписка не возобновлена
+type SubscriptionPause struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	StartDate      YearMonth `json:"start_date"`
+	EndDate        YearMonth `json:"end_date,omitzero"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EndingTrial - подписка, пробный период которой скоро заканчивается. EndsOn - последний день пробного периода
+type EndingTrial struct {
+	Subscription Subscription `json:"subscription"`
+	EndsOn       time.Time    `json:"ends_on"`
+	DaysLeft     int32        `json:"days_left"`
+}
+
+// TrialFilter - отбор подписок, последний месяц пробного периода которых попадает в период с From по To
+type TrialFilter struct {
+	From   YearMonth `json:"from"`
+	To     YearMonth `json:"to"`
+	UserID uuid.UUID `json:"user_id,omitempty"`
+}
+
+// TrialEndsOn возвращает последний день пробного периода, который заканчивается месяцем trialEndDate
+func TrialEndsOn(trialEndDate YearMonth) time.Time {
+	return trialEndDate.AddMonths(1).Time().AddDate(0, 0, -1)
+}
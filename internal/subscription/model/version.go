@@ -0,0 +1,32 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag возвращает ETag подписки - ее версию в кавычках
+func (s Subscription) ETag() string {
+	return strconv.Quote(strconv.FormatInt(s.Version, 10))
+}
+
+// ParseETag возвращает версию подписки из ETag. Принимаются также слабые ETag (W/"3") и версия без кавычек.
+// Пустой ETag и "*" (любая версия) означают, что версия не проверяется, - для них возвращается 0
+func ParseETag(etag string) (int64, error) {
+	etag = strings.TrimSpace(etag)
+	if etag == "" || etag == "*" {
+		return 0, nil
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	if unquoted, err := strconv.Unquote(etag); err == nil {
+		etag = unquoted
+	}
+
+	version, err := strconv.ParseInt(etag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, ErrInvalidETag
+	}
+
+	return version, nil
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseETag(t *testing.T) {
+	assert.Equal(t, `"3"`, Subscription{Version: 3}.ETag())
+
+	for etag, expected := range map[string]int64{`"3"`: 3, `W/"3"`: 3, "3": 3, " \"12\" ": 12, "": 0, "*": 0} {
+		version, err := ParseETag(etag)
+		assert.NoError(t, err, etag)
+		assert.Equal(t, expected, version, etag)
+	}
+
+	for _, etag := range []string{`"abc"`, `"0"`, `"-1"`, `"3", "4"`, `"3`} {
+		_, err := ParseETag(etag)
+		assert.ErrorIs(t, err, ErrInvalidETag, etag)
+	}
+}
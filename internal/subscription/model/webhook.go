@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook - получатель событий об изменении подписок. Secret заполняется только при создании и ротации секрета:
+// после этого получить его нельзя
+type Webhook struct {
+	ID          uuid.UUID `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	// EventTypes - типы событий, которые получает вебхук. Пустой список - все события
+	EventTypes []EventType `json:"event_types"`
+	Secret     string      `json:"-"`
+	// PreviousSecretExpiresAt - до этого времени запросы подписываются и секретом до ротации
+	PreviousSecretExpiresAt time.Time `json:"previous_secret_expires_at,omitzero"`
+	CreatedAt               time.Time `json:"created_at"`
+	SecretRotatedAt         time.Time `json:"secret_rotated_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed - доставка не удалась за максимальное число попыток. Ее можно повторить вручную
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery - доставка события получателю. Payload - тело запроса, событие в формате JSON
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id"`
+	EventID        int64                 `json:"event_id"`
+	EventType      EventType             `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                 `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at,omitzero"`
+	LastAttemptAt  time.Time             `json:"last_attempt_at,omitzero"`
+	ResponseStatus int32                 `json:"response_status,omitzero"`
+	ResponseBody   string                `json:"response_body,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    time.Time             `json:"delivered_at,omitzero"`
+}
+
+// WebhookRequest - доставка, взятая в работу, с адресом получателя и действующими секретами для подписи
+type WebhookRequest struct {
+	DeliveryID int64
+	WebhookID  uuid.UUID
+	EventID    int64
+	EventType  EventType
+	Payload    []byte
+	Attempts   int32
+	URL        string
+	Secrets    []string
+}
+
+// WebhookResponse - результат попытки доставки. Err заполняется, если получатель не ответил
+// или ответил статусом не из 2xx
+type WebhookResponse struct {
+	Status int32
+	Body   string
+	Err    error
+}
+
+// WebhookDeliveryFilter - условия выборки журнала доставок вебхука
+type WebhookDeliveryFilter struct {
+	WebhookID uuid.UUID
+	// Status - статус доставок, пустой - все статусы
+	Status   WebhookDeliveryStatus
+	PageSize int
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// YearMonthLayout - формат месяца в API и JSON: MM-YYYY
+const YearMonthLayout = "01-2006"
+
+// YearMonth - календарный месяц без дня и времени. Нулевое значение означает, что месяц не задан
+// (например, месяц окончания бессрочной подписки)
+type YearMonth struct {
+	year  int
+	month time.Month
+}
+
+// NewYearMonth возвращает месяц month года year. Значения month вне диапазона 1..12 переносятся
+// на соседние годы так же, как в time.Date
+func NewYearMonth(year int, month time.Month) YearMonth {
+	t := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return YearMonth{year: t.Year(), month: t.Month()}
+}
+
+// YearMonthOf возвращает месяц, в который попадает t. Нулевому времени соответствует нулевой месяц
+func YearMonthOf(t time.Time) YearMonth {
+	if t.IsZero() {
+		return YearMonth{}
+	}
+
+	return YearMonth{year: t.Year(), month: t.Month()}
+}
+
+// ParseYearMonth разбирает месяц в формате MM-YYYY
+func ParseYearMonth(value string) (YearMonth, error) {
+	t, err := time.Parse(YearMonthLayout, value)
+	if err != nil || t.Year() < 1 {
+		return YearMonth{}, fmt.Errorf("%w: %q", ErrInvalidYearMonth, value)
+	}
+
+	return YearMonthOf(t), nil
+}
+
+func (m YearMonth) Year() int {
+	return m.year
+}
+
+func (m YearMonth) Month() time.Month {
+	return m.month
+}
+
+func (m YearMonth) IsZero() bool {
+	return m == YearMonth{}
+}
+
+// Time возвращает первое число месяца в UTC, для нулевого месяца - нулевое время
+func (m YearMonth) Time() time.Time {
+	if m.IsZero() {
+		return time.Time{}
+	}
+
+	return time.Date(m.year, m.month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Compare возвращает -1, 0 или 1, если m раньше, совпадает или позже other
+func (m YearMonth) Compare(other YearMonth) int {
+	switch diff := other.MonthsUntil(m); {
+	case diff < 0:
+		return -1
+	case diff > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m YearMonth) Before(other YearMonth) bool {
+	return m.Compare(other) < 0
+}
+
+func (m YearMonth) After(other YearMonth) bool {
+	return m.Compare(other) > 0
+}
+
+// AddMonths возвращает месяц, отстоящий от m на months месяцев (в прошлое при отрицательном months)
+func (m YearMonth) AddMonths(months int) YearMonth {
+	return NewYearMonth(m.year, m.month+time.Month(months))
+}
+
+// MonthsUntil возвращает число месяцев от m до other, отрицательное, если other раньше m
+func (m YearMonth) MonthsUntil(other YearMonth) int {
+	return (other.year-m.year)*12 + int(other.month) - int(m.month)
+}
+
+// String возвращает месяц в формате MM-YYYY, для нулевого месяца - пустую строку
+func (m YearMonth) String() string {
+	if m.IsZero() {
+		return ""
+	}
+
+	return m.Time().Format(YearMonthLayout)
+}
+
+// MarshalText кодирует месяц в формате MM-YYYY. Используется при кодировании в JSON
+func (m YearMonth) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText разбирает месяц в формате MM-YYYY, пустая строка соответствует нулевому месяцу
+func (m *YearMonth) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = YearMonth{}
+		return nil
+	}
+
+	month, err := ParseYearMonth(string(text))
+	if err != nil {
+		return err
+	}
+
+	*m = month
+	return nil
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseYearMonth(t *testing.T) {
+	month, err := ParseYearMonth("07-2025")
+	assert.NoError(t, err)
+	assert.Equal(t, NewYearMonth(2025, time.July), month)
+	assert.Equal(t, "07-2025", month.String())
+	assert.Equal(t, time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC), month.Time())
+
+	for _, value := range []string{"", "7-2025", "13-2025", "00-2025", "07-0000", "2025-07", "07-2025-01"} {
+		_, err := ParseYearMonth(value)
+		assert.ErrorIs(t, err, ErrInvalidYearMonth, value)
+	}
+}
+
+func TestYearMonth_Arithmetic(t *testing.T) {
+	january := NewYearMonth(2025, time.January)
+
+	assert.Equal(t, NewYearMonth(2024, time.December), january.AddMonths(-1))
+	assert.Equal(t, NewYearMonth(2026, time.March), january.AddMonths(14))
+	assert.Equal(t, NewYearMonth(2026, time.January), NewYearMonth(2025, 13))
+	assert.Equal(t, 14, january.MonthsUntil(NewYearMonth(2026, time.March)))
+	assert.Equal(t, -1, january.MonthsUntil(NewYearMonth(2024, time.December)))
+
+	assert.True(t, january.Before(january.AddMonths(1)))
+	assert.True(t, january.After(january.AddMonths(-1)))
+	assert.Equal(t, 0, january.Compare(NewYearMonth(2025, time.January)))
+	assert.Equal(t, -1, NewYearMonth(2024, time.December).Compare(january))
+}
+
+func TestYearMonth_Zero(t *testing.T) {
+	var zero YearMonth
+
+	assert.True(t, zero.IsZero())
+	assert.True(t, YearMonthOf(time.Time{}).IsZero())
+	assert.True(t, zero.Time().IsZero())
+	assert.Empty(t, zero.String())
+	assert.False(t, NewYearMonth(1, time.January).IsZero())
+}
+
+func TestYearMonth_JSON(t *testing.T) {
+	type period struct {
+		Start YearMonth `json:"start"`
+		End   YearMonth `json:"end,omitzero"`
+	}
+
+	data, err := json.Marshal(period{Start: NewYearMonth(2025, time.March)})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"start":"03-2025"}`, string(data))
+
+	var decoded period
+	assert.NoError(t, json.Unmarshal([]byte(`{"start":"03-2025","end":"12-2025"}`), &decoded))
+	assert.Equal(t, period{Start: NewYearMonth(2025, time.March), End: NewYearMonth(2025, time.December)}, decoded)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start":"2025-03-01"}`), &decoded), ErrInvalidYearMonth)
+}
@@ -0,0 +1,33 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+)
+
+type eventPublisher interface {
+	Publish(ctx context.Context, event model.Event) error
+}
+
+// FanoutPublisher публикует событие во все publishers. Если хотя бы один из них вернул ошибку, событие будет
+// опубликовано повторно во все, поэтому каждый получатель должен отбрасывать повторы
+type FanoutPublisher struct {
+	publishers []eventPublisher
+}
+
+func NewFanoutPublisher(publishers ...eventPublisher) *FanoutPublisher {
+	return &FanoutPublisher{publishers: publishers}
+}
+
+func (p *FanoutPublisher) Publish(ctx context.Context, event model.Event) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+)
+
+const (
+	// EventIDHeader - ID события, по которому получатель отбрасывает повторы
+	EventIDHeader = "X-Event-Id"
+	// EventTypeHeader - тип события
+	EventTypeHeader = "X-Event-Type"
+)
+
+// HTTPPublisher отправляет каждое событие POST-запросом с телом в формате JSON. Событие считается
+// доставленным, если получатель ответил статусом 2xx
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPPublisher(url string, timeout time.Duration) *HTTPPublisher {
+	return &HTTPPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event model.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, strconv.FormatInt(event.ID, 10))
+	request.Header.Set(EventTypeHeader, string(event.Type))
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// тело ответа дочитывается, чтобы соединение можно было переиспользовать
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("event receiver responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+)
+
+// LogPublisher пишет события в лог. Подходит для локального запуска и отладки
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(_ context.Context, event model.Event) error {
+	p.logger.Info("subscription event",
+		"id", event.ID,
+		"type", event.Type,
+		"subscription_id", event.SubscriptionID,
+		"action", event.Action,
+		"actor", event.Actor,
+		"request_id", event.RequestID,
+	)
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEvent(id int64) model.Event {
+	subscriptionID := uuid.New()
+
+	return model.Event{
+		ID:             id,
+		Type:           model.EventSubscriptionCreated,
+		SubscriptionID: subscriptionID,
+		Action:         model.AuditActionCreate,
+		Subscription:   &model.Subscription{ID: subscriptionID, ServiceName: "Yandex Plus"},
+		Actor:          "alice",
+		OccurredAt:     time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	publisher, err := NewFilePublisher(path)
+	assert.NoError(t, err)
+
+	first, second := testEvent(1), testEvent(2)
+	assert.NoError(t, publisher.Publish(context.Background(), first))
+	assert.NoError(t, publisher.Publish(context.Background(), second))
+	assert.NoError(t, publisher.Close())
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var events []model.Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event model.Event
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, first.ID, events[0].ID)
+		assert.Equal(t, first.SubscriptionID, events[0].SubscriptionID)
+		assert.Equal(t, second.ID, events[1].ID)
+	}
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	status := http.StatusNoContent
+	var received model.Event
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	publisher := NewHTTPPublisher(server.URL, time.Second)
+	event := testEvent(42)
+
+	assert.NoError(t, publisher.Publish(context.Background(), event))
+	assert.Equal(t, event.SubscriptionID, received.SubscriptionID)
+	assert.Equal(t, "42", headers.Get(EventIDHeader))
+	assert.Equal(t, string(model.EventSubscriptionCreated), headers.Get(EventTypeHeader))
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, publisher.Publish(context.Background(), event))
+}
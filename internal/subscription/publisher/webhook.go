@@ -0,0 +1,77 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/webhooksig"
+)
+
+const (
+	// WebhookIDHeader - ID вебхука, которому адресован запрос
+	WebhookIDHeader = "X-Webhook-Id"
+	// WebhookDeliveryIDHeader - ID доставки в журнале доставок
+	WebhookDeliveryIDHeader = "X-Webhook-Delivery-Id"
+
+	// maxWebhookResponseBody - сколько байт ответа получателя сохраняется в журнал доставок
+	maxWebhookResponseBody = 1 << 10
+)
+
+// WebhookSender отправляет событие получателю вебхука POST-запросом, подписанным HMAC-SHA256 (заголовок
+// X-Webhook-Signature). Перенаправления не выполняются: ответ 3xx считается неудачной доставкой
+type WebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, webhook model.WebhookRequest) model.WebhookResponse {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(webhook.Payload))
+	if err != nil {
+		return model.WebhookResponse{Err: err}
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, strconv.FormatInt(webhook.EventID, 10))
+	request.Header.Set(EventTypeHeader, string(webhook.EventType))
+	request.Header.Set(WebhookIDHeader, webhook.WebhookID.String())
+	request.Header.Set(WebhookDeliveryIDHeader, strconv.FormatInt(webhook.DeliveryID, 10))
+	request.Header.Set(webhooksig.Header, webhooksig.Sign(webhook.Payload, s.now(), webhook.Secrets...))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return model.WebhookResponse{Err: err}
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxWebhookResponseBody))
+	// остаток тела дочитывается, чтобы соединение можно было переиспользовать
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	result := model.WebhookResponse{
+		Status: int32(response.StatusCode),
+		Body:   string(bytes.ToValidUTF8(body, []byte(string(utf8.RuneError)))),
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		result.Err = fmt.Errorf("webhook receiver responded with status %d", response.StatusCode)
+	}
+
+	return result
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/Geriler/effective-mobile/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// auditChange - изменение одной подписки: before равен nil при создании, after - при окончательном удалении
+type auditChange struct {
+	before *model.Subscription
+	after  *model.Subscription
+}
+
+// writeAudit записывает изменения подписок в журнал аудита в транзакции cmd. Автор и ID запроса берутся из контекста
+func writeAudit(ctx context.Context, cmd *repository.Queries, action model.AuditAction, changes ...auditChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	meta := requestmeta.From(ctx)
+	params := make([]repository.CreateSubscriptionAuditRecordsParams, 0, len(changes))
+	for _, change := range changes {
+		record, err := auditRecordParams(meta, action, change)
+		if err != nil {
+			return err
+		}
+		params = append(params, record)
+	}
+
+	_, err := cmd.CreateSubscriptionAuditRecords(ctx, params)
+	return err
+}
+
+func auditRecordParams(meta requestmeta.Meta, action model.AuditAction, change auditChange) (repository.CreateSubscriptionAuditRecordsParams, error) {
+	var params repository.CreateSubscriptionAuditRecordsParams
+
+	subscription := change.after
+	if subscription == nil {
+		subscription = change.before
+	}
+
+	diff, err := model.DiffSubscriptions(change.before, change.after)
+	if err != nil {
+		return params, err
+	}
+
+	params = repository.CreateSubscriptionAuditRecordsParams{
+		TenantID:       subscription.TenantID,
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(subscription.ID),
+		Action:         string(action),
+		Actor:          meta.Actor,
+		ClaimedActor:   pgtype.Text{String: meta.ClaimedActor, Valid: meta.ClaimedActor != ""},
+		RequestID:      pgtype.Text{String: meta.RequestID, Valid: meta.RequestID != ""},
+	}
+	if params.Changes, err = json.Marshal(diff); err != nil {
+		return params, err
+	}
+	if change.before != nil {
+		if params.Before, err = json.Marshal(change.before); err != nil {
+			return params, err
+		}
+	}
+	if change.after != nil {
+		if params.After, err = json.Marshal(change.after); err != nil {
+			return params, err
+		}
+	}
+
+	return params, nil
+}
+
+// deletedChange - изменение для подписки, помеченной удаленной: состояние до удаления отличается только deleted_at
+func deletedChange(row repository.Subscription) (auditChange, error) {
+	after, err := subscriptionFromRow(row)
+	if err != nil {
+		return auditChange{}, err
+	}
+
+	before := *after
+	before.DeletedAt = time.Time{}
+	before.Version--
+
+	return auditChange{before: &before, after: after}, nil
+}
+
+// checkVersion проверяет, что заблокированная подписка не удалена и, если expected не равен нулю, что ее версия
+// не изменилась с тех пор, как ее видел клиент
+func checkVersion(subscription *model.Subscription, expected int64) error {
+	if !subscription.DeletedAt.IsZero() {
+		return model.ErrSubscriptionNotFound
+	}
+	if expected != 0 && subscription.Version != expected {
+		return model.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// lockSubscription возвращает подписку, в том числе удаленную, и блокирует ее до конца транзакции:
+// это состояние подписки до изменения для журнала аудита
+func lockSubscription(ctx context.Context, cmd *repository.Queries, id uuid.UUID) (*model.Subscription, error) {
+	row, err := cmd.LockSubscription(ctx, repository.LockSubscriptionParams{
+		TenantID:       tenant.From(ctx),
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptionFromRow(row)
+}
+
+// ListSubscriptionHistory возвращает журнал изменений подписки в порядке изменений. История сохраняется
+// и после окончательного удаления подписки
+func (r *PostgresSubscriptionRepository) ListSubscriptionHistory(ctx context.Context, id uuid.UUID) ([]model.AuditRecord, error) {
+	const op = "PostgresSubscriptionRepository.ListSubscriptionHistory"
+	logger := r.logger.With("op", op).With("subscription_id", id)
+
+	rows, err := r.cmd.ListSubscriptionAuditRecords(ctx, repository.ListSubscriptionAuditRecordsParams{
+		TenantID:       tenant.From(ctx),
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+	})
+	if err != nil {
+		logger.Error("failed to list subscription audit records", "error", err)
+		return nil, err
+	}
+
+	records := make([]model.AuditRecord, 0, len(rows))
+	for _, row := range rows {
+		record, err := auditRecordFromRow(row)
+		if err != nil {
+			logger.Error("failed to convert audit record row", "error", err)
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func auditRecordFromRow(row repository.SubscriptionAuditLog) (model.AuditRecord, error) {
+	record := model.AuditRecord{
+		ID:             row.ID,
+		SubscriptionID: uuid.UUID(row.SubscriptionID.Bytes),
+		Action:         model.AuditAction(row.Action),
+		Actor:          row.Actor,
+		ClaimedActor:   row.ClaimedActor.String,
+		RequestID:      row.RequestID.String,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+
+	if err := json.Unmarshal(row.Changes, &record.Changes); err != nil {
+		return record, err
+	}
+	if row.Before != nil {
+		if err := json.Unmarshal(row.Before, &record.Before); err != nil {
+			return record, err
+		}
+	}
+	if row.After != nil {
+		if err := json.Unmarshal(row.After, &record.After); err != nil {
+			return record, err
+		}
+	}
+
+	return record, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/utils"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func subscriptionFromRow(row repository.Subscription) (*model.Subscription, error) {
+	subscriptionID, err := utils.PgxUUIDToGoogleUUID(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("subscription_id: %w", err)
+	}
+
+	userID, err := utils.PgxUUIDToGoogleUUID(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user_id: %w", err)
+	}
+
+	return &model.Subscription{
+		ID:          subscriptionID,
+		TenantID:    row.TenantID,
+		UserID:      userID,
+		ServiceName: row.ServiceName,
+		Price:       row.Price,
+		Currency:    row.Currency,
+		BillingPeriod: model.BillingPeriod{
+			Unit:     model.BillingUnit(row.BillingUnit),
+			Interval: row.BillingInterval,
+		},
+		StartDate:      yearMonthFromPg(row.StartDate),
+		EndDate:        yearMonthFromPg(row.EndDate),
+		TrialEndDate:   yearMonthFromPg(row.TrialEndDate),
+		Status:         model.SubscriptionStatus(row.Status),
+		OverlapAllowed: row.OverlapAllowed,
+		DeletedAt:      timeFromPg(row.DeletedAt),
+		Version:        row.Version,
+	}, nil
+}
+
+func subscriptionsFromRows(rows []repository.Subscription) ([]model.Subscription, error) {
+	subscriptions := make([]model.Subscription, 0, len(rows))
+	for _, row := range rows {
+		subscription, err := subscriptionFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, nil
+}
+
+// yearMonthToPg возвращает первое число месяца, нулевой месяц сохраняется как NULL
+func yearMonthToPg(month model.YearMonth) pgtype.Date {
+	return pgtype.Date{
+		Time:  month.Time(),
+		Valid: !month.IsZero(),
+	}
+}
+
+func yearMonthFromPg(date pgtype.Date) model.YearMonth {
+	if !date.Valid {
+		return model.YearMonth{}
+	}
+
+	return model.NewYearMonth(date.Time.Year(), date.Time.Month())
+}
+
+// timeFromPg возвращает нулевое время для NULL
+func timeFromPg(timestamp pgtype.Timestamptz) time.Time {
+	if !timestamp.Valid {
+		return time.Time{}
+	}
+
+	return timestamp.Time
+}
+
+// isConflict сообщает, нарушает ли запись уникальность подписки или ограничение на пересечение периодов
+func isConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) &&
+		(pgErr.Code == pgerrcode.UniqueViolation || pgErr.Code == pgerrcode.ExclusionViolation)
+}
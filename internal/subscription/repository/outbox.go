@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/Geriler/effective-mobile/pkg/utils"
+)
+
+// recordChanges записывает изменения подписок в журнал аудита и события о них в outbox в транзакции cmd
+func recordChanges(ctx context.Context, cmd *repository.Queries, action model.AuditAction, changes ...auditChange) error {
+	if err := writeAudit(ctx, cmd, action, changes...); err != nil {
+		return err
+	}
+
+	return writeOutboxEvents(ctx, cmd, action, changes...)
+}
+
+// writeOutboxEvents записывает события об изменениях подписок в outbox. Порядок событий одной подписки
+// совпадает с порядком изменений: изменения подписки выполняются под блокировкой ее строки
+func writeOutboxEvents(ctx context.Context, cmd *repository.Queries, action model.AuditAction, changes ...auditChange) error {
+	meta := requestmeta.From(ctx)
+	now := time.Now()
+
+	params := make([]repository.CreateOutboxEventsParams, 0, len(changes))
+	for _, change := range changes {
+		eventType, ok := model.EventTypeOf(change.before, change.after)
+		if !ok {
+			continue
+		}
+
+		diff, err := model.DiffSubscriptions(change.before, change.after)
+		if err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(model.Event{
+			TenantID:       change.after.TenantID,
+			Type:           eventType,
+			SubscriptionID: change.after.ID,
+			Action:         action,
+			Subscription:   change.after,
+			Changes:        diff,
+			Actor:          meta.Actor,
+			RequestID:      meta.RequestID,
+			OccurredAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+
+		params = append(params, repository.CreateOutboxEventsParams{
+			TenantID:       change.after.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(change.after.ID),
+			UserID:         utils.GoogleUUIDToPgxUUID(change.after.UserID),
+			EventType:      string(eventType),
+			Payload:        payload,
+		})
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	_, err := cmd.CreateOutboxEvents(ctx, params)
+	return err
+}
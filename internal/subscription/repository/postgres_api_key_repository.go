@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/Geriler/effective-mobile/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresAPIKeyRepository struct {
+	cmd    *repository.Queries
+	logger *slog.Logger
+}
+
+func NewPostgresAPIKeyRepository(conn *pgxpool.Pool, logger *slog.Logger) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{
+		cmd:    repository.New(conn),
+		logger: logger,
+	}
+}
+
+// CreateAPIKey сохраняет ключ с хешем key.Hash в арендаторе запроса
+func (r *PostgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error) {
+	const op = "PostgresAPIKeyRepository.CreateAPIKey"
+	logger := r.logger.With("op", op).With("name", key.Name)
+
+	row, err := r.cmd.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
+		TenantID:  tenant.From(ctx),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.Hash,
+		Scopes:    key.Scopes,
+		UserID:    utils.GoogleUUIDToPgxUUID(key.UserID),
+		ExpiresAt: pgtype.Timestamptz{Time: key.ExpiresAt, Valid: !key.ExpiresAt.IsZero()},
+	})
+	if err != nil {
+		logger.Error("failed to create api key", "error", err)
+		return nil, err
+	}
+
+	return apiKeyFromRow(row)
+}
+
+func (r *PostgresAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	const op = "PostgresAPIKeyRepository.ListAPIKeys"
+	logger := r.logger.With("op", op)
+
+	rows, err := r.cmd.ListAPIKeys(ctx, tenant.From(ctx))
+	if err != nil {
+		logger.Error("failed to list api keys", "error", err)
+		return nil, err
+	}
+
+	keys := make([]model.APIKey, 0, len(rows))
+	for _, row := range rows {
+		key, err := apiKeyFromRow(row)
+		if err != nil {
+			logger.Error("failed to convert api key", "error", err)
+			return nil, err
+		}
+
+		keys = append(keys, *key)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ. Время отзыва уже отозванного ключа не меняется
+func (r *PostgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	const op = "PostgresAPIKeyRepository.RevokeAPIKey"
+	logger := r.logger.With("op", op).With("id", id)
+
+	row, err := r.cmd.RevokeAPIKey(ctx, repository.RevokeAPIKeyParams{
+		TenantID: tenant.From(ctx),
+		ID:       utils.GoogleUUIDToPgxUUID(id),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn("api key not found")
+		return nil, model.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		logger.Error("failed to revoke api key", "error", err)
+		return nil, err
+	}
+
+	return apiKeyFromRow(row)
+}
+
+// GetActiveAPIKey возвращает действующий ключ с хешем hash любого арендатора. Отозванный и истекший ключ не найдется
+func (r *PostgresAPIKeyRepository) GetActiveAPIKey(ctx context.Context, hash []byte) (*model.APIKey, error) {
+	const op = "PostgresAPIKeyRepository.GetActiveAPIKey"
+	logger := r.logger.With("op", op)
+
+	row, err := r.cmd.GetActiveAPIKeyByHash(ctx, hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		logger.Error("failed to get api key", "error", err)
+		return nil, err
+	}
+
+	return apiKeyFromRow(row)
+}
+
+// TouchAPIKey запоминает время использования ключа
+func (r *PostgresAPIKeyRepository) TouchAPIKey(ctx context.Context, id uuid.UUID) error {
+	const op = "PostgresAPIKeyRepository.TouchAPIKey"
+	logger := r.logger.With("op", op).With("id", id)
+
+	if err := r.cmd.TouchAPIKey(ctx, utils.GoogleUUIDToPgxUUID(id)); err != nil {
+		logger.Error("failed to touch api key", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func apiKeyFromRow(row repository.ApiKey) (*model.APIKey, error) {
+	id, err := utils.PgxUUIDToGoogleUUID(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("api_key_id: %w", err)
+	}
+
+	key := &model.APIKey{
+		ID:         id,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		Hash:       row.KeyHash,
+		Scopes:     row.Scopes,
+		TenantID:   row.TenantID,
+		CreatedAt:  timeFromPg(row.CreatedAt),
+		ExpiresAt:  timeFromPg(row.ExpiresAt),
+		LastUsedAt: timeFromPg(row.LastUsedAt),
+		RevokedAt:  timeFromPg(row.RevokedAt),
+	}
+	if row.UserID.Valid {
+		key.UserID = row.UserID.Bytes
+	}
+
+	return key, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresAPIKeyRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresAPIKeyRepository(pool, log)
+
+	userID := uuid.New()
+	reports, err := repo.CreateAPIKey(ctx, model.APIKey{
+		Name:   "monthly report",
+		Prefix: "emk_abcdef",
+		Hash:   []byte("reports-hash"),
+		Scopes: []string{model.ScopeReportsRead},
+		UserID: userID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, userID, reports.UserID)
+	assert.True(t, reports.LastUsedAt.IsZero())
+
+	_, err = repo.CreateAPIKey(ctx, model.APIKey{
+		Name:      "expired",
+		Prefix:    "emk_012345",
+		Hash:      []byte("expired-hash"),
+		Scopes:    []string{model.ScopeSubscriptionsRead},
+		ExpiresAt: time.Now().Add(time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	keys, err := repo.ListAPIKeys(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.Equal(t, uuid.Nil, keys[1].UserID)
+	}
+
+	active, err := repo.GetActiveAPIKey(ctx, []byte("reports-hash"))
+	require.NoError(t, err)
+	assert.Equal(t, reports.ID, active.ID)
+	assert.Equal(t, []string{model.ScopeReportsRead}, active.Scopes)
+
+	require.NoError(t, repo.TouchAPIKey(ctx, reports.ID))
+	active, err = repo.GetActiveAPIKey(ctx, []byte("reports-hash"))
+	require.NoError(t, err)
+	assert.False(t, active.LastUsedAt.IsZero())
+
+	time.Sleep(10 * time.Millisecond)
+	_, err = repo.GetActiveAPIKey(ctx, []byte("expired-hash"))
+	assert.ErrorIs(t, err, model.ErrAPIKeyNotFound)
+
+	revoked, err := repo.RevokeAPIKey(ctx, reports.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked.RevokedAt.IsZero())
+
+	// повторный отзыв не меняет время отзыва
+	again, err := repo.RevokeAPIKey(ctx, reports.ID)
+	require.NoError(t, err)
+	assert.Equal(t, revoked.RevokedAt, again.RevokedAt)
+
+	_, err = repo.GetActiveAPIKey(ctx, []byte("reports-hash"))
+	assert.ErrorIs(t, err, model.ErrAPIKeyNotFound)
+
+	_, err = repo.RevokeAPIKey(ctx, uuid.New())
+	assert.ErrorIs(t, err, model.ErrAPIKeyNotFound)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresExchangeRateRepository struct {
+	conn   *pgxpool.Pool
+	cmd    *repository.Queries
+	logger *slog.Logger
+}
+
+func NewPostgresExchangeRateRepository(conn *pgxpool.Pool, logger *slog.Logger) *PostgresExchangeRateRepository {
+	cmd := repository.New(conn)
+
+	return &PostgresExchangeRateRepository{
+		conn:   conn,
+		cmd:    cmd,
+		logger: logger,
+	}
+}
+
+func (r *PostgresExchangeRateRepository) SaveExchangeRates(ctx context.Context, rates []model.ExchangeRate) error {
+	const op = "PostgresExchangeRateRepository.SaveExchangeRates"
+	logger := r.logger.With("op", op).With("count", len(rates))
+
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		for _, rate := range rates {
+			err := cmd.UpsertExchangeRate(ctx, repository.UpsertExchangeRateParams{
+				Currency: rate.Currency,
+				ValidFrom: pgtype.Date{
+					Time:  rate.ValidFrom,
+					Valid: true,
+				},
+				Rate: rate.Rate.FloatString(10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed to save exchange rates", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostgresExchangeRateRepository) ListExchangeRates(ctx context.Context, currencies []string, startDate, endDate time.Time) ([]model.ExchangeRate, error) {
+	const op = "PostgresExchangeRateRepository.ListExchangeRates"
+	logger := r.logger.With("op", op).With("currencies", currencies)
+
+	rows, err := r.cmd.ListExchangeRatesForPeriod(ctx, repository.ListExchangeRatesForPeriodParams{
+		Currencies: currencies,
+		StartDate: pgtype.Date{
+			Time:  startDate,
+			Valid: true,
+		},
+		EndDate: pgtype.Date{
+			Time:  endDate,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		logger.Error("failed to list exchange rates", "error", err)
+		return nil, err
+	}
+
+	rates := make([]model.ExchangeRate, 0, len(rows))
+	for _, row := range rows {
+		rate, ok := new(big.Rat).SetString(row.Rate)
+		if !ok {
+			err = fmt.Errorf("invalid exchange rate %q for %s", row.Rate, row.Currency)
+			logger.Error("failed to parse exchange rate", "error", err)
+			return nil, err
+		}
+
+		rates = append(rates, model.ExchangeRate{
+			Currency:  row.Currency,
+			ValidFrom: row.ValidFrom.Time,
+			Rate:      rate,
+		})
+	}
+
+	return rates, nil
+}
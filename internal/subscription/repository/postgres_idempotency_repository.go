@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresIdempotencyRepository struct {
+	conn   *pgxpool.Pool
+	cmd    *repository.Queries
+	logger *slog.Logger
+}
+
+func NewPostgresIdempotencyRepository(conn *pgxpool.Pool, logger *slog.Logger) *PostgresIdempotencyRepository {
+	cmd := repository.New(conn)
+
+	return &PostgresIdempotencyRepository{
+		conn:   conn,
+		cmd:    cmd,
+		logger: logger,
+	}
+}
+
+// ClaimIdempotencyKey занимает ключ record.Key арендатора запроса. Возвращает false, если ключ уже занят другим запросом
+func (r *PostgresIdempotencyRepository) ClaimIdempotencyKey(ctx context.Context, record model.IdempotencyRecord, staleBefore time.Time) (bool, error) {
+	const op = "PostgresIdempotencyRepository.ClaimIdempotencyKey"
+	logger := r.logger.With("op", op).With("key", record.Key).With("method", record.Method)
+
+	_, err := r.cmd.ClaimIdempotencyKey(ctx, repository.ClaimIdempotencyKeyParams{
+		TenantID:    tenant.From(ctx),
+		Key:         record.Key,
+		Method:      record.Method,
+		RequestHash: record.RequestHash,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  record.ExpiresAt,
+			Valid: true,
+		},
+		StaleBefore: pgtype.Timestamptz{
+			Time:  staleBefore,
+			Valid: true,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		logger.Error("failed to claim idempotency key", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetIdempotencyKey возвращает nil, если ключа нет
+func (r *PostgresIdempotencyRepository) GetIdempotencyKey(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	const op = "PostgresIdempotencyRepository.GetIdempotencyKey"
+	logger := r.logger.With("op", op).With("key", key)
+
+	row, err := r.cmd.GetIdempotencyKey(ctx, repository.GetIdempotencyKeyParams{
+		TenantID: tenant.From(ctx),
+		Key:      key,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		logger.Error("failed to get idempotency key", "error", err)
+		return nil, err
+	}
+
+	return &model.IdempotencyRecord{
+		Key:         row.Key,
+		Method:      row.Method,
+		RequestHash: row.RequestHash,
+		Response:    row.Response,
+		CreatedAt:   row.CreatedAt.Time,
+		ExpiresAt:   row.ExpiresAt.Time,
+	}, nil
+}
+
+func (r *PostgresIdempotencyRepository) SaveIdempotencyResponse(ctx context.Context, key string, response []byte) error {
+	const op = "PostgresIdempotencyRepository.SaveIdempotencyResponse"
+	logger := r.logger.With("op", op).With("key", key)
+
+	err := r.cmd.SaveIdempotencyResponse(ctx, repository.SaveIdempotencyResponseParams{
+		TenantID: tenant.From(ctx),
+		Key:      key,
+		Response: response,
+	})
+	if err != nil {
+		logger.Error("failed to save idempotency response", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey освобождает ключ, если запрос с ним еще не завершен
+func (r *PostgresIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	const op = "PostgresIdempotencyRepository.DeleteIdempotencyKey"
+	logger := r.logger.With("op", op).With("key", key)
+
+	err := r.cmd.DeleteIdempotencyKey(ctx, repository.DeleteIdempotencyKeyParams{
+		TenantID: tenant.From(ctx),
+		Key:      key,
+	})
+	if err != nil {
+		logger.Error("failed to delete idempotency key", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostgresIdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	const op = "PostgresIdempotencyRepository.DeleteExpiredIdempotencyKeys"
+	logger := r.logger.With("op", op)
+
+	count, err := r.cmd.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		logger.Error("failed to delete expired idempotency keys", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresIdempotencyRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresIdempotencyRepository(pool, log)
+
+	now := time.Now()
+	record := model.IdempotencyRecord{
+		Key:         "key",
+		Method:      "/Add",
+		RequestHash: []byte("hash"),
+		ExpiresAt:   now.Add(time.Hour),
+	}
+
+	claimed, err := repo.ClaimIdempotencyKey(ctx, record, now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = repo.ClaimIdempotencyKey(ctx, record, now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	// незавершенный запрос можно занять повторно после истечения lease
+	claimed, err = repo.ClaimIdempotencyKey(ctx, record, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	assert.NoError(t, repo.SaveIdempotencyResponse(ctx, record.Key, []byte("response")))
+
+	claimed, err = repo.ClaimIdempotencyKey(ctx, record, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	saved, err := repo.GetIdempotencyKey(ctx, record.Key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("response"), saved.Response)
+	assert.Equal(t, record.RequestHash, saved.RequestHash)
+
+	// завершенный запрос не освобождается
+	assert.NoError(t, repo.DeleteIdempotencyKey(ctx, record.Key))
+	saved, err = repo.GetIdempotencyKey(ctx, record.Key)
+	assert.NoError(t, err)
+	assert.NotNil(t, saved)
+
+	expired := record
+	expired.Key = "expired"
+	expired.ExpiresAt = now.Add(-time.Second)
+	_, err = repo.ClaimIdempotencyKey(ctx, expired, now.Add(-time.Minute))
+	assert.NoError(t, err)
+
+	count, err := repo.DeleteExpiredIdempotencyKeys(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	missing, err := repo.GetIdempotencyKey(ctx, expired.Key)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/Geriler/effective-mobile/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// outboxLockKey - ключ advisory-блокировки, под которой события публикует только один экземпляр сервиса
+	outboxLockKey int64 = 0x6f7574626f78
+	// outboxNotifyChannel - канал, в который триггер subscription_outbox_notify отправляет уведомления о новых событиях
+	outboxNotifyChannel = "subscription_events"
+)
+
+type PostgresOutboxRepository struct {
+	conn   *pgxpool.Pool
+	cmd    *repository.Queries
+	logger *slog.Logger
+}
+
+func NewPostgresOutboxRepository(conn *pgxpool.Pool, logger *slog.Logger) *PostgresOutboxRepository {
+	cmd := repository.New(conn)
+
+	return &PostgresOutboxRepository{
+		conn:   conn,
+		cmd:    cmd,
+		logger: logger,
+	}
+}
+
+// LockOutbox занимает публикацию событий, чтобы несколько экземпляров сервиса не нарушали порядок событий.
+// Возвращает false, если публикацию уже занял другой экземпляр. Если публикация занята, по ее окончании
+// нужно вызвать release
+func (r *PostgresOutboxRepository) LockOutbox(ctx context.Context) (release func(), locked bool, err error) {
+	const op = "PostgresOutboxRepository.LockOutbox"
+	logger := r.logger.With("op", op)
+
+	conn, err := r.conn.Acquire(ctx)
+	if err != nil {
+		logger.Error("failed to acquire connection", "error", err)
+		return nil, false, err
+	}
+
+	cmd := repository.New(conn)
+	locked, err = cmd.TryAdvisoryLock(ctx, outboxLockKey)
+	if err != nil || !locked {
+		conn.Release()
+		if err != nil {
+			logger.Error("failed to lock outbox", "error", err)
+		}
+		return nil, false, err
+	}
+
+	release = func() {
+		// блокировка снимается и при закрытии соединения, поэтому при ошибке соединение не возвращается в пул
+		if err := cmd.AdvisoryUnlock(context.WithoutCancel(ctx), outboxLockKey); err != nil {
+			logger.Error("failed to unlock outbox", "error", err)
+			_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+		conn.Release()
+	}
+
+	return release, true, nil
+}
+
+// ListPendingOutboxEvents возвращает до limit неопубликованных событий всех арендаторов в порядке записи,
+// не больше одного - первого - события каждой подписки
+func (r *PostgresOutboxRepository) ListPendingOutboxEvents(ctx context.Context, limit int) ([]model.Event, error) {
+	const op = "PostgresOutboxRepository.ListPendingOutboxEvents"
+	logger := r.logger.With("op", op)
+
+	rows, err := r.cmd.ListPendingOutboxEvents(ctx, int32(limit))
+	if err != nil {
+		logger.Error("failed to list pending outbox events", "error", err)
+		return nil, err
+	}
+
+	events := make([]model.Event, 0, len(rows))
+	for _, row := range rows {
+		event, err := eventFromPayload(row.ID, row.TenantID, row.Payload)
+		if err != nil {
+			logger.Error("failed to decode outbox event", "id", row.ID, "error", err)
+			return nil, err
+		}
+		event.Attempts = row.Attempts
+
+		events = append(events, *event)
+	}
+
+	return events, nil
+}
+
+// ListenOutboxEvents слушает уведомления о новых событиях и передает их в notify до отмены ctx или потери соединения.
+// listening вызывается, когда подписка на уведомления оформлена: уведомления о событиях, записанных раньше, не приходят
+func (r *PostgresOutboxRepository) ListenOutboxEvents(ctx context.Context, listening func(), notify func(model.EventNotification)) error {
+	const op = "PostgresOutboxRepository.ListenOutboxEvents"
+	logger := r.logger.With("op", op)
+
+	conn, err := r.conn.Acquire(ctx)
+	if err != nil {
+		logger.Error("failed to acquire connection", "error", err)
+		return err
+	}
+	// соединение с подпиской на уведомления не возвращается в пул
+	defer func() {
+		_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		conn.Release()
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+outboxNotifyChannel); err != nil {
+		logger.Error("failed to listen outbox events", "error", err)
+		return err
+	}
+	listening()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			logger.Error("failed to wait for outbox event notification", "error", err)
+			return err
+		}
+
+		var payload struct {
+			ID       int64     `json:"id"`
+			TenantID string    `json:"tenant_id"`
+			UserID   uuid.UUID `json:"user_id"`
+		}
+		if err = json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			logger.Error("failed to decode outbox event notification", "payload", notification.Payload, "error", err)
+			continue
+		}
+
+		notify(model.EventNotification{ID: payload.ID, TenantID: payload.TenantID, UserID: payload.UserID})
+	}
+}
+
+// GetOutboxEvents возвращает события всех арендаторов с ID из ids в порядке записи. Удаленные события пропускаются
+func (r *PostgresOutboxRepository) GetOutboxEvents(ctx context.Context, ids []int64) ([]model.Event, error) {
+	const op = "PostgresOutboxRepository.GetOutboxEvents"
+	logger := r.logger.With("op", op).With("ids", ids)
+
+	rows, err := r.cmd.ListOutboxEventsByIDs(ctx, ids)
+	if err != nil {
+		logger.Error("failed to get outbox events", "error", err)
+		return nil, err
+	}
+
+	events := make([]model.Event, 0, len(rows))
+	for _, row := range rows {
+		event, err := eventFromPayload(row.ID, row.TenantID, row.Payload)
+		if err != nil {
+			logger.Error("failed to decode outbox event", "id", row.ID, "error", err)
+			return nil, err
+		}
+
+		events = append(events, *event)
+	}
+
+	return events, nil
+}
+
+// ListOutboxEventsAfter возвращает до limit событий арендатора запроса, записанных после события afterID, в порядке записи.
+// При ненулевом userID - только события подписок этого пользователя
+func (r *PostgresOutboxRepository) ListOutboxEventsAfter(ctx context.Context, afterID int64, userID uuid.UUID, limit int) ([]model.Event, error) {
+	const op = "PostgresOutboxRepository.ListOutboxEventsAfter"
+	logger := r.logger.With("op", op).With("after_id", afterID).With("user_id", userID)
+
+	params := repository.ListOutboxEventsAfterParams{
+		TenantID:  tenant.From(ctx),
+		AfterID:   afterID,
+		BatchSize: int32(limit),
+	}
+	if userID != uuid.Nil {
+		params.UserID = utils.GoogleUUIDToPgxUUID(userID)
+	}
+
+	rows, err := r.cmd.ListOutboxEventsAfter(ctx, params)
+	if err != nil {
+		logger.Error("failed to list outbox events", "error", err)
+		return nil, err
+	}
+
+	events := make([]model.Event, 0, len(rows))
+	for _, row := range rows {
+		event, err := eventFromPayload(row.ID, row.TenantID, row.Payload)
+		if err != nil {
+			logger.Error("failed to decode outbox event", "id", row.ID, "error", err)
+			return nil, err
+		}
+
+		events = append(events, *event)
+	}
+
+	return events, nil
+}
+
+// OutboxEventExists сообщает, хранится ли еще событие арендатора запроса с ID id
+func (r *PostgresOutboxRepository) OutboxEventExists(ctx context.Context, id int64) (bool, error) {
+	const op = "PostgresOutboxRepository.OutboxEventExists"
+	logger := r.logger.With("op", op).With("id", id)
+
+	exists, err := r.cmd.OutboxEventExists(ctx, repository.OutboxEventExistsParams{
+		TenantID: tenant.From(ctx),
+		ID:       id,
+	})
+	if err != nil {
+		logger.Error("failed to check outbox event", "error", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *PostgresOutboxRepository) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	const op = "PostgresOutboxRepository.MarkOutboxEventPublished"
+	logger := r.logger.With("op", op).With("id", id)
+
+	if err := r.cmd.MarkOutboxEventPublished(ctx, id); err != nil {
+		logger.Error("failed to mark outbox event published", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkOutboxEventFailed сохраняет ошибку публикации: событие остается неопубликованным и публикуется повторно.
+// При deadLetter попытки исчерпаны: событие больше не публикуется и не задерживает следующие события подписки
+func (r *PostgresOutboxRepository) MarkOutboxEventFailed(ctx context.Context, id int64, reason string, deadLetter bool) error {
+	const op = "PostgresOutboxRepository.MarkOutboxEventFailed"
+	logger := r.logger.With("op", op).With("id", id)
+
+	err := r.cmd.MarkOutboxEventFailed(ctx, repository.MarkOutboxEventFailedParams{
+		ID:         id,
+		LastError:  reason,
+		DeadLetter: deadLetter,
+	})
+	if err != nil {
+		logger.Error("failed to mark outbox event failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeletePublishedOutboxEvents удаляет события, опубликованные до момента before
+func (r *PostgresOutboxRepository) DeletePublishedOutboxEvents(ctx context.Context, before time.Time) (int64, error) {
+	const op = "PostgresOutboxRepository.DeletePublishedOutboxEvents"
+	logger := r.logger.With("op", op).With("before", before)
+
+	deleted, err := r.cmd.DeletePublishedOutboxEvents(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+	if err != nil {
+		logger.Error("failed to delete published outbox events", "error", err)
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// eventFromPayload берет арендатора из столбца: в событиях, записанных до разделения по арендаторам, его нет
+func eventFromPayload(id int64, tenantID string, payload []byte) (*model.Event, error) {
+	var event model.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	event.ID = id
+	event.TenantID = tenantID
+
+	return &event, nil
+}
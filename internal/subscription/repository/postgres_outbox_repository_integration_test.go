@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/internal/subscription/service"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresOutboxRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := requestmeta.With(context.Background(), requestmeta.Meta{RequestID: "req-1", Actor: "alice"})
+	log := logger.Setup("text", "warn")
+	subscriptionRepo := NewPostgresSubscriptionRepository(pool, log)
+	repo := NewPostgresOutboxRepository(pool, log)
+
+	created, err := subscriptionRepo.CreateSubscription(ctx, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   model.NewYearMonth(2025, 1),
+	})
+	assert.NoError(t, err)
+
+	price := int64(50000)
+	_, err = subscriptionRepo.UpdateSubscription(ctx, created.ID, model.SubscriptionUpdate{Price: &price})
+	assert.NoError(t, err)
+	assert.NoError(t, subscriptionRepo.DeleteSubscription(ctx, created.ID, 0))
+
+	// окончательное удаление не порождает события - подписка уже удалена для получателей
+	_, err = subscriptionRepo.PurgeDeletedSubscriptions(ctx, time.Now().Add(time.Hour), nil)
+	assert.NoError(t, err)
+
+	release, locked, err := repo.LockOutbox(ctx)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	_, lockedAgain, err := repo.LockOutbox(ctx)
+	assert.NoError(t, err)
+	assert.False(t, lockedAgain)
+
+	events, err := repo.ListOutboxEventsAfter(ctx, 0, uuid.Nil, 10)
+	assert.NoError(t, err)
+	if !assert.Len(t, events, 3) {
+		release()
+		return
+	}
+
+	assert.Equal(t, model.EventSubscriptionCreated, events[0].Type)
+	assert.Equal(t, model.EventSubscriptionUpdated, events[1].Type)
+	assert.Equal(t, model.EventSubscriptionDeleted, events[2].Type)
+	for _, event := range events {
+		assert.Equal(t, created.ID, event.SubscriptionID)
+		assert.Equal(t, "alice", event.Actor)
+		assert.Equal(t, "req-1", event.RequestID)
+	}
+	if assert.NotNil(t, events[1].Subscription) {
+		assert.Equal(t, int64(50000), events[1].Subscription.Price)
+	}
+
+	// публикуется только первое неопубликованное событие подписки
+	pending, err := repo.ListPendingOutboxEvents(ctx, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, events[0].ID, pending[0].ID)
+	}
+
+	assert.NoError(t, repo.MarkOutboxEventPublished(ctx, events[0].ID))
+	assert.NoError(t, repo.MarkOutboxEventFailed(ctx, events[1].ID, "connection refused", false))
+
+	pending, err = repo.ListPendingOutboxEvents(ctx, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, events[1].ID, pending[0].ID)
+		assert.Equal(t, int32(1), pending[0].Attempts)
+	}
+
+	// отложенное событие больше не задерживает следующее событие подписки
+	assert.NoError(t, repo.MarkOutboxEventFailed(ctx, events[1].ID, "connection refused", true))
+
+	pending, err = repo.ListPendingOutboxEvents(ctx, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, events[2].ID, pending[0].ID)
+	}
+
+	count, err := repo.DeletePublishedOutboxEvents(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	release()
+
+	release, locked, err = repo.LockOutbox(ctx)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	release()
+}
+
+type poisonedEventPublisher struct {
+	poisoned  uuid.UUID
+	published []uuid.UUID
+}
+
+func (p *poisonedEventPublisher) Publish(_ context.Context, event model.Event) error {
+	if event.SubscriptionID == p.poisoned {
+		return errors.New("receiver rejected the event")
+	}
+
+	p.published = append(p.published, event.SubscriptionID)
+	return nil
+}
+
+func TestPostgresOutboxRepository_RelayPoisonedSubscription(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	subscriptionRepo := NewPostgresSubscriptionRepository(pool, log)
+	repo := NewPostgresOutboxRepository(pool, log)
+
+	newSubscription := func(serviceName string) *model.Subscription {
+		created, err := subscriptionRepo.CreateSubscription(ctx, model.Subscription{
+			UserID:      uuid.New(),
+			ServiceName: serviceName,
+			Price:       40000,
+			Currency:    "RUB",
+			StartDate:   model.NewYearMonth(2025, 1),
+		})
+		assert.NoError(t, err)
+		return created
+	}
+
+	// событий подписки poisoned больше, чем помещается в порцию, и все они отклоняются получателем
+	poisoned := newSubscription("Yandex Plus")
+	for price := int64(41000); price <= 43000; price += 1000 {
+		_, err := subscriptionRepo.UpdateSubscription(ctx, poisoned.ID, model.SubscriptionUpdate{Price: &price})
+		assert.NoError(t, err)
+	}
+	healthy := newSubscription("Kinopoisk")
+
+	publisher := &poisonedEventPublisher{poisoned: poisoned.ID}
+	relay := service.NewOutboxRelay(repo, publisher, 2, 2)
+
+	published, failed, deadLettered, err := relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 1, failed)
+	assert.Zero(t, deadLettered)
+	assert.Equal(t, []uuid.UUID{healthy.ID}, publisher.published)
+
+	// вторая неудачная попытка откладывает событие, и в следующий раз выбирается следующее событие подписки
+	_, _, deadLettered, err = relay.Relay(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deadLettered)
+
+	pending, err := repo.ListPendingOutboxEvents(ctx, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, model.EventSubscriptionUpdated, pending[0].Type)
+		assert.Zero(t, pending[0].Attempts)
+	}
+}
+
+func TestPostgresOutboxRepository_Listen(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	log := logger.Setup("text", "warn")
+	subscriptionRepo := NewPostgresSubscriptionRepository(pool, log)
+	repo := NewPostgresOutboxRepository(pool, log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	listening := make(chan struct{})
+	notifications := make(chan model.EventNotification, 10)
+	stopped := make(chan error)
+	go func() {
+		stopped <- repo.ListenOutboxEvents(ctx, func() { close(listening) }, func(notification model.EventNotification) {
+			notifications <- notification
+		})
+	}()
+	<-listening
+
+	alice, bob := uuid.New(), uuid.New()
+	var created []*model.Subscription
+	for _, userID := range []uuid.UUID{alice, bob, alice} {
+		subscription, err := subscriptionRepo.CreateSubscription(context.Background(), model.Subscription{
+			UserID:      userID,
+			ServiceName: "Yandex Plus",
+			Price:       40000,
+			Currency:    "RUB",
+			StartDate:   model.NewYearMonth(2025, 1),
+		})
+		assert.NoError(t, err)
+		created = append(created, subscription)
+	}
+
+	var ids []int64
+	for _, subscription := range created {
+		select {
+		case notification := <-notifications:
+			assert.Equal(t, subscription.UserID, notification.UserID)
+			ids = append(ids, notification.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("notification was not received")
+		}
+	}
+
+	events, err := repo.GetOutboxEvents(context.Background(), ids[1:2])
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, created[1].ID, events[0].SubscriptionID)
+	}
+
+	events, err = repo.ListOutboxEventsAfter(context.Background(), ids[0], alice, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, ids[2], events[0].ID)
+	}
+
+	exists, err := repo.OutboxEventExists(context.Background(), ids[0])
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.OutboxEventExists(context.Background(), ids[2]+1)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	cancel()
+	assert.ErrorIs(t, <-stopped, context.Canceled)
+}
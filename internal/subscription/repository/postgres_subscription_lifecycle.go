@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/Geriler/effective-mobile/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// errStatusChanged - статус подписки изменился между проверкой перехода в сервисе и записью
+var errStatusChanged = fmt.Errorf("%w: subscription status was changed concurrently", model.ErrInvalidStatusTransition)
+
+// CancelSubscription отменяет подписку в статусе from: подписка действует до месяца endDate включительно
+func (r *PostgresSubscriptionRepository) CancelSubscription(ctx context.Context, id uuid.UUID, from model.SubscriptionStatus, endDate model.YearMonth) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.CancelSubscription"
+	logger := r.logger.With("op", op).With("subscription_id", id).With("end_date", endDate)
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		before, err := lockSubscription(ctx, cmd, id)
+		if err != nil {
+			return err
+		}
+
+		row, err := cmd.ChangeSubscriptionStatus(ctx, repository.ChangeSubscriptionStatusParams{
+			TenantID:       before.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+			FromStatus:     string(from),
+			Status:         string(model.SubscriptionStatusCancelled),
+			EndDate:        yearMonthToPg(endDate),
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionCancel, auditChange{before: before, after: result})
+	})
+	if err != nil {
+		logger.Error("failed to cancel subscription", "error", err)
+		return nil, statusChangeError(err)
+	}
+
+	return result, nil
+}
+
+// PauseSubscription приостанавливает подписку в статусе from начиная с месяца startDate
+func (r *PostgresSubscriptionRepository) PauseSubscription(ctx context.Context, id uuid.UUID, from model.SubscriptionStatus, startDate model.YearMonth) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.PauseSubscription"
+	logger := r.logger.With("op", op).With("subscription_id", id).With("start_date", startDate)
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		before, err := lockSubscription(ctx, cmd, id)
+		if err != nil {
+			return err
+		}
+
+		err = cmd.CreateSubscriptionPause(ctx, repository.CreateSubscriptionPauseParams{
+			TenantID:       before.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+			StartDate:      yearMonthToPg(startDate),
+		})
+		if err != nil {
+			return err
+		}
+
+		row, err := cmd.ChangeSubscriptionStatus(ctx, repository.ChangeSubscriptionStatusParams{
+			TenantID:       before.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+			FromStatus:     string(from),
+			Status:         string(model.SubscriptionStatusPaused),
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionPause, auditChange{before: before, after: result})
+	})
+	if err != nil {
+		logger.Error("failed to pause subscription", "error", err)
+
+		if isConflict(err) {
+			return nil, fmt.Errorf("%w: pause overlaps with a previous pause", model.ErrInvalidStatusTransition)
+		}
+		return nil, statusChangeError(err)
+	}
+
+	return result, nil
+}
+
+// ResumeSubscription возобновляет приостановленную подписку с месяца startDate: текущая приостановка
+// завершается предыдущим месяцем
+func (r *PostgresSubscriptionRepository) ResumeSubscription(ctx context.Context, id uuid.UUID, startDate model.YearMonth) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.ResumeSubscription"
+	logger := r.logger.With("op", op).With("subscription_id", id).With("start_date", startDate)
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		before, err := lockSubscription(ctx, cmd, id)
+		if err != nil {
+			return err
+		}
+
+		closed, err := cmd.CloseSubscriptionPause(ctx, repository.CloseSubscriptionPauseParams{
+			TenantID:       before.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+			EndDate:        yearMonthToPg(startDate.AddMonths(-1)),
+		})
+		if err != nil {
+			return err
+		}
+		if closed == 0 {
+			return errStatusChanged
+		}
+
+		row, err := cmd.ChangeSubscriptionStatus(ctx, repository.ChangeSubscriptionStatusParams{
+			TenantID:       before.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+			FromStatus:     string(model.SubscriptionStatusPaused),
+			Status:         string(model.SubscriptionStatusActive),
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionResume, auditChange{before: before, after: result})
+	})
+	if err != nil {
+		logger.Error("failed to resume subscription", "error", err)
+		return nil, statusChangeError(err)
+	}
+
+	return result, nil
+}
+
+func (r *PostgresSubscriptionRepository) ListSubscriptionPauses(ctx context.Context, id uuid.UUID) ([]model.SubscriptionPause, error) {
+	const op = "PostgresSubscriptionRepository.ListSubscriptionPauses"
+	logger := r.logger.With("op", op).With("subscription_id", id)
+
+	rows, err := r.cmd.ListSubscriptionPauses(ctx, repository.ListSubscriptionPausesParams{
+		TenantID:       tenant.From(ctx),
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+	})
+	if err != nil {
+		logger.Error("failed to list subscription pauses", "error", err)
+		return nil, err
+	}
+
+	pauses := make([]model.SubscriptionPause, 0, len(rows))
+	for _, row := range rows {
+		pauses = append(pauses, model.SubscriptionPause{
+			SubscriptionID: id,
+			StartDate:      yearMonthFromPg(row.StartDate),
+			EndDate:        yearMonthFromPg(row.EndDate),
+		})
+	}
+
+	return pauses, nil
+}
+
+// ExpireSubscriptions переводит в статус expired подписки всех арендаторов, закончившиеся до месяца before
+func (r *PostgresSubscriptionRepository) ExpireSubscriptions(ctx context.Context, before model.YearMonth) (int64, error) {
+	const op = "PostgresSubscriptionRepository.ExpireSubscriptions"
+	logger := r.logger.With("op", op).With("before", before)
+
+	var expired int64
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		rows, err := cmd.ExpireSubscriptions(ctx, yearMonthToPg(before))
+		if err != nil {
+			return err
+		}
+
+		changes := make([]auditChange, 0, len(rows))
+		for _, row := range rows {
+			after, err := subscriptionFromRow(repository.Subscription{
+				ID:              row.ID,
+				UserID:          row.UserID,
+				ServiceName:     row.ServiceName,
+				Price:           row.Price,
+				StartDate:       row.StartDate,
+				EndDate:         row.EndDate,
+				Currency:        row.Currency,
+				BillingUnit:     row.BillingUnit,
+				BillingInterval: row.BillingInterval,
+				OverlapAllowed:  row.OverlapAllowed,
+				Status:          row.Status,
+				TrialEndDate:    row.TrialEndDate,
+				DeletedAt:       row.DeletedAt,
+				Version:         row.Version,
+				TenantID:        row.TenantID,
+			})
+			if err != nil {
+				return err
+			}
+
+			previous := *after
+			previous.Status = model.SubscriptionStatus(row.PreviousStatus)
+			previous.Version--
+			changes = append(changes, auditChange{before: &previous, after: after})
+		}
+		expired = int64(len(rows))
+
+		return recordChanges(ctx, cmd, model.AuditActionExpire, changes...)
+	})
+	if err != nil {
+		logger.Error("failed to expire subscriptions", "error", err)
+		return 0, err
+	}
+
+	return expired, nil
+}
+
+// ActivateEndedTrials переводит в статус active подписки всех арендаторов, пробный период которых закончился
+// до месяца before
+func (r *PostgresSubscriptionRepository) ActivateEndedTrials(ctx context.Context, before model.YearMonth) (int64, error) {
+	const op = "PostgresSubscriptionRepository.ActivateEndedTrials"
+	logger := r.logger.With("op", op).With("before", before)
+
+	var activated int64
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		rows, err := cmd.ActivateEndedTrials(ctx, yearMonthToPg(before))
+		if err != nil {
+			return err
+		}
+
+		changes := make([]auditChange, 0, len(rows))
+		for _, row := range rows {
+			after, err := subscriptionFromRow(row)
+			if err != nil {
+				return err
+			}
+
+			previous := *after
+			previous.Status = model.SubscriptionStatusTrial
+			previous.Version--
+			changes = append(changes, auditChange{before: &previous, after: after})
+		}
+		activated = int64(len(rows))
+
+		return recordChanges(ctx, cmd, model.AuditActionActivate, changes...)
+	})
+	if err != nil {
+		logger.Error("failed to activate subscriptions with ended trials", "error", err)
+		return 0, err
+	}
+
+	return activated, nil
+}
+
+func (r *PostgresSubscriptionRepository) ListEndingTrials(ctx context.Context, filter model.TrialFilter) ([]model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.ListEndingTrials"
+	logger := r.logger.With("op", op).With("filter", filter)
+
+	rows, err := r.cmd.ListEndingTrials(ctx, repository.ListEndingTrialsParams{
+		TenantID: tenant.From(ctx),
+		FromDate: yearMonthToPg(filter.From),
+		ToDate:   yearMonthToPg(filter.To),
+		UserID: pgtype.UUID{
+			Bytes: filter.UserID,
+			Valid: filter.UserID != uuid.Nil,
+		},
+	})
+	if err != nil {
+		logger.Error("failed to list ending trials", "error", err)
+		return nil, err
+	}
+
+	subscriptions, err := subscriptionsFromRows(rows)
+	if err != nil {
+		logger.Error("failed to convert subscription row", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// statusChangeError переводит отсутствие обновленной строки в ошибку перехода: сервис уже проверил,
+// что подписка существует, значит ее статус изменился параллельным запросом
+func statusChangeError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errStatusChanged
+	}
+
+	return err
+}
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
 	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
 	"github.com/Geriler/effective-mobile/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
@@ -34,223 +36,647 @@ func NewPostgresSubscriptionRepository(conn *pgxpool.Pool, logger *slog.Logger)
 }
 
 func (r *PostgresSubscriptionRepository) CreateSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error) {
-	// На будущее - можно добавить транзакции, если потребуется, например, outbox
 	const op = "PostgresSubscriptionRepository.CreateSubscription"
 	logger := r.logger.With("op", op).With("subscription", subscription)
 
-	row, err := r.cmd.CreateSubscription(ctx, repository.CreateSubscriptionParams{
+	params := repository.CreateSubscriptionParams{
+		TenantID:    tenant.From(ctx),
 		UserID:      utils.GoogleUUIDToPgxUUID(subscription.UserID),
 		ServiceName: subscription.ServiceName,
 		Price:       subscription.Price,
-		StartDate: pgtype.Date{
-			Time:  subscription.StartDate,
-			Valid: true,
+		Currency:    subscription.Currency,
+		BillingUnit: pgtype.Text{
+			String: string(subscription.BillingPeriod.Unit),
+			Valid:  !subscription.BillingPeriod.IsZero(),
 		},
-		EndDate: pgtype.Date{
-			Time:  subscription.EndDate,
-			Valid: !subscription.EndDate.IsZero(),
+		BillingInterval: pgtype.Int4{
+			Int32: subscription.BillingPeriod.Interval,
+			Valid: !subscription.BillingPeriod.IsZero(),
 		},
+		StartDate:      yearMonthToPg(subscription.StartDate),
+		EndDate:        yearMonthToPg(subscription.EndDate),
+		OverlapAllowed: subscription.OverlapAllowed,
+		Status: pgtype.Text{
+			String: string(subscription.Status),
+			Valid:  subscription.Status != "",
+		},
+		TrialEndDate: yearMonthToPg(subscription.TrialEndDate),
+	}
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		row, err := cmd.CreateSubscription(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		for _, plan := range subscription.InitialPlans() {
+			err = cmd.UpsertSubscriptionPlan(ctx, repository.UpsertSubscriptionPlanParams{
+				TenantID:       row.TenantID,
+				SubscriptionID: row.ID,
+				ValidFrom:      yearMonthToPg(plan.ValidFrom),
+				ServiceName:    plan.ServiceName,
+				Price:          plan.Price,
+				Phase:          string(plan.Phase),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionCreate, auditChange{after: result})
 	})
 	if err != nil {
 		logger.Error("failed to create subscription", "error", err)
 
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		if isConflict(err) {
 			return nil, model.ErrSubscriptionAlreadyExists
 		}
 		return nil, err
 	}
 
-	subscriptionID, err := utils.PgxUUIDToGoogleUUID(row.ID)
+	return result, nil
+}
+
+func (r *PostgresSubscriptionRepository) GetSubscriptionById(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.GetSubscriptionById"
+	logger := r.logger.With("op", op).With("subscription_id", id)
+
+	subscription, err := r.cmd.GetSubscriptionById(ctx, repository.GetSubscriptionByIdParams{
+		TenantID:       tenant.From(ctx),
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn("subscription not found")
+		return nil, model.ErrSubscriptionNotFound
+	}
 	if err != nil {
-		logger.Warn("failed to convert pgx UUID to google UUID (subscription_id)", "error", err)
+		logger.Error("failed to get subscription", "error", err)
 		return nil, err
 	}
 
-	userID, err := utils.PgxUUIDToGoogleUUID(row.UserID)
+	result, err := subscriptionFromRow(subscription)
 	if err != nil {
-		logger.Warn("failed to convert pgx UUID to google UUID (user_id)", "error", err)
+		logger.Error("failed to convert subscription row", "error", err)
 		return nil, err
 	}
 
-	return &model.Subscription{
-		ID:          subscriptionID,
-		UserID:      userID,
-		ServiceName: row.ServiceName,
-		Price:       row.Price,
-		StartDate:   row.StartDate.Time,
-		EndDate:     row.EndDate.Time,
-	}, nil
+	return result, nil
 }
 
-func (r *PostgresSubscriptionRepository) GetSubscriptionById(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
-	const op = "PostgresSubscriptionRepository.GetSubscriptionById"
+// GetSubscriptionOwner возвращает пользователя подписки, в том числе удаленной
+func (r *PostgresSubscriptionRepository) GetSubscriptionOwner(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	const op = "PostgresSubscriptionRepository.GetSubscriptionOwner"
 	logger := r.logger.With("op", op).With("subscription_id", id)
 
-	subscription, err := r.cmd.GetSubscriptionById(ctx, utils.GoogleUUIDToPgxUUID(id))
-	if errors.Is(err, sql.ErrNoRows) {
+	userID, err := r.cmd.GetSubscriptionOwner(ctx, repository.GetSubscriptionOwnerParams{
+		TenantID:       tenant.From(ctx),
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
 		logger.Warn("subscription not found")
-		return nil, model.ErrSubscriptionNotFound
+		return uuid.Nil, model.ErrSubscriptionNotFound
 	}
 	if err != nil {
-		logger.Error("failed to get subscription", "error", err)
+		logger.Error("failed to get subscription owner", "error", err)
+		return uuid.Nil, err
+	}
+
+	return uuid.UUID(userID.Bytes), nil
+}
+
+func (r *PostgresSubscriptionRepository) AllSubscriptions(ctx context.Context, query model.SubscriptionQuery) ([]model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.AllSubscriptions"
+	logger := r.logger.With("op", op).With("query", query)
+
+	rows, err := r.cmd.AllSubscriptions(ctx, allSubscriptionsParams(tenant.From(ctx), query))
+	if err != nil {
+		logger.Error("failed to get all subscriptions", "error", err)
 		return nil, err
 	}
 
-	userID, err := utils.PgxUUIDToGoogleUUID(subscription.UserID)
+	subscriptions, err := subscriptionsFromRows(rows)
 	if err != nil {
-		logger.Error("failed to convert pgx UUID to google UUID", "error", err)
+		logger.Error("failed to convert subscription row", "error", err)
 		return nil, err
 	}
 
-	return &model.Subscription{
-		ID:          id,
-		UserID:      userID,
-		ServiceName: subscription.ServiceName,
-		Price:       subscription.Price,
-		StartDate:   subscription.StartDate.Time,
-		EndDate:     subscription.EndDate.Time,
-	}, nil
+	return subscriptions, nil
 }
 
-func (r *PostgresSubscriptionRepository) AllSubscriptions(ctx context.Context, params model.Pagination) ([]model.Subscription, error) {
-	const op = "PostgresSubscriptionRepository.AllSubscriptions"
-	logger := r.logger.With("op", op)
+func (r *PostgresSubscriptionRepository) CountSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (int64, error) {
+	const op = "PostgresSubscriptionRepository.CountSubscriptions"
+	logger := r.logger.With("op", op).With("filter", filter)
+
+	params := allSubscriptionsParams(tenant.From(ctx), model.SubscriptionQuery{Filter: filter})
+
+	count, err := r.cmd.CountSubscriptions(ctx, repository.CountSubscriptionsParams{
+		TenantID:           params.TenantID,
+		UserID:             params.UserID,
+		ServiceName:        params.ServiceName,
+		ServiceNamePattern: params.ServiceNamePattern,
+		ServiceNameSimilar: params.ServiceNameSimilar,
+		ActiveAt:           params.ActiveAt,
+		MinPrice:           params.MinPrice,
+		MaxPrice:           params.MaxPrice,
+		PriceInMajorUnits:  params.PriceInMajorUnits,
+		PriceCurrency:      params.PriceCurrency,
+		OpenEndedOnly:      params.OpenEndedOnly,
+		Deleted:            params.Deleted,
+	})
+	if err != nil {
+		logger.Error("failed to count subscriptions", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PostgresSubscriptionRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, update model.SubscriptionUpdate) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.UpdateSubscription"
+	logger := r.logger.With("op", op).With("subscription_id", id)
+
+	params := repository.UpdateSubscriptionParams{
+		TenantID:       tenant.From(ctx),
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+		ClearEndDate:   update.ClearEndDate,
+	}
+	if update.UserID != nil {
+		params.UserID = utils.GoogleUUIDToPgxUUID(*update.UserID)
+	}
+	if update.ServiceName != nil {
+		params.ServiceName = pgtype.Text{String: *update.ServiceName, Valid: true}
+	}
+	if update.Price != nil {
+		params.Price = pgtype.Int8{Int64: *update.Price, Valid: true}
+	}
+	if update.Currency != nil {
+		params.Currency = pgtype.Text{String: *update.Currency, Valid: true}
+	}
+	if update.BillingPeriod != nil {
+		params.BillingUnit = pgtype.Text{String: string(update.BillingPeriod.Unit), Valid: true}
+		params.BillingInterval = pgtype.Int4{Int32: update.BillingPeriod.Interval, Valid: true}
+	}
+	if update.StartDate != nil {
+		params.StartDate = yearMonthToPg(*update.StartDate)
+	}
+	if update.EndDate != nil {
+		params.EndDate = yearMonthToPg(*update.EndDate)
+	}
+	if update.OverlapAllowed != nil {
+		params.OverlapAllowed = pgtype.Bool{Bool: *update.OverlapAllowed, Valid: true}
+	}
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		before, err := lockSubscription(ctx, cmd, id)
+		if err != nil {
+			return err
+		}
+		if err = checkVersion(before, update.ExpectedVersion); err != nil {
+			return err
+		}
+
+		row, err := cmd.UpdateSubscription(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if !update.PlanFrom.IsZero() {
+			err = cmd.ChangeSubscriptionPlanFrom(ctx, repository.ChangeSubscriptionPlanFromParams{
+				TenantID:       row.TenantID,
+				SubscriptionID: row.ID,
+				ValidFrom:      yearMonthToPg(update.PlanFrom),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
 
-	rows, err := r.cmd.AllSubscriptions(ctx, repository.AllSubscriptionsParams{
-		Count: params.Count,
-		Page:  params.Page,
+		return recordChanges(ctx, cmd, model.AuditActionUpdate, auditChange{before: before, after: result})
 	})
-	if errors.Is(err, sql.ErrNoRows) {
-		logger.Warn("subscriptions not found")
-		return nil, model.ErrSubscriptionNotFound
+	if err != nil {
+		logger.Error("failed to update subscription", "error", err)
+
+		switch {
+		case isConflict(err):
+			return nil, model.ErrSubscriptionAlreadyExists
+		case errors.Is(err, model.ErrSubscriptionNotFound), errors.Is(err, pgx.ErrNoRows):
+			return nil, model.ErrSubscriptionNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteSubscription помечает подписку удаленной. Если expectedVersion не равен нулю, а версия подписки
+// изменилась, возвращает model.ErrVersionMismatch
+func (r *PostgresSubscriptionRepository) DeleteSubscription(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
+	const op = "PostgresSubscriptionRepository.DeleteSubscription"
+	logger := r.logger.With("op", op).With("subscription_id", id)
+
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		before, err := lockSubscription(ctx, cmd, id)
+		if err != nil {
+			return err
+		}
+		if err = checkVersion(before, expectedVersion); err != nil {
+			return err
+		}
+
+		row, err := cmd.DeleteSubscription(ctx, repository.DeleteSubscriptionParams{
+			TenantID:       tenant.From(ctx),
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+		})
+		if err != nil {
+			return err
+		}
+
+		after, err := subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionDelete, auditChange{before: before, after: after})
+	})
+	if errors.Is(err, model.ErrSubscriptionNotFound) || errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn("subscription not found")
+		return model.ErrSubscriptionNotFound
 	}
 	if err != nil {
-		logger.Error("failed to get all subscriptions", "error", err)
-		return nil, err
+		logger.Error("failed to delete subscription", "error", err)
+		return err
 	}
 
-	subscriptions := make([]model.Subscription, 0, len(rows))
-	for _, row := range rows {
-		subscriptionID, err := utils.PgxUUIDToGoogleUUID(row.ID)
+	return nil
+}
+
+// UndeleteSubscription восстанавливает удаленную подписку. Если подписки нет или она не удалена,
+// возвращает model.ErrSubscriptionNotFound
+func (r *PostgresSubscriptionRepository) UndeleteSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.UndeleteSubscription"
+	logger := r.logger.With("op", op).With("subscription_id", id)
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		before, err := lockSubscription(ctx, cmd, id)
 		if err != nil {
-			logger.Error("failed to convert pgx UUID to google UUID (subscription_id)", "error", err)
-			return nil, err
+			return err
 		}
 
-		userID, err := utils.PgxUUIDToGoogleUUID(row.UserID)
+		row, err := cmd.UndeleteSubscription(ctx, repository.UndeleteSubscriptionParams{
+			TenantID:       tenant.From(ctx),
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+		})
 		if err != nil {
-			logger.Error("failed to convert pgx UUID to google UUID (user_id)", "error", err)
+			return err
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionUndelete, auditChange{before: before, after: result})
+	})
+	if err != nil {
+		logger.Error("failed to undelete subscription", "error", err)
+
+		switch {
+		case errors.Is(err, model.ErrSubscriptionNotFound), errors.Is(err, pgx.ErrNoRows):
+			return nil, model.ErrSubscriptionNotFound
+		// пока подписка была удалена, на тот же период могла быть создана другая
+		case isConflict(err):
+			return nil, model.ErrSubscriptionAlreadyExists
+		default:
 			return nil, err
 		}
+	}
+
+	return result, nil
+}
+
+// PurgeDeletedSubscriptions окончательно удаляет подписки всех арендаторов, удаленные до момента before. Для арендаторов
+// из tenantBefore вместо before действует их момент
+func (r *PostgresSubscriptionRepository) PurgeDeletedSubscriptions(ctx context.Context, before time.Time, tenantBefore map[string]time.Time) (int64, error) {
+	const op = "PostgresSubscriptionRepository.PurgeDeletedSubscriptions"
+	logger := r.logger.With("op", op).With("before", before)
+
+	params := repository.PurgeDeletedSubscriptionsParams{
+		TenantIds:     make([]string, 0, len(tenantBefore)),
+		TenantBefores: make([]pgtype.Timestamptz, 0, len(tenantBefore)),
+		Before:        pgtype.Timestamptz{Time: before, Valid: true},
+	}
+	for tenantID, tenantBefore := range tenantBefore {
+		params.TenantIds = append(params.TenantIds, tenantID)
+		params.TenantBefores = append(params.TenantBefores, pgtype.Timestamptz{Time: tenantBefore, Valid: true})
+	}
+
+	var purged int64
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		rows, err := cmd.PurgeDeletedSubscriptions(ctx, params)
+		if err != nil {
+			return err
+		}
 
-		subscription := model.Subscription{
-			ID:          subscriptionID,
-			UserID:      userID,
-			ServiceName: row.ServiceName,
-			Price:       row.Price,
-			StartDate:   row.StartDate.Time,
-			EndDate:     row.EndDate.Time,
+		changes := make([]auditChange, 0, len(rows))
+		for _, row := range rows {
+			subscription, err := subscriptionFromRow(row)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, auditChange{before: subscription})
 		}
-		subscriptions = append(subscriptions, subscription)
+		purged = int64(len(rows))
+
+		return recordChanges(ctx, cmd, model.AuditActionPurge, changes...)
+	})
+	if err != nil {
+		logger.Error("failed to purge deleted subscriptions", "error", err)
+		return 0, err
 	}
 
-	return subscriptions, nil
+	return purged, nil
 }
 
-func (r *PostgresSubscriptionRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, subscription model.Subscription) (*model.Subscription, error) {
-	// На будущее - можно добавить транзакции, если потребуется, например, outbox
-	const op = "PostgresSubscriptionRepository.UpdateSubscription"
-	logger := r.logger.With("op", op).With("subscription_id", id)
+// FindOverlappingSubscriptions возвращает подписки того же пользователя на тот же сервис, периоды которых
+// пересекаются с периодом subscription. Подписка excludeID не учитывается
+func (r *PostgresSubscriptionRepository) FindOverlappingSubscriptions(ctx context.Context, subscription model.Subscription, excludeID uuid.UUID) ([]model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.FindOverlappingSubscriptions"
+	logger := r.logger.With("op", op).With("subscription", subscription)
 
-	params := repository.UpdateSubscriptionParams{
-		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
-		UserID:         utils.GoogleUUIDToPgxUUID(subscription.UserID),
-		ServiceName: pgtype.Text{
-			String: subscription.ServiceName,
-			Valid:  subscription.ServiceName != "",
-		},
-		Price: pgtype.Int4{
-			Int32: subscription.Price,
-			Valid: true,
+	rows, err := r.cmd.FindOverlappingSubscriptions(ctx, repository.FindOverlappingSubscriptionsParams{
+		TenantID:    tenant.From(ctx),
+		UserID:      utils.GoogleUUIDToPgxUUID(subscription.UserID),
+		ServiceName: subscription.ServiceName,
+		StartDate:   yearMonthToPg(subscription.StartDate),
+		EndDate:     yearMonthToPg(subscription.EndDate),
+		ExcludeID: pgtype.UUID{
+			Bytes: excludeID,
+			Valid: excludeID != uuid.Nil,
 		},
-		StartDate: pgtype.Date{
-			Time:  subscription.StartDate,
-			Valid: !subscription.StartDate.IsZero(),
+	})
+	if err != nil {
+		logger.Error("failed to find overlapping subscriptions", "error", err)
+		return nil, err
+	}
+
+	subscriptions, err := subscriptionsFromRows(rows)
+	if err != nil {
+		logger.Error("failed to convert subscription row", "error", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *PostgresSubscriptionRepository) ListSubscriptionOverlaps(ctx context.Context, filter model.OverlapFilter) ([]model.SubscriptionOverlap, error) {
+	const op = "PostgresSubscriptionRepository.ListSubscriptionOverlaps"
+	logger := r.logger.With("op", op).With("filter", filter)
+
+	rows, err := r.cmd.ListSubscriptionOverlaps(ctx, repository.ListSubscriptionOverlapsParams{
+		TenantID: tenant.From(ctx),
+		UserID: pgtype.UUID{
+			Bytes: filter.UserID,
+			Valid: filter.UserID != uuid.Nil,
 		},
-		EndDate: pgtype.Date{
-			Time:  subscription.EndDate,
-			Valid: !subscription.EndDate.IsZero(),
+		ServiceName: pgtype.Text{
+			String: filter.ServiceName,
+			Valid:  filter.ServiceName != "",
 		},
+	})
+	if err != nil {
+		logger.Error("failed to list subscription overlaps", "error", err)
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []model.SubscriptionOverlap{}, nil
+	}
+
+	ids := make([]pgtype.UUID, 0, 2*len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.SubscriptionID, row.OverlappingID)
 	}
 
-	row, err := r.cmd.UpdateSubscription(ctx, params)
+	subscriptionRows, err := r.cmd.ListSubscriptionsByIDs(ctx, repository.ListSubscriptionsByIDsParams{
+		TenantID: tenant.From(ctx),
+		Ids:      ids,
+	})
 	if err != nil {
-		logger.Error("failed to update subscription", "error", err)
+		logger.Error("failed to list overlapping subscriptions", "error", err)
+		return nil, err
+	}
 
-		var pgErr *pgconn.PgError
-		switch {
-		case errors.As(err, &pgErr):
-			switch pgErr.Code {
-			case pgerrcode.UniqueViolation:
-				return nil, model.ErrSubscriptionAlreadyExists
+	subscriptions, err := subscriptionsFromRows(subscriptionRows)
+	if err != nil {
+		logger.Error("failed to convert subscription row", "error", err)
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]model.Subscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		byID[subscription.ID] = subscription
+	}
+
+	overlaps := make([]model.SubscriptionOverlap, 0, len(rows))
+	for _, row := range rows {
+		overlaps = append(overlaps, model.SubscriptionOverlap{
+			Subscription: byID[uuid.UUID(row.SubscriptionID.Bytes)],
+			Overlapping:  byID[uuid.UUID(row.OverlappingID.Bytes)],
+			Start:        yearMonthFromPg(row.OverlapStart),
+			End:          yearMonthFromPg(row.OverlapEnd),
+		})
+	}
+
+	return overlaps, nil
+}
+
+// MergeSubscriptions удаляет подписки mergedIDs и переносит период startDate..endDate в подписку id
+func (r *PostgresSubscriptionRepository) MergeSubscriptions(ctx context.Context, id uuid.UUID, mergedIDs []uuid.UUID, startDate, endDate model.YearMonth) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.MergeSubscriptions"
+	logger := r.logger.With("op", op).With("subscription_id", id).With("merged_subscription_ids", mergedIDs)
+
+	ids := make([]pgtype.UUID, 0, len(mergedIDs))
+	for _, mergedID := range mergedIDs {
+		ids = append(ids, utils.GoogleUUIDToPgxUUID(mergedID))
+	}
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
+
+		before, err := lockSubscription(ctx, cmd, id)
+		if err != nil {
+			return err
+		}
+
+		deletedRows, err := cmd.DeleteSubscriptionsByIDs(ctx, repository.DeleteSubscriptionsByIDsParams{
+			TenantID: tenant.From(ctx),
+			Ids:      ids,
+		})
+		if err != nil {
+			return err
+		}
+		if len(deletedRows) != len(ids) {
+			return model.ErrSubscriptionNotFound
+		}
+
+		row, err := cmd.MergeSubscriptionPeriod(ctx, repository.MergeSubscriptionPeriodParams{
+			TenantID:       tenant.From(ctx),
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+			StartDate:      yearMonthToPg(startDate),
+			EndDate:        yearMonthToPg(endDate),
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		changes := []auditChange{{before: before, after: result}}
+		for _, deletedRow := range deletedRows {
+			change, err := deletedChange(deletedRow)
+			if err != nil {
+				return err
 			}
-		case errors.Is(err, pgx.ErrNoRows):
+			changes = append(changes, change)
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionMerge, changes...)
+	})
+	if err != nil {
+		logger.Error("failed to merge subscriptions", "error", err)
+
+		switch {
+		case errors.Is(err, model.ErrSubscriptionNotFound), errors.Is(err, pgx.ErrNoRows):
 			return nil, model.ErrSubscriptionNotFound
+		case isConflict(err):
+			return nil, model.ErrSubscriptionAlreadyExists
 		default:
 			return nil, err
 		}
 	}
 
-	subscriptionID, err := utils.PgxUUIDToGoogleUUID(row.ID)
-	if err != nil {
-		logger.Warn("failed to convert pgx UUID to google UUID (subscription_id)", "error", err)
-		return nil, err
-	}
+	return result, nil
+}
+
+func (r *PostgresSubscriptionRepository) ChangeSubscriptionPlan(ctx context.Context, plan model.SubscriptionPlan) (*model.Subscription, error) {
+	const op = "PostgresSubscriptionRepository.ChangeSubscriptionPlan"
+	logger := r.logger.With("op", op).With("plan", plan)
+
+	var result *model.Subscription
+	err := pgx.BeginFunc(ctx, r.conn, func(tx pgx.Tx) error {
+		cmd := r.cmd.WithTx(tx)
 
-	userID, err := utils.PgxUUIDToGoogleUUID(row.UserID)
+		before, err := lockSubscription(ctx, cmd, plan.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		if !before.DeletedAt.IsZero() {
+			return model.ErrSubscriptionNotFound
+		}
+
+		err = cmd.UpsertSubscriptionPlan(ctx, repository.UpsertSubscriptionPlanParams{
+			TenantID:       before.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(plan.SubscriptionID),
+			ValidFrom:      yearMonthToPg(plan.ValidFrom),
+			ServiceName:    plan.ServiceName,
+			Price:          plan.Price,
+			Phase:          string(model.PlanPhaseRegular),
+		})
+		if err != nil {
+			return err
+		}
+
+		row, err := cmd.SyncSubscriptionWithLatestPlan(ctx, repository.SyncSubscriptionWithLatestPlanParams{
+			TenantID:       before.TenantID,
+			SubscriptionID: utils.GoogleUUIDToPgxUUID(plan.SubscriptionID),
+		})
+		if err != nil {
+			return err
+		}
+
+		result, err = subscriptionFromRow(row)
+		if err != nil {
+			return err
+		}
+
+		return recordChanges(ctx, cmd, model.AuditActionChangePlan, auditChange{before: before, after: result})
+	})
 	if err != nil {
-		logger.Warn("failed to convert pgx UUID to google UUID (user_id)", "error", err)
+		logger.Error("failed to change subscription plan", "error", err)
+
+		var pgErr *pgconn.PgError
+		if errors.Is(err, model.ErrSubscriptionNotFound) || errors.Is(err, pgx.ErrNoRows) ||
+			(errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation) {
+			return nil, model.ErrSubscriptionNotFound
+		}
 		return nil, err
 	}
 
-	return &model.Subscription{
-		ID:          subscriptionID,
-		UserID:      userID,
-		ServiceName: row.ServiceName,
-		Price:       row.Price,
-		StartDate:   row.StartDate.Time,
-		EndDate:     row.EndDate.Time,
-	}, nil
+	return result, nil
 }
 
-func (r *PostgresSubscriptionRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	// На будущее - можно добавить транзакции, если потребуется, например, outbox
-	const op = "PostgresSubscriptionRepository.DeleteSubscription"
+func (r *PostgresSubscriptionRepository) ListSubscriptionPlans(ctx context.Context, id uuid.UUID) ([]model.SubscriptionPlan, error) {
+	const op = "PostgresSubscriptionRepository.ListSubscriptionPlans"
 	logger := r.logger.With("op", op).With("subscription_id", id)
 
-	err := r.cmd.DeleteSubscription(ctx, utils.GoogleUUIDToPgxUUID(id))
+	rows, err := r.cmd.ListSubscriptionPlans(ctx, repository.ListSubscriptionPlansParams{
+		TenantID:       tenant.From(ctx),
+		SubscriptionID: utils.GoogleUUIDToPgxUUID(id),
+	})
 	if err != nil {
-		logger.Error("failed to delete subscription", "error", err)
-		return err
+		logger.Error("failed to list subscription plans", "error", err)
+		return nil, err
 	}
 
-	return nil
+	plans := make([]model.SubscriptionPlan, 0, len(rows))
+	for _, row := range rows {
+		plans = append(plans, model.SubscriptionPlan{
+			SubscriptionID: id,
+			ValidFrom:      yearMonthFromPg(row.ValidFrom),
+			ServiceName:    row.ServiceName,
+			Price:          row.Price,
+			Phase:          model.PlanPhase(row.Phase),
+		})
+	}
+
+	return plans, nil
 }
 
-func (r *PostgresSubscriptionRepository) GetSumSubscriptions(ctx context.Context, filters model.Filters) (int32, error) {
-	const op = "PostgresSubscriptionRepository.GetSubscriptionsByFilters"
+func (r *PostgresSubscriptionRepository) ListMonthlyCharges(ctx context.Context, filters model.Filters) ([]model.MonthlyCharge, error) {
+	const op = "PostgresSubscriptionRepository.ListMonthlyCharges"
 	logger := r.logger.With("op", op).With("filters", filters)
 
-	params := repository.GetSumSubscriptionsParams{
-		StartDate: pgtype.Date{
-			Time:  filters.StartDate,
-			Valid: true,
-		},
-		EndDate: pgtype.Date{
-			Time:  filters.EndDate,
-			Valid: true,
-		},
+	params := repository.ListMonthlyChargesParams{
+		TenantID:  tenant.From(ctx),
+		StartDate: yearMonthToPg(filters.StartDate),
+		EndDate:   yearMonthToPg(filters.EndDate),
 		UserID: pgtype.UUID{
 			Bytes: filters.UserID,
 			Valid: filters.UserID != uuid.Nil,
@@ -261,11 +687,93 @@ func (r *PostgresSubscriptionRepository) GetSumSubscriptions(ctx context.Context
 		},
 	}
 
-	sum, err := r.cmd.GetSumSubscriptions(ctx, params)
+	rows, err := r.cmd.ListMonthlyCharges(ctx, params)
 	if err != nil {
-		logger.Error("failed to get sum subscriptions", "error", err)
-		return 0, err
+		logger.Error("failed to list monthly charges", "error", err)
+		return nil, err
 	}
 
-	return sum, nil
+	charges := make([]model.MonthlyCharge, 0, len(rows))
+	for _, row := range rows {
+		subscriptionID, err := utils.PgxUUIDToGoogleUUID(row.ID)
+		if err != nil {
+			logger.Error("failed to convert pgx UUID to google UUID (subscription_id)", "error", err)
+			return nil, err
+		}
+
+		userID, err := utils.PgxUUIDToGoogleUUID(row.UserID)
+		if err != nil {
+			logger.Error("failed to convert pgx UUID to google UUID (user_id)", "error", err)
+			return nil, err
+		}
+
+		charges = append(charges, model.MonthlyCharge{
+			SubscriptionID: subscriptionID,
+			UserID:         userID,
+			ServiceName:    row.ServiceName,
+			Month:          yearMonthFromPg(row.Month),
+			Price:          row.Price,
+			Currency:       row.Currency,
+			Charges:        row.Charges,
+		})
+	}
+
+	return charges, nil
+}
+
+func allSubscriptionsParams(tenantID string, query model.SubscriptionQuery) repository.AllSubscriptionsParams {
+	filter := query.Filter
+
+	params := repository.AllSubscriptionsParams{
+		TenantID:          tenantID,
+		UserID:            utils.GoogleUUIDToPgxUUID(filter.UserID),
+		ActiveAt:          yearMonthToPg(filter.ActiveAt),
+		PriceInMajorUnits: filter.PriceInMajorUnits,
+		OpenEndedOnly:     filter.OpenEndedOnly,
+		Deleted:           string(filter.Deleted),
+		OrderBy:           string(query.Order.By),
+		OrderDesc:         query.Order.Desc,
+		Count:             query.Pagination.Count,
+		Page:              query.Pagination.Page,
+	}
+	if params.OrderBy == "" {
+		params.OrderBy = string(model.SubscriptionOrderByStartDate)
+	}
+	if params.Deleted == "" {
+		params.Deleted = string(model.DeletedFilterExclude)
+	}
+	if filter.MinPrice != nil {
+		params.MinPrice = pgtype.Int8{Int64: *filter.MinPrice, Valid: true}
+	}
+	if filter.MaxPrice != nil {
+		params.MaxPrice = pgtype.Int8{Int64: *filter.MaxPrice, Valid: true}
+	}
+	if filter.PriceCurrency != "" {
+		params.PriceCurrency = pgtype.Text{String: filter.PriceCurrency, Valid: true}
+	}
+
+	if query.After != nil {
+		params.AfterID = utils.GoogleUUIDToPgxUUID(query.After.ID)
+		params.AfterStartDate = yearMonthToPg(query.After.StartDate)
+		params.AfterPrice = pgtype.Int8{Int64: query.After.Price, Valid: true}
+		params.AfterServiceName = pgtype.Text{String: query.After.ServiceName, Valid: true}
+	}
+
+	if filter.ServiceName != "" {
+		switch filter.ServiceNameMatch {
+		case model.ServiceNameMatchPrefix:
+			params.ServiceNamePattern = pgtype.Text{String: likePrefix(filter.ServiceName), Valid: true}
+		case model.ServiceNameMatchSimilar:
+			params.ServiceNameSimilar = pgtype.Text{String: filter.ServiceName, Valid: true}
+		default:
+			params.ServiceName = pgtype.Text{String: filter.ServiceName, Valid: true}
+		}
+	}
+
+	return params
+}
+
+// likePrefix экранирует спецсимволы LIKE и возвращает шаблон поиска по префиксу
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
 }
@@ -6,7 +6,11 @@ import (
 	"time"
 
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/internal/subscription/service"
+	infrapostgres "github.com/Geriler/effective-mobile/pkg/infra/postgres"
 	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/Geriler/effective-mobile/pkg/lib/requestmeta"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
@@ -42,17 +46,38 @@ func setupTestContainer(t *testing.T) (*pgxpool.Pool, func()) {
 		t.Fatalf("failed to get postgres connection string: %v", err)
 	}
 
-	pool, err := pgxpool.New(ctx, connStr)
+	ownerPool, err := pgxpool.New(ctx, connStr)
 	if err != nil {
 		t.Fatalf("failed to create pgxpool: %v", err)
 	}
+	defer ownerPool.Close()
 
-	db := stdlib.OpenDBFromPool(pool)
+	db := stdlib.OpenDBFromPool(ownerPool)
 	err = goose.Up(db, "../../../migrations")
 	if err != nil {
 		t.Fatalf("failed to run migrations: %v", err)
 	}
 
+	// суперпользователь обходит политики защиты строк, поэтому репозитории работают под обычной ролью,
+	// как сервис в продакшене
+	_, err = ownerPool.Exec(ctx, `
+		CREATE ROLE app LOGIN PASSWORD 'app' NOSUPERUSER NOBYPASSRLS;
+		GRANT SELECT, INSERT, UPDATE, DELETE, TRUNCATE ON ALL TABLES IN SCHEMA public TO app;
+		GRANT USAGE, SELECT, UPDATE ON ALL SEQUENCES IN SCHEMA public TO app`)
+	if err != nil {
+		t.Fatalf("failed to create application role: %v", err)
+	}
+
+	cfg := ownerPool.Config()
+	cfg.ConnConfig.User = "app"
+	cfg.ConnConfig.Password = "app"
+	infrapostgres.WithSessionSetting("app.tenant_id", tenant.SessionSetting)(cfg)
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to create pgxpool: %v", err)
+	}
+
 	cleanup := func() {
 		pool.Close()
 		if err = postgresContainer.Terminate(ctx); err != nil {
@@ -63,7 +88,7 @@ func setupTestContainer(t *testing.T) (*pgxpool.Pool, func()) {
 	return pool, cleanup
 }
 
-func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
+func TestPostgresSubscriptionRepository_ListMonthlyCharges(t *testing.T) {
 	pool, cleanup := setupTestContainer(t)
 	defer cleanup()
 
@@ -77,7 +102,7 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 		name          string
 		subscriptions []model.Subscription
 		filters       model.Filters
-		expected      int32
+		expected      int64
 	}{
 		{
 			name: "Подписка активна весь запрошенный период",
@@ -86,13 +111,13 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       500,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
+					EndDate:     model.NewYearMonth(2025, 12),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 3),
+				EndDate:   model.NewYearMonth(2025, 6),
 			},
 			expected: 500 * 4,
 		},
@@ -103,13 +128,13 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       400,
-					StartDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 6),
+					EndDate:     model.NewYearMonth(2025, 12),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 3),
+				EndDate:   model.NewYearMonth(2025, 8),
 			},
 			expected: 400 * 3,
 		},
@@ -120,13 +145,13 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
+					EndDate:     model.NewYearMonth(2025, 6),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 4),
+				EndDate:   model.NewYearMonth(2025, 8),
 			},
 			expected: 450 * 3,
 		},
@@ -137,13 +162,13 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 3),
+					EndDate:     model.NewYearMonth(2025, 6),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 1),
+				EndDate:   model.NewYearMonth(2025, 12),
 			},
 			expected: 450 * 4,
 		},
@@ -154,12 +179,12 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 1),
+				EndDate:   model.NewYearMonth(2025, 12),
 			},
 			expected: 450 * 12,
 		},
@@ -170,18 +195,18 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 				{
 					UserID:      userID1,
 					ServiceName: "YouTube",
 					Price:       300,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 3),
+				EndDate:   model.NewYearMonth(2025, 6),
 			},
 			expected: 450*4 + 300*4,
 		},
@@ -192,18 +217,18 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 				{
 					UserID:      userID1,
 					ServiceName: "YouTube",
 					Price:       300,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 			},
 			filters: model.Filters{
-				StartDate:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				StartDate:   model.NewYearMonth(2025, 3),
+				EndDate:     model.NewYearMonth(2025, 6),
 				ServiceName: "Netflix",
 			},
 			expected: 450 * 4,
@@ -215,18 +240,18 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 				{
 					UserID:      userID2,
 					ServiceName: "YouTube",
 					Price:       300,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 3),
+				EndDate:   model.NewYearMonth(2025, 6),
 				UserID:    userID1,
 			},
 			expected: 450 * 4,
@@ -238,20 +263,20 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
+					EndDate:     model.NewYearMonth(2025, 3),
 				},
 				{
 					UserID:      userID2,
 					ServiceName: "YouTube",
 					Price:       300,
-					StartDate:   time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 7),
+					EndDate:     model.NewYearMonth(2025, 12),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 4),
+				EndDate:   model.NewYearMonth(2025, 6),
 			},
 			expected: 0,
 		},
@@ -262,13 +287,13 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
+					EndDate:     model.NewYearMonth(2025, 6),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 1),
+				EndDate:   model.NewYearMonth(2025, 1),
 			},
 			expected: 450,
 		},
@@ -279,26 +304,26 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 6),
 				},
 				{
 					UserID:      userID1,
 					ServiceName: "YouTube",
 					Price:       300,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
+					EndDate:     model.NewYearMonth(2025, 3),
 				},
 				{
 					UserID:      userID1,
 					ServiceName: "Hulu",
 					Price:       200,
-					StartDate:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
-					EndDate:     time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 2),
+					EndDate:     model.NewYearMonth(2025, 8),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 1),
+				EndDate:   model.NewYearMonth(2025, 6),
 			},
 			expected: 450 + 300*3 + 200*5,
 		},
@@ -309,20 +334,71 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 					UserID:      userID1,
 					ServiceName: "Netflix",
 					Price:       450,
-					StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					StartDate:   model.NewYearMonth(2025, 1),
 				},
 			},
 			filters: model.Filters{
-				StartDate: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				StartDate: model.NewYearMonth(2025, 9),
+				EndDate:   model.NewYearMonth(2026, 3),
 			},
 			expected: 450 * 7,
 		},
+		{
+			name: "Годовая подписка списывается один раз за год",
+			subscriptions: []model.Subscription{
+				{
+					UserID:        userID1,
+					ServiceName:   "Yandex Plus",
+					Price:         3000,
+					BillingPeriod: model.BillingPeriodYearly,
+					StartDate:     model.NewYearMonth(2025, 3),
+				},
+			},
+			filters: model.Filters{
+				StartDate: model.NewYearMonth(2025, 1),
+				EndDate:   model.NewYearMonth(2025, 12),
+			},
+			expected: 3000,
+		},
+		{
+			name: "Квартальная подписка, списание до начала периода не учитывается",
+			subscriptions: []model.Subscription{
+				{
+					UserID:        userID1,
+					ServiceName:   "Netflix",
+					Price:         1200,
+					BillingPeriod: model.BillingPeriodQuarterly,
+					StartDate:     model.NewYearMonth(2025, 1),
+				},
+			},
+			filters: model.Filters{
+				StartDate: model.NewYearMonth(2025, 2),
+				EndDate:   model.NewYearMonth(2025, 12),
+			},
+			expected: 1200 * 3,
+		},
+		{
+			name: "Еженедельная подписка",
+			subscriptions: []model.Subscription{
+				{
+					UserID:        userID1,
+					ServiceName:   "Netflix",
+					Price:         100,
+					BillingPeriod: model.BillingPeriodWeekly,
+					StartDate:     model.NewYearMonth(2025, 1),
+				},
+			},
+			filters: model.Filters{
+				StartDate: model.NewYearMonth(2025, 1),
+				EndDate:   model.NewYearMonth(2025, 2),
+			},
+			expected: 100 * 9,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := pool.Exec(ctx, "TRUNCATE TABLE subscriptions")
+			_, err := pool.Exec(ctx, "TRUNCATE TABLE subscriptions CASCADE")
 			assert.NoError(t, err)
 
 			for _, sub := range tc.subscriptions {
@@ -330,9 +406,753 @@ func TestPostgresSubscriptionRepository_GetSumSubscriptions(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			sum, err := repo.GetSumSubscriptions(ctx, tc.filters)
+			charges, err := repo.ListMonthlyCharges(ctx, tc.filters)
+			assert.NoError(t, err)
+
+			var sum int64
+			for _, charge := range charges {
+				sum += charge.Amount()
+			}
 			assert.Equal(t, tc.expected, sum)
+		})
+	}
+}
+
+func TestPostgresSubscriptionRepository_ChangeSubscriptionPlan(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	subscription, err := repo.CreateSubscription(ctx, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       300,
+		StartDate:   model.NewYearMonth(2025, 1),
+	})
+	assert.NoError(t, err)
+
+	changed, err := repo.ChangeSubscriptionPlan(ctx, model.SubscriptionPlan{
+		SubscriptionID: subscription.ID,
+		ValidFrom:      model.NewYearMonth(2025, 4),
+		ServiceName:    "Yandex Plus",
+		Price:          500,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), changed.Price)
+
+	plans, err := repo.ListSubscriptionPlans(ctx, subscription.ID)
+	assert.NoError(t, err)
+	assert.Len(t, plans, 2)
+
+	charges, err := repo.ListMonthlyCharges(ctx, model.Filters{
+		StartDate: model.NewYearMonth(2025, 1),
+		EndDate:   model.NewYearMonth(2025, 6),
+	})
+	assert.NoError(t, err)
+
+	var sum int64
+	for _, charge := range charges {
+		sum += charge.Amount()
+	}
+	assert.Equal(t, int64(300*3+500*3), sum)
+}
+
+func TestPostgresSubscriptionRepository_UpdateSubscription(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	subscription, err := repo.CreateSubscription(ctx, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       400,
+		StartDate:   model.NewYearMonth(2025, 1),
+		EndDate:     model.NewYearMonth(2025, 6),
+	})
+	assert.NoError(t, err)
+
+	serviceName := "Yandex Pro"
+	updated, err := repo.UpdateSubscription(ctx, subscription.ID, model.SubscriptionUpdate{
+		ServiceName: &serviceName,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Yandex Pro", updated.ServiceName)
+	assert.Equal(t, int64(400), updated.Price)
+	assert.Equal(t, subscription.EndDate, updated.EndDate)
+
+	updated, err = repo.UpdateSubscription(ctx, subscription.ID, model.SubscriptionUpdate{
+		ClearEndDate: true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, updated.EndDate.IsZero())
+}
+
+func TestPostgresSubscriptionRepository_UpdateSubscriptionPrice(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+	svc := service.NewSubscriptionService(repo, NewPostgresExchangeRateRepository(pool, log), "RUB", nil, model.OverlapPolicyReject)
+
+	currentMonth := model.YearMonthOf(time.Now())
+	past := model.Filters{StartDate: currentMonth.AddMonths(-5), EndDate: currentMonth.AddMonths(-1)}
+
+	subscription, _, err := svc.AddSubscription(ctx, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		StartDate:   past.StartDate,
+	})
+	assert.NoError(t, err)
+
+	before, err := svc.GetTotalSum(ctx, past)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(40000*5), before.Sum)
+
+	price := int64(50000)
+	updated, _, err := svc.UpdateSubscription(ctx, subscription.ID, model.SubscriptionUpdate{Price: &price})
+	assert.NoError(t, err)
+	assert.Equal(t, price, updated.Price)
+
+	// изменение цены не переписывает суммы за прошлые месяцы
+	after, err := svc.GetTotalSum(ctx, past)
+	assert.NoError(t, err)
+	assert.Equal(t, before.Sum, after.Sum)
+
+	current, err := svc.GetTotalSum(ctx, model.Filters{StartDate: currentMonth, EndDate: currentMonth})
+	assert.NoError(t, err)
+	assert.Equal(t, price, current.Sum)
+
+	plans, err := repo.ListSubscriptionPlans(ctx, subscription.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, plans, 2) {
+		assert.Equal(t, currentMonth, plans[1].ValidFrom)
+	}
+}
+
+func TestPostgresSubscriptionRepository_AllSubscriptions(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	userID1, userID2 := uuid.New(), uuid.New()
+	subscriptions := []model.Subscription{
+		{UserID: userID1, ServiceName: "Yandex Plus", Price: 400, StartDate: model.NewYearMonth(2025, 1), EndDate: model.NewYearMonth(2025, 6)},
+		{UserID: userID1, ServiceName: "Netflix", Price: 800, StartDate: model.NewYearMonth(2025, 3)},
+		{UserID: userID2, ServiceName: "Yandex Music", Price: 200, StartDate: model.NewYearMonth(2025, 7)},
+		{UserID: userID2, ServiceName: "Spotify", Price: 300, StartDate: model.NewYearMonth(2024, 12), EndDate: model.NewYearMonth(2025, 2)},
+	}
+	created := make(map[string]model.Subscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result, err := repo.CreateSubscription(ctx, subscription)
+		assert.NoError(t, err)
+		created[result.ServiceName] = *result
+	}
+
+	afterSpotify := model.CursorOf(created["Spotify"])
+	minPrice, maxPrice := int64(250), int64(500)
+	minMajorPrice, maxMajorPrice := int64(2), int64(4)
+
+	testCases := []struct {
+		name     string
+		query    model.SubscriptionQuery
+		expected []string
+	}{
+		{
+			name:     "Без фильтров, сортировка по дате старта",
+			query:    model.SubscriptionQuery{},
+			expected: []string{"Spotify", "Yandex Plus", "Netflix", "Yandex Music"},
+		},
+		{
+			name:     "Подписки пользователя",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{UserID: userID1}},
+			expected: []string{"Yandex Plus", "Netflix"},
+		},
+		{
+			name:     "Точное совпадение наименования без учета регистра",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{ServiceName: "netflix"}},
+			expected: []string{"Netflix"},
+		},
+		{
+			name:     "Поиск по префиксу",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{ServiceName: "yandex", ServiceNameMatch: model.ServiceNameMatchPrefix}},
+			expected: []string{"Yandex Plus", "Yandex Music"},
+		},
+		{
+			name:     "Нечеткий поиск",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{ServiceName: "yandx", ServiceNameMatch: model.ServiceNameMatchSimilar}},
+			expected: []string{"Yandex Plus", "Yandex Music"},
+		},
+		{
+			name:     "Активные в месяце",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{ActiveAt: model.NewYearMonth(2025, 5)}},
+			expected: []string{"Yandex Plus", "Netflix"},
+		},
+		{
+			name:     "Диапазон цен",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}},
+			expected: []string{"Spotify", "Yandex Plus"},
+		},
+		{
+			name:     "Диапазон цен в целых единицах валюты",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{MinPrice: &minMajorPrice, MaxPrice: &maxMajorPrice, PriceInMajorUnits: true}},
+			expected: []string{"Spotify", "Yandex Plus", "Yandex Music"},
+		},
+		{
+			name:     "Диапазон цен в другой валюте",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{MinPrice: &minPrice, PriceCurrency: "USD"}},
+			expected: []string{},
+		},
+		{
+			name:     "Только бессрочные, сортировка по цене по убыванию",
+			query:    model.SubscriptionQuery{Filter: model.SubscriptionFilter{OpenEndedOnly: true}, Order: model.SubscriptionOrder{By: model.SubscriptionOrderByPrice, Desc: true}},
+			expected: []string{"Netflix", "Yandex Music"},
+		},
+		{
+			name:     "Сортировка по наименованию",
+			query:    model.SubscriptionQuery{Order: model.SubscriptionOrder{By: model.SubscriptionOrderByServiceName}},
+			expected: []string{"Netflix", "Spotify", "Yandex Music", "Yandex Plus"},
+		},
+		{
+			name:     "Страница после курсора",
+			query:    model.SubscriptionQuery{Order: model.SubscriptionOrder{By: model.SubscriptionOrderByPrice}, After: &afterSpotify},
+			expected: []string{"Yandex Plus", "Netflix"},
+		},
+		{
+			name:     "Страница после курсора по убыванию",
+			query:    model.SubscriptionQuery{Order: model.SubscriptionOrder{By: model.SubscriptionOrderByPrice, Desc: true}, After: &afterSpotify},
+			expected: []string{"Yandex Music"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.query.Pagination = model.Pagination{Count: 10}
+
+			result, err := repo.AllSubscriptions(ctx, tc.query)
+			assert.NoError(t, err)
+
+			names := make([]string, 0, len(result))
+			for _, subscription := range result {
+				names = append(names, subscription.ServiceName)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestPostgresSubscriptionRepository_Overlaps(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	userID := uuid.New()
+	first, err := repo.CreateSubscription(ctx, model.Subscription{
+		UserID:      userID,
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		StartDate:   model.NewYearMonth(2025, 1),
+		EndDate:     model.NewYearMonth(2025, 6),
+	})
+	assert.NoError(t, err)
+
+	overlapping := model.Subscription{
+		UserID:      userID,
+		ServiceName: " yandex  plus",
+		Price:       40000,
+		StartDate:   model.NewYearMonth(2025, 6),
+	}
+
+	// ограничение в базе данных не пропускает пересечение, даже если сервис его не проверил
+	_, err = repo.CreateSubscription(ctx, overlapping)
+	assert.ErrorIs(t, err, model.ErrSubscriptionAlreadyExists)
+
+	found, err := repo.FindOverlappingSubscriptions(ctx, overlapping, uuid.Nil)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	overlapping.OverlapAllowed = true
+	second, err := repo.CreateSubscription(ctx, overlapping)
+	assert.NoError(t, err)
+
+	overlaps, err := repo.ListSubscriptionOverlaps(ctx, model.OverlapFilter{UserID: userID})
+	assert.NoError(t, err)
+	if assert.Len(t, overlaps, 1) {
+		assert.Equal(t, model.NewYearMonth(2025, 6), overlaps[0].Start)
+		assert.Equal(t, model.NewYearMonth(2025, 6), overlaps[0].End)
+		assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, []uuid.UUID{overlaps[0].Subscription.ID, overlaps[0].Overlapping.ID})
+	}
+
+	merged, err := repo.MergeSubscriptions(ctx, first.ID, []uuid.UUID{second.ID}, first.StartDate, model.YearMonth{})
+	assert.NoError(t, err)
+	assert.True(t, merged.EndDate.IsZero())
+	assert.False(t, merged.OverlapAllowed)
+
+	_, err = repo.GetSubscriptionById(ctx, second.ID)
+	assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+
+	overlaps, err = repo.ListSubscriptionOverlaps(ctx, model.OverlapFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Empty(t, overlaps)
+}
+
+func TestPostgresSubscriptionRepository_Lifecycle(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	subscription, err := repo.CreateSubscription(ctx, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		StartDate:   model.NewYearMonth(2025, 1),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusActive, subscription.Status)
+
+	paused, err := repo.PauseSubscription(ctx, subscription.ID, model.SubscriptionStatusActive, model.NewYearMonth(2025, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusPaused, paused.Status)
+
+	// статус уже изменился, повторный переход из active не выполняется
+	_, err = repo.CancelSubscription(ctx, subscription.ID, model.SubscriptionStatusActive, model.NewYearMonth(2025, 6))
+	assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+
+	resumed, err := repo.ResumeSubscription(ctx, subscription.ID, model.NewYearMonth(2025, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusActive, resumed.Status)
+
+	pauses, err := repo.ListSubscriptionPauses(ctx, subscription.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SubscriptionPause{{
+		SubscriptionID: subscription.ID,
+		StartDate:      model.NewYearMonth(2025, 3),
+		EndDate:        model.NewYearMonth(2025, 4),
+	}}, pauses)
+
+	_, err = repo.PauseSubscription(ctx, subscription.ID, model.SubscriptionStatusActive, model.NewYearMonth(2025, 4))
+	assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+
+	cancelled, err := repo.CancelSubscription(ctx, subscription.ID, model.SubscriptionStatusActive, model.NewYearMonth(2025, 6))
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusCancelled, cancelled.Status)
+	assert.Equal(t, model.NewYearMonth(2025, 6), cancelled.EndDate)
+
+	charges, err := repo.ListMonthlyCharges(ctx, model.Filters{
+		StartDate: model.NewYearMonth(2025, 1),
+		EndDate:   model.NewYearMonth(2025, 12),
+	})
+	assert.NoError(t, err)
+
+	months := make([]model.YearMonth, 0, len(charges))
+	for _, charge := range charges {
+		months = append(months, charge.Month)
+	}
+	assert.Equal(t, []model.YearMonth{
+		model.NewYearMonth(2025, 1),
+		model.NewYearMonth(2025, 2),
+		model.NewYearMonth(2025, 5),
+		model.NewYearMonth(2025, 6),
+	}, months)
+
+	expired, err := repo.ExpireSubscriptions(ctx, model.NewYearMonth(2025, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+
+	result, err := repo.GetSubscriptionById(ctx, subscription.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusExpired, result.Status)
+}
+
+func TestPostgresSubscriptionRepository_Trial(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	userID := uuid.New()
+	subscription, err := repo.CreateSubscription(ctx, model.Subscription{
+		UserID:       userID,
+		ServiceName:  "Yandex Plus",
+		Price:        40000,
+		Currency:     "RUB",
+		StartDate:    model.NewYearMonth(2025, 1),
+		Status:       model.SubscriptionStatusTrial,
+		TrialMonths:  2,
+		TrialEndDate: model.NewYearMonth(2025, 2),
+		PromoPhases:  []model.PromoPhase{{Months: 1, Price: 20000}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusTrial, subscription.Status)
+	assert.Equal(t, model.NewYearMonth(2025, 2), subscription.TrialEndDate)
+
+	plans, err := repo.ListSubscriptionPlans(ctx, subscription.ID)
+	assert.NoError(t, err)
+	phases := make([]model.PlanPhase, 0, len(plans))
+	for _, plan := range plans {
+		phases = append(phases, plan.Phase)
+	}
+	assert.Equal(t, []model.PlanPhase{model.PlanPhaseTrial, model.PlanPhasePromo, model.PlanPhaseRegular}, phases)
+
+	charges, err := repo.ListMonthlyCharges(ctx, model.Filters{
+		StartDate: model.NewYearMonth(2025, 1),
+		EndDate:   model.NewYearMonth(2025, 4),
+	})
+	assert.NoError(t, err)
+	prices := make([]int64, 0, len(charges))
+	for _, charge := range charges {
+		prices = append(prices, charge.Price)
+	}
+	assert.Equal(t, []int64{0, 0, 20000, 40000}, prices)
+
+	trials, err := repo.ListEndingTrials(ctx, model.TrialFilter{
+		From:   model.NewYearMonth(2025, 2),
+		To:     model.NewYearMonth(2025, 2),
+		UserID: userID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, trials, 1)
+
+	trials, err = repo.ListEndingTrials(ctx, model.TrialFilter{
+		From: model.NewYearMonth(2025, 3),
+		To:   model.NewYearMonth(2025, 3),
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, trials)
+
+	activated, err := repo.ActivateEndedTrials(ctx, model.NewYearMonth(2025, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), activated)
+
+	result, err := repo.GetSubscriptionById(ctx, subscription.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusActive, result.Status)
+}
+
+func TestPostgresSubscriptionRepository_SoftDelete(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	userID := uuid.New()
+	subscription := model.Subscription{
+		UserID:      userID,
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   model.NewYearMonth(2025, 1),
+	}
+	created, err := repo.CreateSubscription(ctx, subscription)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.DeleteSubscription(ctx, created.ID, 0))
+	assert.ErrorIs(t, repo.DeleteSubscription(ctx, created.ID, 0), model.ErrSubscriptionNotFound)
+
+	_, err = repo.GetSubscriptionById(ctx, created.ID)
+	assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+
+	subscriptions, err := repo.AllSubscriptions(ctx, model.SubscriptionQuery{
+		Filter:     model.SubscriptionFilter{UserID: userID},
+		Pagination: model.Pagination{Count: 10},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, subscriptions)
+
+	trash, err := repo.AllSubscriptions(ctx, model.SubscriptionQuery{
+		Filter:     model.SubscriptionFilter{UserID: userID, Deleted: model.DeletedFilterOnly},
+		Pagination: model.Pagination{Count: 10},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, trash, 1) {
+		assert.Equal(t, created.ID, trash[0].ID)
+		assert.False(t, trash[0].DeletedAt.IsZero())
+	}
+
+	count, err := repo.CountSubscriptions(ctx, model.SubscriptionFilter{UserID: userID, Deleted: model.DeletedFilterInclude})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	charges, err := repo.ListMonthlyCharges(ctx, model.Filters{
+		StartDate: model.NewYearMonth(2025, 1),
+		EndDate:   model.NewYearMonth(2025, 3),
+		UserID:    userID,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, charges)
+
+	// удаленная подписка не мешает создать новую на тот же период, но тогда ее нельзя восстановить
+	replacement, err := repo.CreateSubscription(ctx, subscription)
+	assert.NoError(t, err)
+
+	_, err = repo.UndeleteSubscription(ctx, created.ID)
+	assert.ErrorIs(t, err, model.ErrSubscriptionAlreadyExists)
+
+	assert.NoError(t, repo.DeleteSubscription(ctx, replacement.ID, 0))
+
+	restored, err := repo.UndeleteSubscription(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.True(t, restored.DeletedAt.IsZero())
+
+	_, err = repo.UndeleteSubscription(ctx, created.ID)
+	assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+
+	purged, err := repo.PurgeDeletedSubscriptions(ctx, time.Now().Add(time.Hour), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, err = repo.UndeleteSubscription(ctx, replacement.ID)
+	assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+}
+
+func TestPostgresSubscriptionRepository_History(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := requestmeta.With(context.Background(), requestmeta.Meta{RequestID: "req-1", Actor: "alice", ClaimedActor: "bob"})
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	created, err := repo.CreateSubscription(ctx, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   model.NewYearMonth(2025, 1),
+	})
+	assert.NoError(t, err)
+
+	price := int64(50000)
+	_, err = repo.UpdateSubscription(ctx, created.ID, model.SubscriptionUpdate{Price: &price})
+	assert.NoError(t, err)
+
+	// изменение вне запроса клиента записывается от имени системы
+	assert.NoError(t, repo.DeleteSubscription(context.Background(), created.ID, 0))
+
+	_, err = repo.PurgeDeletedSubscriptions(context.Background(), time.Now().Add(time.Hour), nil)
+	assert.NoError(t, err)
+
+	records, err := repo.ListSubscriptionHistory(ctx, created.ID)
+	assert.NoError(t, err)
+	if !assert.Len(t, records, 4) {
+		return
+	}
+
+	assert.Equal(t, model.AuditActionCreate, records[0].Action)
+	assert.Equal(t, "alice", records[0].Actor)
+	assert.Equal(t, "bob", records[0].ClaimedActor)
+	assert.Equal(t, "req-1", records[0].RequestID)
+	assert.Nil(t, records[0].Before)
+	if assert.NotNil(t, records[0].After) {
+		assert.Equal(t, int64(40000), records[0].After.Price)
+	}
+
+	assert.Equal(t, model.AuditActionUpdate, records[1].Action)
+	assert.Equal(t, []model.FieldChange{{Field: "price", Before: []byte("40000"), After: []byte("50000")}}, records[1].Changes)
+
+	assert.Equal(t, model.AuditActionDelete, records[2].Action)
+	assert.Equal(t, requestmeta.SystemActor, records[2].Actor)
+	assert.Empty(t, records[2].ClaimedActor)
+	assert.Empty(t, records[2].RequestID)
+	if assert.Len(t, records[2].Changes, 1) {
+		assert.Equal(t, "deleted_at", records[2].Changes[0].Field)
+	}
+
+	assert.Equal(t, model.AuditActionPurge, records[3].Action)
+	assert.NotNil(t, records[3].Before)
+	assert.Nil(t, records[3].After)
+}
+
+func TestPostgresSubscriptionRepository_Version(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	created, err := repo.CreateSubscription(ctx, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   model.NewYearMonth(2025, 1),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	price := int64(50000)
+	updated, err := repo.UpdateSubscription(ctx, created.ID, model.SubscriptionUpdate{Price: &price, ExpectedVersion: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	// второй клиент изменяет подписку по устаревшей версии
+	_, err = repo.UpdateSubscription(ctx, created.ID, model.SubscriptionUpdate{Price: &price, ExpectedVersion: 1})
+	assert.ErrorIs(t, err, model.ErrVersionMismatch)
+	assert.ErrorIs(t, repo.DeleteSubscription(ctx, created.ID, 1), model.ErrVersionMismatch)
+
+	// изменение без фактических изменений версию не увеличивает
+	unchanged, err := repo.UpdateSubscription(ctx, created.ID, model.SubscriptionUpdate{Price: &price})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), unchanged.Version)
+
+	assert.NoError(t, repo.DeleteSubscription(ctx, created.ID, 2))
+	assert.ErrorIs(t, repo.DeleteSubscription(ctx, created.ID, 3), model.ErrSubscriptionNotFound)
+}
+
+func TestPostgresSubscriptionRepository_Tenants(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	acme := tenant.With(context.Background(), "acme")
+	globex := tenant.With(context.Background(), "globex")
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresSubscriptionRepository(pool, log)
+
+	subscription := model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   model.NewYearMonth(2025, 1),
+	}
+	acmeSubscription, err := repo.CreateSubscription(acme, subscription)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", acmeSubscription.TenantID)
+
+	// подписки разных арендаторов не пересекаются, даже если совпадают пользователь, сервис и период
+	globexSubscription, err := repo.CreateSubscription(globex, subscription)
+	assert.NoError(t, err)
+
+	_, err = repo.GetSubscriptionById(acme, globexSubscription.ID)
+	assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+	assert.ErrorIs(t, repo.DeleteSubscription(acme, globexSubscription.ID, 0), model.ErrSubscriptionNotFound)
+
+	subscriptions, err := repo.AllSubscriptions(acme, model.SubscriptionQuery{Pagination: model.Pagination{Count: 10}})
+	assert.NoError(t, err)
+	if assert.Len(t, subscriptions, 1) {
+		assert.Equal(t, acmeSubscription.ID, subscriptions[0].ID)
+	}
+
+	t.Run("Срок хранения арендатора", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteSubscription(acme, acmeSubscription.ID, 0))
+		assert.NoError(t, repo.DeleteSubscription(globex, globexSubscription.ID, 0))
+
+		purged, err := repo.PurgeDeletedSubscriptions(tenant.WithAll(context.Background()), time.Now().Add(-time.Hour), map[string]time.Time{
+			"acme": time.Now().Add(time.Hour),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = repo.UndeleteSubscription(globex, globexSubscription.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Политики защиты строк", func(t *testing.T) {
+		count := func(ctx context.Context) int {
+			var count int
+			assert.NoError(t, pool.QueryRow(ctx, "SELECT count(*) FROM subscriptions").Scan(&count))
+			return count
+		}
+		assert.Equal(t, 1, count(globex))
+		assert.Equal(t, 0, count(context.Background()))
+		assert.Equal(t, 1, count(tenant.WithAll(context.Background())))
+
+		_, err = pool.Exec(acme, "UPDATE subscriptions SET tenant_id = 'acme' WHERE id = $1", globexSubscription.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count(globex))
+	})
+}
+
+func TestPostgresRowLevelSecurity(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	acme := requestmeta.With(tenant.With(context.Background(), "acme"), requestmeta.Meta{RequestID: "req-1", Actor: "alice"})
+	globex := tenant.With(context.Background(), "globex")
+	log := logger.Setup("text", "warn")
+	subscriptionRepo := NewPostgresSubscriptionRepository(pool, log)
+	webhookRepo := NewPostgresWebhookRepository(pool, log)
+
+	created, err := subscriptionRepo.CreateSubscription(acme, model.Subscription{
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   model.NewYearMonth(2025, 1),
+	})
+	assert.NoError(t, err)
+	_, err = subscriptionRepo.PauseSubscription(acme, created.ID, model.SubscriptionStatusActive, model.NewYearMonth(2025, 3))
+	assert.NoError(t, err)
+
+	_, err = webhookRepo.CreateWebhook(acme, model.Webhook{URL: "https://acme.example.com/hooks", Secret: "whsec_acme"})
+	assert.NoError(t, err)
+	_, err = webhookRepo.EnqueueWebhookDeliveries(acme, 1, model.EventSubscriptionCreated, []byte(`{"id":1}`))
+	assert.NoError(t, err)
+
+	_, err = NewPostgresAPIKeyRepository(pool, log).CreateAPIKey(acme, model.APIKey{
+		Name:   "acme",
+		Prefix: "emk_acme00",
+		Hash:   []byte("acme-hash"),
+		Scopes: []string{model.ScopeSubscriptionsRead},
+	})
+	assert.NoError(t, err)
+
+	_, err = NewPostgresIdempotencyRepository(pool, log).ClaimIdempotencyKey(acme, model.IdempotencyRecord{
+		Key:         "key",
+		Method:      "/Add",
+		RequestHash: []byte("hash"),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, time.Now())
+	assert.NoError(t, err)
+
+	// запросы без фильтра по арендатору: строки другого арендатора отсекают только политики
+	tables := []string{
+		"subscriptions", "subscription_pauses", "subscription_audit_log", "subscription_outbox",
+		"webhook_endpoints", "webhook_deliveries", "api_keys", "idempotency_keys",
+	}
+	for _, table := range tables {
+		t.Run(table, func(t *testing.T) {
+			count := func(ctx context.Context) int {
+				var count int
+				assert.NoError(t, pool.QueryRow(ctx, "SELECT count(*) FROM "+table).Scan(&count))
+				return count
+			}
+			assert.Positive(t, count(acme))
+			assert.Zero(t, count(globex))
+
+			deleted, err := pool.Exec(globex, "DELETE FROM "+table)
 			assert.NoError(t, err)
+			assert.Zero(t, deleted.RowsAffected())
+			assert.Positive(t, count(acme))
 		})
 	}
+
+	// арендатор не может передать свою строку другому арендатору
+	_, err = pool.Exec(acme, "UPDATE subscriptions SET tenant_id = 'globex' WHERE id = $1", created.ID)
+	assert.Error(t, err)
+
+	_, err = subscriptionRepo.GetSubscriptionById(globex, created.ID)
+	assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
 }
@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	repository "github.com/Geriler/effective-mobile/internal/subscription/repository/sqlc"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/Geriler/effective-mobile/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresWebhookRepository struct {
+	cmd    *repository.Queries
+	logger *slog.Logger
+}
+
+func NewPostgresWebhookRepository(conn *pgxpool.Pool, logger *slog.Logger) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{
+		cmd:    repository.New(conn),
+		logger: logger,
+	}
+}
+
+// CreateWebhook сохраняет вебхук с секретом webhook.Secret и возвращает его вместе с секретом
+func (r *PostgresWebhookRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	const op = "PostgresWebhookRepository.CreateWebhook"
+	logger := r.logger.With("op", op).With("url", webhook.URL)
+
+	eventTypes := make([]string, 0, len(webhook.EventTypes))
+	for _, eventType := range webhook.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	row, err := r.cmd.CreateWebhookEndpoint(ctx, repository.CreateWebhookEndpointParams{
+		TenantID:    tenant.From(ctx),
+		Url:         webhook.URL,
+		Description: webhook.Description,
+		EventTypes:  eventTypes,
+		Secret:      webhook.Secret,
+	})
+	if err != nil {
+		logger.Error("failed to create webhook", "error", err)
+		return nil, err
+	}
+
+	return webhookFromRow(row, true)
+}
+
+func (r *PostgresWebhookRepository) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	const op = "PostgresWebhookRepository.ListWebhooks"
+	logger := r.logger.With("op", op)
+
+	rows, err := r.cmd.ListWebhookEndpoints(ctx, tenant.From(ctx))
+	if err != nil {
+		logger.Error("failed to list webhooks", "error", err)
+		return nil, err
+	}
+
+	webhooks := make([]model.Webhook, 0, len(rows))
+	for _, row := range rows {
+		webhook, err := webhookFromRow(row, false)
+		if err != nil {
+			logger.Error("failed to convert webhook", "error", err)
+			return nil, err
+		}
+
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook удаляет вебхук вместе с журналом его доставок
+func (r *PostgresWebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	const op = "PostgresWebhookRepository.DeleteWebhook"
+	logger := r.logger.With("op", op).With("id", id)
+
+	deleted, err := r.cmd.DeleteWebhookEndpoint(ctx, repository.DeleteWebhookEndpointParams{
+		TenantID: tenant.From(ctx),
+		ID:       utils.GoogleUUIDToPgxUUID(id),
+	})
+	if err != nil {
+		logger.Error("failed to delete webhook", "error", err)
+		return err
+	}
+	if deleted == 0 {
+		logger.Warn("webhook not found")
+		return model.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// RotateWebhookSecret заменяет секрет вебхука на secret. Текущий секрет действует до previousExpiresAt,
+// при нулевом previousExpiresAt он отзывается сразу
+func (r *PostgresWebhookRepository) RotateWebhookSecret(ctx context.Context, id uuid.UUID, secret string, previousExpiresAt time.Time) (*model.Webhook, error) {
+	const op = "PostgresWebhookRepository.RotateWebhookSecret"
+	logger := r.logger.With("op", op).With("id", id)
+
+	row, err := r.cmd.RotateWebhookSecret(ctx, repository.RotateWebhookSecretParams{
+		TenantID:                tenant.From(ctx),
+		ID:                      utils.GoogleUUIDToPgxUUID(id),
+		Secret:                  secret,
+		PreviousSecretExpiresAt: pgtype.Timestamptz{Time: previousExpiresAt, Valid: !previousExpiresAt.IsZero()},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn("webhook not found")
+		return nil, model.ErrWebhookNotFound
+	}
+	if err != nil {
+		logger.Error("failed to rotate webhook secret", "error", err)
+		return nil, err
+	}
+
+	return webhookFromRow(row, true)
+}
+
+// EnqueueWebhookDeliveries создает доставки события всем вебхукам арендатора запроса, подписанным на тип события,
+// и возвращает их количество.
+// Для уже поставленного в очередь события доставки повторно не создаются
+func (r *PostgresWebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, eventID int64, eventType model.EventType, payload []byte) (int64, error) {
+	const op = "PostgresWebhookRepository.EnqueueWebhookDeliveries"
+	logger := r.logger.With("op", op).With("event_id", eventID)
+
+	count, err := r.cmd.EnqueueWebhookDeliveries(ctx, repository.EnqueueWebhookDeliveriesParams{
+		EventID:   eventID,
+		EventType: string(eventType),
+		Payload:   payload,
+		TenantID:  tenant.From(ctx),
+	})
+	if err != nil {
+		logger.Error("failed to enqueue webhook deliveries", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ClaimWebhookDeliveries берет в работу до limit доставок всех арендаторов, время попытки которых наступило. До leaseUntil
+// эти доставки не достанутся другим экземплярам сервиса
+func (r *PostgresWebhookRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookRequest, error) {
+	const op = "PostgresWebhookRepository.ClaimWebhookDeliveries"
+	logger := r.logger.With("op", op)
+
+	rows, err := r.cmd.ClaimWebhookDeliveries(ctx, repository.ClaimWebhookDeliveriesParams{
+		LeaseUntil: pgtype.Timestamptz{Time: leaseUntil, Valid: true},
+		BatchSize:  int32(limit),
+	})
+	if err != nil {
+		logger.Error("failed to claim webhook deliveries", "error", err)
+		return nil, err
+	}
+
+	requests := make([]model.WebhookRequest, 0, len(rows))
+	for _, row := range rows {
+		webhookID, err := utils.PgxUUIDToGoogleUUID(row.EndpointID)
+		if err != nil {
+			logger.Error("failed to convert webhook id", "error", err)
+			return nil, err
+		}
+
+		secrets := []string{row.Secret}
+		if row.PreviousSecret != "" {
+			secrets = append(secrets, row.PreviousSecret)
+		}
+
+		requests = append(requests, model.WebhookRequest{
+			DeliveryID: row.ID,
+			WebhookID:  webhookID,
+			EventID:    row.EventID,
+			EventType:  model.EventType(row.EventType),
+			Payload:    row.Payload,
+			Attempts:   row.Attempts,
+			URL:        row.Url,
+			Secrets:    secrets,
+		})
+	}
+
+	return requests, nil
+}
+
+// RecordWebhookAttempt сохраняет результат попытки доставки, новый статус доставки и время следующей попытки
+func (r *PostgresWebhookRepository) RecordWebhookAttempt(ctx context.Context, id int64, status model.WebhookDeliveryStatus, nextAttemptAt time.Time, response model.WebhookResponse) error {
+	const op = "PostgresWebhookRepository.RecordWebhookAttempt"
+	logger := r.logger.With("op", op).With("id", id)
+
+	params := repository.RecordWebhookDeliveryAttemptParams{
+		ID:             id,
+		Status:         string(status),
+		NextAttemptAt:  pgtype.Timestamptz{Time: nextAttemptAt, Valid: true},
+		ResponseStatus: pgtype.Int4{Int32: response.Status, Valid: response.Status != 0},
+		ResponseBody:   pgtype.Text{String: response.Body, Valid: response.Status != 0},
+	}
+	if response.Err != nil {
+		params.LastError = pgtype.Text{String: response.Err.Error(), Valid: true}
+	}
+
+	if err := r.cmd.RecordWebhookDeliveryAttempt(ctx, params); err != nil {
+		logger.Error("failed to record webhook delivery attempt", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries возвращает журнал доставок вебхука от новых к старым
+func (r *PostgresWebhookRepository) ListWebhookDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]model.WebhookDelivery, error) {
+	const op = "PostgresWebhookRepository.ListWebhookDeliveries"
+	logger := r.logger.With("op", op).With("filter", filter)
+
+	rows, err := r.cmd.ListWebhookDeliveries(ctx, repository.ListWebhookDeliveriesParams{
+		TenantID:   tenant.From(ctx),
+		EndpointID: utils.GoogleUUIDToPgxUUID(filter.WebhookID),
+		Status:     pgtype.Text{String: string(filter.Status), Valid: filter.Status != ""},
+		PageSize:   int32(filter.PageSize),
+	})
+	if err != nil {
+		logger.Error("failed to list webhook deliveries", "error", err)
+		return nil, err
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		delivery, err := webhookDeliveryFromRow(row)
+		if err != nil {
+			logger.Error("failed to convert webhook delivery", "error", err)
+			return nil, err
+		}
+
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, nil
+}
+
+// ReplayWebhookDelivery ставит доставку вебхука в очередь заново
+func (r *PostgresWebhookRepository) ReplayWebhookDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (*model.WebhookDelivery, error) {
+	const op = "PostgresWebhookRepository.ReplayWebhookDelivery"
+	logger := r.logger.With("op", op).With("webhook_id", webhookID).With("delivery_id", deliveryID)
+
+	row, err := r.cmd.ReplayWebhookDelivery(ctx, repository.ReplayWebhookDeliveryParams{
+		TenantID:   tenant.From(ctx),
+		ID:         deliveryID,
+		EndpointID: utils.GoogleUUIDToPgxUUID(webhookID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn("webhook delivery not found")
+		return nil, model.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		logger.Error("failed to replay webhook delivery", "error", err)
+		return nil, err
+	}
+
+	return webhookDeliveryFromRow(row)
+}
+
+// DeleteFinishedWebhookDeliveries удаляет завершенные доставки, созданные до момента before
+func (r *PostgresWebhookRepository) DeleteFinishedWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	const op = "PostgresWebhookRepository.DeleteFinishedWebhookDeliveries"
+	logger := r.logger.With("op", op).With("before", before)
+
+	deleted, err := r.cmd.DeleteFinishedWebhookDeliveries(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+	if err != nil {
+		logger.Error("failed to delete finished webhook deliveries", "error", err)
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// webhookFromRow возвращает вебхук с секретом, только если withSecret: секрет отдается клиенту лишь при создании и ротации
+func webhookFromRow(row repository.WebhookEndpoint, withSecret bool) (*model.Webhook, error) {
+	id, err := utils.PgxUUIDToGoogleUUID(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("webhook_id: %w", err)
+	}
+
+	eventTypes := make([]model.EventType, 0, len(row.EventTypes))
+	for _, eventType := range row.EventTypes {
+		eventTypes = append(eventTypes, model.EventType(eventType))
+	}
+
+	webhook := &model.Webhook{
+		ID:                      id,
+		URL:                     row.Url,
+		Description:             row.Description,
+		EventTypes:              eventTypes,
+		PreviousSecretExpiresAt: timeFromPg(row.PreviousSecretExpiresAt),
+		CreatedAt:               timeFromPg(row.CreatedAt),
+		SecretRotatedAt:         timeFromPg(row.SecretRotatedAt),
+	}
+	if withSecret {
+		webhook.Secret = row.Secret
+	}
+
+	return webhook, nil
+}
+
+func webhookDeliveryFromRow(row repository.WebhookDelivery) (*model.WebhookDelivery, error) {
+	webhookID, err := utils.PgxUUIDToGoogleUUID(row.EndpointID)
+	if err != nil {
+		return nil, fmt.Errorf("webhook_id: %w", err)
+	}
+
+	return &model.WebhookDelivery{
+		ID:             row.ID,
+		WebhookID:      webhookID,
+		EventID:        row.EventID,
+		EventType:      model.EventType(row.EventType),
+		Payload:        row.Payload,
+		Status:         model.WebhookDeliveryStatus(row.Status),
+		Attempts:       row.Attempts,
+		NextAttemptAt:  timeFromPg(row.NextAttemptAt),
+		LastAttemptAt:  timeFromPg(row.LastAttemptAt),
+		ResponseStatus: row.ResponseStatus.Int32,
+		ResponseBody:   row.ResponseBody.String,
+		LastError:      row.LastError.String,
+		CreatedAt:      timeFromPg(row.CreatedAt),
+		DeliveredAt:    timeFromPg(row.DeliveredAt),
+	}, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresWebhookRepository(t *testing.T) {
+	pool, cleanup := setupTestContainer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	log := logger.Setup("text", "warn")
+	repo := NewPostgresWebhookRepository(pool, log)
+
+	billing, err := repo.CreateWebhook(ctx, model.Webhook{
+		URL:        "https://billing.example.com/hooks",
+		EventTypes: []model.EventType{model.EventSubscriptionCreated},
+		Secret:     "whsec_billing",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "whsec_billing", billing.Secret)
+
+	audit, err := repo.CreateWebhook(ctx, model.Webhook{URL: "https://audit.example.com/hooks", Secret: "whsec_audit"})
+	assert.NoError(t, err)
+
+	webhooks, err := repo.ListWebhooks(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, webhooks, 2) {
+		// секрет после создания не возвращается
+		assert.Empty(t, webhooks[0].Secret)
+		assert.Equal(t, []model.EventType{model.EventSubscriptionCreated}, webhooks[0].EventTypes)
+	}
+
+	count, err := repo.EnqueueWebhookDeliveries(ctx, 1, model.EventSubscriptionCreated, []byte(`{"id":1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = repo.EnqueueWebhookDeliveries(ctx, 2, model.EventSubscriptionUpdated, []byte(`{"id":2}`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// повторная публикация события доставки не дублирует
+	count, err = repo.EnqueueWebhookDeliveries(ctx, 1, model.EventSubscriptionCreated, []byte(`{"id":1}`))
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+
+	_, err = repo.RotateWebhookSecret(ctx, billing.ID, "whsec_billing_2", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	requests, err := repo.ClaimWebhookDeliveries(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if !assert.Len(t, requests, 3) {
+		return
+	}
+	for _, request := range requests {
+		if request.WebhookID == billing.ID {
+			assert.Equal(t, []string{"whsec_billing_2", "whsec_billing"}, request.Secrets)
+		} else {
+			assert.Equal(t, []string{"whsec_audit"}, request.Secrets)
+		}
+	}
+
+	// взятые в работу доставки не достаются повторно до окончания аренды
+	claimed, err := repo.ClaimWebhookDeliveries(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	for _, request := range requests {
+		response := model.WebhookResponse{Status: 200, Body: "ok"}
+		status := model.WebhookDeliverySucceeded
+		if request.WebhookID == audit.ID {
+			response = model.WebhookResponse{Status: 503, Body: "unavailable", Err: errors.New("webhook receiver responded with status 503")}
+			status = model.WebhookDeliveryFailed
+		}
+
+		assert.NoError(t, repo.RecordWebhookAttempt(ctx, request.DeliveryID, status, time.Now(), response))
+	}
+
+	deliveries, err := repo.ListWebhookDeliveries(ctx, model.WebhookDeliveryFilter{
+		WebhookID: audit.ID,
+		Status:    model.WebhookDeliveryFailed,
+		PageSize:  10,
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, deliveries, 2) {
+		return
+	}
+	assert.Equal(t, int64(2), deliveries[0].EventID)
+	assert.Equal(t, int32(1), deliveries[0].Attempts)
+	assert.Equal(t, int32(503), deliveries[0].ResponseStatus)
+	assert.Equal(t, "unavailable", deliveries[0].ResponseBody)
+	assert.NotEmpty(t, deliveries[0].LastError)
+	assert.JSONEq(t, `{"id":2}`, string(deliveries[0].Payload))
+
+	replayed, err := repo.ReplayWebhookDelivery(ctx, audit.ID, deliveries[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.WebhookDeliveryPending, replayed.Status)
+	assert.Zero(t, replayed.Attempts)
+
+	_, err = repo.ReplayWebhookDelivery(ctx, billing.ID, deliveries[0].ID)
+	assert.ErrorIs(t, err, model.ErrWebhookDeliveryNotFound)
+
+	requests, err = repo.ClaimWebhookDeliveries(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+
+	purged, err := repo.DeleteFinishedWebhookDeliveries(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	assert.NoError(t, repo.DeleteWebhook(ctx, audit.ID))
+	assert.ErrorIs(t, repo.DeleteWebhook(ctx, audit.ID), model.ErrWebhookNotFound)
+
+	_, err = repo.RotateWebhookSecret(ctx, uuid.New(), "whsec", time.Time{})
+	assert.ErrorIs(t, err, model.ErrWebhookNotFound)
+}
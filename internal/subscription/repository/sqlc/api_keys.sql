@@ -0,0 +1,32 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (tenant_id, name, prefix, key_hash, scopes, user_id, expires_at)
+VALUES (sqlc.arg(tenant_id)::TEXT, sqlc.arg(name)::TEXT, sqlc.arg(prefix)::TEXT, sqlc.arg(key_hash)::BYTEA, sqlc.arg(scopes)::TEXT[],
+        sqlc.narg(user_id)::UUID, sqlc.narg(expires_at)::TIMESTAMPTZ)
+RETURNING id, name, prefix, key_hash, scopes, user_id, created_at, expires_at, last_used_at, revoked_at, tenant_id;
+
+-- name: ListAPIKeys :many
+SELECT id, name, prefix, key_hash, scopes, user_id, created_at, expires_at, last_used_at, revoked_at, tenant_id
+FROM api_keys
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+ORDER BY created_at, id;
+
+-- name: RevokeAPIKey :one
+-- Повторный отзыв не меняет время отзыва
+UPDATE api_keys
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(id)::UUID
+RETURNING id, name, prefix, key_hash, scopes, user_id, created_at, expires_at, last_used_at, revoked_at, tenant_id;
+
+-- name: GetActiveAPIKeyByHash :one
+-- Возвращает действующий ключ любого арендатора: не отозванный и не истекший. Арендатора запроса определяет ключ
+SELECT id, name, prefix, key_hash, scopes, user_id, created_at, expires_at, last_used_at, revoked_at, tenant_id
+FROM api_keys
+WHERE key_hash = sqlc.arg(key_hash)::BYTEA
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > now());
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = sqlc.arg(id)::UUID;
@@ -0,0 +1,10 @@
+-- name: CreateSubscriptionAuditRecords :copyfrom
+INSERT INTO subscription_audit_log (tenant_id, subscription_id, action, actor, claimed_actor, request_id, before, after, changes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListSubscriptionAuditRecords :many
+SELECT id, subscription_id, action, actor, request_id, before, after, changes, created_at, tenant_id, claimed_actor
+FROM subscription_audit_log
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND subscription_id = sqlc.arg(subscription_id)::uuid
+ORDER BY id;
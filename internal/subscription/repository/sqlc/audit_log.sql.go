@@ -0,0 +1,69 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit_log.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateSubscriptionAuditRecordsParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+	Action         string
+	Actor          string
+	ClaimedActor   pgtype.Text
+	RequestID      pgtype.Text
+	Before         []byte
+	After          []byte
+	Changes        []byte
+}
+
+const listSubscriptionAuditRecords = `-- name: ListSubscriptionAuditRecords :many
+SELECT id, subscription_id, action, actor, request_id, before, after, changes, created_at, tenant_id, claimed_actor
+FROM subscription_audit_log
+WHERE tenant_id = $1::TEXT
+  AND subscription_id = $2::uuid
+ORDER BY id
+`
+
+type ListSubscriptionAuditRecordsParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+func (q *Queries) ListSubscriptionAuditRecords(ctx context.Context, arg ListSubscriptionAuditRecordsParams) ([]SubscriptionAuditLog, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionAuditRecords, arg.TenantID, arg.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionAuditLog
+	for rows.Next() {
+		var i SubscriptionAuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.Action,
+			&i.Actor,
+			&i.RequestID,
+			&i.Before,
+			&i.After,
+			&i.Changes,
+			&i.CreatedAt,
+			&i.TenantID,
+			&i.ClaimedActor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,86 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: copyfrom.go
+
+package repository
+
+import (
+	"context"
+)
+
+// iteratorForCreateOutboxEvents implements pgx.CopyFromSource.
+type iteratorForCreateOutboxEvents struct {
+	rows                 []CreateOutboxEventsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateOutboxEvents) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateOutboxEvents) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].TenantID,
+		r.rows[0].SubscriptionID,
+		r.rows[0].UserID,
+		r.rows[0].EventType,
+		r.rows[0].Payload,
+	}, nil
+}
+
+func (r iteratorForCreateOutboxEvents) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateOutboxEvents(ctx context.Context, arg []CreateOutboxEventsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"subscription_outbox"}, []string{"tenant_id", "subscription_id", "user_id", "event_type", "payload"}, &iteratorForCreateOutboxEvents{rows: arg})
+}
+
+// iteratorForCreateSubscriptionAuditRecords implements pgx.CopyFromSource.
+type iteratorForCreateSubscriptionAuditRecords struct {
+	rows                 []CreateSubscriptionAuditRecordsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateSubscriptionAuditRecords) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateSubscriptionAuditRecords) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].TenantID,
+		r.rows[0].SubscriptionID,
+		r.rows[0].Action,
+		r.rows[0].Actor,
+		r.rows[0].ClaimedActor,
+		r.rows[0].RequestID,
+		r.rows[0].Before,
+		r.rows[0].After,
+		r.rows[0].Changes,
+	}, nil
+}
+
+func (r iteratorForCreateSubscriptionAuditRecords) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateSubscriptionAuditRecords(ctx context.Context, arg []CreateSubscriptionAuditRecordsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"subscription_audit_log"}, []string{"tenant_id", "subscription_id", "action", "actor", "claimed_actor", "request_id", "before", "after", "changes"}, &iteratorForCreateSubscriptionAuditRecords{rows: arg})
+}
@@ -0,0 +1,15 @@
+-- name: UpsertExchangeRate :exec
+INSERT INTO exchange_rates (currency, valid_from, rate)
+VALUES (sqlc.arg(currency)::TEXT, sqlc.arg(valid_from)::DATE, sqlc.arg(rate)::TEXT::NUMERIC)
+ON CONFLICT (currency, valid_from) DO UPDATE SET rate = excluded.rate;
+
+-- name: ListExchangeRatesForPeriod :many
+SELECT r.currency, r.valid_from, r.rate::TEXT AS rate
+FROM exchange_rates r
+WHERE r.currency = ANY (sqlc.arg(currencies)::TEXT[])
+  AND r.valid_from <= sqlc.arg(end_date)::DATE
+  AND r.valid_from >= COALESCE((SELECT MAX(p.valid_from)
+                                FROM exchange_rates p
+                                WHERE p.currency = r.currency
+                                  AND p.valid_from <= sqlc.arg(start_date)::DATE), sqlc.arg(start_date)::DATE)
+ORDER BY r.currency, r.valid_from;
@@ -0,0 +1,73 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: exchange_rates.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listExchangeRatesForPeriod = `-- name: ListExchangeRatesForPeriod :many
+SELECT r.currency, r.valid_from, r.rate::TEXT AS rate
+FROM exchange_rates r
+WHERE r.currency = ANY ($1::TEXT[])
+  AND r.valid_from <= $2::DATE
+  AND r.valid_from >= COALESCE((SELECT MAX(p.valid_from)
+                                FROM exchange_rates p
+                                WHERE p.currency = r.currency
+                                  AND p.valid_from <= $3::DATE), $3::DATE)
+ORDER BY r.currency, r.valid_from
+`
+
+type ListExchangeRatesForPeriodParams struct {
+	Currencies []string
+	EndDate    pgtype.Date
+	StartDate  pgtype.Date
+}
+
+type ListExchangeRatesForPeriodRow struct {
+	Currency  string
+	ValidFrom pgtype.Date
+	Rate      string
+}
+
+func (q *Queries) ListExchangeRatesForPeriod(ctx context.Context, arg ListExchangeRatesForPeriodParams) ([]ListExchangeRatesForPeriodRow, error) {
+	rows, err := q.db.Query(ctx, listExchangeRatesForPeriod, arg.Currencies, arg.EndDate, arg.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListExchangeRatesForPeriodRow
+	for rows.Next() {
+		var i ListExchangeRatesForPeriodRow
+		if err := rows.Scan(&i.Currency, &i.ValidFrom, &i.Rate); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertExchangeRate = `-- name: UpsertExchangeRate :exec
+INSERT INTO exchange_rates (currency, valid_from, rate)
+VALUES ($1::TEXT, $2::DATE, $3::TEXT::NUMERIC)
+ON CONFLICT (currency, valid_from) DO UPDATE SET rate = excluded.rate
+`
+
+type UpsertExchangeRateParams struct {
+	Currency  string
+	ValidFrom pgtype.Date
+	Rate      string
+}
+
+func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) error {
+	_, err := q.db.Exec(ctx, upsertExchangeRate, arg.Currency, arg.ValidFrom, arg.Rate)
+	return err
+}
@@ -0,0 +1,38 @@
+-- name: ClaimIdempotencyKey :one
+-- Занимает ключ, если он свободен, срок его хранения истек или запрос с этим ключом не завершился до stale_before.
+-- Если ключ занят, строка не возвращается
+INSERT INTO idempotency_keys (tenant_id, key, method, request_hash, expires_at)
+VALUES (sqlc.arg(tenant_id)::TEXT, sqlc.arg(key)::TEXT, sqlc.arg(method)::TEXT, sqlc.arg(request_hash)::BYTEA, sqlc.arg(expires_at)::TIMESTAMPTZ)
+ON CONFLICT (tenant_id, key) DO UPDATE SET method       = excluded.method,
+                                request_hash = excluded.request_hash,
+                                response     = NULL,
+                                created_at   = now(),
+                                expires_at   = excluded.expires_at
+WHERE idempotency_keys.expires_at <= now()
+   OR (idempotency_keys.response IS NULL AND idempotency_keys.created_at <= sqlc.arg(stale_before)::TIMESTAMPTZ)
+RETURNING key;
+
+-- name: GetIdempotencyKey :one
+SELECT key, method, request_hash, response, created_at, expires_at, tenant_id
+FROM idempotency_keys
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND key = sqlc.arg(key)::TEXT;
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys
+SET response = sqlc.arg(response)::BYTEA
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND key = sqlc.arg(key)::TEXT
+  AND response IS NULL;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE
+FROM idempotency_keys
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND key = sqlc.arg(key)::TEXT
+  AND response IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE
+FROM idempotency_keys
+WHERE expires_at <= now();
@@ -0,0 +1,128 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: idempotency_keys.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (tenant_id, key, method, request_hash, expires_at)
+VALUES ($1::TEXT, $2::TEXT, $3::TEXT, $4::BYTEA, $5::TIMESTAMPTZ)
+ON CONFLICT (tenant_id, key) DO UPDATE SET method       = excluded.method,
+                                request_hash = excluded.request_hash,
+                                response     = NULL,
+                                created_at   = now(),
+                                expires_at   = excluded.expires_at
+WHERE idempotency_keys.expires_at <= now()
+   OR (idempotency_keys.response IS NULL AND idempotency_keys.created_at <= $6::TIMESTAMPTZ)
+RETURNING key
+`
+
+type ClaimIdempotencyKeyParams struct {
+	TenantID    string
+	Key         string
+	Method      string
+	RequestHash []byte
+	ExpiresAt   pgtype.Timestamptz
+	StaleBefore pgtype.Timestamptz
+}
+
+// Занимает ключ, если он свободен, срок его хранения истек или запрос с этим ключом не завершился до stale_before.
+// Если ключ занят, строка не возвращается
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (string, error) {
+	row := q.db.QueryRow(ctx, claimIdempotencyKey,
+		arg.TenantID,
+		arg.Key,
+		arg.Method,
+		arg.RequestHash,
+		arg.ExpiresAt,
+		arg.StaleBefore,
+	)
+	var key string
+	err := row.Scan(&key)
+	return key, err
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE
+FROM idempotency_keys
+WHERE expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteIdempotencyKey = `-- name: DeleteIdempotencyKey :exec
+DELETE
+FROM idempotency_keys
+WHERE tenant_id = $1::TEXT
+  AND key = $2::TEXT
+  AND response IS NULL
+`
+
+type DeleteIdempotencyKeyParams struct {
+	TenantID string
+	Key      string
+}
+
+func (q *Queries) DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, deleteIdempotencyKey, arg.TenantID, arg.Key)
+	return err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT key, method, request_hash, response, created_at, expires_at, tenant_id
+FROM idempotency_keys
+WHERE tenant_id = $1::TEXT
+  AND key = $2::TEXT
+`
+
+type GetIdempotencyKeyParams struct {
+	TenantID string
+	Key      string
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.TenantID, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Key,
+		&i.Method,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const saveIdempotencyResponse = `-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys
+SET response = $1::BYTEA
+WHERE tenant_id = $2::TEXT
+  AND key = $3::TEXT
+  AND response IS NULL
+`
+
+type SaveIdempotencyResponseParams struct {
+	Response []byte
+	TenantID string
+	Key      string
+}
+
+func (q *Queries) SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error {
+	_, err := q.db.Exec(ctx, saveIdempotencyResponse, arg.Response, arg.TenantID, arg.Key)
+	return err
+}
@@ -8,11 +8,157 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// API-ключи машинных клиентов
+type ApiKey struct {
+	ID   pgtype.UUID
+	Name string
+	// Начало ключа, по которому его можно узнать. Сам ключ не хранится
+	Prefix string
+	// SHA-256 ключа
+	KeyHash []byte
+	Scopes  []string
+	// Пользователь, подписками которого ограничен ключ. NULL - подписки всех пользователей
+	UserID    pgtype.UUID
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	// Время последнего использования, обновляется не чаще раза в минуту
+	LastUsedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	TenantID   string
+}
+
+type ExchangeRate struct {
+	Currency  string
+	ValidFrom pgtype.Date
+	Rate      pgtype.Numeric
+}
+
+type IdempotencyKey struct {
+	Key         string
+	Method      string
+	RequestHash []byte
+	// Ответ на запрос, NULL - запрос еще выполняется
+	Response  []byte
+	CreatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+	TenantID  string
+}
+
 type Subscription struct {
 	ID          pgtype.UUID
 	UserID      pgtype.UUID
 	ServiceName string
-	Price       int32
-	StartDate   pgtype.Date
-	EndDate     pgtype.Date
+	// Стоимость в минимальных единицах валюты (копейках, центах)
+	Price           int64
+	StartDate       pgtype.Date
+	EndDate         pgtype.Date
+	Currency        string
+	BillingUnit     string
+	BillingInterval int32
+	// Подписка сохранена с пересечением по политике warn или allow и не участвует в ограничении subscriptions_overlap_excl
+	OverlapAllowed bool
+	// Статус жизненного цикла: trial, active, paused, cancelled или expired
+	Status string
+	// Последний месяц бесплатного пробного периода
+	TrialEndDate pgtype.Date
+	// Время удаления: удаленная подписка не участвует в выборках и удаляется окончательно по истечении срока хранения
+	DeletedAt pgtype.Timestamptz
+	// Версия подписки для оптимистичной блокировки, увеличивается при каждом изменении строки
+	Version int64
+	// Арендатор: подписки разных арендаторов не видны друг другу
+	TenantID string
+}
+
+// Журнал изменений подписок: состояние до и после изменения, автор и ID запроса
+type SubscriptionAuditLog struct {
+	ID             int64
+	SubscriptionID pgtype.UUID
+	Action         string
+	// Субъект аутентифицированного клиента, unauthenticated без аутентификации или system для фоновых задач
+	Actor     string
+	RequestID pgtype.Text
+	// Подписка до изменения, NULL при создании
+	Before []byte
+	// Подписка после изменения, NULL при окончательном удалении
+	After []byte
+	// Измененные поля: [{"field": ..., "before": ..., "after": ...}]
+	Changes   []byte
+	CreatedAt pgtype.Timestamptz
+	TenantID  string
+	// Значение заголовка X-Actor. Задается клиентом и не проверяется
+	ClaimedActor pgtype.Text
+}
+
+// Исходящие события об изменении подписок (transactional outbox)
+type SubscriptionOutbox struct {
+	ID             int64
+	SubscriptionID pgtype.UUID
+	EventType      string
+	Payload        []byte
+	CreatedAt      pgtype.Timestamptz
+	// Время публикации, NULL у еще не опубликованного события
+	PublishedAt pgtype.Timestamptz
+	// Количество неудачных попыток публикации
+	Attempts  int32
+	LastError pgtype.Text
+	// Пользователь подписки после изменения, по нему фильтруются подписки на изменения
+	UserID   pgtype.UUID
+	TenantID string
+	// Время, когда попытки публикации исчерпаны. Такое событие больше не публикуется и не задерживает следующие события подписки
+	FailedAt pgtype.Timestamptz
+}
+
+// Приостановки подписок: месяцы с start_date по end_date включительно не оплачиваются, end_date пуст у текущей приостановки
+type SubscriptionPause struct {
+	SubscriptionID pgtype.UUID
+	StartDate      pgtype.Date
+	EndDate        pgtype.Date
+	TenantID       string
+}
+
+type SubscriptionPlan struct {
+	SubscriptionID pgtype.UUID
+	ValidFrom      pgtype.Date
+	ServiceName    string
+	// Стоимость в минимальных единицах валюты подписки
+	Price int64
+	// Фаза тарифа: trial - пробный период, promo - льготная цена, regular - обычная цена
+	Phase    string
+	TenantID string
+}
+
+type WebhookDelivery struct {
+	ID         int64
+	EndpointID pgtype.UUID
+	EventID    int64
+	EventType  string
+	Payload    []byte
+	Status     string
+	Attempts   int32
+	// Время следующей попытки доставки, для отправляемой доставки - окончание аренды
+	NextAttemptAt pgtype.Timestamptz
+	LastAttemptAt pgtype.Timestamptz
+	// HTTP-статус ответа получателя на последнюю попытку
+	ResponseStatus pgtype.Int4
+	ResponseBody   pgtype.Text
+	LastError      pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	DeliveredAt    pgtype.Timestamptz
+	TenantID       string
+}
+
+// Получатели событий об изменении подписок
+type WebhookEndpoint struct {
+	ID          pgtype.UUID
+	Url         string
+	Description string
+	// Типы событий, которые получает вебхук. Пустой список - все события
+	EventTypes []string
+	Secret     string
+	// Секрет до ротации, которым запросы подписываются до previous_secret_expires_at
+	PreviousSecret          pgtype.Text
+	PreviousSecretExpiresAt pgtype.Timestamptz
+	CreatedAt               pgtype.Timestamptz
+	SecretRotatedAt         pgtype.Timestamptz
+	TenantID                string
 }
@@ -0,0 +1,65 @@
+-- name: CreateOutboxEvents :copyfrom
+INSERT INTO subscription_outbox (tenant_id, subscription_id, user_id, event_type, payload)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListPendingOutboxEvents :many
+-- Первые неопубликованные события подписок всех арендаторов в порядке записи. Следующее событие подписки выбирается
+-- только после публикации предыдущего, поэтому недоставляемое событие не задерживает события других подписок
+SELECT id, tenant_id, subscription_id, event_type, payload, created_at, attempts
+FROM (SELECT DISTINCT ON (subscription_id) id, tenant_id, subscription_id, event_type, payload, created_at, attempts
+      FROM subscription_outbox
+      WHERE published_at IS NULL
+        AND failed_at IS NULL
+      ORDER BY subscription_id, id) pending
+ORDER BY id
+LIMIT sqlc.arg(batch_size)::INT;
+
+-- name: ListOutboxEventsByIDs :many
+-- События всех арендаторов: раздаются наблюдателям своих арендаторов
+SELECT id, tenant_id, payload
+FROM subscription_outbox
+WHERE id = ANY (sqlc.arg(ids)::BIGINT[])
+ORDER BY id;
+
+-- name: ListOutboxEventsAfter :many
+-- События арендатора после события after_id в порядке записи, при заданном user_id - только события подписок пользователя
+SELECT id, tenant_id, payload
+FROM subscription_outbox
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id > sqlc.arg(after_id)::BIGINT
+  AND (sqlc.narg(user_id)::UUID IS NULL OR user_id = sqlc.narg(user_id)::UUID)
+ORDER BY id
+LIMIT sqlc.arg(batch_size)::INT;
+
+-- name: OutboxEventExists :one
+SELECT EXISTS(SELECT 1
+              FROM subscription_outbox
+              WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+                AND id = sqlc.arg(id)::BIGINT);
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE subscription_outbox
+SET published_at = now(),
+    last_error   = NULL
+WHERE id = sqlc.arg(id)::BIGINT;
+
+-- name: MarkOutboxEventFailed :exec
+-- Сохраняет ошибку публикации. При dead_letter событие больше не публикуется
+UPDATE subscription_outbox
+SET attempts   = attempts + 1,
+    last_error = sqlc.arg(last_error)::TEXT,
+    failed_at  = CASE WHEN sqlc.arg(dead_letter)::BOOLEAN THEN now() END
+WHERE id = sqlc.arg(id)::BIGINT;
+
+-- name: DeletePublishedOutboxEvents :execrows
+-- Удаляет события, опубликованные до момента before
+DELETE
+FROM subscription_outbox
+WHERE published_at < sqlc.arg(before)::TIMESTAMPTZ;
+
+-- name: TryAdvisoryLock :one
+-- Сессионная блокировка: держится, пока соединение не вызовет AdvisoryUnlock или не закроется
+SELECT pg_try_advisory_lock(sqlc.arg(key)::BIGINT);
+
+-- name: AdvisoryUnlock :exec
+SELECT pg_advisory_unlock(sqlc.arg(key)::BIGINT);
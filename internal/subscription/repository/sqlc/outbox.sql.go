@@ -0,0 +1,241 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: outbox.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const advisoryUnlock = `-- name: AdvisoryUnlock :exec
+SELECT pg_advisory_unlock($1::BIGINT)
+`
+
+func (q *Queries) AdvisoryUnlock(ctx context.Context, key int64) error {
+	_, err := q.db.Exec(ctx, advisoryUnlock, key)
+	return err
+}
+
+type CreateOutboxEventsParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+	UserID         pgtype.UUID
+	EventType      string
+	Payload        []byte
+}
+
+const deletePublishedOutboxEvents = `-- name: DeletePublishedOutboxEvents :execrows
+DELETE
+FROM subscription_outbox
+WHERE published_at < $1::TIMESTAMPTZ
+`
+
+// Удаляет события, опубликованные до момента before
+func (q *Queries) DeletePublishedOutboxEvents(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePublishedOutboxEvents, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listOutboxEventsAfter = `-- name: ListOutboxEventsAfter :many
+SELECT id, tenant_id, payload
+FROM subscription_outbox
+WHERE tenant_id = $1::TEXT
+  AND id > $2::BIGINT
+  AND ($3::UUID IS NULL OR user_id = $3::UUID)
+ORDER BY id
+LIMIT $4::INT
+`
+
+type ListOutboxEventsAfterParams struct {
+	TenantID  string
+	AfterID   int64
+	UserID    pgtype.UUID
+	BatchSize int32
+}
+
+type ListOutboxEventsAfterRow struct {
+	ID       int64
+	TenantID string
+	Payload  []byte
+}
+
+// События арендатора после события after_id в порядке записи, при заданном user_id - только события подписок пользователя
+func (q *Queries) ListOutboxEventsAfter(ctx context.Context, arg ListOutboxEventsAfterParams) ([]ListOutboxEventsAfterRow, error) {
+	rows, err := q.db.Query(ctx, listOutboxEventsAfter,
+		arg.TenantID,
+		arg.AfterID,
+		arg.UserID,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOutboxEventsAfterRow
+	for rows.Next() {
+		var i ListOutboxEventsAfterRow
+		if err := rows.Scan(&i.ID, &i.TenantID, &i.Payload); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutboxEventsByIDs = `-- name: ListOutboxEventsByIDs :many
+SELECT id, tenant_id, payload
+FROM subscription_outbox
+WHERE id = ANY ($1::BIGINT[])
+ORDER BY id
+`
+
+type ListOutboxEventsByIDsRow struct {
+	ID       int64
+	TenantID string
+	Payload  []byte
+}
+
+// События всех арендаторов: раздаются наблюдателям своих арендаторов
+func (q *Queries) ListOutboxEventsByIDs(ctx context.Context, ids []int64) ([]ListOutboxEventsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, listOutboxEventsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOutboxEventsByIDsRow
+	for rows.Next() {
+		var i ListOutboxEventsByIDsRow
+		if err := rows.Scan(&i.ID, &i.TenantID, &i.Payload); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingOutboxEvents = `-- name: ListPendingOutboxEvents :many
+SELECT id, tenant_id, subscription_id, event_type, payload, created_at, attempts
+FROM (SELECT DISTINCT ON (subscription_id) id, tenant_id, subscription_id, event_type, payload, created_at, attempts
+      FROM subscription_outbox
+      WHERE published_at IS NULL
+        AND failed_at IS NULL
+      ORDER BY subscription_id, id) pending
+ORDER BY id
+LIMIT $1::INT
+`
+
+type ListPendingOutboxEventsRow struct {
+	ID             int64
+	TenantID       string
+	SubscriptionID pgtype.UUID
+	EventType      string
+	Payload        []byte
+	CreatedAt      pgtype.Timestamptz
+	Attempts       int32
+}
+
+// Первые неопубликованные события подписок всех арендаторов в порядке записи. Следующее событие подписки выбирается
+// только после публикации предыдущего, поэтому недоставляемое событие не задерживает события других подписок
+func (q *Queries) ListPendingOutboxEvents(ctx context.Context, batchSize int32) ([]ListPendingOutboxEventsRow, error) {
+	rows, err := q.db.Query(ctx, listPendingOutboxEvents, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPendingOutboxEventsRow
+	for rows.Next() {
+		var i ListPendingOutboxEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.SubscriptionID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.Attempts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventFailed = `-- name: MarkOutboxEventFailed :exec
+UPDATE subscription_outbox
+SET attempts   = attempts + 1,
+    last_error = $1::TEXT,
+    failed_at  = CASE WHEN $2::BOOLEAN THEN now() END
+WHERE id = $3::BIGINT
+`
+
+type MarkOutboxEventFailedParams struct {
+	LastError  string
+	DeadLetter bool
+	ID         int64
+}
+
+// Сохраняет ошибку публикации. При dead_letter событие больше не публикуется
+func (q *Queries) MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error {
+	_, err := q.db.Exec(ctx, markOutboxEventFailed, arg.LastError, arg.DeadLetter, arg.ID)
+	return err
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :exec
+UPDATE subscription_outbox
+SET published_at = now(),
+    last_error   = NULL
+WHERE id = $1::BIGINT
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markOutboxEventPublished, id)
+	return err
+}
+
+const outboxEventExists = `-- name: OutboxEventExists :one
+SELECT EXISTS(SELECT 1
+              FROM subscription_outbox
+              WHERE tenant_id = $1::TEXT
+                AND id = $2::BIGINT)
+`
+
+type OutboxEventExistsParams struct {
+	TenantID string
+	ID       int64
+}
+
+func (q *Queries) OutboxEventExists(ctx context.Context, arg OutboxEventExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, outboxEventExists, arg.TenantID, arg.ID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const tryAdvisoryLock = `-- name: TryAdvisoryLock :one
+SELECT pg_try_advisory_lock($1::BIGINT)
+`
+
+// Сессионная блокировка: держится, пока соединение не вызовет AdvisoryUnlock или не закроется
+func (q *Queries) TryAdvisoryLock(ctx context.Context, key int64) (bool, error) {
+	row := q.db.QueryRow(ctx, tryAdvisoryLock, key)
+	var pg_try_advisory_lock bool
+	err := row.Scan(&pg_try_advisory_lock)
+	return pg_try_advisory_lock, err
+}
@@ -1,43 +1,337 @@
 -- name: CreateSubscription :one
-INSERT INTO subscriptions (user_id, service_name, price, start_date, end_date)
-VALUES (sqlc.arg(user_id)::uuid, sqlc.arg(service_name)::TEXT, sqlc.arg(price)::INT, sqlc.arg(start_date)::DATE,
-        sqlc.narg(end_date)::DATE)
-RETURNING id, user_id, service_name, price, start_date, end_date;
+INSERT INTO subscriptions (tenant_id, user_id, service_name, price, currency, billing_unit, billing_interval, start_date,
+                           end_date, overlap_allowed, status, trial_end_date)
+VALUES (sqlc.arg(tenant_id)::TEXT, sqlc.arg(user_id)::uuid, sqlc.arg(service_name)::TEXT, sqlc.arg(price)::BIGINT, sqlc.arg(currency)::TEXT,
+        COALESCE(sqlc.narg(billing_unit)::TEXT, 'month'), COALESCE(sqlc.narg(billing_interval)::INT, 1),
+        sqlc.arg(start_date)::DATE,
+        sqlc.narg(end_date)::DATE,
+        sqlc.arg(overlap_allowed)::BOOLEAN, COALESCE(sqlc.narg(status)::TEXT, 'active'),
+        sqlc.narg(trial_end_date)::DATE)
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
 
 -- name: GetSubscriptionById :one
-SELECT id, user_id, service_name, price, start_date, end_date
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 FROM subscriptions
-WHERE id = sqlc.arg(subscription_id);
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(subscription_id)
+  AND deleted_at IS NULL;
+
+-- name: GetSubscriptionOwner :one
+-- Пользователь подписки, в том числе удаленной: проверка доступа клиента к подписке
+SELECT user_id
+FROM subscriptions
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(subscription_id);
+
+-- name: LockSubscription :one
+-- Подписка, в том числе удаленная, с блокировкой до конца транзакции: состояние до изменения для журнала аудита
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+FROM subscriptions
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(subscription_id)::uuid
+FOR UPDATE;
 
 -- name: AllSubscriptions :many
-SELECT id, user_id, service_name, price, start_date, end_date
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 FROM subscriptions
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND CASE sqlc.arg(deleted)::TEXT
+          WHEN 'include' THEN TRUE
+          WHEN 'only' THEN deleted_at IS NOT NULL
+          ELSE deleted_at IS NULL
+          END
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id)::uuid)
+  AND (sqlc.narg(service_name)::TEXT IS NULL OR lower(service_name) = lower(sqlc.narg(service_name)::TEXT))
+  AND (sqlc.narg(service_name_pattern)::TEXT IS NULL OR lower(service_name) LIKE lower(sqlc.narg(service_name_pattern)::TEXT))
+  AND (sqlc.narg(service_name_similar)::TEXT IS NULL OR sqlc.narg(service_name_similar)::TEXT <% service_name)
+  AND (sqlc.narg(active_at)::DATE IS NULL OR
+       (start_date <= sqlc.narg(active_at)::DATE AND (end_date IS NULL OR end_date >= sqlc.narg(active_at)::DATE)))
+  AND (sqlc.narg(min_price)::BIGINT IS NULL OR price >= sqlc.narg(min_price)::BIGINT *
+      CASE WHEN sqlc.arg(price_in_major_units)::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND (sqlc.narg(max_price)::BIGINT IS NULL OR price <= sqlc.narg(max_price)::BIGINT *
+      CASE WHEN sqlc.arg(price_in_major_units)::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND (sqlc.narg(price_currency)::TEXT IS NULL OR currency = sqlc.narg(price_currency)::TEXT)
+  AND (NOT sqlc.arg(open_ended_only)::BOOLEAN OR end_date IS NULL)
+  AND (sqlc.narg(after_id)::uuid IS NULL OR CASE
+    WHEN sqlc.arg(order_by)::TEXT = 'price' AND NOT sqlc.arg(order_desc)::BOOLEAN
+        THEN (price, id) > (sqlc.narg(after_price)::BIGINT, sqlc.narg(after_id)::uuid)
+    WHEN sqlc.arg(order_by)::TEXT = 'price'
+        THEN (price, id) < (sqlc.narg(after_price)::BIGINT, sqlc.narg(after_id)::uuid)
+    WHEN sqlc.arg(order_by)::TEXT = 'service_name' AND NOT sqlc.arg(order_desc)::BOOLEAN
+        THEN (lower(service_name), id) > (lower(sqlc.narg(after_service_name)::TEXT), sqlc.narg(after_id)::uuid)
+    WHEN sqlc.arg(order_by)::TEXT = 'service_name'
+        THEN (lower(service_name), id) < (lower(sqlc.narg(after_service_name)::TEXT), sqlc.narg(after_id)::uuid)
+    WHEN NOT sqlc.arg(order_desc)::BOOLEAN
+        THEN (start_date, id) > (sqlc.narg(after_start_date)::DATE, sqlc.narg(after_id)::uuid)
+    ELSE (start_date, id) < (sqlc.narg(after_start_date)::DATE, sqlc.narg(after_id)::uuid)
+    END)
+ORDER BY CASE WHEN sqlc.arg(order_by)::TEXT = 'start_date' AND NOT sqlc.arg(order_desc)::BOOLEAN THEN start_date END,
+         CASE WHEN sqlc.arg(order_by)::TEXT = 'start_date' AND sqlc.arg(order_desc)::BOOLEAN THEN start_date END DESC,
+         CASE WHEN sqlc.arg(order_by)::TEXT = 'price' AND NOT sqlc.arg(order_desc)::BOOLEAN THEN price END,
+         CASE WHEN sqlc.arg(order_by)::TEXT = 'price' AND sqlc.arg(order_desc)::BOOLEAN THEN price END DESC,
+         CASE WHEN sqlc.arg(order_by)::TEXT = 'service_name' AND NOT sqlc.arg(order_desc)::BOOLEAN THEN lower(service_name) END,
+         CASE WHEN sqlc.arg(order_by)::TEXT = 'service_name' AND sqlc.arg(order_desc)::BOOLEAN THEN lower(service_name) END DESC,
+         CASE WHEN NOT sqlc.arg(order_desc)::BOOLEAN THEN id END,
+         CASE WHEN sqlc.arg(order_desc)::BOOLEAN THEN id END DESC
 LIMIT sqlc.arg(count)::INT OFFSET sqlc.arg(count)::INT * sqlc.arg(page)::INT;
 
+-- name: CountSubscriptions :one
+SELECT count(*)
+FROM subscriptions
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND CASE sqlc.arg(deleted)::TEXT
+          WHEN 'include' THEN TRUE
+          WHEN 'only' THEN deleted_at IS NOT NULL
+          ELSE deleted_at IS NULL
+          END
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id)::uuid)
+  AND (sqlc.narg(service_name)::TEXT IS NULL OR lower(service_name) = lower(sqlc.narg(service_name)::TEXT))
+  AND (sqlc.narg(service_name_pattern)::TEXT IS NULL OR lower(service_name) LIKE lower(sqlc.narg(service_name_pattern)::TEXT))
+  AND (sqlc.narg(service_name_similar)::TEXT IS NULL OR sqlc.narg(service_name_similar)::TEXT <% service_name)
+  AND (sqlc.narg(active_at)::DATE IS NULL OR
+       (start_date <= sqlc.narg(active_at)::DATE AND (end_date IS NULL OR end_date >= sqlc.narg(active_at)::DATE)))
+  AND (sqlc.narg(min_price)::BIGINT IS NULL OR price >= sqlc.narg(min_price)::BIGINT *
+      CASE WHEN sqlc.arg(price_in_major_units)::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND (sqlc.narg(max_price)::BIGINT IS NULL OR price <= sqlc.narg(max_price)::BIGINT *
+      CASE WHEN sqlc.arg(price_in_major_units)::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND (sqlc.narg(price_currency)::TEXT IS NULL OR currency = sqlc.narg(price_currency)::TEXT)
+  AND (NOT sqlc.arg(open_ended_only)::BOOLEAN OR end_date IS NULL);
+
 -- name: UpdateSubscription :one
 UPDATE subscriptions
-SET user_id      = COALESCE(sqlc.narg(user_id)::uuid, user_id),
-    service_name = COALESCE(sqlc.narg(service_name)::TEXT, service_name),
-    price        = COALESCE(sqlc.narg(price)::INT, price),
-    start_date   = COALESCE(sqlc.narg(start_date)::DATE, start_date),
-    end_date     = COALESCE(sqlc.narg(end_date)::DATE, end_date)
-WHERE id = sqlc.arg(subscription_id)
-RETURNING id, user_id, service_name, price, start_date, end_date;
-
--- name: DeleteSubscription :exec
-DELETE
+SET user_id          = COALESCE(sqlc.narg(user_id)::uuid, user_id),
+    service_name     = COALESCE(sqlc.narg(service_name)::TEXT, service_name),
+    price            = COALESCE(sqlc.narg(price)::BIGINT, price),
+    currency         = COALESCE(sqlc.narg(currency)::TEXT, currency),
+    billing_unit     = COALESCE(sqlc.narg(billing_unit)::TEXT, billing_unit),
+    billing_interval = COALESCE(sqlc.narg(billing_interval)::INT, billing_interval),
+    start_date       = COALESCE(sqlc.narg(start_date)::DATE, start_date),
+    end_date         = CASE
+                           WHEN sqlc.arg(clear_end_date)::BOOLEAN THEN NULL
+                           ELSE COALESCE(sqlc.narg(end_date)::DATE, end_date)
+        END,
+    overlap_allowed  = COALESCE(sqlc.narg(overlap_allowed)::BOOLEAN, overlap_allowed)
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(subscription_id)
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
+
+-- name: FindOverlappingSubscriptions :many
+-- Подписки того же пользователя на тот же сервис, периоды которых пересекаются с указанным
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 FROM subscriptions
-WHERE id = sqlc.arg(subscription_id);
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND user_id = sqlc.arg(user_id)::uuid
+  AND normalize_service_name(service_name) = normalize_service_name(sqlc.arg(service_name)::TEXT)
+  AND subscription_period(start_date, end_date) &&
+      subscription_period(sqlc.arg(start_date)::DATE, sqlc.narg(end_date)::DATE)
+  AND (sqlc.narg(exclude_id)::uuid IS NULL OR id <> sqlc.narg(exclude_id)::uuid)
+  AND deleted_at IS NULL
+ORDER BY start_date, id;
+
+-- name: ListSubscriptionOverlaps :many
+-- Пары пересекающихся подписок одного пользователя на один сервис и общий период каждой пары
+SELECT a.id                                                                          AS subscription_id,
+       b.id                                                                          AS overlapping_id,
+       GREATEST(a.start_date, b.start_date)::DATE                                    AS overlap_start,
+       CASE
+           WHEN a.end_date IS NULL THEN b.end_date
+           WHEN b.end_date IS NULL THEN a.end_date
+           ELSE LEAST(a.end_date, b.end_date)
+           END::DATE                                                                 AS overlap_end
+FROM subscriptions a
+         JOIN subscriptions b
+              ON b.tenant_id = a.tenant_id
+                  AND b.user_id = a.user_id
+                  AND normalize_service_name(b.service_name) = normalize_service_name(a.service_name)
+                  AND subscription_period(b.start_date, b.end_date) && subscription_period(a.start_date, a.end_date)
+                  AND b.id > a.id
+                  AND b.deleted_at IS NULL
+WHERE a.tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND a.deleted_at IS NULL
+  AND (sqlc.narg(user_id)::uuid IS NULL OR a.user_id = sqlc.narg(user_id)::uuid)
+  AND (sqlc.narg(service_name)::TEXT IS NULL OR
+       normalize_service_name(a.service_name) = normalize_service_name(sqlc.narg(service_name)::TEXT))
+ORDER BY a.user_id, normalize_service_name(a.service_name), overlap_start, a.id, b.id;
+
+-- name: ListSubscriptionsByIDs :many
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+FROM subscriptions
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = ANY (sqlc.arg(ids)::uuid[])
+  AND deleted_at IS NULL;
+
+-- name: DeleteSubscriptionsByIDs :many
+UPDATE subscriptions
+SET deleted_at = now()
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = ANY (sqlc.arg(ids)::uuid[])
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
+
+-- name: MergeSubscriptionPeriod :one
+-- Расширяет период подписки до объединения периодов объединяемых подписок. Пометка overlap_allowed снимается,
+-- если подписка больше ни с чем не пересекается
+UPDATE subscriptions s
+SET start_date      = sqlc.arg(start_date)::DATE,
+    end_date        = sqlc.narg(end_date)::DATE,
+    overlap_allowed = EXISTS (SELECT 1
+                              FROM subscriptions o
+                              WHERE o.id <> s.id
+                                AND o.deleted_at IS NULL
+                                AND o.tenant_id = s.tenant_id
+                                AND o.user_id = s.user_id
+                                AND normalize_service_name(o.service_name) = normalize_service_name(s.service_name)
+                                AND subscription_period(o.start_date, o.end_date) &&
+                                    subscription_period(sqlc.arg(start_date)::DATE, sqlc.narg(end_date)::DATE))
+WHERE s.tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND s.id = sqlc.arg(subscription_id)::uuid
+  AND s.deleted_at IS NULL
+RETURNING s.id, s.user_id, s.service_name, s.price, s.start_date, s.end_date, s.currency, s.billing_unit, s.billing_interval,
+          s.overlap_allowed, s.status, s.trial_end_date, s.deleted_at, s.version, s.tenant_id;
+
+-- name: DeleteSubscription :one
+-- Помечает подписку удаленной, окончательно она удаляется PurgeDeletedSubscriptions
+UPDATE subscriptions
+SET deleted_at = now()
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(subscription_id)
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
+
+-- name: UndeleteSubscription :one
+UPDATE subscriptions
+SET deleted_at = NULL
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(subscription_id)::uuid
+  AND deleted_at IS NOT NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
+
+-- name: PurgeDeletedSubscriptions :many
+-- Окончательно удаляет подписки всех арендаторов вместе с тарифами и приостановками. Подписки арендатора
+-- tenant_ids[i] удаляются, если удалены до момента tenant_befores[i], остальных арендаторов - до момента before
+DELETE
+FROM subscriptions s
+WHERE s.deleted_at < COALESCE((sqlc.arg(tenant_befores)::TIMESTAMPTZ[])[array_position(sqlc.arg(tenant_ids)::TEXT[], s.tenant_id)],
+                               sqlc.arg(before)::TIMESTAMPTZ)
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
+
+-- name: ListMonthlyCharges :many
+-- Для каждой подписки и каждого месяца периода считает количество списаний, попавших в этот месяц:
+-- помесячные тарифы списываются раз в billing_interval месяцев от start_date,
+-- понедельные - каждые 7 * billing_interval дней от первого числа месяца start_date.
+-- Цена и наименование берутся из тарифа, действовавшего в этом месяце.
+-- Месяцы без списаний (charges = 0) тоже возвращаются: в них подписка активна. Месяцы приостановки не возвращаются
+WITH charges AS (SELECT s.id,
+                        s.user_id,
+                        plan.service_name,
+                        s.currency,
+                        months.month::DATE AS month,
+                        plan.price,
+                        CASE s.billing_unit
+                            WHEN 'week' THEN
+                                ((months.month + INTERVAL '1 month')::DATE - s.start_date + 7 * s.billing_interval - 1) /
+                                (7 * s.billing_interval) -
+                                (months.month::DATE - s.start_date + 7 * s.billing_interval - 1) /
+                                (7 * s.billing_interval)
+                            ELSE
+                                CASE
+                                    WHEN ((EXTRACT(YEAR FROM months.month) - EXTRACT(YEAR FROM s.start_date)) * 12 +
+                                          EXTRACT(MONTH FROM months.month) - EXTRACT(MONTH FROM s.start_date))::INT %
+                                         s.billing_interval = 0 THEN 1
+                                    ELSE 0
+                                    END
+                            END::INT   AS charges
+                 FROM subscriptions s
+                          JOIN generate_series(sqlc.arg(start_date)::DATE, sqlc.arg(end_date)::DATE,
+                                               INTERVAL '1 month') AS months(month)
+                               ON months.month >= s.start_date AND (s.end_date IS NULL OR months.month <= s.end_date)
+                                   -- месяцы приостановки не оплачиваются
+                                   AND NOT EXISTS (SELECT 1
+                                                   FROM subscription_pauses sp
+                                                   WHERE sp.subscription_id = s.id
+                                                     AND months.month >= sp.start_date
+                                                     AND (sp.end_date IS NULL OR months.month <= sp.end_date))
+                          JOIN LATERAL (SELECT p.service_name, p.price
+                                        FROM subscription_plans p
+                                        WHERE p.subscription_id = s.id
+                                        -- последний тариф, начавшийся не позже месяца, иначе самый ранний
+                                        ORDER BY p.valid_from <= months.month DESC,
+                                                 ABS(months.month::DATE - p.valid_from)
+                                        LIMIT 1) plan ON TRUE
+                 WHERE s.tenant_id = sqlc.arg(tenant_id)::TEXT
+                   AND s.deleted_at IS NULL
+                   AND s.start_date <= sqlc.arg(end_date)::DATE
+                   AND (s.end_date IS NULL OR s.end_date >= sqlc.arg(start_date)::DATE)
+                   AND (sqlc.narg(user_id)::uuid IS NULL OR s.user_id = sqlc.narg(user_id)::uuid))
+SELECT id, user_id, service_name, currency, month, price, charges
+FROM charges
+WHERE (sqlc.narg(service_name)::TEXT IS NULL OR service_name ILIKE sqlc.narg(service_name)::TEXT)
+ORDER BY month, id;
+
+-- name: ChangeSubscriptionStatus :one
+-- Переводит подписку из статуса from_status в статус status. Если статус подписки уже изменился, строка не обновляется.
+-- Непустой end_date заменяет дату окончания (отмена подписки)
+UPDATE subscriptions
+SET status   = sqlc.arg(status)::TEXT,
+    end_date = COALESCE(sqlc.narg(end_date)::DATE, end_date)
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(subscription_id)::uuid
+  AND status = sqlc.arg(from_status)::TEXT
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
+
+-- name: ExpireSubscriptions :many
+-- Подписки всех арендаторов, закончившиеся до месяца before, становятся истекшими. previous_status - статус до изменения
+WITH expiring AS (SELECT id, status
+                  FROM subscriptions
+                  WHERE status IN ('trial', 'active', 'paused', 'cancelled')
+                    AND end_date < sqlc.arg(before)::DATE
+                    AND deleted_at IS NULL
+                      FOR UPDATE)
+UPDATE subscriptions s
+SET status = 'expired'
+FROM expiring e
+WHERE s.id = e.id
+RETURNING s.id, s.user_id, s.service_name, s.price, s.start_date, s.end_date, s.currency, s.billing_unit, s.billing_interval,
+          s.overlap_allowed, s.status, s.trial_end_date, s.deleted_at, s.version, s.tenant_id,
+          e.status AS previous_status;
+
+-- name: CreateSubscriptionPause :exec
+INSERT INTO subscription_pauses (tenant_id, subscription_id, start_date)
+VALUES (sqlc.arg(tenant_id)::TEXT, sqlc.arg(subscription_id)::uuid, sqlc.arg(start_date)::DATE);
+
+-- name: CloseSubscriptionPause :execrows
+-- Завершает текущую приостановку подписки месяцем end_date
+UPDATE subscription_pauses
+SET end_date = sqlc.arg(end_date)::DATE
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND subscription_id = sqlc.arg(subscription_id)::uuid
+  AND end_date IS NULL;
+
+-- name: ListSubscriptionPauses :many
+SELECT subscription_id, start_date, end_date, tenant_id
+FROM subscription_pauses
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND subscription_id = sqlc.arg(subscription_id)::uuid
+ORDER BY start_date;
+
+-- name: ActivateEndedTrials :many
+-- Подписки всех арендаторов, пробный период которых закончился до месяца before, становятся активными
+UPDATE subscriptions
+SET status = 'active'
+WHERE status = 'trial'
+  AND trial_end_date < sqlc.arg(before)::DATE
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id;
 
--- name: GetSumSubscriptions :one
-SELECT COALESCE(SUM(price * (
-    (EXTRACT (YEAR FROM (LEAST(COALESCE(end_date, sqlc.arg(end_date)::DATE), sqlc.arg(end_date)::DATE))) -
-     EXTRACT (YEAR FROM GREATEST(start_date, sqlc.arg(start_date)::DATE))) * 12 +
-    (EXTRACT (MONTH FROM (LEAST(COALESCE(end_date, sqlc.arg(end_date)::DATE), sqlc.arg(end_date)::DATE))) -
-     EXTRACT (MONTH FROM GREATEST(start_date, sqlc.arg(start_date)::DATE))) + 1
-    )), 0)::INT
+-- name: ListEndingTrials :many
+-- Подписки в пробном периоде, последний месяц которого попадает в период с from_date по to_date
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 FROM subscriptions
-WHERE start_date <= sqlc.arg(end_date)::DATE
-  AND (end_date IS NULL OR end_date >= sqlc.arg(start_date)::DATE)
-  AND (sqlc.narg(service_name)::TEXT IS NULL OR service_name ILIKE sqlc.narg(service_name)::TEXT)
-  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id)::uuid);
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND status = 'trial'
+  AND deleted_at IS NULL
+  AND trial_end_date BETWEEN sqlc.arg(from_date)::DATE AND sqlc.arg(to_date)::DATE
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id)::uuid)
+ORDER BY trial_end_date, id;
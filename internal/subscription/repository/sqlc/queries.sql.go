@@ -11,19 +11,143 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const activateEndedTrials = `-- name: ActivateEndedTrials :many
+UPDATE subscriptions
+SET status = 'active'
+WHERE status = 'trial'
+  AND trial_end_date < $1::DATE
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+`
+
+// Подписки всех арендаторов, пробный период которых закончился до месяца before, становятся активными
+func (q *Queries) ActivateEndedTrials(ctx context.Context, before pgtype.Date) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, activateEndedTrials, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Price,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const allSubscriptions = `-- name: AllSubscriptions :many
-SELECT id, user_id, service_name, price, start_date, end_date
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 FROM subscriptions
-LIMIT $1::INT OFFSET $1::INT * $2::INT
+WHERE tenant_id = $1::TEXT
+  AND CASE $2::TEXT
+          WHEN 'include' THEN TRUE
+          WHEN 'only' THEN deleted_at IS NOT NULL
+          ELSE deleted_at IS NULL
+          END
+  AND ($3::uuid IS NULL OR user_id = $3::uuid)
+  AND ($4::TEXT IS NULL OR lower(service_name) = lower($4::TEXT))
+  AND ($5::TEXT IS NULL OR lower(service_name) LIKE lower($5::TEXT))
+  AND ($6::TEXT IS NULL OR $6::TEXT <% service_name)
+  AND ($7::DATE IS NULL OR
+       (start_date <= $7::DATE AND (end_date IS NULL OR end_date >= $7::DATE)))
+  AND ($8::BIGINT IS NULL OR price >= $8::BIGINT *
+      CASE WHEN $9::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND ($10::BIGINT IS NULL OR price <= $10::BIGINT *
+      CASE WHEN $9::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND ($11::TEXT IS NULL OR currency = $11::TEXT)
+  AND (NOT $12::BOOLEAN OR end_date IS NULL)
+  AND ($13::uuid IS NULL OR CASE
+    WHEN $14::TEXT = 'price' AND NOT $15::BOOLEAN
+        THEN (price, id) > ($16::BIGINT, $13::uuid)
+    WHEN $14::TEXT = 'price'
+        THEN (price, id) < ($16::BIGINT, $13::uuid)
+    WHEN $14::TEXT = 'service_name' AND NOT $15::BOOLEAN
+        THEN (lower(service_name), id) > (lower($17::TEXT), $13::uuid)
+    WHEN $14::TEXT = 'service_name'
+        THEN (lower(service_name), id) < (lower($17::TEXT), $13::uuid)
+    WHEN NOT $15::BOOLEAN
+        THEN (start_date, id) > ($18::DATE, $13::uuid)
+    ELSE (start_date, id) < ($18::DATE, $13::uuid)
+    END)
+ORDER BY CASE WHEN $14::TEXT = 'start_date' AND NOT $15::BOOLEAN THEN start_date END,
+         CASE WHEN $14::TEXT = 'start_date' AND $15::BOOLEAN THEN start_date END DESC,
+         CASE WHEN $14::TEXT = 'price' AND NOT $15::BOOLEAN THEN price END,
+         CASE WHEN $14::TEXT = 'price' AND $15::BOOLEAN THEN price END DESC,
+         CASE WHEN $14::TEXT = 'service_name' AND NOT $15::BOOLEAN THEN lower(service_name) END,
+         CASE WHEN $14::TEXT = 'service_name' AND $15::BOOLEAN THEN lower(service_name) END DESC,
+         CASE WHEN NOT $15::BOOLEAN THEN id END,
+         CASE WHEN $15::BOOLEAN THEN id END DESC
+LIMIT $19::INT OFFSET $19::INT * $20::INT
 `
 
 type AllSubscriptionsParams struct {
-	Count int32
-	Page  int32
+	TenantID           string
+	Deleted            string
+	UserID             pgtype.UUID
+	ServiceName        pgtype.Text
+	ServiceNamePattern pgtype.Text
+	ServiceNameSimilar pgtype.Text
+	ActiveAt           pgtype.Date
+	MinPrice           pgtype.Int8
+	PriceInMajorUnits  bool
+	MaxPrice           pgtype.Int8
+	PriceCurrency      pgtype.Text
+	OpenEndedOnly      bool
+	AfterID            pgtype.UUID
+	OrderBy            string
+	OrderDesc          bool
+	AfterPrice         pgtype.Int8
+	AfterServiceName   pgtype.Text
+	AfterStartDate     pgtype.Date
+	Count              int32
+	Page               int32
 }
 
 func (q *Queries) AllSubscriptions(ctx context.Context, arg AllSubscriptionsParams) ([]Subscription, error) {
-	rows, err := q.db.Query(ctx, allSubscriptions, arg.Count, arg.Page)
+	rows, err := q.db.Query(ctx, allSubscriptions,
+		arg.TenantID,
+		arg.Deleted,
+		arg.UserID,
+		arg.ServiceName,
+		arg.ServiceNamePattern,
+		arg.ServiceNameSimilar,
+		arg.ActiveAt,
+		arg.MinPrice,
+		arg.PriceInMajorUnits,
+		arg.MaxPrice,
+		arg.PriceCurrency,
+		arg.OpenEndedOnly,
+		arg.AfterID,
+		arg.OrderBy,
+		arg.OrderDesc,
+		arg.AfterPrice,
+		arg.AfterServiceName,
+		arg.AfterStartDate,
+		arg.Count,
+		arg.Page,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +162,15 @@ func (q *Queries) AllSubscriptions(ctx context.Context, arg AllSubscriptionsPara
 			&i.Price,
 			&i.StartDate,
 			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
 		); err != nil {
 			return nil, err
 		}
@@ -49,28 +182,178 @@ func (q *Queries) AllSubscriptions(ctx context.Context, arg AllSubscriptionsPara
 	return items, nil
 }
 
+const changeSubscriptionStatus = `-- name: ChangeSubscriptionStatus :one
+UPDATE subscriptions
+SET status   = $1::TEXT,
+    end_date = COALESCE($2::DATE, end_date)
+WHERE tenant_id = $3::TEXT
+  AND id = $4::uuid
+  AND status = $5::TEXT
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+`
+
+type ChangeSubscriptionStatusParams struct {
+	Status         string
+	EndDate        pgtype.Date
+	TenantID       string
+	SubscriptionID pgtype.UUID
+	FromStatus     string
+}
+
+// Переводит подписку из статуса from_status в статус status. Если статус подписки уже изменился, строка не обновляется.
+// Непустой end_date заменяет дату окончания (отмена подписки)
+func (q *Queries) ChangeSubscriptionStatus(ctx context.Context, arg ChangeSubscriptionStatusParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, changeSubscriptionStatus,
+		arg.Status,
+		arg.EndDate,
+		arg.TenantID,
+		arg.SubscriptionID,
+		arg.FromStatus,
+	)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ServiceName,
+		&i.Price,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const closeSubscriptionPause = `-- name: CloseSubscriptionPause :execrows
+UPDATE subscription_pauses
+SET end_date = $1::DATE
+WHERE tenant_id = $2::TEXT
+  AND subscription_id = $3::uuid
+  AND end_date IS NULL
+`
+
+type CloseSubscriptionPauseParams struct {
+	EndDate        pgtype.Date
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+// Завершает текущую приостановку подписки месяцем end_date
+func (q *Queries) CloseSubscriptionPause(ctx context.Context, arg CloseSubscriptionPauseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, closeSubscriptionPause, arg.EndDate, arg.TenantID, arg.SubscriptionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countSubscriptions = `-- name: CountSubscriptions :one
+SELECT count(*)
+FROM subscriptions
+WHERE tenant_id = $1::TEXT
+  AND CASE $2::TEXT
+          WHEN 'include' THEN TRUE
+          WHEN 'only' THEN deleted_at IS NOT NULL
+          ELSE deleted_at IS NULL
+          END
+  AND ($3::uuid IS NULL OR user_id = $3::uuid)
+  AND ($4::TEXT IS NULL OR lower(service_name) = lower($4::TEXT))
+  AND ($5::TEXT IS NULL OR lower(service_name) LIKE lower($5::TEXT))
+  AND ($6::TEXT IS NULL OR $6::TEXT <% service_name)
+  AND ($7::DATE IS NULL OR
+       (start_date <= $7::DATE AND (end_date IS NULL OR end_date >= $7::DATE)))
+  AND ($8::BIGINT IS NULL OR price >= $8::BIGINT *
+      CASE WHEN $9::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND ($10::BIGINT IS NULL OR price <= $10::BIGINT *
+      CASE WHEN $9::BOOLEAN THEN currency_minor_factor(currency) ELSE 1 END)
+  AND ($11::TEXT IS NULL OR currency = $11::TEXT)
+  AND (NOT $12::BOOLEAN OR end_date IS NULL)
+`
+
+type CountSubscriptionsParams struct {
+	TenantID           string
+	Deleted            string
+	UserID             pgtype.UUID
+	ServiceName        pgtype.Text
+	ServiceNamePattern pgtype.Text
+	ServiceNameSimilar pgtype.Text
+	ActiveAt           pgtype.Date
+	MinPrice           pgtype.Int8
+	PriceInMajorUnits  bool
+	MaxPrice           pgtype.Int8
+	PriceCurrency      pgtype.Text
+	OpenEndedOnly      bool
+}
+
+func (q *Queries) CountSubscriptions(ctx context.Context, arg CountSubscriptionsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSubscriptions,
+		arg.TenantID,
+		arg.Deleted,
+		arg.UserID,
+		arg.ServiceName,
+		arg.ServiceNamePattern,
+		arg.ServiceNameSimilar,
+		arg.ActiveAt,
+		arg.MinPrice,
+		arg.PriceInMajorUnits,
+		arg.MaxPrice,
+		arg.PriceCurrency,
+		arg.OpenEndedOnly,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSubscription = `-- name: CreateSubscription :one
-INSERT INTO subscriptions (user_id, service_name, price, start_date, end_date)
-VALUES ($1::uuid, $2::TEXT, $3::INT, $4::DATE,
-        $5::DATE)
-RETURNING id, user_id, service_name, price, start_date, end_date
+INSERT INTO subscriptions (tenant_id, user_id, service_name, price, currency, billing_unit, billing_interval, start_date,
+                           end_date, overlap_allowed, status, trial_end_date)
+VALUES ($1::TEXT, $2::uuid, $3::TEXT, $4::BIGINT, $5::TEXT,
+        COALESCE($6::TEXT, 'month'), COALESCE($7::INT, 1),
+        $8::DATE,
+        $9::DATE,
+        $10::BOOLEAN, COALESCE($11::TEXT, 'active'),
+        $12::DATE)
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 `
 
 type CreateSubscriptionParams struct {
-	UserID      pgtype.UUID
-	ServiceName string
-	Price       int32
-	StartDate   pgtype.Date
-	EndDate     pgtype.Date
+	TenantID        string
+	UserID          pgtype.UUID
+	ServiceName     string
+	Price           int64
+	Currency        string
+	BillingUnit     pgtype.Text
+	BillingInterval pgtype.Int4
+	StartDate       pgtype.Date
+	EndDate         pgtype.Date
+	OverlapAllowed  bool
+	Status          pgtype.Text
+	TrialEndDate    pgtype.Date
 }
 
 func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error) {
 	row := q.db.QueryRow(ctx, createSubscription,
+		arg.TenantID,
 		arg.UserID,
 		arg.ServiceName,
 		arg.Price,
+		arg.Currency,
+		arg.BillingUnit,
+		arg.BillingInterval,
 		arg.StartDate,
 		arg.EndDate,
+		arg.OverlapAllowed,
+		arg.Status,
+		arg.TrialEndDate,
 	)
 	var i Subscription
 	err := row.Scan(
@@ -80,29 +363,277 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		&i.Price,
 		&i.StartDate,
 		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
 	)
 	return i, err
 }
 
-const deleteSubscription = `-- name: DeleteSubscription :exec
-DELETE
-FROM subscriptions
-WHERE id = $1
+const createSubscriptionPause = `-- name: CreateSubscriptionPause :exec
+INSERT INTO subscription_pauses (tenant_id, subscription_id, start_date)
+VALUES ($1::TEXT, $2::uuid, $3::DATE)
 `
 
-func (q *Queries) DeleteSubscription(ctx context.Context, subscriptionID pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteSubscription, subscriptionID)
+type CreateSubscriptionPauseParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+	StartDate      pgtype.Date
+}
+
+func (q *Queries) CreateSubscriptionPause(ctx context.Context, arg CreateSubscriptionPauseParams) error {
+	_, err := q.db.Exec(ctx, createSubscriptionPause, arg.TenantID, arg.SubscriptionID, arg.StartDate)
 	return err
 }
 
+const deleteSubscription = `-- name: DeleteSubscription :one
+UPDATE subscriptions
+SET deleted_at = now()
+WHERE tenant_id = $1::TEXT
+  AND id = $2
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+`
+
+type DeleteSubscriptionParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+// Помечает подписку удаленной, окончательно она удаляется PurgeDeletedSubscriptions
+func (q *Queries) DeleteSubscription(ctx context.Context, arg DeleteSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, deleteSubscription, arg.TenantID, arg.SubscriptionID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ServiceName,
+		&i.Price,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const deleteSubscriptionsByIDs = `-- name: DeleteSubscriptionsByIDs :many
+UPDATE subscriptions
+SET deleted_at = now()
+WHERE tenant_id = $1::TEXT
+  AND id = ANY ($2::uuid[])
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+`
+
+type DeleteSubscriptionsByIDsParams struct {
+	TenantID string
+	Ids      []pgtype.UUID
+}
+
+func (q *Queries) DeleteSubscriptionsByIDs(ctx context.Context, arg DeleteSubscriptionsByIDsParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, deleteSubscriptionsByIDs, arg.TenantID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Price,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const expireSubscriptions = `-- name: ExpireSubscriptions :many
+WITH expiring AS (SELECT id, status
+                  FROM subscriptions
+                  WHERE status IN ('trial', 'active', 'paused', 'cancelled')
+                    AND end_date < $1::DATE
+                    AND deleted_at IS NULL
+                      FOR UPDATE)
+UPDATE subscriptions s
+SET status = 'expired'
+FROM expiring e
+WHERE s.id = e.id
+RETURNING s.id, s.user_id, s.service_name, s.price, s.start_date, s.end_date, s.currency, s.billing_unit, s.billing_interval,
+          s.overlap_allowed, s.status, s.trial_end_date, s.deleted_at, s.version, s.tenant_id,
+          e.status AS previous_status
+`
+
+type ExpireSubscriptionsRow struct {
+	ID              pgtype.UUID
+	UserID          pgtype.UUID
+	ServiceName     string
+	Price           int64
+	StartDate       pgtype.Date
+	EndDate         pgtype.Date
+	Currency        string
+	BillingUnit     string
+	BillingInterval int32
+	OverlapAllowed  bool
+	Status          string
+	TrialEndDate    pgtype.Date
+	DeletedAt       pgtype.Timestamptz
+	Version         int64
+	TenantID        string
+	PreviousStatus  string
+}
+
+// Подписки всех арендаторов, закончившиеся до месяца before, становятся истекшими. previous_status - статус до изменения
+func (q *Queries) ExpireSubscriptions(ctx context.Context, before pgtype.Date) ([]ExpireSubscriptionsRow, error) {
+	rows, err := q.db.Query(ctx, expireSubscriptions, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExpireSubscriptionsRow
+	for rows.Next() {
+		var i ExpireSubscriptionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Price,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+			&i.PreviousStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findOverlappingSubscriptions = `-- name: FindOverlappingSubscriptions :many
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+FROM subscriptions
+WHERE tenant_id = $1::TEXT
+  AND user_id = $2::uuid
+  AND normalize_service_name(service_name) = normalize_service_name($3::TEXT)
+  AND subscription_period(start_date, end_date) &&
+      subscription_period($4::DATE, $5::DATE)
+  AND ($6::uuid IS NULL OR id <> $6::uuid)
+  AND deleted_at IS NULL
+ORDER BY start_date, id
+`
+
+type FindOverlappingSubscriptionsParams struct {
+	TenantID    string
+	UserID      pgtype.UUID
+	ServiceName string
+	StartDate   pgtype.Date
+	EndDate     pgtype.Date
+	ExcludeID   pgtype.UUID
+}
+
+// Подписки того же пользователя на тот же сервис, периоды которых пересекаются с указанным
+func (q *Queries) FindOverlappingSubscriptions(ctx context.Context, arg FindOverlappingSubscriptionsParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, findOverlappingSubscriptions,
+		arg.TenantID,
+		arg.UserID,
+		arg.ServiceName,
+		arg.StartDate,
+		arg.EndDate,
+		arg.ExcludeID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Price,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubscriptionById = `-- name: GetSubscriptionById :one
-SELECT id, user_id, service_name, price, start_date, end_date
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 FROM subscriptions
-WHERE id = $1
+WHERE tenant_id = $1::TEXT
+  AND id = $2
+  AND deleted_at IS NULL
 `
 
-func (q *Queries) GetSubscriptionById(ctx context.Context, subscriptionID pgtype.UUID) (Subscription, error) {
-	row := q.db.QueryRow(ctx, getSubscriptionById, subscriptionID)
+type GetSubscriptionByIdParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+func (q *Queries) GetSubscriptionById(ctx context.Context, arg GetSubscriptionByIdParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, getSubscriptionById, arg.TenantID, arg.SubscriptionID)
 	var i Subscription
 	err := row.Scan(
 		&i.ID,
@@ -111,70 +642,587 @@ func (q *Queries) GetSubscriptionById(ctx context.Context, subscriptionID pgtype
 		&i.Price,
 		&i.StartDate,
 		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
 	)
 	return i, err
 }
 
-const getSumSubscriptions = `-- name: GetSumSubscriptions :one
-SELECT COALESCE(SUM(price * (
-    (EXTRACT (YEAR FROM (LEAST(COALESCE(end_date, $1::DATE), $1::DATE))) -
-     EXTRACT (YEAR FROM GREATEST(start_date, $2::DATE))) * 12 +
-    (EXTRACT (MONTH FROM (LEAST(COALESCE(end_date, $1::DATE), $1::DATE))) -
-     EXTRACT (MONTH FROM GREATEST(start_date, $2::DATE))) + 1
-    )), 0)::INT
+const getSubscriptionOwner = `-- name: GetSubscriptionOwner :one
+SELECT user_id
+FROM subscriptions
+WHERE tenant_id = $1::TEXT
+  AND id = $2
+`
+
+type GetSubscriptionOwnerParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+// Пользователь подписки, в том числе удаленной: проверка доступа клиента к подписке
+func (q *Queries) GetSubscriptionOwner(ctx context.Context, arg GetSubscriptionOwnerParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getSubscriptionOwner, arg.TenantID, arg.SubscriptionID)
+	var user_id pgtype.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const listEndingTrials = `-- name: ListEndingTrials :many
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
 FROM subscriptions
-WHERE start_date <= $1::DATE
-  AND (end_date IS NULL OR end_date >= $2::DATE)
-  AND ($3::TEXT IS NULL OR service_name ILIKE $3::TEXT)
+WHERE tenant_id = $1::TEXT
+  AND status = 'trial'
+  AND deleted_at IS NULL
+  AND trial_end_date BETWEEN $2::DATE AND $3::DATE
   AND ($4::uuid IS NULL OR user_id = $4::uuid)
+ORDER BY trial_end_date, id
 `
 
-type GetSumSubscriptionsParams struct {
-	EndDate     pgtype.Date
-	StartDate   pgtype.Date
+type ListEndingTrialsParams struct {
+	TenantID string
+	FromDate pgtype.Date
+	ToDate   pgtype.Date
+	UserID   pgtype.UUID
+}
+
+// Подписки в пробном периоде, последний месяц которого попадает в период с from_date по to_date
+func (q *Queries) ListEndingTrials(ctx context.Context, arg ListEndingTrialsParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listEndingTrials,
+		arg.TenantID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Price,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMonthlyCharges = `-- name: ListMonthlyCharges :many
+WITH charges AS (SELECT s.id,
+                        s.user_id,
+                        plan.service_name,
+                        s.currency,
+                        months.month::DATE AS month,
+                        plan.price,
+                        CASE s.billing_unit
+                            WHEN 'week' THEN
+                                ((months.month + INTERVAL '1 month')::DATE - s.start_date + 7 * s.billing_interval - 1) /
+                                (7 * s.billing_interval) -
+                                (months.month::DATE - s.start_date + 7 * s.billing_interval - 1) /
+                                (7 * s.billing_interval)
+                            ELSE
+                                CASE
+                                    WHEN ((EXTRACT(YEAR FROM months.month) - EXTRACT(YEAR FROM s.start_date)) * 12 +
+                                          EXTRACT(MONTH FROM months.month) - EXTRACT(MONTH FROM s.start_date))::INT %
+                                         s.billing_interval = 0 THEN 1
+                                    ELSE 0
+                                    END
+                            END::INT   AS charges
+                 FROM subscriptions s
+                          JOIN generate_series($2::DATE, $3::DATE,
+                                               INTERVAL '1 month') AS months(month)
+                               ON months.month >= s.start_date AND (s.end_date IS NULL OR months.month <= s.end_date)
+                                   -- месяцы приостановки не оплачиваются
+                                   AND NOT EXISTS (SELECT 1
+                                                   FROM subscription_pauses sp
+                                                   WHERE sp.subscription_id = s.id
+                                                     AND months.month >= sp.start_date
+                                                     AND (sp.end_date IS NULL OR months.month <= sp.end_date))
+                          JOIN LATERAL (SELECT p.service_name, p.price
+                                        FROM subscription_plans p
+                                        WHERE p.subscription_id = s.id
+                                        -- последний тариф, начавшийся не позже месяца, иначе самый ранний
+                                        ORDER BY p.valid_from <= months.month DESC,
+                                                 ABS(months.month::DATE - p.valid_from)
+                                        LIMIT 1) plan ON TRUE
+                 WHERE s.tenant_id = $4::TEXT
+                   AND s.deleted_at IS NULL
+                   AND s.start_date <= $3::DATE
+                   AND (s.end_date IS NULL OR s.end_date >= $2::DATE)
+                   AND ($5::uuid IS NULL OR s.user_id = $5::uuid))
+SELECT id, user_id, service_name, currency, month, price, charges
+FROM charges
+WHERE ($1::TEXT IS NULL OR service_name ILIKE $1::TEXT)
+ORDER BY month, id
+`
+
+type ListMonthlyChargesParams struct {
 	ServiceName pgtype.Text
+	StartDate   pgtype.Date
+	EndDate     pgtype.Date
+	TenantID    string
 	UserID      pgtype.UUID
 }
 
-func (q *Queries) GetSumSubscriptions(ctx context.Context, arg GetSumSubscriptionsParams) (int32, error) {
-	row := q.db.QueryRow(ctx, getSumSubscriptions,
-		arg.EndDate,
-		arg.StartDate,
+type ListMonthlyChargesRow struct {
+	ID          pgtype.UUID
+	UserID      pgtype.UUID
+	ServiceName string
+	Currency    string
+	Month       pgtype.Date
+	Price       int64
+	Charges     int32
+}
+
+// Для каждой подписки и каждого месяца периода считает количество списаний, попавших в этот месяц:
+// помесячные тарифы списываются раз в billing_interval месяцев от start_date,
+// понедельные - каждые 7 * billing_interval дней от первого числа месяца start_date.
+// Цена и наименование берутся из тарифа, действовавшего в этом месяце.
+// Месяцы без списаний (charges = 0) тоже возвращаются: в них подписка активна. Месяцы приостановки не возвращаются
+func (q *Queries) ListMonthlyCharges(ctx context.Context, arg ListMonthlyChargesParams) ([]ListMonthlyChargesRow, error) {
+	rows, err := q.db.Query(ctx, listMonthlyCharges,
 		arg.ServiceName,
+		arg.StartDate,
+		arg.EndDate,
+		arg.TenantID,
 		arg.UserID,
 	)
-	var column_1 int32
-	err := row.Scan(&column_1)
-	return column_1, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMonthlyChargesRow
+	for rows.Next() {
+		var i ListMonthlyChargesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Currency,
+			&i.Month,
+			&i.Price,
+			&i.Charges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateSubscription = `-- name: UpdateSubscription :one
-UPDATE subscriptions
-SET user_id      = COALESCE($1::uuid, user_id),
-    service_name = COALESCE($2::TEXT, service_name),
-    price        = COALESCE($3::INT, price),
-    start_date   = COALESCE($4::DATE, start_date),
-    end_date     = COALESCE($5::DATE, end_date)
-WHERE id = $6
-RETURNING id, user_id, service_name, price, start_date, end_date
+const listSubscriptionOverlaps = `-- name: ListSubscriptionOverlaps :many
+SELECT a.id                                                                          AS subscription_id,
+       b.id                                                                          AS overlapping_id,
+       GREATEST(a.start_date, b.start_date)::DATE                                    AS overlap_start,
+       CASE
+           WHEN a.end_date IS NULL THEN b.end_date
+           WHEN b.end_date IS NULL THEN a.end_date
+           ELSE LEAST(a.end_date, b.end_date)
+           END::DATE                                                                 AS overlap_end
+FROM subscriptions a
+         JOIN subscriptions b
+              ON b.tenant_id = a.tenant_id
+                  AND b.user_id = a.user_id
+                  AND normalize_service_name(b.service_name) = normalize_service_name(a.service_name)
+                  AND subscription_period(b.start_date, b.end_date) && subscription_period(a.start_date, a.end_date)
+                  AND b.id > a.id
+                  AND b.deleted_at IS NULL
+WHERE a.tenant_id = $1::TEXT
+  AND a.deleted_at IS NULL
+  AND ($2::uuid IS NULL OR a.user_id = $2::uuid)
+  AND ($3::TEXT IS NULL OR
+       normalize_service_name(a.service_name) = normalize_service_name($3::TEXT))
+ORDER BY a.user_id, normalize_service_name(a.service_name), overlap_start, a.id, b.id
 `
 
-type UpdateSubscriptionParams struct {
-	UserID         pgtype.UUID
-	ServiceName    pgtype.Text
-	Price          pgtype.Int4
+type ListSubscriptionOverlapsParams struct {
+	TenantID    string
+	UserID      pgtype.UUID
+	ServiceName pgtype.Text
+}
+
+type ListSubscriptionOverlapsRow struct {
+	SubscriptionID pgtype.UUID
+	OverlappingID  pgtype.UUID
+	OverlapStart   pgtype.Date
+	OverlapEnd     pgtype.Date
+}
+
+// Пары пересекающихся подписок одного пользователя на один сервис и общий период каждой пары
+func (q *Queries) ListSubscriptionOverlaps(ctx context.Context, arg ListSubscriptionOverlapsParams) ([]ListSubscriptionOverlapsRow, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionOverlaps, arg.TenantID, arg.UserID, arg.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSubscriptionOverlapsRow
+	for rows.Next() {
+		var i ListSubscriptionOverlapsRow
+		if err := rows.Scan(
+			&i.SubscriptionID,
+			&i.OverlappingID,
+			&i.OverlapStart,
+			&i.OverlapEnd,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionPauses = `-- name: ListSubscriptionPauses :many
+SELECT subscription_id, start_date, end_date, tenant_id
+FROM subscription_pauses
+WHERE tenant_id = $1::TEXT
+  AND subscription_id = $2::uuid
+ORDER BY start_date
+`
+
+type ListSubscriptionPausesParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+func (q *Queries) ListSubscriptionPauses(ctx context.Context, arg ListSubscriptionPausesParams) ([]SubscriptionPause, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionPauses, arg.TenantID, arg.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPause
+	for rows.Next() {
+		var i SubscriptionPause
+		if err := rows.Scan(
+			&i.SubscriptionID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscriptionsByIDs = `-- name: ListSubscriptionsByIDs :many
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+FROM subscriptions
+WHERE tenant_id = $1::TEXT
+  AND id = ANY ($2::uuid[])
+  AND deleted_at IS NULL
+`
+
+type ListSubscriptionsByIDsParams struct {
+	TenantID string
+	Ids      []pgtype.UUID
+}
+
+func (q *Queries) ListSubscriptionsByIDs(ctx context.Context, arg ListSubscriptionsByIDsParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionsByIDs, arg.TenantID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Price,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSubscription = `-- name: LockSubscription :one
+SELECT id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+FROM subscriptions
+WHERE tenant_id = $1::TEXT
+  AND id = $2::uuid
+FOR UPDATE
+`
+
+type LockSubscriptionParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+// Подписка, в том числе удаленная, с блокировкой до конца транзакции: состояние до изменения для журнала аудита
+func (q *Queries) LockSubscription(ctx context.Context, arg LockSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, lockSubscription, arg.TenantID, arg.SubscriptionID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ServiceName,
+		&i.Price,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const mergeSubscriptionPeriod = `-- name: MergeSubscriptionPeriod :one
+UPDATE subscriptions s
+SET start_date      = $1::DATE,
+    end_date        = $2::DATE,
+    overlap_allowed = EXISTS (SELECT 1
+                              FROM subscriptions o
+                              WHERE o.id <> s.id
+                                AND o.deleted_at IS NULL
+                                AND o.tenant_id = s.tenant_id
+                                AND o.user_id = s.user_id
+                                AND normalize_service_name(o.service_name) = normalize_service_name(s.service_name)
+                                AND subscription_period(o.start_date, o.end_date) &&
+                                    subscription_period($1::DATE, $2::DATE))
+WHERE s.tenant_id = $3::TEXT
+  AND s.id = $4::uuid
+  AND s.deleted_at IS NULL
+RETURNING s.id, s.user_id, s.service_name, s.price, s.start_date, s.end_date, s.currency, s.billing_unit, s.billing_interval,
+          s.overlap_allowed, s.status, s.trial_end_date, s.deleted_at, s.version, s.tenant_id
+`
+
+type MergeSubscriptionPeriodParams struct {
 	StartDate      pgtype.Date
 	EndDate        pgtype.Date
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+// Расширяет период подписки до объединения периодов объединяемых подписок. Пометка overlap_allowed снимается,
+// если подписка больше ни с чем не пересекается
+func (q *Queries) MergeSubscriptionPeriod(ctx context.Context, arg MergeSubscriptionPeriodParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, mergeSubscriptionPeriod,
+		arg.StartDate,
+		arg.EndDate,
+		arg.TenantID,
+		arg.SubscriptionID,
+	)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ServiceName,
+		&i.Price,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const purgeDeletedSubscriptions = `-- name: PurgeDeletedSubscriptions :many
+DELETE
+FROM subscriptions s
+WHERE s.deleted_at < COALESCE(($1::TIMESTAMPTZ[])[array_position($2::TEXT[], s.tenant_id)],
+                               $3::TIMESTAMPTZ)
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+`
+
+type PurgeDeletedSubscriptionsParams struct {
+	TenantBefores []pgtype.Timestamptz
+	TenantIds     []string
+	Before        pgtype.Timestamptz
+}
+
+// Окончательно удаляет подписки всех арендаторов вместе с тарифами и приостановками. Подписки арендатора
+// tenant_ids[i] удаляются, если удалены до момента tenant_befores[i], остальных арендаторов - до момента before
+func (q *Queries) PurgeDeletedSubscriptions(ctx context.Context, arg PurgeDeletedSubscriptionsParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedSubscriptions, arg.TenantBefores, arg.TenantIds, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Subscription
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ServiceName,
+			&i.Price,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Currency,
+			&i.BillingUnit,
+			&i.BillingInterval,
+			&i.OverlapAllowed,
+			&i.Status,
+			&i.TrialEndDate,
+			&i.DeletedAt,
+			&i.Version,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const undeleteSubscription = `-- name: UndeleteSubscription :one
+UPDATE subscriptions
+SET deleted_at = NULL
+WHERE tenant_id = $1::TEXT
+  AND id = $2::uuid
+  AND deleted_at IS NOT NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+`
+
+type UndeleteSubscriptionParams struct {
+	TenantID       string
 	SubscriptionID pgtype.UUID
 }
 
+func (q *Queries) UndeleteSubscription(ctx context.Context, arg UndeleteSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, undeleteSubscription, arg.TenantID, arg.SubscriptionID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ServiceName,
+		&i.Price,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const updateSubscription = `-- name: UpdateSubscription :one
+UPDATE subscriptions
+SET user_id          = COALESCE($1::uuid, user_id),
+    service_name     = COALESCE($2::TEXT, service_name),
+    price            = COALESCE($3::BIGINT, price),
+    currency         = COALESCE($4::TEXT, currency),
+    billing_unit     = COALESCE($5::TEXT, billing_unit),
+    billing_interval = COALESCE($6::INT, billing_interval),
+    start_date       = COALESCE($7::DATE, start_date),
+    end_date         = CASE
+                           WHEN $8::BOOLEAN THEN NULL
+                           ELSE COALESCE($9::DATE, end_date)
+        END,
+    overlap_allowed  = COALESCE($10::BOOLEAN, overlap_allowed)
+WHERE tenant_id = $11::TEXT
+  AND id = $12
+  AND deleted_at IS NULL
+RETURNING id, user_id, service_name, price, start_date, end_date, currency, billing_unit, billing_interval, overlap_allowed, status, trial_end_date, deleted_at, version, tenant_id
+`
+
+type UpdateSubscriptionParams struct {
+	UserID          pgtype.UUID
+	ServiceName     pgtype.Text
+	Price           pgtype.Int8
+	Currency        pgtype.Text
+	BillingUnit     pgtype.Text
+	BillingInterval pgtype.Int4
+	StartDate       pgtype.Date
+	ClearEndDate    bool
+	EndDate         pgtype.Date
+	OverlapAllowed  pgtype.Bool
+	TenantID        string
+	SubscriptionID  pgtype.UUID
+}
+
 func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error) {
 	row := q.db.QueryRow(ctx, updateSubscription,
 		arg.UserID,
 		arg.ServiceName,
 		arg.Price,
+		arg.Currency,
+		arg.BillingUnit,
+		arg.BillingInterval,
 		arg.StartDate,
+		arg.ClearEndDate,
 		arg.EndDate,
+		arg.OverlapAllowed,
+		arg.TenantID,
 		arg.SubscriptionID,
 	)
 	var i Subscription
@@ -185,6 +1233,15 @@ func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscription
 		&i.Price,
 		&i.StartDate,
 		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
 	)
 	return i, err
 }
@@ -0,0 +1,47 @@
+-- name: UpsertSubscriptionPlan :exec
+INSERT INTO subscription_plans (tenant_id, subscription_id, valid_from, service_name, price, phase)
+VALUES (sqlc.arg(tenant_id)::TEXT, sqlc.arg(subscription_id)::uuid, sqlc.arg(valid_from)::DATE, sqlc.arg(service_name)::TEXT, sqlc.arg(price)::BIGINT,
+        sqlc.arg(phase)::TEXT)
+ON CONFLICT (subscription_id, valid_from) DO UPDATE SET service_name = excluded.service_name,
+                                                        price        = excluded.price,
+                                                        phase        = excluded.phase;
+
+-- name: ListSubscriptionPlans :many
+SELECT subscription_id, valid_from, service_name, price, phase, tenant_id
+FROM subscription_plans
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND subscription_id = sqlc.arg(subscription_id)::uuid
+ORDER BY valid_from;
+
+-- name: ChangeSubscriptionPlanFrom :exec
+-- Переносит цену и наименование из подписки в тариф, действующий с месяца valid_from. Тарифы прошлых месяцев
+-- не меняются: с valid_from начинается новый тариф, а последний тариф, начавшийся не раньше valid_from, исправляется
+INSERT INTO subscription_plans (tenant_id, subscription_id, valid_from, service_name, price, phase)
+SELECT s.tenant_id, s.id, GREATEST(l.valid_from, sqlc.arg(valid_from)::DATE), s.service_name, s.price, l.phase
+FROM subscriptions s
+         JOIN LATERAL (SELECT p.valid_from, p.phase
+                       FROM subscription_plans p
+                       WHERE p.subscription_id = s.id
+                       ORDER BY p.valid_from DESC
+                       LIMIT 1) l ON TRUE
+WHERE s.tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND s.id = sqlc.arg(subscription_id)::uuid
+ON CONFLICT (subscription_id, valid_from) DO UPDATE SET service_name = excluded.service_name,
+                                                        price        = excluded.price;
+
+-- name: SyncSubscriptionWithLatestPlan :one
+-- Подписка хранит цену и наименование последнего тарифа
+UPDATE subscriptions s
+SET service_name = p.service_name,
+    price        = p.price
+FROM (SELECT l.service_name, l.price
+      FROM subscription_plans l
+      WHERE l.tenant_id = sqlc.arg(tenant_id)::TEXT
+        AND l.subscription_id = sqlc.arg(subscription_id)::uuid
+      ORDER BY l.valid_from DESC
+      LIMIT 1) p
+WHERE s.tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND s.id = sqlc.arg(subscription_id)::uuid
+  AND s.deleted_at IS NULL
+RETURNING s.id, s.user_id, s.service_name, s.price, s.start_date, s.end_date, s.currency, s.billing_unit, s.billing_interval,
+          s.overlap_allowed, s.status, s.trial_end_date, s.deleted_at, s.version, s.tenant_id;
@@ -0,0 +1,156 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: subscription_plans.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const changeSubscriptionPlanFrom = `-- name: ChangeSubscriptionPlanFrom :exec
+INSERT INTO subscription_plans (tenant_id, subscription_id, valid_from, service_name, price, phase)
+SELECT s.tenant_id, s.id, GREATEST(l.valid_from, $1::DATE), s.service_name, s.price, l.phase
+FROM subscriptions s
+         JOIN LATERAL (SELECT p.valid_from, p.phase
+                       FROM subscription_plans p
+                       WHERE p.subscription_id = s.id
+                       ORDER BY p.valid_from DESC
+                       LIMIT 1) l ON TRUE
+WHERE s.tenant_id = $2::TEXT
+  AND s.id = $3::uuid
+ON CONFLICT (subscription_id, valid_from) DO UPDATE SET service_name = excluded.service_name,
+                                                        price        = excluded.price
+`
+
+type ChangeSubscriptionPlanFromParams struct {
+	ValidFrom      pgtype.Date
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+// Переносит цену и наименование из подписки в тариф, действующий с месяца valid_from. Тарифы прошлых месяцев
+// не меняются: с valid_from начинается новый тариф, а последний тариф, начавшийся не раньше valid_from, исправляется
+func (q *Queries) ChangeSubscriptionPlanFrom(ctx context.Context, arg ChangeSubscriptionPlanFromParams) error {
+	_, err := q.db.Exec(ctx, changeSubscriptionPlanFrom, arg.ValidFrom, arg.TenantID, arg.SubscriptionID)
+	return err
+}
+
+const listSubscriptionPlans = `-- name: ListSubscriptionPlans :many
+SELECT subscription_id, valid_from, service_name, price, phase, tenant_id
+FROM subscription_plans
+WHERE tenant_id = $1::TEXT
+  AND subscription_id = $2::uuid
+ORDER BY valid_from
+`
+
+type ListSubscriptionPlansParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+func (q *Queries) ListSubscriptionPlans(ctx context.Context, arg ListSubscriptionPlansParams) ([]SubscriptionPlan, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionPlans, arg.TenantID, arg.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubscriptionPlan
+	for rows.Next() {
+		var i SubscriptionPlan
+		if err := rows.Scan(
+			&i.SubscriptionID,
+			&i.ValidFrom,
+			&i.ServiceName,
+			&i.Price,
+			&i.Phase,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const syncSubscriptionWithLatestPlan = `-- name: SyncSubscriptionWithLatestPlan :one
+UPDATE subscriptions s
+SET service_name = p.service_name,
+    price        = p.price
+FROM (SELECT l.service_name, l.price
+      FROM subscription_plans l
+      WHERE l.tenant_id = $1::TEXT
+        AND l.subscription_id = $2::uuid
+      ORDER BY l.valid_from DESC
+      LIMIT 1) p
+WHERE s.tenant_id = $1::TEXT
+  AND s.id = $2::uuid
+  AND s.deleted_at IS NULL
+RETURNING s.id, s.user_id, s.service_name, s.price, s.start_date, s.end_date, s.currency, s.billing_unit, s.billing_interval,
+          s.overlap_allowed, s.status, s.trial_end_date, s.deleted_at, s.version, s.tenant_id
+`
+
+type SyncSubscriptionWithLatestPlanParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+}
+
+// Подписка хранит цену и наименование последнего тарифа
+func (q *Queries) SyncSubscriptionWithLatestPlan(ctx context.Context, arg SyncSubscriptionWithLatestPlanParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, syncSubscriptionWithLatestPlan, arg.TenantID, arg.SubscriptionID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ServiceName,
+		&i.Price,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Currency,
+		&i.BillingUnit,
+		&i.BillingInterval,
+		&i.OverlapAllowed,
+		&i.Status,
+		&i.TrialEndDate,
+		&i.DeletedAt,
+		&i.Version,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const upsertSubscriptionPlan = `-- name: UpsertSubscriptionPlan :exec
+INSERT INTO subscription_plans (tenant_id, subscription_id, valid_from, service_name, price, phase)
+VALUES ($1::TEXT, $2::uuid, $3::DATE, $4::TEXT, $5::BIGINT,
+        $6::TEXT)
+ON CONFLICT (subscription_id, valid_from) DO UPDATE SET service_name = excluded.service_name,
+                                                        price        = excluded.price,
+                                                        phase        = excluded.phase
+`
+
+type UpsertSubscriptionPlanParams struct {
+	TenantID       string
+	SubscriptionID pgtype.UUID
+	ValidFrom      pgtype.Date
+	ServiceName    string
+	Price          int64
+	Phase          string
+}
+
+func (q *Queries) UpsertSubscriptionPlan(ctx context.Context, arg UpsertSubscriptionPlanParams) error {
+	_, err := q.db.Exec(ctx, upsertSubscriptionPlan,
+		arg.TenantID,
+		arg.SubscriptionID,
+		arg.ValidFrom,
+		arg.ServiceName,
+		arg.Price,
+		arg.Phase,
+	)
+	return err
+}
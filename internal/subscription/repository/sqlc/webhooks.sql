@@ -0,0 +1,100 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (tenant_id, url, description, event_types, secret)
+VALUES (sqlc.arg(tenant_id)::TEXT, sqlc.arg(url)::TEXT, sqlc.arg(description)::TEXT, sqlc.arg(event_types)::TEXT[], sqlc.arg(secret)::TEXT)
+RETURNING id, url, description, event_types, secret, previous_secret, previous_secret_expires_at, created_at,
+    secret_rotated_at, tenant_id;
+
+-- name: ListWebhookEndpoints :many
+SELECT id, url, description, event_types, secret, previous_secret, previous_secret_expires_at, created_at,
+       secret_rotated_at, tenant_id
+FROM webhook_endpoints
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+ORDER BY created_at, id;
+
+-- name: DeleteWebhookEndpoint :execrows
+DELETE
+FROM webhook_endpoints
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(id)::UUID;
+
+-- name: RotateWebhookSecret :one
+-- Новый секрет заменяет текущий, текущий остается действительным до previous_secret_expires_at.
+-- При NULL предыдущий секрет отзывается сразу
+UPDATE webhook_endpoints
+SET previous_secret            = CASE WHEN sqlc.narg(previous_secret_expires_at)::TIMESTAMPTZ IS NULL THEN NULL ELSE secret END,
+    previous_secret_expires_at = sqlc.narg(previous_secret_expires_at)::TIMESTAMPTZ,
+    secret                     = sqlc.arg(secret)::TEXT,
+    secret_rotated_at          = now()
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(id)::UUID
+RETURNING id, url, description, event_types, secret, previous_secret, previous_secret_expires_at, created_at,
+    secret_rotated_at, tenant_id;
+
+-- name: EnqueueWebhookDeliveries :execrows
+-- Создает доставки события всем вебхукам арендатора события, подписанным на его тип. Повторная публикация события
+-- доставки не дублирует
+INSERT INTO webhook_deliveries (tenant_id, endpoint_id, event_id, event_type, payload)
+SELECT tenant_id, id, sqlc.arg(event_id)::BIGINT, sqlc.arg(event_type)::TEXT, sqlc.arg(payload)::JSONB
+FROM webhook_endpoints
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND (cardinality(event_types) = 0 OR sqlc.arg(event_type)::TEXT = ANY (event_types))
+ON CONFLICT (endpoint_id, event_id) DO NOTHING;
+
+-- name: ClaimWebhookDeliveries :many
+-- Берет в работу доставки всех арендаторов, время попытки которых наступило, и продлевает их до lease_until, чтобы другие экземпляры
+-- сервиса не отправили их повторно. Если экземпляр упадет во время отправки, доставка будет повторена после lease_until
+UPDATE webhook_deliveries d
+SET next_attempt_at = sqlc.arg(lease_until)::TIMESTAMPTZ
+FROM webhook_endpoints e
+WHERE e.id = d.endpoint_id
+  AND d.id IN (SELECT id
+               FROM webhook_deliveries
+               WHERE status = 'pending'
+                 AND next_attempt_at <= now()
+               ORDER BY next_attempt_at, id
+               LIMIT sqlc.arg(batch_size)::INT FOR UPDATE SKIP LOCKED)
+RETURNING d.id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.attempts,
+    e.url, e.secret, COALESCE(CASE WHEN e.previous_secret_expires_at > now() THEN e.previous_secret END, '')::TEXT AS previous_secret;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET status          = sqlc.arg(status)::TEXT,
+    attempts        = attempts + 1,
+    last_attempt_at = now(),
+    next_attempt_at = sqlc.arg(next_attempt_at)::TIMESTAMPTZ,
+    response_status = sqlc.narg(response_status)::INT,
+    response_body   = sqlc.narg(response_body)::TEXT,
+    last_error      = sqlc.narg(last_error)::TEXT,
+    delivered_at    = CASE WHEN sqlc.arg(status)::TEXT = 'succeeded' THEN now() END
+WHERE id = sqlc.arg(id)::BIGINT;
+
+-- name: ListWebhookDeliveries :many
+-- Доставки вебхука от новых к старым
+SELECT id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at,
+       response_status, response_body, last_error, created_at, delivered_at, tenant_id
+FROM webhook_deliveries
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND endpoint_id = sqlc.arg(endpoint_id)::UUID
+  AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status)::TEXT)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size)::INT;
+
+-- name: ReplayWebhookDelivery :one
+-- Ставит доставку в очередь заново с полным числом попыток, в том числе уже доставленную
+UPDATE webhook_deliveries
+SET status          = 'pending',
+    attempts        = 0,
+    next_attempt_at = now(),
+    delivered_at    = NULL
+WHERE tenant_id = sqlc.arg(tenant_id)::TEXT
+  AND id = sqlc.arg(id)::BIGINT
+  AND endpoint_id = sqlc.arg(endpoint_id)::UUID
+RETURNING id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at,
+    response_status, response_body, last_error, created_at, delivered_at, tenant_id;
+
+-- name: DeleteFinishedWebhookDeliveries :execrows
+-- Удаляет завершенные доставки всех арендаторов, созданные до момента before
+DELETE
+FROM webhook_deliveries
+WHERE status <> 'pending'
+  AND created_at < sqlc.arg(before)::TIMESTAMPTZ;
@@ -0,0 +1,402 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET next_attempt_at = $1::TIMESTAMPTZ
+FROM webhook_endpoints e
+WHERE e.id = d.endpoint_id
+  AND d.id IN (SELECT id
+               FROM webhook_deliveries
+               WHERE status = 'pending'
+                 AND next_attempt_at <= now()
+               ORDER BY next_attempt_at, id
+               LIMIT $2::INT FOR UPDATE SKIP LOCKED)
+RETURNING d.id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.attempts,
+    e.url, e.secret, COALESCE(CASE WHEN e.previous_secret_expires_at > now() THEN e.previous_secret END, '')::TEXT AS previous_secret
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseUntil pgtype.Timestamptz
+	BatchSize  int32
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID             int64
+	EndpointID     pgtype.UUID
+	EventID        int64
+	EventType      string
+	Payload        []byte
+	Attempts       int32
+	Url            string
+	Secret         string
+	PreviousSecret string
+}
+
+// Берет в работу доставки всех арендаторов, время попытки которых наступило, и продлевает их до lease_until, чтобы другие экземпляры
+// сервиса не отправили их повторно. Если экземпляр упадет во время отправки, доставка будет повторена после lease_until
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.Query(ctx, claimWebhookDeliveries, arg.LeaseUntil, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Attempts,
+			&i.Url,
+			&i.Secret,
+			&i.PreviousSecret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhookEndpoint = `-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (tenant_id, url, description, event_types, secret)
+VALUES ($1::TEXT, $2::TEXT, $3::TEXT, $4::TEXT[], $5::TEXT)
+RETURNING id, url, description, event_types, secret, previous_secret, previous_secret_expires_at, created_at,
+    secret_rotated_at, tenant_id
+`
+
+type CreateWebhookEndpointParams struct {
+	TenantID    string
+	Url         string
+	Description string
+	EventTypes  []string
+	Secret      string
+}
+
+func (q *Queries) CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, createWebhookEndpoint,
+		arg.TenantID,
+		arg.Url,
+		arg.Description,
+		arg.EventTypes,
+		arg.Secret,
+	)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Description,
+		&i.EventTypes,
+		&i.Secret,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+		&i.CreatedAt,
+		&i.SecretRotatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const deleteFinishedWebhookDeliveries = `-- name: DeleteFinishedWebhookDeliveries :execrows
+DELETE
+FROM webhook_deliveries
+WHERE status <> 'pending'
+  AND created_at < $1::TIMESTAMPTZ
+`
+
+// Удаляет завершенные доставки всех арендаторов, созданные до момента before
+func (q *Queries) DeleteFinishedWebhookDeliveries(ctx context.Context, before pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFinishedWebhookDeliveries, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteWebhookEndpoint = `-- name: DeleteWebhookEndpoint :execrows
+DELETE
+FROM webhook_endpoints
+WHERE tenant_id = $1::TEXT
+  AND id = $2::UUID
+`
+
+type DeleteWebhookEndpointParams struct {
+	TenantID string
+	ID       pgtype.UUID
+}
+
+func (q *Queries) DeleteWebhookEndpoint(ctx context.Context, arg DeleteWebhookEndpointParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhookEndpoint, arg.TenantID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (tenant_id, endpoint_id, event_id, event_type, payload)
+SELECT tenant_id, id, $1::BIGINT, $2::TEXT, $3::JSONB
+FROM webhook_endpoints
+WHERE tenant_id = $4::TEXT
+  AND (cardinality(event_types) = 0 OR $2::TEXT = ANY (event_types))
+ON CONFLICT (endpoint_id, event_id) DO NOTHING
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	EventID   int64
+	EventType string
+	Payload   []byte
+	TenantID  string
+}
+
+// Создает доставки события всем вебхукам арендатора события, подписанным на его тип. Повторная публикация события
+// доставки не дублирует
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enqueueWebhookDeliveries,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+		arg.TenantID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at,
+       response_status, response_body, last_error, created_at, delivered_at, tenant_id
+FROM webhook_deliveries
+WHERE tenant_id = $1::TEXT
+  AND endpoint_id = $2::UUID
+  AND ($3::TEXT IS NULL OR status = $3::TEXT)
+ORDER BY id DESC
+LIMIT $4::INT
+`
+
+type ListWebhookDeliveriesParams struct {
+	TenantID   string
+	EndpointID pgtype.UUID
+	Status     pgtype.Text
+	PageSize   int32
+}
+
+// Доставки вебхука от новых к старым
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveries,
+		arg.TenantID,
+		arg.EndpointID,
+		arg.Status,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.ResponseStatus,
+			&i.ResponseBody,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookEndpoints = `-- name: ListWebhookEndpoints :many
+SELECT id, url, description, event_types, secret, previous_secret, previous_secret_expires_at, created_at,
+       secret_rotated_at, tenant_id
+FROM webhook_endpoints
+WHERE tenant_id = $1::TEXT
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListWebhookEndpoints(ctx context.Context, tenantID string) ([]WebhookEndpoint, error) {
+	rows, err := q.db.Query(ctx, listWebhookEndpoints, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookEndpoint
+	for rows.Next() {
+		var i WebhookEndpoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Description,
+			&i.EventTypes,
+			&i.Secret,
+			&i.PreviousSecret,
+			&i.PreviousSecretExpiresAt,
+			&i.CreatedAt,
+			&i.SecretRotatedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordWebhookDeliveryAttempt = `-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET status          = $1::TEXT,
+    attempts        = attempts + 1,
+    last_attempt_at = now(),
+    next_attempt_at = $2::TIMESTAMPTZ,
+    response_status = $3::INT,
+    response_body   = $4::TEXT,
+    last_error      = $5::TEXT,
+    delivered_at    = CASE WHEN $1::TEXT = 'succeeded' THEN now() END
+WHERE id = $6::BIGINT
+`
+
+type RecordWebhookDeliveryAttemptParams struct {
+	Status         string
+	NextAttemptAt  pgtype.Timestamptz
+	ResponseStatus pgtype.Int4
+	ResponseBody   pgtype.Text
+	LastError      pgtype.Text
+	ID             int64
+}
+
+func (q *Queries) RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) error {
+	_, err := q.db.Exec(ctx, recordWebhookDeliveryAttempt,
+		arg.Status,
+		arg.NextAttemptAt,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
+
+const replayWebhookDelivery = `-- name: ReplayWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status          = 'pending',
+    attempts        = 0,
+    next_attempt_at = now(),
+    delivered_at    = NULL
+WHERE tenant_id = $1::TEXT
+  AND id = $2::BIGINT
+  AND endpoint_id = $3::UUID
+RETURNING id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at,
+    response_status, response_body, last_error, created_at, delivered_at, tenant_id
+`
+
+type ReplayWebhookDeliveryParams struct {
+	TenantID   string
+	ID         int64
+	EndpointID pgtype.UUID
+}
+
+// Ставит доставку в очередь заново с полным числом попыток, в том числе уже доставленную
+func (q *Queries) ReplayWebhookDelivery(ctx context.Context, arg ReplayWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, replayWebhookDelivery, arg.TenantID, arg.ID, arg.EndpointID)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const rotateWebhookSecret = `-- name: RotateWebhookSecret :one
+UPDATE webhook_endpoints
+SET previous_secret            = CASE WHEN $1::TIMESTAMPTZ IS NULL THEN NULL ELSE secret END,
+    previous_secret_expires_at = $1::TIMESTAMPTZ,
+    secret                     = $2::TEXT,
+    secret_rotated_at          = now()
+WHERE tenant_id = $3::TEXT
+  AND id = $4::UUID
+RETURNING id, url, description, event_types, secret, previous_secret, previous_secret_expires_at, created_at,
+    secret_rotated_at, tenant_id
+`
+
+type RotateWebhookSecretParams struct {
+	PreviousSecretExpiresAt pgtype.Timestamptz
+	Secret                  string
+	TenantID                string
+	ID                      pgtype.UUID
+}
+
+// Новый секрет заменяет текущий, текущий остается действительным до previous_secret_expires_at.
+// При NULL предыдущий секрет отзывается сразу
+func (q *Queries) RotateWebhookSecret(ctx context.Context, arg RotateWebhookSecretParams) (WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, rotateWebhookSecret,
+		arg.PreviousSecretExpiresAt,
+		arg.Secret,
+		arg.TenantID,
+		arg.ID,
+	)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Description,
+		&i.EventTypes,
+		&i.Secret,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+		&i.CreatedAt,
+		&i.SecretRotatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/google/uuid"
+)
+
+// scopeUserID ограничивает выборку пользователем клиента: без userID выбираются подписки клиента, а подписки
+// другого пользователя клиенту без прав администратора недоступны. Для администратора и запросов без
+// аутентификации userID возвращается без изменений
+func scopeUserID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	scoped := principal.ScopedUserID(ctx)
+	if scoped == uuid.Nil {
+		return userID, nil
+	}
+	if userID != uuid.Nil && userID != scoped {
+		return uuid.Nil, model.ErrPermissionDenied
+	}
+
+	return scoped, nil
+}
+
+// requireAdmin разрешает действие только администратору и запросам без аутентификации
+func requireAdmin(ctx context.Context) error {
+	if caller, ok := principal.From(ctx); ok && !caller.Admin {
+		return model.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// getSubscription возвращает подписку, доступную клиенту. Чужая подписка для клиента не существует,
+// чтобы по ответу нельзя было узнать, есть ли подписка с таким ID
+func (s *SubscriptionService) getSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	subscription, err := s.repo.GetSubscriptionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if scoped := principal.ScopedUserID(ctx); scoped != uuid.Nil && subscription.UserID != scoped {
+		return nil, model.ErrSubscriptionNotFound
+	}
+
+	return subscription, nil
+}
+
+// listMonthlyCharges возвращает списания по подпискам, доступным клиенту
+func (s *SubscriptionService) listMonthlyCharges(ctx context.Context, filters model.Filters) ([]model.MonthlyCharge, error) {
+	userID, err := scopeUserID(ctx, filters.UserID)
+	if err != nil {
+		return nil, err
+	}
+	filters.UserID = userID
+
+	return s.repo.ListMonthlyCharges(ctx, filters)
+}
+
+// authorizeSubscription проверяет, что клиенту доступна подписка id, в том числе удаленная
+func (s *SubscriptionService) authorizeSubscription(ctx context.Context, id uuid.UUID) error {
+	scoped := principal.ScopedUserID(ctx)
+	if scoped == uuid.Nil {
+		return nil
+	}
+
+	owner, err := s.repo.GetSubscriptionOwner(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owner != scoped {
+		return model.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/google/uuid"
+)
+
+const (
+	// APIKeyPrefix отличает API-ключи от JWT в заголовке Authorization и от других секретов в конфигурациях клиентов
+	APIKeyPrefix = "emk_"
+	// apiKeyDisplayLength - сколько первых символов ключа хранится, чтобы ключ можно было узнать в списке
+	apiKeyDisplayLength = len(APIKeyPrefix) + 6
+	// apiKeyTouchInterval - как часто обновляется время последнего использования ключа
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) (*model.APIKey, error)
+	GetActiveAPIKey(ctx context.Context, hash []byte) (*model.APIKey, error)
+	TouchAPIKey(ctx context.Context, id uuid.UUID) error
+}
+
+// APIKeyService выпускает API-ключи машинных клиентов и проверяет их. Управлять ключами может только администратор
+type APIKeyService struct {
+	repo APIKeyRepository
+	now  func() time.Time
+}
+
+func NewAPIKeyService(repo APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// CreateAPIKey выпускает ключ со случайным значением. Значение возвращается только здесь, сохраняется лишь его хеш
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, scope := range key.Scopes {
+		if !slices.Contains(model.APIKeyScopes, scope) {
+			return nil, apperror.NewValidationError("scopes", "unknown scope "+scope)
+		}
+	}
+	if len(key.Scopes) == 0 {
+		return nil, apperror.NewValidationError("scopes", "at least one scope is required")
+	}
+	if !key.ExpiresAt.IsZero() && !key.ExpiresAt.After(s.now()) {
+		return nil, apperror.NewValidationError("expires_at", "must be in the future")
+	}
+
+	value, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.Prefix = value[:apiKeyDisplayLength]
+	key.Hash = hashAPIKey(value)
+
+	created, err := s.repo.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	created.Key = value
+
+	return created, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListAPIKeys(ctx)
+}
+
+// RevokeAPIKey отзывает ключ: запросы с ним перестают приниматься сразу
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.repo.RevokeAPIKey(ctx, id)
+}
+
+// AuthenticateAPIKey определяет клиента по ключу. ok ложно, если ключ не выпускался, отозван или истек.
+// Клиенту доступны только методы областей доступа ключа и подписки арендатора и пользователя ключа, если он задан
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, value string) (principal.Principal, bool, error) {
+	// арендатор запроса еще неизвестен: его определяет сам ключ
+	ctx = tenant.WithAll(ctx)
+
+	key, err := s.repo.GetActiveAPIKey(ctx, hashAPIKey(value))
+	if errors.Is(err, model.ErrAPIKeyNotFound) {
+		return principal.Principal{}, false, nil
+	}
+	if err != nil {
+		return principal.Principal{}, false, err
+	}
+
+	if s.now().Sub(key.LastUsedAt) >= apiKeyTouchInterval {
+		// время использования справочное, поэтому сбой его записи не мешает запросу
+		_ = s.repo.TouchAPIKey(ctx, key.ID)
+	}
+
+	return principal.Principal{
+		Subject:      "api-key:" + key.ID.String(),
+		UserID:       key.UserID,
+		TenantID:     key.TenantID,
+		Scopes:       key.Scopes,
+		ScopeLimited: true,
+	}, true, nil
+}
+
+func newAPIKey() (string, error) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(value), nil
+}
+
+// hashAPIKey возвращает хеш ключа для хранения и поиска. Ключ случаен и достаточно длинный, поэтому медленный
+// хеш с солью, как для паролей, не нужен
+func hashAPIKey(value string) []byte {
+	hash := sha256.Sum256([]byte(value))
+	return hash[:]
+}
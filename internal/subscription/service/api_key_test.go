@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAPIKeyRepository struct {
+	APIKeyRepository
+	keys    []model.APIKey
+	touched []uuid.UUID
+}
+
+func (r *stubAPIKeyRepository) CreateAPIKey(_ context.Context, key model.APIKey) (*model.APIKey, error) {
+	key.ID = uuid.New()
+	r.keys = append(r.keys, key)
+	return &key, nil
+}
+
+func (r *stubAPIKeyRepository) GetActiveAPIKey(_ context.Context, hash []byte) (*model.APIKey, error) {
+	for _, key := range r.keys {
+		if string(key.Hash) == string(hash) && key.RevokedAt.IsZero() {
+			return &key, nil
+		}
+	}
+
+	return nil, model.ErrAPIKeyNotFound
+}
+
+func (r *stubAPIKeyRepository) TouchAPIKey(_ context.Context, id uuid.UUID) error {
+	r.touched = append(r.touched, id)
+	return nil
+}
+
+func TestAPIKeyService(t *testing.T) {
+	now := time.Now()
+	repo := &stubAPIKeyRepository{}
+	service := NewAPIKeyService(repo)
+	service.now = func() time.Time { return now }
+
+	admin := principal.With(context.Background(), principal.Principal{Subject: "ops", Admin: true})
+	userID := uuid.New()
+
+	created, err := service.CreateAPIKey(admin, model.APIKey{
+		Name:   "monthly report",
+		Scopes: []string{model.ScopeReportsRead},
+		UserID: userID,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+	// хранится только хеш ключа
+	assert.Empty(t, repo.keys[0].Key)
+	assert.Equal(t, hashAPIKey(created.Key), repo.keys[0].Hash)
+
+	t.Run("Проверка ключа", func(t *testing.T) {
+		caller, ok, err := service.AuthenticateAPIKey(context.Background(), created.Key)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, userID, caller.UserID)
+		assert.Equal(t, []string{model.ScopeReportsRead}, caller.Scopes)
+		assert.True(t, caller.ScopeLimited)
+		assert.False(t, caller.Admin)
+		assert.Equal(t, []uuid.UUID{created.ID}, repo.touched)
+
+		_, ok, err = service.AuthenticateAPIKey(context.Background(), APIKeyPrefix+"forged")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Время использования обновляется не чаще раза в минуту", func(t *testing.T) {
+		repo.touched = nil
+		repo.keys[0].LastUsedAt = now.Add(-30 * time.Second)
+
+		_, ok, err := service.AuthenticateAPIKey(context.Background(), created.Key)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, repo.touched)
+	})
+
+	t.Run("Некорректный ключ", func(t *testing.T) {
+		for name, key := range map[string]model.APIKey{
+			"scopes":     {Name: "job", Scopes: []string{"subscriptions:admin"}},
+			"no scopes":  {Name: "job"},
+			"expires_at": {Name: "job", Scopes: []string{model.ScopeSubscriptionsRead}, ExpiresAt: now.Add(-time.Hour)},
+		} {
+			_, err := service.CreateAPIKey(admin, key)
+			var validationErr *apperror.ValidationError
+			assert.ErrorAs(t, err, &validationErr, name)
+		}
+	})
+
+	t.Run("Управлять ключами может только администратор", func(t *testing.T) {
+		user := principal.With(context.Background(), principal.Principal{Subject: "alice", UserID: userID})
+		_, err := service.CreateAPIKey(user, model.APIKey{Name: "job", Scopes: []string{model.ScopeSubscriptionsRead}})
+		assert.ErrorIs(t, err, model.ErrPermissionDenied)
+
+		_, err = service.RevokeAPIKey(user, created.ID)
+		assert.ErrorIs(t, err, model.ErrPermissionDenied)
+	})
+}
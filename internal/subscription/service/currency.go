@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+)
+
+// rateTable хранит курсы валют к базовой валюте, отсортированные по дате начала действия
+type rateTable struct {
+	base  string
+	rates map[string][]model.ExchangeRate
+	used  map[string]map[time.Time]model.ExchangeRate
+}
+
+func newRateTable(base string, rates []model.ExchangeRate) *rateTable {
+	table := &rateTable{
+		base:  base,
+		rates: make(map[string][]model.ExchangeRate),
+		used:  make(map[string]map[time.Time]model.ExchangeRate),
+	}
+
+	for _, rate := range rates {
+		table.rates[rate.Currency] = append(table.rates[rate.Currency], rate)
+	}
+	for _, currencyRates := range table.rates {
+		sort.Slice(currencyRates, func(i, j int) bool {
+			return currencyRates[i].ValidFrom.Before(currencyRates[j].ValidFrom)
+		})
+	}
+
+	return table
+}
+
+// rateAt возвращает курс, действующий на первое число месяца month
+func (t *rateTable) rateAt(currency string, month model.YearMonth) (*big.Rat, error) {
+	if currency == t.base {
+		return big.NewRat(1, 1), nil
+	}
+
+	currencyRates := t.rates[currency]
+	i := sort.Search(len(currencyRates), func(i int) bool {
+		return currencyRates[i].ValidFrom.After(month.Time())
+	})
+	if i == 0 {
+		return nil, fmt.Errorf("%w: %s on %s", model.ErrExchangeRateNotFound, currency, month)
+	}
+
+	rate := currencyRates[i-1]
+	if t.used[currency] == nil {
+		t.used[currency] = make(map[time.Time]model.ExchangeRate)
+	}
+	t.used[currency][rate.ValidFrom] = rate
+
+	return rate.Rate, nil
+}
+
+// convert переводит сумму в минимальных единицах валюты from в минимальные единицы валюты to
+func (t *rateTable) convert(amount int64, from, to string, month model.YearMonth) (*big.Rat, error) {
+	result := new(big.Rat).SetInt64(amount)
+	if from == to {
+		return result, nil
+	}
+
+	fromRate, err := t.rateAt(from, month)
+	if err != nil {
+		return nil, err
+	}
+
+	toRate, err := t.rateAt(to, month)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Mul(result, fromRate)
+	result.Quo(result, toRate)
+	result.Mul(result, big.NewRat(model.MinorUnitsFactor(to), model.MinorUnitsFactor(from)))
+
+	return result, nil
+}
+
+// usedRates возвращает курсы, которые участвовали в конвертации, в стабильном порядке
+func (t *rateTable) usedRates() []model.ExchangeRate {
+	var rates []model.ExchangeRate
+	for _, currencyRates := range t.used {
+		for _, rate := range currencyRates {
+			rates = append(rates, rate)
+		}
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Currency != rates[j].Currency {
+			return rates[i].Currency < rates[j].Currency
+		}
+		return rates[i].ValidFrom.Before(rates[j].ValidFrom)
+	})
+
+	return rates
+}
+
+// roundRat округляет число до целого, половины округляются от нуля
+func roundRat(value *big.Rat) int64 {
+	num := new(big.Int).Set(value.Num())
+	den := value.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	return quo.Int64()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+)
+
+var currencyCodeRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type ExchangeRateRepository interface {
+	SaveExchangeRates(ctx context.Context, rates []model.ExchangeRate) error
+	ListExchangeRates(ctx context.Context, currencies []string, startDate, endDate time.Time) ([]model.ExchangeRate, error)
+}
+
+type ExchangeRateService struct {
+	repo ExchangeRateRepository
+}
+
+func NewExchangeRateService(repo ExchangeRateRepository) *ExchangeRateService {
+	return &ExchangeRateService{
+		repo: repo,
+	}
+}
+
+// ImportFile загружает курсы из CSV-файла вида "currency,valid_from,rate",
+// где valid_from - дата в формате YYYY-MM-DD, а rate - стоимость единицы валюты в базовой валюте
+func (s *ExchangeRateService) ImportFile(ctx context.Context, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	rates, err := parseExchangeRates(file)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+
+	err = s.repo.SaveExchangeRates(ctx, rates)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rates), nil
+}
+
+func parseExchangeRates(r io.Reader) ([]model.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var rates []model.ExchangeRate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+
+		currency := strings.ToUpper(record[0])
+		if !currencyCodeRegexp.MatchString(currency) {
+			return nil, fmt.Errorf("line %d: invalid currency code %q", line, record[0])
+		}
+
+		validFrom, err := time.Parse(time.DateOnly, record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q: %w", line, record[1], err)
+		}
+
+		rate, ok := new(big.Rat).SetString(record[2])
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[2])
+		}
+
+		rates = append(rates, model.ExchangeRate{
+			Currency:  currency,
+			ValidFrom: validFrom,
+			Rate:      rate,
+		})
+	}
+
+	return rates, nil
+}
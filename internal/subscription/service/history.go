@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/google/uuid"
+)
+
+// ListSubscriptionHistory возвращает журнал изменений подписки. Журнал доступен и для удаленных подписок,
+// в том числе окончательно (клиенту, ограниченному своими подписками, - только до окончательного удаления);
+// пустой журнал означает, что подписки нет или она создана до появления журнала
+func (s *SubscriptionService) ListSubscriptionHistory(ctx context.Context, id uuid.UUID) ([]model.AuditRecord, error) {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.ListSubscriptionHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 {
+		return records, nil
+	}
+
+	if _, err = s.repo.GetSubscriptionById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+)
+
+type IdempotencyRepository interface {
+	ClaimIdempotencyKey(ctx context.Context, record model.IdempotencyRecord, staleBefore time.Time) (bool, error)
+	GetIdempotencyKey(ctx context.Context, key string) (*model.IdempotencyRecord, error)
+	SaveIdempotencyResponse(ctx context.Context, key string, response []byte) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+// claimAttempts - сколько раз пытаться занять ключ, если он освободился между попыткой занять и чтением
+const claimAttempts = 3
+
+// IdempotencyService хранит ответы на запросы с ключами идемпотентности в течение ttl.
+// Запрос, не завершившийся за lease (например, из-за падения сервиса), можно повторить с тем же ключом
+type IdempotencyService struct {
+	repo  IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewIdempotencyService(repo IdempotencyRepository, ttl, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo:  repo,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+// Begin занимает ключ для запроса. Если запрос с этим ключом уже выполнен, возвращает сохраненный ответ,
+// если выполняется - model.ErrIdempotencyKeyInProgress, если ключ использован с другим запросом - model.ErrIdempotencyKeyReused.
+// Если ключ занят, ответ равен nil, и после выполнения запроса нужно вызвать Complete или Release
+func (s *IdempotencyService) Begin(ctx context.Context, key, method string, requestHash []byte) ([]byte, error) {
+	for range claimAttempts {
+		now := time.Now()
+
+		claimed, err := s.repo.ClaimIdempotencyKey(ctx, model.IdempotencyRecord{
+			Key:         key,
+			Method:      method,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(s.ttl),
+		}, now.Add(-s.lease))
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		record, err := s.repo.GetIdempotencyKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			continue
+		}
+
+		if record.Method != method || !bytes.Equal(record.RequestHash, requestHash) {
+			return nil, model.ErrIdempotencyKeyReused
+		}
+		if record.Response == nil {
+			return nil, model.ErrIdempotencyKeyInProgress
+		}
+
+		return record.Response, nil
+	}
+
+	return nil, model.ErrIdempotencyKeyInProgress
+}
+
+// Complete сохраняет ответ на запрос, выполненный с ключом
+func (s *IdempotencyService) Complete(ctx context.Context, key string, response []byte) error {
+	return s.repo.SaveIdempotencyResponse(ctx, key, response)
+}
+
+// Release освобождает ключ запроса, завершившегося ошибкой, чтобы его можно было повторить
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	return s.repo.DeleteIdempotencyKey(ctx, key)
+}
+
+// PurgeExpired удаляет ключи с истекшим сроком хранения
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpiredIdempotencyKeys(ctx)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubIdempotencyRepository struct {
+	IdempotencyRepository
+	records map[string]model.IdempotencyRecord
+}
+
+func (r *stubIdempotencyRepository) ClaimIdempotencyKey(_ context.Context, record model.IdempotencyRecord, staleBefore time.Time) (bool, error) {
+	existing, ok := r.records[record.Key]
+	if ok && existing.ExpiresAt.After(time.Now()) && (existing.Response != nil || existing.CreatedAt.After(staleBefore)) {
+		return false, nil
+	}
+
+	record.CreatedAt = time.Now()
+	r.records[record.Key] = record
+	return true, nil
+}
+
+func (r *stubIdempotencyRepository) GetIdempotencyKey(_ context.Context, key string) (*model.IdempotencyRecord, error) {
+	record, ok := r.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (r *stubIdempotencyRepository) SaveIdempotencyResponse(_ context.Context, key string, response []byte) error {
+	record := r.records[key]
+	record.Response = response
+	r.records[key] = record
+	return nil
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	repo := &stubIdempotencyRepository{records: map[string]model.IdempotencyRecord{}}
+	svc := NewIdempotencyService(repo, time.Hour, time.Minute)
+	ctx := context.Background()
+
+	response, err := svc.Begin(ctx, "key", "/Add", []byte("hash"))
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+
+	_, err = svc.Begin(ctx, "key", "/Add", []byte("hash"))
+	assert.ErrorIs(t, err, model.ErrIdempotencyKeyInProgress)
+
+	assert.NoError(t, svc.Complete(ctx, "key", []byte("response")))
+
+	response, err = svc.Begin(ctx, "key", "/Add", []byte("hash"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("response"), response)
+
+	_, err = svc.Begin(ctx, "key", "/Add", []byte("other"))
+	assert.ErrorIs(t, err, model.ErrIdempotencyKeyReused)
+
+	_, err = svc.Begin(ctx, "key", "/Delete", []byte("hash"))
+	assert.ErrorIs(t, err, model.ErrIdempotencyKeyReused)
+
+	stale := repo.records["key"]
+	stale.Response = nil
+	stale.CreatedAt = time.Now().Add(-2 * time.Minute)
+	repo.records["key"] = stale
+
+	response, err = svc.Begin(ctx, "key", "/Add", []byte("hash"))
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+// CancelSubscription отменяет подписку: она действует до месяца effective включительно и после него истекает.
+// Нулевой effective означает текущий месяц
+func (s *SubscriptionService) CancelSubscription(ctx context.Context, id uuid.UUID, effective model.YearMonth) (*model.Subscription, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subscription.Status.TransitionTo(model.SubscriptionStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	if effective.IsZero() {
+		effective = s.currentMonth()
+	}
+	if err := validateEffectiveMonth(*subscription, effective); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CancelSubscription(ctx, id, subscription.Status, effective)
+}
+
+// PauseSubscription приостанавливает подписку начиная с месяца effective: месяцы приостановки не оплачиваются
+// до возобновления. Нулевой effective означает текущий месяц
+func (s *SubscriptionService) PauseSubscription(ctx context.Context, id uuid.UUID, effective model.YearMonth) (*model.Subscription, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subscription.Status.TransitionTo(model.SubscriptionStatusPaused); err != nil {
+		return nil, err
+	}
+
+	if effective.IsZero() {
+		effective = s.currentMonth()
+	}
+	if err := validateEffectiveMonth(*subscription, effective); err != nil {
+		return nil, err
+	}
+
+	pauses, err := s.repo.ListSubscriptionPauses(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(pauses) > 0 && !effective.After(pauses[len(pauses)-1].EndDate) {
+		return nil, apperror.NewValidationError("effective_date", "must be after the previous pause ended in "+pauses[len(pauses)-1].EndDate.String())
+	}
+
+	return s.repo.PauseSubscription(ctx, id, subscription.Status, effective)
+}
+
+// ResumeSubscription возобновляет приостановленную подписку с месяца effective, нулевой effective означает
+// текущий месяц. Приостановка завершается месяцем, предшествующим effective
+func (s *SubscriptionService) ResumeSubscription(ctx context.Context, id uuid.UUID, effective model.YearMonth) (*model.Subscription, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status != model.SubscriptionStatusPaused {
+		return nil, fmt.Errorf("%w: only paused subscription can be resumed, subscription is %s", model.ErrInvalidStatusTransition, subscription.Status)
+	}
+
+	if effective.IsZero() {
+		effective = s.currentMonth()
+	}
+
+	pauses, err := s.repo.ListSubscriptionPauses(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(pauses) > 0 && !effective.After(pauses[len(pauses)-1].StartDate) {
+		return nil, apperror.NewValidationError("effective_date", "must be after the pause start "+pauses[len(pauses)-1].StartDate.String())
+	}
+
+	return s.repo.ResumeSubscription(ctx, id, effective)
+}
+
+// UpdateStatuses переводит в статус active подписки, пробный период которых закончился, а затем в статус expired -
+// подписки, месяц окончания которых прошел. Возвращает количество активированных и истекших подписок
+func (s *SubscriptionService) UpdateStatuses(ctx context.Context) (int64, int64, error) {
+	currentMonth := s.currentMonth()
+
+	activated, err := s.repo.ActivateEndedTrials(ctx, currentMonth)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	expired, err := s.repo.ExpireSubscriptions(ctx, currentMonth)
+	if err != nil {
+		return activated, 0, err
+	}
+
+	return activated, expired, nil
+}
+
+// initialStatus возвращает статус новой подписки: подписка, закончившаяся до текущего месяца, сразу истекшая,
+// подписка с незакончившимся пробным периодом - в статусе trial
+func (s *SubscriptionService) initialStatus(subscription model.Subscription) model.SubscriptionStatus {
+	currentMonth := s.currentMonth()
+
+	if !subscription.EndDate.IsZero() && subscription.EndDate.Before(currentMonth) {
+		return model.SubscriptionStatusExpired
+	}
+	if !subscription.TrialEndDate.IsZero() && !subscription.TrialEndDate.Before(currentMonth) {
+		return model.SubscriptionStatusTrial
+	}
+
+	return model.SubscriptionStatusActive
+}
+
+func (s *SubscriptionService) currentMonth() model.YearMonth {
+	return model.YearMonthOf(s.now().UTC())
+}
+
+// validateEffectiveMonth проверяет, что месяц перехода попадает в период подписки
+func validateEffectiveMonth(subscription model.Subscription, effective model.YearMonth) error {
+	if effective.Before(subscription.StartDate) {
+		return apperror.NewValidationError("effective_date", "must not be before start_date "+subscription.StartDate.String())
+	}
+	if !subscription.EndDate.IsZero() && effective.After(subscription.EndDate) {
+		return apperror.NewValidationError("effective_date", "must not be after end_date "+subscription.EndDate.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+)
+
+type OutboxRepository interface {
+	LockOutbox(ctx context.Context) (release func(), locked bool, err error)
+	ListPendingOutboxEvents(ctx context.Context, limit int) ([]model.Event, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	MarkOutboxEventFailed(ctx context.Context, id int64, reason string, deadLetter bool) error
+	DeletePublishedOutboxEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventPublisher доставляет события получателям. Ошибка означает, что событие не доставлено и будет
+// опубликовано повторно; повтор возможен и после успешной доставки, если сервис остановится до отметки о ней
+type EventPublisher interface {
+	Publish(ctx context.Context, event model.Event) error
+}
+
+// OutboxRelay публикует события из outbox через publisher не менее одного раза, сохраняя порядок событий
+// каждой подписки: пока событие подписки не опубликовано, следующие события этой подписки не публикуются.
+// Событие, которое не удалось опубликовать за maxAttempts попыток, откладывается (dead letter), чтобы
+// не задерживать следующие события подписки
+type OutboxRelay struct {
+	repo        OutboxRepository
+	publisher   EventPublisher
+	batchSize   int
+	maxAttempts int
+	now         func() time.Time
+}
+
+func NewOutboxRelay(repo OutboxRepository, publisher EventPublisher, batchSize, maxAttempts int) *OutboxRelay {
+	return &OutboxRelay{
+		repo:        repo,
+		publisher:   publisher,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		now:         time.Now,
+	}
+}
+
+// Relay публикует очередную порцию событий - не больше одного события каждой подписки - и возвращает количество
+// опубликованных, неопубликованных из-за ошибок и отложенных после исчерпания попыток (входят в failed).
+// Если события публикует другой экземпляр сервиса, ничего не делает
+func (r *OutboxRelay) Relay(ctx context.Context) (published, failed, deadLettered int, err error) {
+	release, locked, err := r.repo.LockOutbox(ctx)
+	if err != nil || !locked {
+		return 0, 0, 0, err
+	}
+	defer release()
+
+	events, err := r.repo.ListPendingOutboxEvents(ctx, r.batchSize)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	for _, event := range events {
+		if publishErr := r.publisher.Publish(ctx, event); publishErr != nil {
+			if ctx.Err() != nil {
+				return published, failed, deadLettered, ctx.Err()
+			}
+
+			failed++
+			deadLetter := int(event.Attempts)+1 >= r.maxAttempts
+			if deadLetter {
+				deadLettered++
+			}
+			if err = r.repo.MarkOutboxEventFailed(ctx, event.ID, publishErr.Error(), deadLetter); err != nil {
+				return published, failed, deadLettered, err
+			}
+			continue
+		}
+
+		if err = r.repo.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+			return published, failed, deadLettered, err
+		}
+		published++
+	}
+
+	return published, failed, deadLettered, nil
+}
+
+// PurgePublished удаляет события, опубликованные раньше, чем retention назад
+func (r *OutboxRelay) PurgePublished(ctx context.Context, retention time.Duration) (int64, error) {
+	return r.repo.DeletePublishedOutboxEvents(ctx, r.now().Add(-retention))
+}
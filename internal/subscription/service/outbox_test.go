@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubOutboxRepository struct {
+	OutboxRepository
+	locked       bool
+	events       []model.Event
+	published    []int64
+	failed       map[int64]string
+	deadLettered []int64
+}
+
+func (r *stubOutboxRepository) LockOutbox(_ context.Context) (func(), bool, error) {
+	if r.locked {
+		return nil, false, nil
+	}
+
+	r.locked = true
+	return func() { r.locked = false }, true, nil
+}
+
+func (r *stubOutboxRepository) ListPendingOutboxEvents(_ context.Context, limit int) ([]model.Event, error) {
+	var pending []model.Event
+	selected := make(map[uuid.UUID]bool)
+	for _, event := range r.events {
+		if slices.Contains(r.published, event.ID) || slices.Contains(r.deadLettered, event.ID) || selected[event.SubscriptionID] {
+			continue
+		}
+
+		selected[event.SubscriptionID] = true
+		if len(pending) < limit {
+			pending = append(pending, event)
+		}
+	}
+
+	return pending, nil
+}
+
+func (r *stubOutboxRepository) MarkOutboxEventPublished(_ context.Context, id int64) error {
+	r.published = append(r.published, id)
+	return nil
+}
+
+func (r *stubOutboxRepository) MarkOutboxEventFailed(_ context.Context, id int64, reason string, deadLetter bool) error {
+	r.failed[id] = reason
+	for i := range r.events {
+		if r.events[i].ID == id {
+			r.events[i].Attempts++
+		}
+	}
+	if deadLetter {
+		r.deadLettered = append(r.deadLettered, id)
+	}
+	return nil
+}
+
+type stubEventPublisher struct {
+	failing   map[int64]bool
+	published []int64
+}
+
+func (p *stubEventPublisher) Publish(_ context.Context, event model.Event) error {
+	if p.failing[event.ID] {
+		return errors.New("receiver is unavailable")
+	}
+
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	repo := &stubOutboxRepository{
+		failed: map[int64]string{},
+		events: []model.Event{
+			{ID: 1, SubscriptionID: first},
+			{ID: 2, SubscriptionID: second},
+			{ID: 3, SubscriptionID: first},
+			{ID: 4, SubscriptionID: second},
+		},
+	}
+	publisher := &stubEventPublisher{failing: map[int64]bool{1: true}}
+	relay := NewOutboxRelay(repo, publisher, 10, 5)
+
+	// событие 1 не доставлено, поэтому следующее событие той же подписки ждет его повторной отправки
+	published, failed, _, err := relay.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, []int64{2}, publisher.published)
+	assert.Equal(t, "receiver is unavailable", repo.failed[1])
+	assert.False(t, repo.locked)
+
+	publisher.failing = nil
+	published, failed, _, err = relay.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Zero(t, failed)
+
+	published, _, _, err = relay.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []int64{2, 1, 4, 3}, publisher.published)
+
+	t.Run("Публикацию занял другой экземпляр", func(t *testing.T) {
+		repo.locked = true
+		repo.events = append(repo.events, model.Event{ID: 5, SubscriptionID: first})
+
+		published, _, _, err := relay.Relay(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, published)
+	})
+}
+
+func TestOutboxRelay_DeadLetter(t *testing.T) {
+	poisoned, healthy := uuid.New(), uuid.New()
+	repo := &stubOutboxRepository{failed: map[int64]string{}}
+	publisher := &stubEventPublisher{failing: map[int64]bool{}}
+	// у подписки poisoned недоставляемых событий больше, чем помещается в порцию
+	for id := int64(1); id <= 5; id++ {
+		repo.events = append(repo.events, model.Event{ID: id, SubscriptionID: poisoned})
+		publisher.failing[id] = true
+	}
+	repo.events = append(repo.events, model.Event{ID: 6, SubscriptionID: healthy})
+	relay := NewOutboxRelay(repo, publisher, 3, 2)
+
+	// событие другой подписки публикуется, несмотря на недоставляемые события перед ним
+	published, failed, deadLettered, err := relay.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 1, failed)
+	assert.Zero(t, deadLettered)
+	assert.Equal(t, []int64{6}, publisher.published)
+
+	// после maxAttempts попыток событие откладывается, и публикуется следующее событие подписки
+	_, _, deadLettered, err = relay.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deadLettered)
+	assert.Equal(t, []int64{1}, repo.deadLettered)
+
+	delete(publisher.failing, 2)
+	published, _, _, err = relay.Relay(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []int64{6, 2}, publisher.published)
+}
+
+func TestOutboxRelay_PurgePublished(t *testing.T) {
+	repo := &stubPurgeOutboxRepository{}
+	relay := NewOutboxRelay(repo, nil, 10, 5)
+	relay.now = func() time.Time { return time.Date(2025, time.April, 15, 12, 0, 0, 0, time.UTC) }
+
+	_, err := relay.PurgePublished(context.Background(), 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.April, 14, 12, 0, 0, 0, time.UTC), repo.before)
+}
+
+type stubPurgeOutboxRepository struct {
+	OutboxRepository
+	before time.Time
+}
+
+func (r *stubPurgeOutboxRepository) DeletePublishedOutboxEvents(_ context.Context, before time.Time) (int64, error) {
+	r.before = before
+	return 0, nil
+}
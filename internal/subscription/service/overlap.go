@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/google/uuid"
+)
+
+// checkOverlaps возвращает подписки, пересекающиеся с subscription, или ошибку, если политика запрещает пересечения.
+// При политике allow пересечения не проверяются
+func (s *SubscriptionService) checkOverlaps(ctx context.Context, subscription model.Subscription, excludeID uuid.UUID) ([]model.Subscription, error) {
+	if s.overlapPolicy == model.OverlapPolicyAllow {
+		return nil, nil
+	}
+
+	overlaps, err := s.repo.FindOverlappingSubscriptions(ctx, subscription, excludeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(overlaps) > 0 && s.overlapPolicy == model.OverlapPolicyReject {
+		ids := make([]string, 0, len(overlaps))
+		for _, overlap := range overlaps {
+			ids = append(ids, overlap.ID.String())
+		}
+
+		return nil, fmt.Errorf("%w: overlaps with %s", model.ErrSubscriptionAlreadyExists, strings.Join(ids, ", "))
+	}
+
+	return overlaps, nil
+}
+
+// overlapAllowed сообщает, нужно ли исключить подписку из ограничения на пересечения в базе данных
+func (s *SubscriptionService) overlapAllowed(overlaps []model.Subscription) bool {
+	return s.overlapPolicy == model.OverlapPolicyAllow || len(overlaps) > 0
+}
+
+func (s *SubscriptionService) ListSubscriptionOverlaps(ctx context.Context, filter model.OverlapFilter) ([]model.SubscriptionOverlap, error) {
+	userID, err := scopeUserID(ctx, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = userID
+
+	return s.repo.ListSubscriptionOverlaps(ctx, filter)
+}
+
+// MergeSubscriptions объединяет подписки mergedIDs с подпиской id: ее период расширяется до объединения периодов,
+// тарифы сохраняются, объединенные подписки удаляются. Объединять можно только подписки одного пользователя
+// на один сервис
+func (s *SubscriptionService) MergeSubscriptions(ctx context.Context, id uuid.UUID, mergedIDs []uuid.UUID) (*model.Subscription, error) {
+	if slices.Contains(mergedIDs, id) {
+		return nil, fmt.Errorf("%w: subscription cannot be merged with itself", model.ErrSubscriptionsNotMergeable)
+	}
+
+	target, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate := target.StartDate, target.EndDate
+	for _, mergedID := range mergedIDs {
+		merged, err := s.getSubscription(ctx, mergedID)
+		if err != nil {
+			return nil, err
+		}
+
+		if merged.UserID != target.UserID ||
+			model.NormalizeServiceName(merged.ServiceName) != model.NormalizeServiceName(target.ServiceName) {
+			return nil, fmt.Errorf("%w: %s", model.ErrSubscriptionsNotMergeable, mergedID)
+		}
+
+		if merged.StartDate.Before(startDate) {
+			startDate = merged.StartDate
+		}
+		endDate = laterEndDate(endDate, merged.EndDate)
+	}
+
+	return s.repo.MergeSubscriptions(ctx, id, mergedIDs, startDate, endDate)
+}
+
+// laterEndDate возвращает более позднюю дату окончания; нулевая дата (бессрочная подписка) позже любой
+func laterEndDate(a, b model.YearMonth) model.YearMonth {
+	if a.IsZero() || b.IsZero() {
+		return model.YearMonth{}
+	}
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/google/uuid"
+)
+
+// GetSpendTimeSeries возвращает расходы за период по интервалам размера granularity.
+// Интервалы без расходов тоже возвращаются, сумма интервалов равна GetTotalSum за тот же период
+func (s *SubscriptionService) GetSpendTimeSeries(ctx context.Context, filters model.Filters, granularity model.Granularity) (*model.SpendTimeSeries, error) {
+	if err := validateFilters(filters); err != nil {
+		return nil, err
+	}
+	if filters.Currency == "" {
+		filters.Currency = s.defaultCurrency(ctx)
+	}
+
+	months, rates, err := s.monthlySpend(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	spendByMonth := make(map[model.YearMonth]monthSpend, len(months))
+	for _, month := range months {
+		spendByMonth[month.month] = month
+	}
+
+	series := &model.SpendTimeSeries{
+		Currency: filters.Currency,
+		Rates:    rates.usedRates(),
+	}
+
+	var active map[uuid.UUID]struct{}
+	for month := filters.StartDate; !month.After(filters.EndDate); month = month.AddMonths(1) {
+		last := len(series.Buckets) - 1
+		if last < 0 || granularity.BucketStart(month) != granularity.BucketStart(series.Buckets[last].StartDate) {
+			series.Buckets = append(series.Buckets, model.SpendBucket{StartDate: month})
+			active = make(map[uuid.UUID]struct{})
+			last++
+		}
+
+		bucket := &series.Buckets[last]
+		bucket.EndDate = month
+
+		spend := spendByMonth[month]
+		bucket.Amount += spend.amount
+		for id := range spend.subscriptions {
+			active[id] = struct{}{}
+		}
+		bucket.ActiveSubscriptions = int32(len(active))
+
+		series.Total += spend.amount
+	}
+
+	return series, nil
+}
+
+// GetSpendBreakdown возвращает расходы за период в разрезе groupBy. Если limit больше нуля, возвращаются limit
+// крупнейших групп, а остальные объединяются в строку Other. Сумма строк равна GetTotalSum за тот же период
+func (s *SubscriptionService) GetSpendBreakdown(ctx context.Context, filters model.Filters, groupBy model.BreakdownDimension, limit int) (*model.SpendBreakdown, error) {
+	if err := validateFilters(filters); err != nil {
+		return nil, err
+	}
+	if filters.Currency == "" {
+		filters.Currency = s.defaultCurrency(ctx)
+	}
+
+	charges, err := s.listMonthlyCharges(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.loadRates(ctx, filters, charges)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*spendGroup)
+	for _, month := range groupChargesByMonth(charges) {
+		byGroup := make(map[string][]model.MonthlyCharge)
+		for _, charge := range month.charges {
+			key := breakdownKey(charge, groupBy)
+			byGroup[key] = append(byGroup[key], charge)
+
+			if groups[key] == nil {
+				groups[key] = &spendGroup{subscriptions: make(map[uuid.UUID]struct{})}
+			}
+			groups[key].subscriptions[charge.SubscriptionID] = struct{}{}
+		}
+
+		amounts, err := allocateMonth(rates, month.month, byGroup, filters.Currency)
+		if err != nil {
+			return nil, err
+		}
+		for key, amount := range amounts {
+			groups[key].amount += amount
+		}
+	}
+
+	breakdown := &model.SpendBreakdown{
+		Currency: filters.Currency,
+		Rates:    rates.usedRates(),
+	}
+	for key, group := range groups {
+		breakdown.Total += group.amount
+		breakdown.Rows = append(breakdown.Rows, model.SpendBreakdownRow{
+			Key:           key,
+			Amount:        group.amount,
+			Subscriptions: int32(len(group.subscriptions)),
+		})
+	}
+
+	sort.Slice(breakdown.Rows, func(i, j int) bool {
+		if breakdown.Rows[i].Amount != breakdown.Rows[j].Amount {
+			return breakdown.Rows[i].Amount > breakdown.Rows[j].Amount
+		}
+		return breakdown.Rows[i].Key < breakdown.Rows[j].Key
+	})
+
+	if limit > 0 && len(breakdown.Rows) > limit {
+		other := model.SpendBreakdownRow{Other: true}
+		subscriptions := make(map[uuid.UUID]struct{})
+		for _, row := range breakdown.Rows[limit:] {
+			other.Amount += row.Amount
+			for id := range groups[row.Key].subscriptions {
+				subscriptions[id] = struct{}{}
+			}
+		}
+		other.Subscriptions = int32(len(subscriptions))
+
+		breakdown.Rows = append(breakdown.Rows[:limit], other)
+	}
+
+	for i := range breakdown.Rows {
+		if breakdown.Total != 0 {
+			breakdown.Rows[i].Share = float64(breakdown.Rows[i].Amount) / float64(breakdown.Total)
+		}
+	}
+
+	return breakdown, nil
+}
+
+type spendGroup struct {
+	amount        int64
+	subscriptions map[uuid.UUID]struct{}
+}
+
+func breakdownKey(charge model.MonthlyCharge, groupBy model.BreakdownDimension) string {
+	if groupBy == model.BreakdownByUserID {
+		return charge.UserID.String()
+	}
+
+	return charge.ServiceName
+}
+
+// allocateMonth распределяет округленную сумму месяца между группами методом наибольшего остатка:
+// каждая группа получает целую часть своей доли, оставшиеся единицы достаются группам с наибольшей дробной частью.
+// Так сумма по группам в точности совпадает с суммой месяца в GetTotalSum
+func allocateMonth(rates *rateTable, month model.YearMonth, byGroup map[string][]model.MonthlyCharge, currency string) (map[string]int64, error) {
+	type share struct {
+		key       string
+		remainder *big.Rat
+	}
+
+	total := new(big.Rat)
+	amounts := make(map[string]int64, len(byGroup))
+	shares := make([]share, 0, len(byGroup))
+	var allocated int64
+
+	for key, charges := range byGroup {
+		exact := new(big.Rat)
+		for _, charge := range charges {
+			if charge.Amount() == 0 {
+				continue
+			}
+
+			converted, err := rates.convert(charge.Amount(), charge.Currency, currency, month)
+			if err != nil {
+				return nil, err
+			}
+			exact.Add(exact, converted)
+		}
+		total.Add(total, exact)
+
+		floor := new(big.Int).Quo(exact.Num(), exact.Denom())
+		amounts[key] = floor.Int64()
+		allocated += floor.Int64()
+		shares = append(shares, share{
+			key:       key,
+			remainder: new(big.Rat).Sub(exact, new(big.Rat).SetInt(floor)),
+		})
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if c := shares[i].remainder.Cmp(shares[j].remainder); c != 0 {
+			return c > 0
+		}
+		return shares[i].key < shares[j].key
+	})
+
+	for i := int64(0); i < roundRat(total)-allocated; i++ {
+		amounts[shares[i].key]++
+	}
+
+	return amounts, nil
+}
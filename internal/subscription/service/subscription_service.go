@@ -2,50 +2,453 @@ package service
 
 import (
 	"context"
+	"math/big"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
 	"github.com/google/uuid"
 )
 
 type SubscriptionRepository interface {
 	CreateSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error)
 	GetSubscriptionById(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
-	AllSubscriptions(ctx context.Context, pagination model.Pagination) ([]model.Subscription, error)
-	UpdateSubscription(ctx context.Context, id uuid.UUID, subscription model.Subscription) (*model.Subscription, error)
-	DeleteSubscription(ctx context.Context, id uuid.UUID) error
-	GetSumSubscriptions(ctx context.Context, filters model.Filters) (int32, error)
+	GetSubscriptionOwner(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	AllSubscriptions(ctx context.Context, query model.SubscriptionQuery) ([]model.Subscription, error)
+	CountSubscriptions(ctx context.Context, filter model.SubscriptionFilter) (int64, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, update model.SubscriptionUpdate) (*model.Subscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID, expectedVersion int64) error
+	UndeleteSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	PurgeDeletedSubscriptions(ctx context.Context, before time.Time, tenantBefore map[string]time.Time) (int64, error)
+	ChangeSubscriptionPlan(ctx context.Context, plan model.SubscriptionPlan) (*model.Subscription, error)
+	ListSubscriptionPlans(ctx context.Context, id uuid.UUID) ([]model.SubscriptionPlan, error)
+	ListMonthlyCharges(ctx context.Context, filters model.Filters) ([]model.MonthlyCharge, error)
+	FindOverlappingSubscriptions(ctx context.Context, subscription model.Subscription, excludeID uuid.UUID) ([]model.Subscription, error)
+	ListSubscriptionOverlaps(ctx context.Context, filter model.OverlapFilter) ([]model.SubscriptionOverlap, error)
+	MergeSubscriptions(ctx context.Context, id uuid.UUID, mergedIDs []uuid.UUID, startDate, endDate model.YearMonth) (*model.Subscription, error)
+	CancelSubscription(ctx context.Context, id uuid.UUID, from model.SubscriptionStatus, endDate model.YearMonth) (*model.Subscription, error)
+	PauseSubscription(ctx context.Context, id uuid.UUID, from model.SubscriptionStatus, startDate model.YearMonth) (*model.Subscription, error)
+	ResumeSubscription(ctx context.Context, id uuid.UUID, startDate model.YearMonth) (*model.Subscription, error)
+	ListSubscriptionPauses(ctx context.Context, id uuid.UUID) ([]model.SubscriptionPause, error)
+	ExpireSubscriptions(ctx context.Context, before model.YearMonth) (int64, error)
+	ActivateEndedTrials(ctx context.Context, before model.YearMonth) (int64, error)
+	ListEndingTrials(ctx context.Context, filter model.TrialFilter) ([]model.Subscription, error)
+	ListSubscriptionHistory(ctx context.Context, id uuid.UUID) ([]model.AuditRecord, error)
 }
 
 type SubscriptionService struct {
-	repo SubscriptionRepository
+	repo          SubscriptionRepository
+	rates         ExchangeRateRepository
+	baseCurrency  string
+	tenants       map[string]TenantSettings
+	overlapPolicy model.OverlapPolicy
+	now           func() time.Time
 }
 
-func NewSubscriptionService(repo SubscriptionRepository) *SubscriptionService {
+// NewSubscriptionService создает сервис подписок. baseCurrency - валюта, к которой заданы курсы валют, и валюта
+// по умолчанию для арендаторов, которым tenants не задает свою
+func NewSubscriptionService(repo SubscriptionRepository, rates ExchangeRateRepository, baseCurrency string, tenants map[string]TenantSettings, overlapPolicy model.OverlapPolicy) *SubscriptionService {
 	return &SubscriptionService{
-		repo: repo,
+		repo:          repo,
+		rates:         rates,
+		baseCurrency:  baseCurrency,
+		tenants:       tenants,
+		overlapPolicy: overlapPolicy,
+		now:           time.Now,
 	}
 }
 
-func (s *SubscriptionService) AddSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, error) {
-	return s.repo.CreateSubscription(ctx, subscription)
+// AddSubscription создает подписку. Пересечения с подписками того же пользователя на тот же сервис обрабатываются
+// по политике overlapPolicy: при политике warn пересекающиеся подписки возвращаются вместе с созданной
+func (s *SubscriptionService) AddSubscription(ctx context.Context, subscription model.Subscription) (*model.Subscription, []model.Subscription, error) {
+	if subscription.Currency == "" {
+		subscription.Currency = s.defaultCurrency(ctx)
+	}
+	if subscription.BillingPeriod.IsZero() {
+		subscription.BillingPeriod = model.BillingPeriodMonthly
+	}
+	if subscription.PriceInMajorUnits {
+		subscription.Price = model.ToMinorUnits(subscription.Price, subscription.Currency)
+		subscription.PromoPhases = slices.Clone(subscription.PromoPhases)
+		for i := range subscription.PromoPhases {
+			subscription.PromoPhases[i].Price = model.ToMinorUnits(subscription.PromoPhases[i].Price, subscription.Currency)
+		}
+		subscription.PriceInMajorUnits = false
+	}
+	if err := validateSubscription(subscription); err != nil {
+		return nil, nil, err
+	}
+	if err := applyTrial(&subscription); err != nil {
+		return nil, nil, err
+	}
+	subscription.Status = s.initialStatus(subscription)
+
+	userID, err := scopeUserID(ctx, subscription.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	subscription.UserID = userID
+
+	overlaps, err := s.checkOverlaps(ctx, subscription, uuid.Nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	subscription.OverlapAllowed = s.overlapAllowed(overlaps)
+
+	created, err := s.repo.CreateSubscription(ctx, subscription)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return created, overlaps, nil
 }
 
 func (s *SubscriptionService) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
-	return s.repo.GetSubscriptionById(ctx, id)
+	return s.getSubscription(ctx, id)
+}
+
+// ListSubscriptions возвращает страницу подписок. Запрашивается на одну подписку больше размера страницы,
+// чтобы без отдельного запроса понять, есть ли следующая страница
+func (s *SubscriptionService) ListSubscriptions(ctx context.Context, query model.SubscriptionQuery) (*model.SubscriptionPage, error) {
+	pageSize := query.Pagination.Count
+
+	userID, err := scopeUserID(ctx, query.Filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	query.Filter.UserID = userID
+
+	if query.After != nil {
+		query.Pagination.Page = 0
+	}
+	query.Pagination.Count = pageSize + 1
+
+	subscriptions, err := s.repo.AllSubscriptions(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.SubscriptionPage{Subscriptions: subscriptions}
+	if len(subscriptions) > int(pageSize) {
+		page.Subscriptions = subscriptions[:pageSize]
+		next := model.CursorOf(page.Subscriptions[pageSize-1])
+		page.Next = &next
+	}
+
+	if query.WithTotalSize {
+		totalSize, err := s.repo.CountSubscriptions(ctx, query.Filter)
+		if err != nil {
+			return nil, err
+		}
+		page.TotalSize = &totalSize
+	}
+
+	return page, nil
+}
+
+// UpdateSubscription изменяет подписку. Инварианты проверяются для подписки после изменения, поэтому, например,
+// новая дата начала сравнивается с сохраненной датой окончания. Если меняются пользователь, наименование
+// или период, пересечения проверяются так же, как при создании
+func (s *SubscriptionService) UpdateSubscription(ctx context.Context, id uuid.UUID, update model.SubscriptionUpdate) (*model.Subscription, []model.Subscription, error) {
+	current, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	// версия проверяется до валидации, чтобы клиент с устаревшими данными получил ErrVersionMismatch,
+	// а не ошибку, вызванную чужим изменением. Окончательно ее проверяет репозиторий при записи
+	if update.ExpectedVersion != 0 && current.Version != update.ExpectedVersion {
+		return nil, nil, model.ErrVersionMismatch
+	}
+	if update.UserID != nil {
+		// передать подписку другому пользователю может только администратор
+		if _, err = scopeUserID(ctx, *update.UserID); err != nil {
+			return nil, nil, err
+		}
+	}
+	updated := current.Apply(update)
+	if err := validateSubscription(updated); err != nil {
+		return nil, nil, err
+	}
+	// валюта и периодичность хранятся только в подписке, а не в тарифах, поэтому их изменение переписало бы
+	// списания за все прошлые месяцы, а смена валюты - еще и прочитала бы суммы в минимальных единицах другой валюты
+	if updated.Currency != current.Currency || updated.BillingPeriod != current.BillingPeriod {
+		return nil, nil, model.ErrSubscriptionTermsFixed
+	}
+	// у отмененной и истекшей подписки дата окончания определяет статус, поэтому меняется только через
+	// переходы статусов: иначе бессрочная подписка осталась бы в статусе cancelled или expired
+	closed := current.Status == model.SubscriptionStatusCancelled || current.Status == model.SubscriptionStatusExpired
+	if closed && updated.EndDate != current.EndDate {
+		return nil, nil, model.ErrSubscriptionClosed
+	}
+
+	if update.Price != nil && (update.PriceInMajorUnits || update.PriceCurrency != "") {
+		if update.PriceCurrency != "" && update.PriceCurrency != updated.Currency {
+			return nil, nil, apperror.NewValidationError("price.currency", "must match subscription currency "+updated.Currency)
+		}
+
+		if update.PriceInMajorUnits {
+			price := model.ToMinorUnits(*update.Price, updated.Currency)
+			update.Price = &price
+			update.PriceInMajorUnits = false
+		}
+		if err := validatePrice("price", *update.Price, updated.Currency); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if (update.Price != nil && *update.Price != current.Price) ||
+		(update.ServiceName != nil && *update.ServiceName != current.ServiceName) {
+		// цена и наименование меняются новым тарифом с текущего месяца, чтобы не переписать прошлые списания
+		update.PlanFrom = model.YearMonthOf(s.now())
+		if !updated.EndDate.IsZero() && updated.EndDate.Before(update.PlanFrom) {
+			return nil, nil, model.ErrPlanOutsideSubscription
+		}
+	}
+
+	var overlaps []model.Subscription
+	if update.ChangesPeriodOrOwner() {
+		overlaps, err = s.checkOverlaps(ctx, updated, id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		overlapAllowed := s.overlapAllowed(overlaps)
+		update.OverlapAllowed = &overlapAllowed
+	}
+
+	result, err := s.repo.UpdateSubscription(ctx, id, update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, overlaps, nil
 }
 
-func (s *SubscriptionService) ListSubscriptions(ctx context.Context, pagination model.Pagination) ([]model.Subscription, error) {
-	return s.repo.AllSubscriptions(ctx, pagination)
+// DeleteSubscription помечает подписку удаленной: она перестает учитываться в выборках и суммах,
+// но до окончательного удаления может быть восстановлена UndeleteSubscription. Если expectedVersion не равен нулю,
+// подписка удаляется, только пока ее версия не изменилась
+func (s *SubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSubscription(ctx, id, expectedVersion)
 }
 
-func (s *SubscriptionService) UpdateSubscription(ctx context.Context, id uuid.UUID, subscription model.Subscription) (*model.Subscription, error) {
-	return s.repo.UpdateSubscription(ctx, id, subscription)
+// UndeleteSubscription восстанавливает удаленную подписку. Если за время удаления была создана пересекающаяся
+// с ней подписка, восстановление завершается ошибкой model.ErrSubscriptionAlreadyExists
+func (s *SubscriptionService) UndeleteSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	if err := s.authorizeSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.UndeleteSubscription(ctx, id)
 }
 
-func (s *SubscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	return s.repo.DeleteSubscription(ctx, id)
+// PurgeDeletedSubscriptions окончательно удаляет подписки всех арендаторов, удаленные раньше, чем retention назад.
+// Для арендаторов со своим сроком хранения действует их срок
+func (s *SubscriptionService) PurgeDeletedSubscriptions(ctx context.Context, retention time.Duration) (int64, error) {
+	now := s.now()
+
+	tenantBefore := make(map[string]time.Time)
+	for tenantID, settings := range s.tenants {
+		if settings.DeletionRetention > 0 {
+			tenantBefore[tenantID] = now.Add(-settings.DeletionRetention)
+		}
+	}
+
+	return s.repo.PurgeDeletedSubscriptions(ctx, now.Add(-retention), tenantBefore)
 }
 
-func (s *SubscriptionService) GetTotalSum(ctx context.Context, filters model.Filters) (int32, error) {
-	return s.repo.GetSumSubscriptions(ctx, filters)
+// ChangeSubscriptionPlan меняет цену (и, при необходимости, наименование) подписки начиная с месяца plan.ValidFrom,
+// не затрагивая суммы за предыдущие месяцы
+func (s *SubscriptionService) ChangeSubscriptionPlan(ctx context.Context, plan model.SubscriptionPlan) (*model.Subscription, error) {
+	subscription, err := s.getSubscription(ctx, plan.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.ValidFrom.Before(subscription.StartDate) ||
+		(!subscription.EndDate.IsZero() && plan.ValidFrom.After(subscription.EndDate)) {
+		return nil, model.ErrPlanOutsideSubscription
+	}
+
+	if plan.ServiceName == "" {
+		plan.ServiceName = subscription.ServiceName
+	}
+	if plan.Currency != "" && plan.Currency != subscription.Currency {
+		return nil, apperror.NewValidationError("price.currency", "must match subscription currency "+subscription.Currency)
+	}
+	plan.Currency = subscription.Currency
+	if plan.PriceInMajorUnits {
+		plan.Price = model.ToMinorUnits(plan.Price, subscription.Currency)
+		plan.PriceInMajorUnits = false
+	}
+	if err := validatePrice("price", plan.Price, plan.Currency); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ChangeSubscriptionPlan(ctx, plan)
+}
+
+func (s *SubscriptionService) ListSubscriptionPlans(ctx context.Context, id uuid.UUID) ([]model.SubscriptionPlan, error) {
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.repo.ListSubscriptionPlans(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range plans {
+		plans[i].Currency = subscription.Currency
+		if i+1 < len(plans) {
+			plans[i].ValidTo = plans[i+1].ValidFrom.AddMonths(-1)
+		} else {
+			plans[i].ValidTo = subscription.EndDate
+		}
+	}
+
+	return plans, nil
+}
+
+func (s *SubscriptionService) GetTotalSum(ctx context.Context, filters model.Filters) (*model.TotalSum, error) {
+	if err := validateFilters(filters); err != nil {
+		return nil, err
+	}
+	if filters.Currency == "" {
+		filters.Currency = s.defaultCurrency(ctx)
+	}
+
+	months, rates, err := s.monthlySpend(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var sum int64
+	for _, month := range months {
+		sum += month.amount
+	}
+
+	return &model.TotalSum{
+		Sum:      sum,
+		Currency: filters.Currency,
+		Rates:    rates.usedRates(),
+	}, nil
+}
+
+// monthSpend - расходы за один месяц в целевой валюте и подписки, активные в этом месяце
+type monthSpend struct {
+	month         model.YearMonth
+	amount        int64
+	subscriptions map[uuid.UUID]struct{}
+}
+
+// monthlySpend загружает списания за период и переводит их в валюту filters.Currency помесячно.
+// Все отчеты строятся из помесячных сумм, поэтому их итоги совпадают с GetTotalSum
+func (s *SubscriptionService) monthlySpend(ctx context.Context, filters model.Filters) ([]monthSpend, *rateTable, error) {
+	charges, err := s.listMonthlyCharges(ctx, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rates, err := s.loadRates(ctx, filters, charges)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	months := groupChargesByMonth(charges)
+	result := make([]monthSpend, 0, len(months))
+	for _, month := range months {
+		amount, err := convertMonth(rates, month, filters.Currency)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result = append(result, monthSpend{
+			month:         month.month,
+			amount:        amount,
+			subscriptions: month.subscriptions,
+		})
+	}
+
+	return result, rates, nil
+}
+
+// loadRates загружает курсы всех валют, участвующих в расчёте, за запрошенный период
+func (s *SubscriptionService) loadRates(ctx context.Context, filters model.Filters, charges []model.MonthlyCharge) (*rateTable, error) {
+	currencies := map[string]struct{}{filters.Currency: {}}
+	for _, charge := range charges {
+		currencies[charge.Currency] = struct{}{}
+	}
+	delete(currencies, s.baseCurrency)
+
+	if len(currencies) == 0 {
+		return newRateTable(s.baseCurrency, nil), nil
+	}
+
+	codes := make([]string, 0, len(currencies))
+	for currency := range currencies {
+		codes = append(codes, currency)
+	}
+	sort.Strings(codes)
+
+	rates, err := s.rates.ListExchangeRates(ctx, codes, filters.StartDate.Time(), filters.EndDate.Time())
+	if err != nil {
+		return nil, err
+	}
+
+	return newRateTable(s.baseCurrency, rates), nil
+}
+
+// monthCharges - суммы списаний за один месяц в разрезе валют
+type monthCharges struct {
+	month         model.YearMonth
+	amounts       map[string]int64
+	subscriptions map[uuid.UUID]struct{}
+	charges       []model.MonthlyCharge
+}
+
+func groupChargesByMonth(charges []model.MonthlyCharge) []monthCharges {
+	var months []monthCharges
+	for _, charge := range charges {
+		if len(months) == 0 || months[len(months)-1].month != charge.Month {
+			months = append(months, monthCharges{
+				month:         charge.Month,
+				amounts:       make(map[string]int64),
+				subscriptions: make(map[uuid.UUID]struct{}),
+			})
+		}
+
+		months[len(months)-1].amounts[charge.Currency] += charge.Amount()
+		months[len(months)-1].subscriptions[charge.SubscriptionID] = struct{}{}
+		months[len(months)-1].charges = append(months[len(months)-1].charges, charge)
+	}
+
+	return months
+}
+
+// convertMonth переводит списания за месяц в целевую валюту по курсу этого месяца.
+// Округление выполняется один раз на месяц, чтобы помесячные суммы сходились с итоговой
+func convertMonth(rates *rateTable, month monthCharges, currency string) (int64, error) {
+	total := new(big.Rat)
+	for chargeCurrency, amount := range month.amounts {
+		// месяцы без списаний (например, между оплатами годовой подписки) не требуют курса
+		if amount == 0 {
+			continue
+		}
+
+		converted, err := rates.convert(amount, chargeCurrency, currency, month.month)
+		if err != nil {
+			return 0, err
+		}
+
+		total.Add(total, converted)
+	}
+
+	return roundRat(total), nil
 }
@@ -0,0 +1,1070 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSubscriptionRepository struct {
+	SubscriptionRepository
+	charges       []model.MonthlyCharge
+	subscriptions []model.Subscription
+	pauses        []model.SubscriptionPause
+	trialFilter   model.TrialFilter
+	purgedBefore  time.Time
+	tenantBefore  map[string]time.Time
+	history       []model.AuditRecord
+	query         model.SubscriptionQuery
+	chargeFilters model.Filters
+	update        model.SubscriptionUpdate
+}
+
+func (r *stubSubscriptionRepository) CreateSubscription(_ context.Context, subscription model.Subscription) (*model.Subscription, error) {
+	return &subscription, nil
+}
+
+func (r *stubSubscriptionRepository) GetSubscriptionById(_ context.Context, id uuid.UUID) (*model.Subscription, error) {
+	for _, subscription := range r.subscriptions {
+		if subscription.ID == id {
+			return &subscription, nil
+		}
+	}
+
+	return nil, model.ErrSubscriptionNotFound
+}
+
+func (r *stubSubscriptionRepository) UpdateSubscription(_ context.Context, id uuid.UUID, update model.SubscriptionUpdate) (*model.Subscription, error) {
+	r.update = update
+
+	subscription, err := r.GetSubscriptionById(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := subscription.Apply(update)
+	return &updated, nil
+}
+
+func (r *stubSubscriptionRepository) FindOverlappingSubscriptions(_ context.Context, subscription model.Subscription, excludeID uuid.UUID) ([]model.Subscription, error) {
+	var overlaps []model.Subscription
+	for _, existing := range r.subscriptions {
+		if existing.ID != excludeID && existing.Overlaps(subscription) {
+			overlaps = append(overlaps, existing)
+		}
+	}
+
+	return overlaps, nil
+}
+
+func (r *stubSubscriptionRepository) MergeSubscriptions(_ context.Context, id uuid.UUID, _ []uuid.UUID, startDate, endDate model.YearMonth) (*model.Subscription, error) {
+	subscription, err := r.GetSubscriptionById(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.StartDate, subscription.EndDate = startDate, endDate
+	return subscription, nil
+}
+
+func (r *stubSubscriptionRepository) ChangeSubscriptionPlan(_ context.Context, plan model.SubscriptionPlan) (*model.Subscription, error) {
+	subscription, err := r.GetSubscriptionById(context.Background(), plan.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Price = plan.Price
+	subscription.ServiceName = plan.ServiceName
+
+	return subscription, nil
+}
+
+func (r *stubSubscriptionRepository) GetSubscriptionOwner(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	subscription, err := r.GetSubscriptionById(ctx, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return subscription.UserID, nil
+}
+
+func (r *stubSubscriptionRepository) AllSubscriptions(_ context.Context, query model.SubscriptionQuery) ([]model.Subscription, error) {
+	r.query = query
+	return r.subscriptions[:min(len(r.subscriptions), int(query.Pagination.Count))], nil
+}
+
+func (r *stubSubscriptionRepository) CountSubscriptions(_ context.Context, _ model.SubscriptionFilter) (int64, error) {
+	return int64(len(r.subscriptions)), nil
+}
+
+func (r *stubSubscriptionRepository) ListMonthlyCharges(_ context.Context, filters model.Filters) ([]model.MonthlyCharge, error) {
+	r.chargeFilters = filters
+	return r.charges, nil
+}
+
+// setStatus меняет сохраненную подписку, чтобы следующие запросы видели новый статус
+func (r *stubSubscriptionRepository) setStatus(id uuid.UUID, status model.SubscriptionStatus, endDate model.YearMonth) (*model.Subscription, error) {
+	for i := range r.subscriptions {
+		if r.subscriptions[i].ID == id {
+			r.subscriptions[i].Status = status
+			if !endDate.IsZero() {
+				r.subscriptions[i].EndDate = endDate
+			}
+
+			subscription := r.subscriptions[i]
+			return &subscription, nil
+		}
+	}
+
+	return nil, model.ErrSubscriptionNotFound
+}
+
+func (r *stubSubscriptionRepository) CancelSubscription(_ context.Context, id uuid.UUID, _ model.SubscriptionStatus, endDate model.YearMonth) (*model.Subscription, error) {
+	return r.setStatus(id, model.SubscriptionStatusCancelled, endDate)
+}
+
+func (r *stubSubscriptionRepository) PauseSubscription(_ context.Context, id uuid.UUID, _ model.SubscriptionStatus, startDate model.YearMonth) (*model.Subscription, error) {
+	r.pauses = append(r.pauses, model.SubscriptionPause{SubscriptionID: id, StartDate: startDate})
+	return r.setStatus(id, model.SubscriptionStatusPaused, model.YearMonth{})
+}
+
+func (r *stubSubscriptionRepository) ResumeSubscription(_ context.Context, id uuid.UUID, startDate model.YearMonth) (*model.Subscription, error) {
+	r.pauses[len(r.pauses)-1].EndDate = startDate.AddMonths(-1)
+	return r.setStatus(id, model.SubscriptionStatusActive, model.YearMonth{})
+}
+
+func (r *stubSubscriptionRepository) ListSubscriptionPauses(_ context.Context, _ uuid.UUID) ([]model.SubscriptionPause, error) {
+	return r.pauses, nil
+}
+
+func (r *stubSubscriptionRepository) ListEndingTrials(_ context.Context, filter model.TrialFilter) ([]model.Subscription, error) {
+	r.trialFilter = filter
+	return r.subscriptions, nil
+}
+
+func (r *stubSubscriptionRepository) ListSubscriptionHistory(_ context.Context, id uuid.UUID) ([]model.AuditRecord, error) {
+	var records []model.AuditRecord
+	for _, record := range r.history {
+		if record.SubscriptionID == id {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func (r *stubSubscriptionRepository) PurgeDeletedSubscriptions(_ context.Context, before time.Time, tenantBefore map[string]time.Time) (int64, error) {
+	r.purgedBefore = before
+	r.tenantBefore = tenantBefore
+	return 0, nil
+}
+
+type stubExchangeRateRepository struct {
+	ExchangeRateRepository
+	rates []model.ExchangeRate
+}
+
+func (r *stubExchangeRateRepository) ListExchangeRates(_ context.Context, _ []string, _, _ time.Time) ([]model.ExchangeRate, error) {
+	return r.rates, nil
+}
+
+func month(year int, month time.Month) model.YearMonth {
+	return model.NewYearMonth(year, month)
+}
+
+func TestSubscriptionService_GetTotalSum(t *testing.T) {
+	rates := []model.ExchangeRate{
+		{Currency: "USD", ValidFrom: month(2025, 1).Time(), Rate: big.NewRat(100, 1)},
+		{Currency: "USD", ValidFrom: month(2025, 3).Time(), Rate: big.NewRat(80, 1)},
+		{Currency: "EUR", ValidFrom: month(2025, 1).Time(), Rate: big.NewRat(110, 1)},
+		{Currency: "JPY", ValidFrom: month(2025, 1).Time(), Rate: big.NewRat(3, 5)},
+	}
+
+	testCases := []struct {
+		name          string
+		charges       []model.MonthlyCharge
+		currency      string
+		expected      int64
+		expectedRates int
+		expectedErr   error
+	}{
+		{
+			name: "Все списания в базовой валюте",
+			charges: []model.MonthlyCharge{
+				{Month: month(2025, 1), Price: 400, Currency: "RUB", Charges: 1},
+				{Month: month(2025, 2), Price: 400, Currency: "RUB", Charges: 1},
+			},
+			expected: 800,
+		},
+		{
+			name: "Курс меняется внутри периода",
+			charges: []model.MonthlyCharge{
+				{Month: month(2025, 2), Price: 10, Currency: "USD", Charges: 1},
+				{Month: month(2025, 3), Price: 10, Currency: "USD", Charges: 1},
+			},
+			expected:      10*100 + 10*80,
+			expectedRates: 2,
+		},
+		{
+			name: "Перевод из рублей в другую валюту",
+			charges: []model.MonthlyCharge{
+				{Month: month(2025, 3), Price: 400, Currency: "RUB", Charges: 1},
+				{Month: month(2025, 3), Price: 5, Currency: "USD", Charges: 1},
+			},
+			currency:      "USD",
+			expected:      10,
+			expectedRates: 1,
+		},
+		{
+			name: "Кросс-курс через базовую валюту",
+			charges: []model.MonthlyCharge{
+				{Month: month(2025, 1), Price: 11, Currency: "EUR", Charges: 1},
+			},
+			currency:      "USD",
+			expected:      12,
+			expectedRates: 2,
+		},
+		{
+			name: "Валюта без дробной части",
+			charges: []model.MonthlyCharge{
+				{Month: month(2025, 1), Price: 1000, Currency: "JPY", Charges: 1},
+			},
+			expected:      60000,
+			expectedRates: 1,
+		},
+		{
+			name: "Перевод в валюту без дробной части с округлением",
+			charges: []model.MonthlyCharge{
+				{Month: month(2025, 1), Price: 40050, Currency: "RUB", Charges: 1},
+			},
+			currency:      "JPY",
+			expected:      668,
+			expectedRates: 1,
+		},
+		{
+			name: "Нет курса на месяц списания",
+			charges: []model.MonthlyCharge{
+				{Month: month(2024, 12), Price: 10, Currency: "USD", Charges: 1},
+			},
+			expectedErr: model.ErrExchangeRateNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewSubscriptionService(
+				&stubSubscriptionRepository{charges: tc.charges},
+				&stubExchangeRateRepository{rates: rates},
+				"RUB",
+				nil,
+				model.OverlapPolicyReject,
+			)
+
+			sum, err := svc.GetTotalSum(context.Background(), model.Filters{
+				StartDate: month(2024, 1),
+				EndDate:   month(2025, 12),
+				Currency:  tc.currency,
+			})
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, sum.Sum)
+			assert.Len(t, sum.Rates, tc.expectedRates)
+		})
+	}
+}
+
+func TestSubscriptionService_ListSubscriptions(t *testing.T) {
+	subscriptions := make([]model.Subscription, 5)
+	for i := range subscriptions {
+		subscriptions[i] = model.Subscription{ID: uuid.New(), Price: int64(i * 100), StartDate: month(2025, time.Month(i+1))}
+	}
+
+	svc := NewSubscriptionService(&stubSubscriptionRepository{subscriptions: subscriptions}, &stubExchangeRateRepository{}, "RUB", nil, model.OverlapPolicyReject)
+
+	page, err := svc.ListSubscriptions(context.Background(), model.SubscriptionQuery{
+		Pagination:    model.Pagination{Count: 3},
+		WithTotalSize: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, page.Subscriptions, 3)
+	if assert.NotNil(t, page.Next) {
+		assert.Equal(t, model.CursorOf(subscriptions[2]), *page.Next)
+	}
+	if assert.NotNil(t, page.TotalSize) {
+		assert.Equal(t, int64(5), *page.TotalSize)
+	}
+
+	page, err = svc.ListSubscriptions(context.Background(), model.SubscriptionQuery{
+		Pagination: model.Pagination{Count: 5},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, page.Subscriptions, 5)
+	assert.Nil(t, page.Next)
+	assert.Nil(t, page.TotalSize)
+}
+
+func TestSubscriptionService_GetSpendTimeSeries(t *testing.T) {
+	rates := []model.ExchangeRate{
+		{Currency: "USD", ValidFrom: month(2025, 1).Time(), Rate: big.NewRat(1001, 10)},
+	}
+	first, second := uuid.New(), uuid.New()
+	charges := []model.MonthlyCharge{
+		{SubscriptionID: first, Month: month(2025, 1), Price: 400, Currency: "RUB", Charges: 1},
+		{SubscriptionID: second, Month: month(2025, 1), Price: 3, Currency: "USD", Charges: 1},
+		{SubscriptionID: first, Month: month(2025, 2), Price: 400, Currency: "RUB", Charges: 1},
+		{SubscriptionID: second, Month: month(2025, 2), Price: 3, Currency: "USD", Charges: 0},
+		{SubscriptionID: second, Month: month(2025, 4), Price: 3, Currency: "USD", Charges: 1},
+	}
+
+	svc := NewSubscriptionService(
+		&stubSubscriptionRepository{charges: charges},
+		&stubExchangeRateRepository{rates: rates},
+		"RUB",
+		nil,
+		model.OverlapPolicyReject,
+	)
+	filters := model.Filters{StartDate: month(2025, 1), EndDate: month(2025, 5), Currency: "USD"}
+
+	series, err := svc.GetSpendTimeSeries(context.Background(), filters, model.GranularityQuarter)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SpendBucket{
+		{StartDate: month(2025, 1), EndDate: month(2025, 3), Amount: 4 + 3 + 4, ActiveSubscriptions: 2},
+		{StartDate: month(2025, 4), EndDate: month(2025, 5), Amount: 3, ActiveSubscriptions: 1},
+	}, series.Buckets)
+
+	sum, err := svc.GetTotalSum(context.Background(), filters)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(sum.Sum), series.Total)
+
+	monthly, err := svc.GetSpendTimeSeries(context.Background(), filters, model.GranularityMonth)
+	assert.NoError(t, err)
+	assert.Len(t, monthly.Buckets, 5)
+	assert.Equal(t, series.Total, monthly.Total)
+}
+
+func TestSubscriptionService_GetSpendBreakdown(t *testing.T) {
+	rates := []model.ExchangeRate{
+		{Currency: "USD", ValidFrom: month(2025, 1).Time(), Rate: big.NewRat(3, 1)},
+	}
+	yandex, netflix, spotify := uuid.New(), uuid.New(), uuid.New()
+	charges := []model.MonthlyCharge{
+		{SubscriptionID: yandex, ServiceName: "Yandex Plus", Month: month(2025, 1), Price: 10, Currency: "RUB", Charges: 1},
+		{SubscriptionID: netflix, ServiceName: "Netflix", Month: month(2025, 1), Price: 10, Currency: "RUB", Charges: 1},
+		{SubscriptionID: spotify, ServiceName: "Spotify", Month: month(2025, 1), Price: 10, Currency: "RUB", Charges: 1},
+		{SubscriptionID: yandex, ServiceName: "Yandex Plus", Month: month(2025, 2), Price: 10, Currency: "RUB", Charges: 1},
+	}
+
+	svc := NewSubscriptionService(
+		&stubSubscriptionRepository{charges: charges},
+		&stubExchangeRateRepository{rates: rates},
+		"RUB",
+		nil,
+		model.OverlapPolicyReject,
+	)
+	filters := model.Filters{StartDate: month(2025, 1), EndDate: month(2025, 2), Currency: "USD"}
+
+	breakdown, err := svc.GetSpendBreakdown(context.Background(), filters, model.BreakdownByServiceName, 0)
+	assert.NoError(t, err)
+
+	sum, err := svc.GetTotalSum(context.Background(), filters)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(sum.Sum), breakdown.Total)
+
+	var rowsTotal int64
+	for _, row := range breakdown.Rows {
+		rowsTotal += row.Amount
+	}
+	assert.Equal(t, breakdown.Total, rowsTotal)
+	assert.Equal(t, "Yandex Plus", breakdown.Rows[0].Key)
+
+	top, err := svc.GetSpendBreakdown(context.Background(), filters, model.BreakdownByServiceName, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, top.Rows, 2) {
+		assert.Equal(t, "Yandex Plus", top.Rows[0].Key)
+		assert.True(t, top.Rows[1].Other)
+		assert.Equal(t, int32(2), top.Rows[1].Subscriptions)
+		assert.Equal(t, breakdown.Total, top.Rows[0].Amount+top.Rows[1].Amount)
+		assert.InDelta(t, 1, top.Rows[0].Share+top.Rows[1].Share, 1e-9)
+	}
+}
+
+func TestSubscriptionService_MinorUnits(t *testing.T) {
+	existing := model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Netflix",
+		Price:       1000,
+		Currency:    "JPY",
+		StartDate:   month(2025, 1),
+	}
+	svc := NewSubscriptionService(&stubSubscriptionRepository{subscriptions: []model.Subscription{existing}}, nil, "RUB", nil, model.OverlapPolicyReject)
+
+	t.Run("Цена API v1 в рублях переводится в копейки", func(t *testing.T) {
+		created, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+			ServiceName:       "Yandex Plus",
+			Price:             400,
+			StartDate:         month(2025, 1),
+			PriceInMajorUnits: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(40000), created.Price)
+		assert.Equal(t, "RUB", created.Currency)
+	})
+
+	t.Run("Льготные цены API v1 переводятся в копейки", func(t *testing.T) {
+		phases := []model.PromoPhase{{Months: 2, Price: 200}}
+		created, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+			ServiceName:       "Yandex Plus",
+			Price:             400,
+			StartDate:         month(2025, 1),
+			PromoPhases:       phases,
+			PriceInMajorUnits: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.PromoPhase{{Months: 2, Price: 20000}}, created.PromoPhases)
+		assert.Equal(t, int64(200), phases[0].Price)
+	})
+
+	t.Run("Цена в целых единицах должна помещаться в int32", func(t *testing.T) {
+		var validationErr *apperror.ValidationError
+		_, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+			ServiceName: "Yandex Plus",
+			Price:       model.MaxPrice("RUB") + 1,
+			Currency:    "RUB",
+			StartDate:   month(2025, 1),
+		})
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "price", validationErr.Violations[0].Field)
+
+		_, _, err = svc.AddSubscription(context.Background(), model.Subscription{
+			ServiceName: "Yandex Plus",
+			Price:       40000,
+			Currency:    "RUB",
+			StartDate:   month(2025, 1),
+			PromoPhases: []model.PromoPhase{{Months: 1, Price: model.MaxPrice("RUB") + 1}},
+		})
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "promo_phases[0].price", validationErr.Violations[0].Field)
+
+		price := model.MaxPrice("JPY") + 1
+		_, _, err = svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			Price:         &price,
+			PriceCurrency: "JPY",
+		})
+		assert.ErrorAs(t, err, &validationErr)
+
+		_, err = svc.ChangeSubscriptionPlan(context.Background(), model.SubscriptionPlan{
+			SubscriptionID: existing.ID,
+			ValidFrom:      month(2025, 3),
+			Price:          model.MaxPrice("JPY") + 1,
+		})
+		assert.ErrorAs(t, err, &validationErr)
+
+		created, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+			ServiceName: "Yandex Plus",
+			Price:       model.MaxPrice("RUB"),
+			Currency:    "RUB",
+			StartDate:   month(2025, 1),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(math.MaxInt32), model.ToMajorUnits(created.Price, created.Currency))
+	})
+
+	t.Run("Цена API v1 в валюте без дробной части не меняется", func(t *testing.T) {
+		price := int64(1500)
+		updated, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			Price:             &price,
+			PriceInMajorUnits: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1500), updated.Price)
+	})
+
+	t.Run("Валюта и периодичность подписки не меняются", func(t *testing.T) {
+		price, currency := int64(15), "USD"
+		_, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			Price:             &price,
+			Currency:          &currency,
+			PriceInMajorUnits: true,
+		})
+		assert.ErrorIs(t, err, model.ErrSubscriptionTermsFixed)
+
+		yearly := model.BillingPeriodYearly
+		_, _, err = svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{BillingPeriod: &yearly})
+		assert.ErrorIs(t, err, model.ErrSubscriptionTermsFixed)
+
+		// то же значение, например при update_mask "*", изменением не считается
+		currency = existing.Currency
+		_, _, err = svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{Currency: &currency})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Валюта цены не совпадает с валютой подписки", func(t *testing.T) {
+		price := int64(1500)
+		_, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			Price:         &price,
+			PriceCurrency: "RUB",
+		})
+
+		var validationErr *apperror.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+
+		_, err = svc.ChangeSubscriptionPlan(context.Background(), model.SubscriptionPlan{
+			SubscriptionID: existing.ID,
+			ValidFrom:      month(2025, 3),
+			Price:          1500,
+			Currency:       "RUB",
+		})
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestSubscriptionService_Overlaps(t *testing.T) {
+	userID := uuid.New()
+	existing := model.Subscription{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   month(2025, 1),
+		EndDate:     month(2025, 6),
+	}
+	repo := &stubSubscriptionRepository{subscriptions: []model.Subscription{existing}}
+
+	candidate := model.Subscription{
+		UserID:      userID,
+		ServiceName: "  yandex   PLUS ",
+		Price:       40000,
+		StartDate:   month(2025, 6),
+	}
+
+	t.Run("Политика reject", func(t *testing.T) {
+		svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyReject)
+
+		_, _, err := svc.AddSubscription(context.Background(), candidate)
+		assert.ErrorIs(t, err, model.ErrSubscriptionAlreadyExists)
+
+		nonOverlapping := candidate
+		nonOverlapping.StartDate = month(2025, 7)
+		created, overlaps, err := svc.AddSubscription(context.Background(), nonOverlapping)
+		assert.NoError(t, err)
+		assert.Empty(t, overlaps)
+		assert.False(t, created.OverlapAllowed)
+	})
+
+	t.Run("Политика warn", func(t *testing.T) {
+		svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyWarn)
+
+		created, overlaps, err := svc.AddSubscription(context.Background(), candidate)
+		assert.NoError(t, err)
+		assert.True(t, created.OverlapAllowed)
+		if assert.Len(t, overlaps, 1) {
+			assert.Equal(t, existing.ID, overlaps[0].ID)
+		}
+	})
+
+	t.Run("Политика allow", func(t *testing.T) {
+		svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyAllow)
+
+		created, overlaps, err := svc.AddSubscription(context.Background(), candidate)
+		assert.NoError(t, err)
+		assert.Empty(t, overlaps)
+		assert.True(t, created.OverlapAllowed)
+	})
+
+	t.Run("Изменение периода проверяется на пересечения", func(t *testing.T) {
+		other := candidate
+		other.ID = uuid.New()
+		other.StartDate = month(2025, 8)
+		svc := NewSubscriptionService(&stubSubscriptionRepository{subscriptions: []model.Subscription{existing, other}}, nil, "RUB", nil, model.OverlapPolicyReject)
+
+		startDate := month(2025, 3)
+		_, _, err := svc.UpdateSubscription(context.Background(), other.ID, model.SubscriptionUpdate{StartDate: &startDate})
+		assert.ErrorIs(t, err, model.ErrSubscriptionAlreadyExists)
+
+		price := int64(50000)
+		_, _, err = svc.UpdateSubscription(context.Background(), other.ID, model.SubscriptionUpdate{Price: &price})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Объединение расширяет период", func(t *testing.T) {
+		other := candidate
+		other.ID = uuid.New()
+		openEnded := existing
+		openEnded.ID = uuid.New()
+		openEnded.UserID = uuid.New()
+		svc := NewSubscriptionService(&stubSubscriptionRepository{subscriptions: []model.Subscription{existing, other, openEnded}}, nil, "RUB", nil, model.OverlapPolicyReject)
+
+		merged, err := svc.MergeSubscriptions(context.Background(), existing.ID, []uuid.UUID{other.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, month(2025, 1), merged.StartDate)
+		assert.True(t, merged.EndDate.IsZero())
+
+		_, err = svc.MergeSubscriptions(context.Background(), existing.ID, []uuid.UUID{openEnded.ID})
+		assert.ErrorIs(t, err, model.ErrSubscriptionsNotMergeable)
+
+		_, err = svc.MergeSubscriptions(context.Background(), existing.ID, []uuid.UUID{existing.ID})
+		assert.ErrorIs(t, err, model.ErrSubscriptionsNotMergeable)
+	})
+}
+
+func TestSubscriptionService_Period(t *testing.T) {
+	existing := model.Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   month(2025, 1),
+		EndDate:     month(2025, 6),
+	}
+	svc := NewSubscriptionService(&stubSubscriptionRepository{subscriptions: []model.Subscription{existing}}, nil, "RUB", nil, model.OverlapPolicyReject)
+
+	t.Run("Дата окончания раньше даты начала", func(t *testing.T) {
+		_, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+			UserID:      uuid.New(),
+			ServiceName: "Netflix",
+			Price:       1000,
+			StartDate:   month(2025, 5),
+			EndDate:     month(2025, 4),
+		})
+
+		var validationErr *apperror.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, "end_date", validationErr.Violations[0].Field)
+		}
+	})
+
+	t.Run("Подписка на один месяц", func(t *testing.T) {
+		_, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+			UserID:      uuid.New(),
+			ServiceName: "Netflix",
+			Price:       1000,
+			StartDate:   month(2025, 5),
+			EndDate:     month(2025, 5),
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Новая дата начала проверяется по сохраненной дате окончания", func(t *testing.T) {
+		startDate := month(2025, 7)
+		_, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{StartDate: &startDate})
+
+		var validationErr *apperror.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+
+		_, _, err = svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			StartDate:    &startDate,
+			ClearEndDate: true,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Период отчета", func(t *testing.T) {
+		_, err := svc.GetTotalSum(context.Background(), model.Filters{StartDate: month(2025, 3), EndDate: month(2025, 2)})
+
+		var validationErr *apperror.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestSubscriptionService_Lifecycle(t *testing.T) {
+	subscription := model.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   month(2025, 1),
+		Status:      model.SubscriptionStatusActive,
+	}
+	repo := &stubSubscriptionRepository{subscriptions: []model.Subscription{subscription}}
+	svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyReject)
+	svc.now = func() time.Time { return time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC) }
+
+	_, err := svc.ResumeSubscription(context.Background(), subscription.ID, model.YearMonth{})
+	assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+
+	paused, err := svc.PauseSubscription(context.Background(), subscription.ID, model.YearMonth{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusPaused, paused.Status)
+	assert.Equal(t, month(2025, 4), repo.pauses[0].StartDate)
+
+	_, err = svc.PauseSubscription(context.Background(), subscription.ID, month(2025, 5))
+	assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+
+	var validationErr *apperror.ValidationError
+	_, err = svc.ResumeSubscription(context.Background(), subscription.ID, month(2025, 4))
+	assert.ErrorAs(t, err, &validationErr)
+
+	resumed, err := svc.ResumeSubscription(context.Background(), subscription.ID, month(2025, 6))
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusActive, resumed.Status)
+	assert.Equal(t, month(2025, 5), repo.pauses[0].EndDate)
+
+	_, err = svc.PauseSubscription(context.Background(), subscription.ID, month(2025, 5))
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = svc.CancelSubscription(context.Background(), subscription.ID, month(2024, 12))
+	assert.ErrorAs(t, err, &validationErr)
+
+	cancelled, err := svc.CancelSubscription(context.Background(), subscription.ID, month(2025, 9))
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusCancelled, cancelled.Status)
+	assert.Equal(t, month(2025, 9), cancelled.EndDate)
+
+	_, err = svc.CancelSubscription(context.Background(), subscription.ID, month(2025, 9))
+	assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+	_, err = svc.PauseSubscription(context.Background(), subscription.ID, month(2025, 8))
+	assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+
+	created, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		StartDate:   month(2024, 1),
+		EndDate:     month(2025, 3),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusExpired, created.Status)
+}
+
+func TestSubscriptionService_Trial(t *testing.T) {
+	repo := &stubSubscriptionRepository{}
+	svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyReject)
+	svc.now = func() time.Time { return time.Date(2025, time.April, 15, 12, 0, 0, 0, time.UTC) }
+
+	created, _, err := svc.AddSubscription(context.Background(), model.Subscription{
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		StartDate:   month(2025, 3),
+		TrialMonths: 2,
+		PromoPhases: []model.PromoPhase{{Months: 3, Price: 20000, Currency: "RUB"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusTrial, created.Status)
+	assert.Equal(t, month(2025, 4), created.TrialEndDate)
+	assert.Equal(t, []model.SubscriptionPlan{
+		{ValidFrom: month(2025, 3), ServiceName: "Yandex Plus", Price: 0, Phase: model.PlanPhaseTrial, Currency: "RUB"},
+		{ValidFrom: month(2025, 5), ServiceName: "Yandex Plus", Price: 20000, Phase: model.PlanPhasePromo, Currency: "RUB"},
+		{ValidFrom: month(2025, 8), ServiceName: "Yandex Plus", Price: 40000, Phase: model.PlanPhaseRegular, Currency: "RUB"},
+	}, created.InitialPlans())
+
+	created, _, err = svc.AddSubscription(context.Background(), model.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		StartDate:   month(2025, 1),
+		TrialMonths: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SubscriptionStatusActive, created.Status)
+
+	var validationErr *apperror.ValidationError
+	_, _, err = svc.AddSubscription(context.Background(), model.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		StartDate:   month(2025, 1),
+		EndDate:     month(2025, 2),
+		TrialMonths: 3,
+	})
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "trial_months", validationErr.Violations[0].Field)
+
+	_, _, err = svc.AddSubscription(context.Background(), model.Subscription{
+		ServiceName: "Netflix",
+		Price:       1000,
+		StartDate:   month(2025, 1),
+		PromoPhases: []model.PromoPhase{{Months: 1, Price: 500, Currency: "USD"}},
+	})
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "promo_phases[0].price.currency", validationErr.Violations[0].Field)
+
+	repo.subscriptions = []model.Subscription{{ID: uuid.New(), Status: model.SubscriptionStatusTrial, TrialEndDate: month(2025, 4)}}
+	trials, err := svc.ListEndingTrials(context.Background(), 15, uuid.Nil)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TrialFilter{From: month(2025, 4), To: month(2025, 4)}, repo.trialFilter)
+	assert.Len(t, trials, 1)
+	assert.Equal(t, time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC), trials[0].EndsOn)
+	assert.Equal(t, int32(15), trials[0].DaysLeft)
+
+	trials, err = svc.ListEndingTrials(context.Background(), 7, uuid.Nil)
+	assert.NoError(t, err)
+	assert.Empty(t, trials)
+}
+
+func TestSubscriptionService_PurgeDeletedSubscriptions(t *testing.T) {
+	repo := &stubSubscriptionRepository{}
+	svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyReject)
+	svc.now = func() time.Time { return time.Date(2025, time.April, 15, 12, 0, 0, 0, time.UTC) }
+
+	_, err := svc.PurgeDeletedSubscriptions(context.Background(), 30*24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.March, 16, 12, 0, 0, 0, time.UTC), repo.purgedBefore)
+}
+
+func TestSubscriptionService_TenantSettings(t *testing.T) {
+	repo := &stubSubscriptionRepository{}
+	svc := NewSubscriptionService(repo, &stubExchangeRateRepository{}, "RUB", map[string]TenantSettings{
+		"acme":   {DefaultCurrency: "USD", DeletionRetention: 7 * 24 * time.Hour},
+		"globex": {DefaultCurrency: "EUR"},
+	}, model.OverlapPolicyAllow)
+	svc.now = func() time.Time { return time.Date(2025, time.April, 15, 12, 0, 0, 0, time.UTC) }
+
+	t.Run("Валюта по умолчанию арендатора", func(t *testing.T) {
+		created, _, err := svc.AddSubscription(tenant.With(context.Background(), "acme"), model.Subscription{
+			ServiceName: "Yandex Plus",
+			Price:       400,
+			StartDate:   month(2025, 1),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", created.Currency)
+
+		created, _, err = svc.AddSubscription(tenant.With(context.Background(), "initech"), model.Subscription{
+			ServiceName: "Yandex Plus",
+			Price:       400,
+			StartDate:   month(2025, 1),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "RUB", created.Currency)
+	})
+
+	t.Run("Сумма в валюте арендатора", func(t *testing.T) {
+		sum, err := svc.GetTotalSum(tenant.With(context.Background(), "globex"), model.Filters{
+			StartDate: month(2025, 1),
+			EndDate:   month(2025, 1),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "EUR", sum.Currency)
+	})
+
+	t.Run("Срок хранения удаленных подписок арендатора", func(t *testing.T) {
+		_, err := svc.PurgeDeletedSubscriptions(context.Background(), 30*24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, time.March, 16, 12, 0, 0, 0, time.UTC), repo.purgedBefore)
+		assert.Equal(t, map[string]time.Time{"acme": time.Date(2025, time.April, 8, 12, 0, 0, 0, time.UTC)}, repo.tenantBefore)
+	})
+}
+
+func TestSubscriptionService_ListSubscriptionHistory(t *testing.T) {
+	subscriptionID, purgedID, legacyID := uuid.New(), uuid.New(), uuid.New()
+	repo := &stubSubscriptionRepository{
+		subscriptions: []model.Subscription{{ID: legacyID}},
+		history: []model.AuditRecord{
+			{ID: 1, SubscriptionID: subscriptionID, Action: model.AuditActionCreate},
+			{ID: 2, SubscriptionID: purgedID, Action: model.AuditActionPurge},
+			{ID: 3, SubscriptionID: subscriptionID, Action: model.AuditActionUpdate},
+		},
+	}
+	svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyReject)
+
+	t.Run("history", func(t *testing.T) {
+		records, err := svc.ListSubscriptionHistory(context.Background(), subscriptionID)
+		assert.NoError(t, err)
+		if assert.Len(t, records, 2) {
+			assert.Equal(t, model.AuditActionCreate, records[0].Action)
+			assert.Equal(t, model.AuditActionUpdate, records[1].Action)
+		}
+	})
+
+	t.Run("purged subscription", func(t *testing.T) {
+		records, err := svc.ListSubscriptionHistory(context.Background(), purgedID)
+		assert.NoError(t, err)
+		assert.Len(t, records, 1)
+	})
+
+	t.Run("subscription created before audit log", func(t *testing.T) {
+		records, err := svc.ListSubscriptionHistory(context.Background(), legacyID)
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := svc.ListSubscriptionHistory(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+	})
+}
+
+func TestSubscriptionService_UpdateSubscriptionVersion(t *testing.T) {
+	existing := model.Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   month(2025, 1),
+		Version:     3,
+	}
+	svc := NewSubscriptionService(&stubSubscriptionRepository{subscriptions: []model.Subscription{existing}}, nil, "RUB", nil, model.OverlapPolicyReject)
+
+	t.Run("Версия совпадает", func(t *testing.T) {
+		price := int64(50000)
+		updated, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			Price:           &price,
+			ExpectedVersion: 3,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, price, updated.Price)
+	})
+
+	t.Run("Устаревшая версия проверяется раньше валидации", func(t *testing.T) {
+		endDate := month(2024, 1)
+		_, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			EndDate:         &endDate,
+			ExpectedVersion: 2,
+		})
+		assert.ErrorIs(t, err, model.ErrVersionMismatch)
+	})
+}
+
+func TestSubscriptionService_UpdateSubscriptionPlan(t *testing.T) {
+	existing := model.Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   month(2025, 1),
+	}
+	ended := model.Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Kinopoisk",
+		Price:       30000,
+		Currency:    "RUB",
+		StartDate:   month(2025, 1),
+		EndDate:     month(2025, 3),
+	}
+	repo := &stubSubscriptionRepository{subscriptions: []model.Subscription{existing, ended}}
+	svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyReject)
+	svc.now = func() time.Time { return time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC) }
+
+	t.Run("Новая цена действует с текущего месяца", func(t *testing.T) {
+		price := int64(50000)
+		_, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{Price: &price})
+		assert.NoError(t, err)
+		assert.Equal(t, month(2025, 6), repo.update.PlanFrom)
+	})
+
+	t.Run("Прежние цена и наименование не меняют тариф", func(t *testing.T) {
+		price, serviceName := existing.Price, existing.ServiceName
+		_, _, err := svc.UpdateSubscription(context.Background(), existing.ID, model.SubscriptionUpdate{
+			Price:       &price,
+			ServiceName: &serviceName,
+		})
+		assert.NoError(t, err)
+		assert.True(t, repo.update.PlanFrom.IsZero())
+	})
+
+	t.Run("Закончившаяся подписка", func(t *testing.T) {
+		serviceName := "Kinopoisk HD"
+		_, _, err := svc.UpdateSubscription(context.Background(), ended.ID, model.SubscriptionUpdate{ServiceName: &serviceName})
+		assert.ErrorIs(t, err, model.ErrPlanOutsideSubscription)
+	})
+}
+
+func TestSubscriptionService_UpdateClosedSubscriptionEndDate(t *testing.T) {
+	cancelled := model.Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       40000,
+		Currency:    "RUB",
+		StartDate:   month(2025, 1),
+		EndDate:     month(2025, 6),
+		Status:      model.SubscriptionStatusCancelled,
+	}
+	expired := cancelled
+	expired.ID = uuid.New()
+	expired.ServiceName = "Kinopoisk"
+	expired.Status = model.SubscriptionStatusExpired
+	repo := &stubSubscriptionRepository{subscriptions: []model.Subscription{cancelled, expired}}
+	svc := NewSubscriptionService(repo, nil, "RUB", nil, model.OverlapPolicyReject)
+
+	for _, subscription := range []model.Subscription{cancelled, expired} {
+		t.Run(string(subscription.Status), func(t *testing.T) {
+			endDate := month(2025, 9)
+			_, _, err := svc.UpdateSubscription(context.Background(), subscription.ID, model.SubscriptionUpdate{EndDate: &endDate})
+			assert.ErrorIs(t, err, model.ErrSubscriptionClosed)
+
+			_, _, err = svc.UpdateSubscription(context.Background(), subscription.ID, model.SubscriptionUpdate{ClearEndDate: true})
+			assert.ErrorIs(t, err, model.ErrSubscriptionClosed)
+
+			sameEndDate := subscription.EndDate
+			_, _, err = svc.UpdateSubscription(context.Background(), subscription.ID, model.SubscriptionUpdate{EndDate: &sameEndDate})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSubscriptionService_UserScope(t *testing.T) {
+	aliceID, bobID := uuid.New(), uuid.New()
+	alicesSubscription := model.Subscription{ID: uuid.New(), UserID: aliceID, ServiceName: "Yandex Plus", Price: 40000, Currency: "RUB", StartDate: month(2025, 1)}
+	bobsSubscription := model.Subscription{ID: uuid.New(), UserID: bobID, ServiceName: "Kinopoisk", Price: 30000, Currency: "RUB", StartDate: month(2025, 1)}
+	repo := &stubSubscriptionRepository{subscriptions: []model.Subscription{alicesSubscription, bobsSubscription}}
+	svc := NewSubscriptionService(repo, &stubExchangeRateRepository{}, "RUB", nil, model.OverlapPolicyAllow)
+
+	alice := principal.With(context.Background(), principal.Principal{Subject: "alice", UserID: aliceID})
+	admin := principal.With(context.Background(), principal.Principal{Subject: "admin", Admin: true})
+
+	t.Run("Список ограничен подписками клиента", func(t *testing.T) {
+		_, err := svc.ListSubscriptions(alice, model.SubscriptionQuery{Pagination: model.Pagination{Count: 10}})
+		assert.NoError(t, err)
+		assert.Equal(t, aliceID, repo.query.Filter.UserID)
+
+		_, err = svc.GetTotalSum(alice, model.Filters{StartDate: month(2025, 1), EndDate: month(2025, 12)})
+		assert.NoError(t, err)
+		assert.Equal(t, aliceID, repo.chargeFilters.UserID)
+	})
+
+	t.Run("Чужие подписки недоступны", func(t *testing.T) {
+		_, err := svc.ListSubscriptions(alice, model.SubscriptionQuery{
+			Filter:     model.SubscriptionFilter{UserID: bobID},
+			Pagination: model.Pagination{Count: 10},
+		})
+		assert.ErrorIs(t, err, model.ErrPermissionDenied)
+
+		_, _, err = svc.AddSubscription(alice, model.Subscription{UserID: bobID, ServiceName: "Okko", Price: 20000, StartDate: month(2025, 1)})
+		assert.ErrorIs(t, err, model.ErrPermissionDenied)
+
+		_, err = svc.GetSubscription(alice, bobsSubscription.ID)
+		assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+
+		err = svc.DeleteSubscription(alice, bobsSubscription.ID, 0)
+		assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+
+		_, err = svc.ListSubscriptionHistory(alice, bobsSubscription.ID)
+		assert.ErrorIs(t, err, model.ErrSubscriptionNotFound)
+	})
+
+	t.Run("Подписку нельзя передать другому пользователю", func(t *testing.T) {
+		_, _, err := svc.UpdateSubscription(alice, alicesSubscription.ID, model.SubscriptionUpdate{UserID: &bobID})
+		assert.ErrorIs(t, err, model.ErrPermissionDenied)
+
+		_, _, err = svc.UpdateSubscription(admin, alicesSubscription.ID, model.SubscriptionUpdate{UserID: &bobID})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Администратору доступны подписки всех пользователей", func(t *testing.T) {
+		subscription, err := svc.GetSubscription(admin, bobsSubscription.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, bobID, subscription.UserID)
+
+		_, err = svc.ListSubscriptions(admin, model.SubscriptionQuery{Pagination: model.Pagination{Count: 10}})
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, repo.query.Filter.UserID)
+	})
+}
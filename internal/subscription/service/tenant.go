@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+)
+
+// TenantSettings - настройки арендатора, заменяющие общие. Пустое поле означает общую настройку
+type TenantSettings struct {
+	// DefaultCurrency - валюта новых подписок без валюты и расчета расходов без параметра currency
+	DefaultCurrency string
+	// DeletionRetention - срок, после которого удаленные подписки арендатора удаляются окончательно
+	DeletionRetention time.Duration
+}
+
+// defaultCurrency возвращает валюту по умолчанию арендатора запроса
+func (s *SubscriptionService) defaultCurrency(ctx context.Context) string {
+	if currency := s.tenants[tenant.From(ctx)].DefaultCurrency; currency != "" {
+		return currency
+	}
+
+	return s.baseCurrency
+}
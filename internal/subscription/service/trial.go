@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/google/uuid"
+)
+
+// ListEndingTrials возвращает подписки, пробный период которых заканчивается в ближайшие withinDays дней,
+// включая заканчивающиеся сегодня. Пробный период заканчивается последним днем своего последнего месяца
+func (s *SubscriptionService) ListEndingTrials(ctx context.Context, withinDays int, userID uuid.UUID) ([]model.EndingTrial, error) {
+	if withinDays <= 0 {
+		return nil, apperror.NewValidationError("within_days", "must be positive")
+	}
+
+	userID, err := scopeUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	until := today.AddDate(0, 0, withinDays)
+
+	// Последний день месяца попадает в окно, если до конца окна наступает первое число следующего месяца
+	filter := model.TrialFilter{
+		From:   model.YearMonthOf(today),
+		To:     model.YearMonthOf(until.AddDate(0, 0, 1)).AddMonths(-1),
+		UserID: userID,
+	}
+	if filter.To.Before(filter.From) {
+		return []model.EndingTrial{}, nil
+	}
+
+	subscriptions, err := s.repo.ListEndingTrials(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	trials := make([]model.EndingTrial, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		endsOn := model.TrialEndsOn(subscription.TrialEndDate)
+		trials = append(trials, model.EndingTrial{
+			Subscription: subscription,
+			EndsOn:       endsOn,
+			DaysLeft:     int32(endsOn.Sub(today).Hours() / 24),
+		})
+	}
+
+	return trials, nil
+}
+
+// applyTrial проверяет пробный период и льготные фазы новой подписки и вычисляет последний месяц пробного периода
+func applyTrial(subscription *model.Subscription) error {
+	if subscription.TrialMonths < 0 {
+		return apperror.NewValidationError("trial_months", "must not be negative")
+	}
+
+	for i, phase := range subscription.PromoPhases {
+		field := fmt.Sprintf("promo_phases[%d]", i)
+		if phase.Months <= 0 {
+			return apperror.NewValidationError(field+".months", "must be positive")
+		}
+		if phase.Currency != "" && phase.Currency != subscription.Currency {
+			return apperror.NewValidationError(field+".price.currency", "must match subscription currency "+subscription.Currency)
+		}
+		if err := validatePrice(field+".price", phase.Price, subscription.Currency); err != nil {
+			return err
+		}
+	}
+
+	if subscription.TrialMonths > 0 {
+		subscription.TrialEndDate = subscription.StartDate.AddMonths(int(subscription.TrialMonths) - 1)
+		if !subscription.EndDate.IsZero() && subscription.TrialEndDate.After(subscription.EndDate) {
+			return apperror.NewValidationError("trial_months", "trial must end not later than end_date "+subscription.EndDate.String())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+)
+
+// validateSubscription проверяет инварианты подписки, которые не выражаются проверками отдельных полей запроса.
+// Подписка проверяется целиком, поэтому при частичном обновлении проверяется результат слияния с сохраненной подпиской
+func validateSubscription(subscription model.Subscription) error {
+	if strings.TrimSpace(subscription.ServiceName) == "" {
+		return apperror.NewValidationError("service_name", "must not be empty")
+	}
+	if err := validatePrice("price", subscription.Price, subscription.Currency); err != nil {
+		return err
+	}
+
+	return validatePeriod(subscription.StartDate, subscription.EndDate)
+}
+
+// validatePrice проверяет цену в минимальных единицах валюты
+func validatePrice(field string, price int64, currency string) error {
+	if price < 0 {
+		return apperror.NewValidationError(field, "must not be negative")
+	}
+	if maxPrice := model.MaxPrice(currency); price > maxPrice {
+		return apperror.NewValidationError(field, fmt.Sprintf("must not exceed %d", maxPrice))
+	}
+
+	return nil
+}
+
+// validatePeriod проверяет, что период начинается с заданного месяца и заканчивается не раньше него.
+// Нулевой end означает бессрочный период
+func validatePeriod(start, end model.YearMonth) error {
+	if start.IsZero() {
+		return apperror.NewValidationError("start_date", "is required")
+	}
+	if !end.IsZero() && end.Before(start) {
+		return apperror.NewValidationError("end_date", "end_date "+end.String()+" must not be before start_date "+start.String())
+	}
+
+	return nil
+}
+
+// validateFilters проверяет период отчета: в отличие от подписки, он всегда ограничен
+func validateFilters(filters model.Filters) error {
+	if filters.EndDate.IsZero() {
+		return apperror.NewValidationError("end_date", "is required")
+	}
+
+	return validatePeriod(filters.StartDate, filters.EndDate)
+}
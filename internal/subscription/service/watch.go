@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/google/uuid"
+)
+
+const (
+	// watchBufferSize - сколько событий может накопиться у наблюдателя. Отстающий наблюдатель отключается
+	// и возобновляет наблюдение с последнего полученного события
+	watchBufferSize = 256
+	// watchCatchUpBatchSize - сколько пропущенных событий читается за раз при возобновлении наблюдения
+	watchCatchUpBatchSize = 500
+)
+
+type EventStreamRepository interface {
+	ListenOutboxEvents(ctx context.Context, listening func(), notify func(model.EventNotification)) error
+	GetOutboxEvents(ctx context.Context, ids []int64) ([]model.Event, error)
+	ListOutboxEventsAfter(ctx context.Context, afterID int64, userID uuid.UUID, limit int) ([]model.Event, error)
+	OutboxEventExists(ctx context.Context, id int64) (bool, error)
+}
+
+// EventBroker раздает события об изменении подписок наблюдателям. Один слушатель уведомлений Postgres на экземпляр
+// сервиса загружает каждое событие один раз и передает его всем наблюдателям, которым оно подходит
+type EventBroker struct {
+	repo EventStreamRepository
+
+	mu        sync.Mutex
+	listening bool
+	watchers  map[*eventWatcher]struct{}
+}
+
+type eventWatcher struct {
+	tenantID string
+	userID   uuid.UUID
+	events   chan model.Event
+}
+
+// matches сообщает, подходит ли наблюдателю событие из уведомления: события других арендаторов не подходят никогда
+func (w *eventWatcher) matches(notification model.EventNotification) bool {
+	return w.tenantID == notification.TenantID && (w.userID == uuid.Nil || w.userID == notification.UserID)
+}
+
+func NewEventBroker(repo EventStreamRepository) *EventBroker {
+	return &EventBroker{
+		repo:     repo,
+		watchers: make(map[*eventWatcher]struct{}),
+	}
+}
+
+// Run слушает уведомления о новых событиях до отмены ctx или потери соединения. При выходе все наблюдатели
+// отключаются: уведомления, пришедшие до повторного запуска, потеряны, и наблюдатели возобновляют наблюдение сами
+func (b *EventBroker) Run(ctx context.Context) error {
+	defer b.disconnectAll()
+
+	return b.repo.ListenOutboxEvents(ctx, func() {
+		b.mu.Lock()
+		b.listening = true
+		b.mu.Unlock()
+	}, func(notification model.EventNotification) {
+		if !b.hasWatchers(notification) {
+			return
+		}
+
+		events, err := b.repo.GetOutboxEvents(ctx, []int64{notification.ID})
+		if err != nil {
+			// событие пропало бы для всех наблюдателей, поэтому они отключаются и догоняют его при возобновлении
+			b.disconnectAll()
+			return
+		}
+
+		for _, event := range events {
+			b.dispatch(notification, event)
+		}
+	})
+}
+
+// Watch отправляет в send изменения подписок пользователя userID (всех подписок арендатора запроса при нулевом userID) до отмены ctx
+// или ошибки send. При ненулевом afterEventID сначала отправляются события, записанные после него. ready вызывается,
+// когда наблюдение началось: события, записанные после этого, не будут пропущены
+func (b *EventBroker) Watch(ctx context.Context, userID uuid.UUID, afterEventID int64, ready func() error, send func(model.Event) error) error {
+	userID, err := scopeUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	watch, err := b.watch(ctx, userID, afterEventID)
+	if err != nil {
+		return err
+	}
+	defer watch.Close()
+
+	if err = ready(); err != nil {
+		return err
+	}
+
+	for {
+		event, err := watch.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if err = send(event); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *EventBroker) watch(ctx context.Context, userID uuid.UUID, afterEventID int64) (*subscriptionWatch, error) {
+	watcher, err := b.subscribe(tenant.From(ctx), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	watch := &subscriptionWatch{
+		broker:  b,
+		watcher: watcher,
+		userID:  userID,
+		afterID: afterEventID,
+	}
+
+	if afterEventID > 0 {
+		// если событие, с которого возобновляется наблюдение, уже удалено, часть следующих могла удалиться вместе с ним
+		exists, err := b.repo.OutboxEventExists(ctx, afterEventID)
+		if err != nil {
+			watch.Close()
+			return nil, err
+		}
+		if !exists {
+			watch.Close()
+			return nil, model.ErrWatchResumeExpired
+		}
+
+		watch.catchingUp = true
+		watch.delivered = make(map[int64]struct{})
+	}
+
+	return watch, nil
+}
+
+func (b *EventBroker) subscribe(tenantID string, userID uuid.UUID) (*eventWatcher, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.listening {
+		return nil, model.ErrWatchInterrupted
+	}
+
+	watcher := &eventWatcher{tenantID: tenantID, userID: userID, events: make(chan model.Event, watchBufferSize)}
+	b.watchers[watcher] = struct{}{}
+
+	return watcher, nil
+}
+
+func (b *EventBroker) unsubscribe(watcher *eventWatcher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.watchers[watcher]; ok {
+		delete(b.watchers, watcher)
+		close(watcher.events)
+	}
+}
+
+func (b *EventBroker) hasWatchers(notification model.EventNotification) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for watcher := range b.watchers {
+		if watcher.matches(notification) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *EventBroker) dispatch(notification model.EventNotification, event model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for watcher := range b.watchers {
+		if !watcher.matches(notification) {
+			continue
+		}
+
+		select {
+		case watcher.events <- event:
+		default:
+			delete(b.watchers, watcher)
+			close(watcher.events)
+		}
+	}
+}
+
+func (b *EventBroker) disconnectAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listening = false
+	for watcher := range b.watchers {
+		delete(b.watchers, watcher)
+		close(watcher.events)
+	}
+}
+
+// subscriptionWatch - наблюдение за изменениями подписок. Сначала отдает пропущенные события из outbox,
+// затем новые по мере их записи
+type subscriptionWatch struct {
+	broker     *EventBroker
+	watcher    *eventWatcher
+	userID     uuid.UUID
+	afterID    int64
+	catchingUp bool
+	pending    []model.Event
+	// delivered - ID событий, отданных при догонянии: эти события могли уже прийти и как новые
+	delivered map[int64]struct{}
+}
+
+// Next возвращает следующее событие, ожидая его до отмены ctx. ErrWatchInterrupted означает, что наблюдение
+// прервано и его нужно возобновить с последнего полученного события
+func (w *subscriptionWatch) Next(ctx context.Context) (model.Event, error) {
+	for w.catchingUp {
+		if len(w.pending) == 0 {
+			events, err := w.broker.repo.ListOutboxEventsAfter(ctx, w.afterID, w.userID, watchCatchUpBatchSize)
+			if err != nil {
+				return model.Event{}, err
+			}
+			if len(events) == 0 {
+				w.catchingUp = false
+				break
+			}
+			w.pending = events
+		}
+
+		event := w.pending[0]
+		w.pending = w.pending[1:]
+		w.afterID = event.ID
+		w.delivered[event.ID] = struct{}{}
+
+		return event, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return model.Event{}, ctx.Err()
+		case event, ok := <-w.watcher.events:
+			if !ok {
+				return model.Event{}, model.ErrWatchInterrupted
+			}
+			if _, ok = w.delivered[event.ID]; ok {
+				delete(w.delivered, event.ID)
+				continue
+			}
+
+			return event, nil
+		}
+	}
+}
+
+func (w *subscriptionWatch) Close() {
+	w.broker.unsubscribe(w.watcher)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubEventStreamRepository struct {
+	events        []model.Event
+	notifications chan model.EventNotification
+}
+
+func (r *stubEventStreamRepository) ListenOutboxEvents(ctx context.Context, listening func(), notify func(model.EventNotification)) error {
+	listening()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case notification := <-r.notifications:
+			notify(notification)
+		}
+	}
+}
+
+func (r *stubEventStreamRepository) GetOutboxEvents(_ context.Context, ids []int64) ([]model.Event, error) {
+	var events []model.Event
+	for _, event := range r.events {
+		for _, id := range ids {
+			if event.ID == id {
+				events = append(events, event)
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func (r *stubEventStreamRepository) ListOutboxEventsAfter(_ context.Context, afterID int64, userID uuid.UUID, limit int) ([]model.Event, error) {
+	var events []model.Event
+	for _, event := range r.events {
+		if event.ID > afterID && (userID == uuid.Nil || event.Subscription.UserID == userID) && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (r *stubEventStreamRepository) OutboxEventExists(_ context.Context, id int64) (bool, error) {
+	for _, event := range r.events {
+		if event.ID == id {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func userEvent(id int64, userID uuid.UUID) model.Event {
+	return model.Event{ID: id, Subscription: &model.Subscription{UserID: userID}}
+}
+
+func userNotification(userID uuid.UUID) model.EventNotification {
+	return model.EventNotification{TenantID: tenant.Default, UserID: userID}
+}
+
+func TestEventBroker_Watch(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	t.Run("Слушатель уведомлений не запущен", func(t *testing.T) {
+		broker := NewEventBroker(&stubEventStreamRepository{})
+
+		_, err := broker.watch(ctx, alice, 0)
+		assert.ErrorIs(t, err, model.ErrWatchInterrupted)
+	})
+
+	t.Run("Новые события пользователя", func(t *testing.T) {
+		broker := NewEventBroker(&stubEventStreamRepository{})
+		broker.listening = true
+
+		watch, err := broker.watch(ctx, alice, 0)
+		require.NoError(t, err)
+		defer watch.Close()
+
+		broker.dispatch(userNotification(bob), userEvent(1, bob))
+		broker.dispatch(userNotification(alice), userEvent(2, alice))
+
+		event, err := watch.Next(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), event.ID)
+	})
+
+	t.Run("События других арендаторов не приходят", func(t *testing.T) {
+		broker := NewEventBroker(&stubEventStreamRepository{})
+		broker.listening = true
+
+		watch, err := broker.watch(tenant.With(ctx, "acme"), uuid.Nil, 0)
+		require.NoError(t, err)
+		defer watch.Close()
+
+		broker.dispatch(userNotification(alice), userEvent(1, alice))
+		broker.dispatch(model.EventNotification{TenantID: "acme", UserID: alice}, userEvent(2, alice))
+
+		event, err := watch.Next(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), event.ID)
+	})
+
+	t.Run("Возобновление без повторов", func(t *testing.T) {
+		repo := &stubEventStreamRepository{events: []model.Event{
+			userEvent(1, alice), userEvent(2, bob), userEvent(3, alice), userEvent(4, alice),
+		}}
+		broker := NewEventBroker(repo)
+		broker.listening = true
+
+		watch, err := broker.watch(ctx, alice, 1)
+		require.NoError(t, err)
+		defer watch.Close()
+
+		// событие 4 записано уже после начала наблюдения и приходит и из outbox, и как новое
+		broker.dispatch(userNotification(alice), userEvent(4, alice))
+		repo.events = append(repo.events, userEvent(5, alice))
+		broker.dispatch(userNotification(alice), userEvent(5, alice))
+
+		var ids []int64
+		for range 3 {
+			event, err := watch.Next(ctx)
+			require.NoError(t, err)
+			ids = append(ids, event.ID)
+		}
+		assert.Equal(t, []int64{3, 4, 5}, ids)
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = watch.Next(canceled)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Событие для возобновления удалено", func(t *testing.T) {
+		broker := NewEventBroker(&stubEventStreamRepository{events: []model.Event{userEvent(5, alice)}})
+		broker.listening = true
+
+		_, err := broker.watch(ctx, alice, 1)
+		assert.ErrorIs(t, err, model.ErrWatchResumeExpired)
+		assert.Empty(t, broker.watchers)
+	})
+
+	t.Run("Отстающий наблюдатель отключается", func(t *testing.T) {
+		broker := NewEventBroker(&stubEventStreamRepository{})
+		broker.listening = true
+
+		watch, err := broker.watch(ctx, uuid.Nil, 0)
+		require.NoError(t, err)
+		defer watch.Close()
+
+		for id := range int64(watchBufferSize + 1) {
+			broker.dispatch(userNotification(alice), userEvent(id+1, alice))
+		}
+
+		for range watchBufferSize {
+			_, err = watch.Next(ctx)
+			require.NoError(t, err)
+		}
+		_, err = watch.Next(ctx)
+		assert.ErrorIs(t, err, model.ErrWatchInterrupted)
+	})
+}
+
+func TestEventBroker_Run(t *testing.T) {
+	alice := uuid.New()
+	repo := &stubEventStreamRepository{
+		events:        []model.Event{userEvent(1, alice)},
+		notifications: make(chan model.EventNotification),
+	}
+	broker := NewEventBroker(repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- broker.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		return broker.listening
+	}, time.Second, time.Millisecond)
+
+	var received []int64
+	ready := make(chan struct{})
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- broker.Watch(context.Background(), alice, 0, func() error {
+			close(ready)
+			return nil
+		}, func(event model.Event) error {
+			received = append(received, event.ID)
+			cancel()
+			return nil
+		})
+	}()
+
+	<-ready
+	repo.notifications <- model.EventNotification{ID: 1, TenantID: tenant.Default, UserID: alice}
+
+	// остановка слушателя прерывает наблюдение
+	assert.ErrorIs(t, <-stopped, context.Canceled)
+	assert.ErrorIs(t, <-watchErr, model.ErrWatchInterrupted)
+	assert.Equal(t, []int64{1}, received)
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/tenant"
+	"github.com/google/uuid"
+)
+
+// webhookSecretPrefix отличает секреты вебхуков от других секретов в конфигурациях получателей
+const webhookSecretPrefix = "whsec_"
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	RotateWebhookSecret(ctx context.Context, id uuid.UUID, secret string, previousExpiresAt time.Time) (*model.Webhook, error)
+	EnqueueWebhookDeliveries(ctx context.Context, eventID int64, eventType model.EventType, payload []byte) (int64, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookRequest, error)
+	RecordWebhookAttempt(ctx context.Context, id int64, status model.WebhookDeliveryStatus, nextAttemptAt time.Time, response model.WebhookResponse) error
+	ListWebhookDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]model.WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (*model.WebhookDelivery, error)
+	DeleteFinishedWebhookDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookSender отправляет запрос получателю вебхука. Ошибки доставки возвращаются в model.WebhookResponse
+type WebhookSender interface {
+	Send(ctx context.Context, request model.WebhookRequest) model.WebhookResponse
+}
+
+// WebhookPolicy - параметры доставки вебхуков
+type WebhookPolicy struct {
+	// MaxAttempts - количество попыток, после которого доставка считается неудачной
+	MaxAttempts int
+	// InitialBackoff - пауза после первой неудачной попытки, после каждой следующей пауза удваивается до MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// SecretGracePeriod - сколько после ротации запросы подписываются и прежним секретом
+	SecretGracePeriod time.Duration
+	BatchSize         int
+	// Lease - на сколько доставка закрепляется за экземпляром сервиса, который ее отправляет
+	Lease time.Duration
+}
+
+// WebhookService управляет вебхуками и доставляет им события. Как EventPublisher ставит событие в очередь доставки
+// каждому подписанному вебхуку, а Deliver отправляет доставки из очереди с экспоненциальной паузой между попытками
+type WebhookService struct {
+	repo   WebhookRepository
+	sender WebhookSender
+	policy WebhookPolicy
+	now    func() time.Time
+}
+
+func NewWebhookService(repo WebhookRepository, sender WebhookSender, policy WebhookPolicy) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// CreateWebhook создает вебхук со случайным секретом. Секрет возвращается только здесь и при ротации.
+// Управлять вебхуками может только администратор: они получают изменения подписок всех пользователей
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhookURL(webhook.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = secret
+
+	return s.repo.CreateWebhook(ctx, webhook)
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListWebhooks(ctx)
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteWebhook(ctx, id)
+}
+
+// RotateWebhookSecret выдает вебхуку новый секрет. Прежний секрет действует еще SecretGracePeriod, чтобы получатель
+// успел перейти на новый, а при revokePrevious отзывается сразу - например, если он скомпрометирован
+func (s *WebhookService) RotateWebhookSecret(ctx context.Context, id uuid.UUID, revokePrevious bool) (*model.Webhook, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	var previousExpiresAt time.Time
+	if !revokePrevious && s.policy.SecretGracePeriod > 0 {
+		previousExpiresAt = s.now().Add(s.policy.SecretGracePeriod)
+	}
+
+	return s.repo.RotateWebhookSecret(ctx, id, secret, previousExpiresAt)
+}
+
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]model.WebhookDelivery, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListWebhookDeliveries(ctx, filter)
+}
+
+// ReplayWebhookDelivery повторяет доставку с полным числом попыток, в том числе уже успешную
+func (s *WebhookService) ReplayWebhookDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (*model.WebhookDelivery, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ReplayWebhookDelivery(ctx, webhookID, deliveryID)
+}
+
+// Publish ставит событие в очередь доставки всем вебхукам арендатора события, подписанным на его тип. Тело запроса
+// совпадает с событием, которое получают остальные публикаторы
+func (s *WebhookService) Publish(ctx context.Context, event model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.EnqueueWebhookDeliveries(tenant.With(ctx, event.TenantID), event.ID, event.Type, payload)
+	return err
+}
+
+// Deliver отправляет очередную порцию доставок, время попытки которых наступило, и возвращает количество успешных
+// и неудачных попыток. Доставки отправляются параллельно
+func (s *WebhookService) Deliver(ctx context.Context) (delivered, failed int, err error) {
+	requests, err := s.repo.ClaimWebhookDeliveries(ctx, s.policy.BatchSize, s.now().Add(s.policy.Lease))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	responses := make([]model.WebhookResponse, len(requests))
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		wg.Go(func() {
+			responses[i] = s.sender.Send(ctx, request)
+		})
+	}
+	wg.Wait()
+
+	for i, request := range requests {
+		response := responses[i]
+		status, nextAttemptAt := s.nextAttempt(request.Attempts+1, response)
+		if err = s.repo.RecordWebhookAttempt(ctx, request.DeliveryID, status, nextAttemptAt, response); err != nil {
+			return delivered, failed, err
+		}
+
+		if response.Err != nil {
+			failed++
+		} else {
+			delivered++
+		}
+	}
+
+	return delivered, failed, nil
+}
+
+// PurgeDeliveries удаляет из журнала завершенные доставки старше retention
+func (s *WebhookService) PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.DeleteFinishedWebhookDeliveries(ctx, s.now().Add(-retention))
+}
+
+// nextAttempt возвращает статус доставки после attempts-й попытки и время следующей попытки
+func (s *WebhookService) nextAttempt(attempts int32, response model.WebhookResponse) (model.WebhookDeliveryStatus, time.Time) {
+	now := s.now()
+	switch {
+	case response.Err == nil:
+		return model.WebhookDeliverySucceeded, now
+	case int(attempts) >= s.policy.MaxAttempts:
+		return model.WebhookDeliveryFailed, now
+	default:
+		return model.WebhookDeliveryPending, now.Add(s.backoff(attempts))
+	}
+}
+
+// backoff возвращает паузу после attempts неудачных попыток: InitialBackoff, затем вдвое больше, но не больше MaxBackoff
+func (s *WebhookService) backoff(attempts int32) time.Duration {
+	backoff := s.policy.InitialBackoff
+	for range attempts - 1 {
+		if backoff >= s.policy.MaxBackoff/2 {
+			return s.policy.MaxBackoff
+		}
+		backoff *= 2
+	}
+
+	return min(backoff, s.policy.MaxBackoff)
+}
+
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return apperror.NewValidationError("url", "url must be an absolute http or https URL")
+	}
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
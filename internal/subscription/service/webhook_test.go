@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Geriler/effective-mobile/internal/subscription/model"
+	"github.com/Geriler/effective-mobile/internal/subscription/publisher"
+	"github.com/Geriler/effective-mobile/pkg/lib/apperror"
+	"github.com/Geriler/effective-mobile/pkg/lib/principal"
+	"github.com/Geriler/effective-mobile/pkg/lib/webhooksig"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubWebhookRepository struct {
+	WebhookRepository
+	now        func() time.Time
+	webhook    model.Webhook
+	secrets    []string
+	deliveries []model.WebhookDelivery
+	rotatedTo  string
+	expiresAt  time.Time
+}
+
+func (r *stubWebhookRepository) CreateWebhook(_ context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	webhook.ID = uuid.New()
+	return &webhook, nil
+}
+
+func (r *stubWebhookRepository) RotateWebhookSecret(_ context.Context, id uuid.UUID, secret string, previousExpiresAt time.Time) (*model.Webhook, error) {
+	r.rotatedTo, r.expiresAt = secret, previousExpiresAt
+	return &model.Webhook{ID: id, Secret: secret, PreviousSecretExpiresAt: previousExpiresAt}, nil
+}
+
+func (r *stubWebhookRepository) EnqueueWebhookDeliveries(_ context.Context, eventID int64, eventType model.EventType, payload []byte) (int64, error) {
+	r.deliveries = append(r.deliveries, model.WebhookDelivery{
+		ID:            int64(len(r.deliveries) + 1),
+		WebhookID:     r.webhook.ID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: r.now(),
+	})
+	return 1, nil
+}
+
+func (r *stubWebhookRepository) ClaimWebhookDeliveries(_ context.Context, limit int, leaseUntil time.Time) ([]model.WebhookRequest, error) {
+	var requests []model.WebhookRequest
+	for i := range r.deliveries {
+		delivery := &r.deliveries[i]
+		if delivery.Status != model.WebhookDeliveryPending || delivery.NextAttemptAt.After(r.now()) || len(requests) == limit {
+			continue
+		}
+
+		delivery.NextAttemptAt = leaseUntil
+		requests = append(requests, model.WebhookRequest{
+			DeliveryID: delivery.ID,
+			WebhookID:  delivery.WebhookID,
+			EventID:    delivery.EventID,
+			EventType:  delivery.EventType,
+			Payload:    delivery.Payload,
+			Attempts:   delivery.Attempts,
+			URL:        r.webhook.URL,
+			Secrets:    r.secrets,
+		})
+	}
+
+	return requests, nil
+}
+
+func (r *stubWebhookRepository) RecordWebhookAttempt(_ context.Context, id int64, status model.WebhookDeliveryStatus, nextAttemptAt time.Time, response model.WebhookResponse) error {
+	delivery := &r.deliveries[id-1]
+	delivery.Status = status
+	delivery.Attempts++
+	delivery.NextAttemptAt = nextAttemptAt
+	delivery.ResponseStatus = response.Status
+	if response.Err != nil {
+		delivery.LastError = response.Err.Error()
+	}
+	return nil
+}
+
+// webhookReceiver - получатель вебхуков, который проверяет подпись и отвечает статусами из statuses по очереди
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	statuses []int
+	received []model.Event
+}
+
+func (h *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if err := webhooksig.Verify(r.Header.Get(webhooksig.Header), body, h.secret, 5*time.Minute, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	status := h.statuses[0]
+	if len(h.statuses) > 1 {
+		h.statuses = h.statuses[1:]
+	}
+	if status == http.StatusOK {
+		var event model.Event
+		_ = json.Unmarshal(body, &event)
+		h.received = append(h.received, event)
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(http.StatusText(status)))
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	receiver := &webhookReceiver{secret: "whsec_new", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	now := time.Now()
+	repo := &stubWebhookRepository{
+		now:     func() time.Time { return now },
+		webhook: model.Webhook{ID: uuid.New(), URL: server.URL},
+		// во время ротации получатель мог еще не перейти на новый секрет, поэтому запрос подписывается обоими
+		secrets: []string{"whsec_new", "whsec_old"},
+	}
+	service := NewWebhookService(repo, publisher.NewWebhookSender(time.Second), WebhookPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Hour,
+		BatchSize:      10,
+		Lease:          time.Minute,
+	})
+	service.now = repo.now
+
+	subscriptionID := uuid.New()
+	assert.NoError(t, service.Publish(context.Background(), model.Event{
+		ID:             7,
+		Type:           model.EventSubscriptionCreated,
+		SubscriptionID: subscriptionID,
+	}))
+
+	delivered, failed, err := service.Deliver(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, model.WebhookDeliveryPending, repo.deliveries[0].Status)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), repo.deliveries[0].ResponseStatus)
+	assert.Equal(t, now.Add(time.Minute), repo.deliveries[0].NextAttemptAt)
+
+	// до окончания паузы доставка не повторяется
+	delivered, failed, err = service.Deliver(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, delivered+failed)
+
+	now = now.Add(time.Minute)
+	delivered, failed, err = service.Deliver(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 0, failed)
+	assert.Equal(t, model.WebhookDeliverySucceeded, repo.deliveries[0].Status)
+	assert.Equal(t, int32(2), repo.deliveries[0].Attempts)
+
+	if assert.Len(t, receiver.received, 1) {
+		assert.Equal(t, int64(7), receiver.received[0].ID)
+		assert.Equal(t, subscriptionID, receiver.received[0].SubscriptionID)
+	}
+}
+
+func TestWebhookService_DeliverGivesUp(t *testing.T) {
+	// получатель не знает секрет, поэтому отвечает 401 на каждую попытку
+	receiver := &webhookReceiver{secret: "whsec_other", statuses: []int{http.StatusOK}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	now := time.Now()
+	repo := &stubWebhookRepository{
+		now:     func() time.Time { return now },
+		webhook: model.Webhook{ID: uuid.New(), URL: server.URL},
+		secrets: []string{"whsec_new"},
+	}
+	service := NewWebhookService(repo, publisher.NewWebhookSender(time.Second), WebhookPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Hour,
+		BatchSize:      10,
+		Lease:          time.Minute,
+	})
+	service.now = repo.now
+
+	assert.NoError(t, service.Publish(context.Background(), model.Event{ID: 1, Type: model.EventSubscriptionDeleted}))
+
+	for range 2 {
+		_, failed, err := service.Deliver(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failed)
+		now = now.Add(time.Hour)
+	}
+
+	assert.Equal(t, model.WebhookDeliveryFailed, repo.deliveries[0].Status)
+	assert.Equal(t, int32(http.StatusUnauthorized), repo.deliveries[0].ResponseStatus)
+	assert.Contains(t, repo.deliveries[0].LastError, "401")
+	assert.Empty(t, receiver.received)
+}
+
+func TestWebhookService_backoff(t *testing.T) {
+	service := NewWebhookService(nil, nil, WebhookPolicy{InitialBackoff: 30 * time.Second, MaxBackoff: 10 * time.Minute})
+
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 5, want: 8 * time.Minute},
+		{attempts: 6, want: 10 * time.Minute},
+		{attempts: 100, want: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, service.backoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	service := NewWebhookService(&stubWebhookRepository{}, nil, WebhookPolicy{})
+
+	webhook, err := service.CreateWebhook(context.Background(), model.Webhook{URL: "https://billing.example.com/hooks"})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(webhook.Secret, webhookSecretPrefix))
+
+	for _, url := range []string{"billing.example.com/hooks", "ftp://billing.example.com", "https://", ":"} {
+		_, err = service.CreateWebhook(context.Background(), model.Webhook{URL: url})
+		var validationErr *apperror.ValidationError
+		assert.ErrorAs(t, err, &validationErr, url)
+	}
+
+	// вебхуки получают изменения подписок всех пользователей, поэтому управляет ими только администратор
+	user := principal.With(context.Background(), principal.Principal{Subject: "alice", UserID: uuid.New()})
+	_, err = service.CreateWebhook(user, model.Webhook{URL: "https://billing.example.com/hooks"})
+	assert.ErrorIs(t, err, model.ErrPermissionDenied)
+
+	admin := principal.With(context.Background(), principal.Principal{Subject: "admin", Admin: true})
+	_, err = service.CreateWebhook(admin, model.Webhook{URL: "https://billing.example.com/hooks"})
+	assert.NoError(t, err)
+}
+
+func TestWebhookService_RotateWebhookSecret(t *testing.T) {
+	now := time.Now()
+	repo := &stubWebhookRepository{}
+	service := NewWebhookService(repo, nil, WebhookPolicy{SecretGracePeriod: 24 * time.Hour})
+	service.now = func() time.Time { return now }
+
+	webhook, err := service.RotateWebhookSecret(context.Background(), uuid.New(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, repo.rotatedTo, webhook.Secret)
+	assert.Equal(t, now.Add(24*time.Hour), repo.expiresAt)
+
+	previous := repo.rotatedTo
+	_, err = service.RotateWebhookSecret(context.Background(), uuid.New(), true)
+	assert.NoError(t, err)
+	assert.NotEqual(t, previous, repo.rotatedTo)
+	assert.True(t, repo.expiresAt.IsZero())
+}
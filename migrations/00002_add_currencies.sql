@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS currency TEXT DEFAULT 'RUB' NOT NULL;
+
+CREATE TABLE IF NOT EXISTS exchange_rates
+(
+    currency   TEXT            NOT NULL,
+    valid_from DATE            NOT NULL,
+    rate       NUMERIC(20, 10) NOT NULL,
+    CONSTRAINT exchange_rates_pk PRIMARY KEY (currency, valid_from),
+    CONSTRAINT exchange_rates_rate_check CHECK (rate > 0)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
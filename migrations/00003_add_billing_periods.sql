@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS billing_unit     TEXT    DEFAULT 'month' NOT NULL
+        CONSTRAINT subscriptions_billing_unit_check CHECK (billing_unit IN ('week', 'month')),
+    ADD COLUMN IF NOT EXISTS billing_interval INTEGER DEFAULT 1       NOT NULL
+        CONSTRAINT subscriptions_billing_interval_check CHECK (billing_interval > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS billing_interval,
+    DROP COLUMN IF EXISTS billing_unit;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS subscription_plans
+(
+    subscription_id uuid    NOT NULL
+        CONSTRAINT subscription_plans_subscription_id_fk REFERENCES subscriptions (id) ON DELETE CASCADE,
+    valid_from      DATE    NOT NULL,
+    service_name    TEXT    NOT NULL,
+    price           INTEGER NOT NULL,
+    CONSTRAINT subscription_plans_pk PRIMARY KEY (subscription_id, valid_from)
+);
+
+INSERT INTO subscription_plans (subscription_id, valid_from, service_name, price)
+SELECT id, start_date, service_name, price
+FROM subscriptions
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_plans;
+-- +goose StatementEnd
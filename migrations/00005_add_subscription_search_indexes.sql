@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS subscriptions_user_id_start_date_idx ON subscriptions (user_id, start_date, id);
+CREATE INDEX IF NOT EXISTS subscriptions_start_date_idx ON subscriptions (start_date, id);
+CREATE INDEX IF NOT EXISTS subscriptions_price_idx ON subscriptions (price, id);
+CREATE INDEX IF NOT EXISTS subscriptions_service_name_idx ON subscriptions (lower(service_name) text_pattern_ops, id);
+CREATE INDEX IF NOT EXISTS subscriptions_service_name_trgm_idx ON subscriptions USING gin (service_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS subscriptions_open_ended_idx ON subscriptions (user_id, start_date) WHERE end_date IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS subscriptions_open_ended_idx;
+DROP INDEX IF EXISTS subscriptions_service_name_trgm_idx;
+DROP INDEX IF EXISTS subscriptions_service_name_idx;
+DROP INDEX IF EXISTS subscriptions_price_idx;
+DROP INDEX IF EXISTS subscriptions_start_date_idx;
+DROP INDEX IF EXISTS subscriptions_user_id_start_date_idx;
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Количество минимальных единиц в единице валюты по ISO 4217, для большинства валют - 100
+CREATE OR REPLACE FUNCTION currency_minor_factor(currency TEXT) RETURNS BIGINT
+    LANGUAGE SQL
+    IMMUTABLE AS
+$$
+SELECT CASE
+           WHEN currency IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF', 'UGX', 'UYI',
+                             'VND', 'VUV', 'XAF', 'XOF', 'XPF') THEN 1
+           WHEN currency IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1000
+           ELSE 100
+           END
+$$;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN price TYPE BIGINT USING price::BIGINT * currency_minor_factor(currency);
+
+ALTER TABLE subscription_plans
+    ALTER COLUMN price TYPE BIGINT;
+
+UPDATE subscription_plans p
+SET price = p.price * currency_minor_factor(s.currency)
+FROM subscriptions s
+WHERE s.id = p.subscription_id;
+
+COMMENT ON COLUMN subscriptions.price IS 'Стоимость в минимальных единицах валюты (копейках, центах)';
+COMMENT ON COLUMN subscription_plans.price IS 'Стоимость в минимальных единицах валюты подписки';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE subscription_plans p
+SET price = p.price / currency_minor_factor(s.currency)
+FROM subscriptions s
+WHERE s.id = p.subscription_id;
+
+ALTER TABLE subscription_plans
+    ALTER COLUMN price TYPE INTEGER;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN price TYPE INTEGER USING (price / currency_minor_factor(currency))::INTEGER;
+
+COMMENT ON COLUMN subscriptions.price IS NULL;
+COMMENT ON COLUMN subscription_plans.price IS NULL;
+
+DROP FUNCTION IF EXISTS currency_minor_factor(TEXT);
+-- +goose StatementEnd
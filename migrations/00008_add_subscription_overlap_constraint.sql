@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Наименование для сравнения подписок: без учета регистра и лишних пробелов
+CREATE OR REPLACE FUNCTION normalize_service_name(service_name TEXT) RETURNS TEXT
+    LANGUAGE SQL
+    IMMUTABLE AS
+$$
+SELECT lower(regexp_replace(btrim(service_name), '\s+', ' ', 'g'))
+$$;
+
+-- Период активности подписки, включая месяц end_date
+CREATE OR REPLACE FUNCTION subscription_period(start_date DATE, end_date DATE) RETURNS DATERANGE
+    LANGUAGE SQL
+    IMMUTABLE AS
+$$
+SELECT daterange(start_date, end_date, '[]')
+$$;
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS overlap_allowed BOOLEAN DEFAULT FALSE NOT NULL;
+
+COMMENT ON COLUMN subscriptions.overlap_allowed IS 'Подписка сохранена с пересечением по политике warn или allow и не участвует в ограничении subscriptions_overlap_excl';
+
+-- Уже существующие пересечения сохраняются: помечается каждая подписка, пересекающаяся с подпиской с меньшим id
+UPDATE subscriptions s
+SET overlap_allowed = TRUE
+WHERE EXISTS (SELECT 1
+              FROM subscriptions o
+              WHERE o.user_id = s.user_id
+                AND normalize_service_name(o.service_name) = normalize_service_name(s.service_name)
+                AND subscription_period(o.start_date, o.end_date) && subscription_period(s.start_date, s.end_date)
+                AND o.id < s.id);
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_overlap_excl EXCLUDE USING gist (
+        user_id WITH =,
+        normalize_service_name(service_name) WITH =,
+        subscription_period(start_date, end_date) WITH &&
+        ) WHERE (NOT overlap_allowed);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_overlap_excl;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS overlap_allowed;
+
+DROP FUNCTION IF EXISTS subscription_period(DATE, DATE);
+DROP FUNCTION IF EXISTS normalize_service_name(TEXT);
+-- +goose StatementEnd
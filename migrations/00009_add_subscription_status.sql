@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS status TEXT DEFAULT 'active' NOT NULL
+        CONSTRAINT subscriptions_status_check CHECK (status IN ('trial', 'active', 'paused', 'cancelled', 'expired'));
+
+COMMENT ON COLUMN subscriptions.status IS 'Статус жизненного цикла: trial, active, paused, cancelled или expired';
+
+-- Подписки, закончившиеся до текущего месяца, считаются истекшими
+UPDATE subscriptions
+SET status = 'expired'
+WHERE end_date < date_trunc('month', now());
+
+CREATE TABLE IF NOT EXISTS subscription_pauses
+(
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    start_date      DATE NOT NULL,
+    end_date        DATE,
+    PRIMARY KEY (subscription_id, start_date),
+    CONSTRAINT subscription_pauses_period_check CHECK (end_date IS NULL OR end_date >= start_date),
+    CONSTRAINT subscription_pauses_overlap_excl EXCLUDE USING gist (
+        subscription_id WITH =,
+        subscription_period(start_date, end_date) WITH &&
+        )
+);
+
+COMMENT ON TABLE subscription_pauses IS 'Приостановки подписок: месяцы с start_date по end_date включительно не оплачиваются, end_date пуст у текущей приостановки';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_pauses;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
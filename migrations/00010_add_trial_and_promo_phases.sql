@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS trial_end_date DATE;
+
+COMMENT ON COLUMN subscriptions.trial_end_date IS 'Последний месяц бесплатного пробного периода';
+
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS phase TEXT DEFAULT 'regular' NOT NULL
+        CONSTRAINT subscription_plans_phase_check CHECK (phase IN ('trial', 'promo', 'regular'));
+
+COMMENT ON COLUMN subscription_plans.phase IS 'Фаза тарифа: trial - пробный период, promo - льготная цена, regular - обычная цена';
+
+CREATE INDEX IF NOT EXISTS subscriptions_trial_end_date_idx ON subscriptions (trial_end_date) WHERE status = 'trial';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS subscriptions_trial_end_date_idx;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS phase;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS trial_end_date;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN subscriptions.deleted_at IS 'Время удаления: удаленная подписка не участвует в выборках и удаляется окончательно по истечении срока хранения';
+
+-- Удаленные подписки не мешают создавать новые на тот же период
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_overlap_excl;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_overlap_excl EXCLUDE USING gist (
+        user_id WITH =,
+        normalize_service_name(service_name) WITH =,
+        subscription_period(start_date, end_date) WITH &&
+        ) WHERE (NOT overlap_allowed AND deleted_at IS NULL);
+
+CREATE INDEX IF NOT EXISTS subscriptions_deleted_at_idx ON subscriptions (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE
+FROM subscriptions
+WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS subscriptions_deleted_at_idx;
+
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_overlap_excl;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_overlap_excl EXCLUDE USING gist (
+        user_id WITH =,
+        normalize_service_name(service_name) WITH =,
+        subscription_period(start_date, end_date) WITH &&
+        ) WHERE (NOT overlap_allowed);
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
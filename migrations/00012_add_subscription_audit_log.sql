@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Журнал изменений подписок. Записи не ссылаются на подписку, чтобы история сохранялась после окончательного удаления
+CREATE TABLE IF NOT EXISTS subscription_audit_log
+(
+    id              BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    subscription_id UUID                      NOT NULL,
+    action          TEXT                      NOT NULL,
+    actor           TEXT                      NOT NULL,
+    request_id      TEXT,
+    before          JSONB,
+    after           JSONB,
+    changes         JSONB       DEFAULT '[]'  NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE subscription_audit_log IS 'Журнал изменений подписок: состояние до и после изменения, автор и ID запроса';
+COMMENT ON COLUMN subscription_audit_log.before IS 'Подписка до изменения, NULL при создании';
+COMMENT ON COLUMN subscription_audit_log.after IS 'Подписка после изменения, NULL при окончательном удалении';
+COMMENT ON COLUMN subscription_audit_log.changes IS 'Измененные поля: [{"field": ..., "before": ..., "after": ...}]';
+
+CREATE INDEX IF NOT EXISTS subscription_audit_log_subscription_id_idx ON subscription_audit_log (subscription_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_audit_log;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS version BIGINT DEFAULT 1 NOT NULL;
+
+COMMENT ON COLUMN subscriptions.version IS 'Версия подписки для оптимистичной блокировки, увеличивается при каждом изменении строки';
+
+CREATE OR REPLACE FUNCTION increment_subscription_version() RETURNS TRIGGER
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    IF NEW IS DISTINCT FROM OLD THEN
+        NEW.version = OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER subscriptions_version_trg
+    BEFORE UPDATE
+    ON subscriptions
+    FOR EACH ROW
+EXECUTE FUNCTION increment_subscription_version();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS subscriptions_version_trg ON subscriptions;
+DROP FUNCTION IF EXISTS increment_subscription_version();
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
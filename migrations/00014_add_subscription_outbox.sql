@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- События об изменении подписок, записанные в той же транзакции, что и изменение. Публикуются фоновой задачей
+-- в порядке id, поэтому события одной подписки публикуются в порядке изменений
+CREATE TABLE IF NOT EXISTS subscription_outbox
+(
+    id              BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    subscription_id UUID                      NOT NULL,
+    event_type      TEXT                      NOT NULL,
+    payload         JSONB                     NOT NULL,
+    created_at      TIMESTAMPTZ DEFAULT now() NOT NULL,
+    published_at    TIMESTAMPTZ,
+    attempts        INT         DEFAULT 0     NOT NULL,
+    last_error      TEXT
+);
+
+COMMENT ON TABLE subscription_outbox IS 'Исходящие события об изменении подписок (transactional outbox)';
+COMMENT ON COLUMN subscription_outbox.published_at IS 'Время публикации, NULL у еще не опубликованного события';
+COMMENT ON COLUMN subscription_outbox.attempts IS 'Количество неудачных попыток публикации';
+
+CREATE INDEX IF NOT EXISTS subscription_outbox_pending_idx ON subscription_outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS subscription_outbox_published_at_idx ON subscription_outbox (published_at) WHERE published_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_outbox;
+-- +goose StatementEnd
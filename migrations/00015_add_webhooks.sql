@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_endpoints
+(
+    id                         UUID        DEFAULT gen_random_uuid() PRIMARY KEY,
+    url                        TEXT                                  NOT NULL,
+    description                TEXT        DEFAULT ''                NOT NULL,
+    event_types                TEXT[]      DEFAULT '{}'              NOT NULL,
+    secret                     TEXT                                  NOT NULL,
+    previous_secret            TEXT,
+    previous_secret_expires_at TIMESTAMPTZ,
+    created_at                 TIMESTAMPTZ DEFAULT now()             NOT NULL,
+    secret_rotated_at          TIMESTAMPTZ DEFAULT now()             NOT NULL
+);
+
+COMMENT ON TABLE webhook_endpoints IS 'Получатели событий об изменении подписок';
+COMMENT ON COLUMN webhook_endpoints.event_types IS 'Типы событий, которые получает вебхук. Пустой список - все события';
+COMMENT ON COLUMN webhook_endpoints.previous_secret IS 'Секрет до ротации, которым запросы подписываются до previous_secret_expires_at';
+
+-- Журнал доставок: по одной строке на событие и получателя. Повторная запись события не создает новую доставку
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    endpoint_id     UUID                          NOT NULL REFERENCES webhook_endpoints (id) ON DELETE CASCADE,
+    event_id        BIGINT                        NOT NULL,
+    event_type      TEXT                          NOT NULL,
+    payload         JSONB                         NOT NULL,
+    status          TEXT        DEFAULT 'pending' NOT NULL,
+    attempts        INT         DEFAULT 0         NOT NULL,
+    next_attempt_at TIMESTAMPTZ DEFAULT now()     NOT NULL,
+    last_attempt_at TIMESTAMPTZ,
+    response_status INT,
+    response_body   TEXT,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ DEFAULT now()     NOT NULL,
+    delivered_at    TIMESTAMPTZ,
+    CONSTRAINT webhook_deliveries_event_uniq UNIQUE (endpoint_id, event_id),
+    CONSTRAINT webhook_deliveries_status_check CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'Время следующей попытки доставки, для отправляемой доставки - окончание аренды';
+COMMENT ON COLUMN webhook_deliveries.response_status IS 'HTTP-статус ответа получателя на последнюю попытку';
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_endpoint_idx ON webhook_deliveries (endpoint_id, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
+-- +goose StatementEnd
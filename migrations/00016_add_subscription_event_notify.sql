@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscription_outbox
+    ADD COLUMN IF NOT EXISTS user_id UUID;
+
+UPDATE subscription_outbox
+SET user_id = (payload -> 'subscription' ->> 'user_id')::UUID
+WHERE user_id IS NULL;
+
+ALTER TABLE subscription_outbox
+    ALTER COLUMN user_id SET NOT NULL;
+
+COMMENT ON COLUMN subscription_outbox.user_id IS 'Пользователь подписки после изменения, по нему фильтруются подписки на изменения';
+
+CREATE INDEX IF NOT EXISTS subscription_outbox_user_id_idx ON subscription_outbox (user_id, id);
+
+-- Уведомление о каждом новом событии доставляется слушателям канала subscription_events после фиксации транзакции
+CREATE OR REPLACE FUNCTION notify_subscription_event() RETURNS TRIGGER
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    PERFORM pg_notify('subscription_events', json_build_object('id', NEW.id, 'user_id', NEW.user_id)::TEXT);
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER subscription_outbox_notify
+    AFTER INSERT
+    ON subscription_outbox
+    FOR EACH ROW
+EXECUTE FUNCTION notify_subscription_event();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS subscription_outbox_notify ON subscription_outbox;
+DROP FUNCTION IF EXISTS notify_subscription_event();
+DROP INDEX IF EXISTS subscription_outbox_user_id_idx;
+ALTER TABLE subscription_outbox
+    DROP COLUMN IF EXISTS user_id;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           UUID        DEFAULT gen_random_uuid() PRIMARY KEY,
+    name         TEXT                                  NOT NULL,
+    prefix       TEXT                                  NOT NULL,
+    key_hash     BYTEA                                 NOT NULL,
+    scopes       TEXT[]                                NOT NULL,
+    user_id      UUID,
+    created_at   TIMESTAMPTZ DEFAULT now()             NOT NULL,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    CONSTRAINT api_keys_key_hash_uniq UNIQUE (key_hash)
+);
+
+COMMENT ON TABLE api_keys IS 'API-ключи машинных клиентов';
+COMMENT ON COLUMN api_keys.prefix IS 'Начало ключа, по которому его можно узнать. Сам ключ не хранится';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 ключа';
+COMMENT ON COLUMN api_keys.user_id IS 'Пользователь, подписками которого ограничен ключ. NULL - подписки всех пользователей';
+COMMENT ON COLUMN api_keys.last_used_at IS 'Время последнего использования, обновляется не чаще раза в минуту';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
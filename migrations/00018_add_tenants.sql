@@ -0,0 +1,246 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Данные каждого арендатора видны только запросам этого арендатора. Существующие данные переходят арендатору default.
+-- Курсы валют общие для всех арендаторов
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE subscription_pauses
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE subscription_audit_log
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE subscription_outbox
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE webhook_endpoints
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE webhook_deliveries
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT DEFAULT 'default' NOT NULL;
+
+-- Новые строки получают арендатора только явно
+ALTER TABLE subscriptions
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE subscription_plans
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE subscription_pauses
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE subscription_audit_log
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE subscription_outbox
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_endpoints
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE api_keys
+    ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE idempotency_keys
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+COMMENT ON COLUMN subscriptions.tenant_id IS 'Арендатор: подписки разных арендаторов не видны друг другу';
+
+-- Пересечения проверяются в пределах арендатора
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_overlap_excl;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_overlap_excl EXCLUDE USING gist (
+        tenant_id WITH =,
+        user_id WITH =,
+        normalize_service_name(service_name) WITH =,
+        subscription_period(start_date, end_date) WITH &&
+        ) WHERE (NOT overlap_allowed AND deleted_at IS NULL);
+
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT IF EXISTS idempotency_keys_pk;
+
+ALTER TABLE idempotency_keys
+    ADD CONSTRAINT idempotency_keys_pk PRIMARY KEY (tenant_id, key);
+
+CREATE INDEX IF NOT EXISTS subscriptions_tenant_id_start_date_idx ON subscriptions (tenant_id, start_date, id);
+CREATE INDEX IF NOT EXISTS webhook_endpoints_tenant_id_idx ON webhook_endpoints (tenant_id, created_at, id);
+CREATE INDEX IF NOT EXISTS api_keys_tenant_id_idx ON api_keys (tenant_id, created_at, id);
+
+-- Уведомление о событии сообщает арендатора, чтобы событие получили только наблюдатели этого арендатора
+CREATE OR REPLACE FUNCTION notify_subscription_event() RETURNS TRIGGER
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    PERFORM pg_notify('subscription_events',
+                      json_build_object('id', NEW.id, 'tenant_id', NEW.tenant_id, 'user_id', NEW.user_id)::TEXT);
+    RETURN NULL;
+END
+$$;
+
+-- Строка видна, если ее арендатор совпадает с параметром сеанса app.tenant_id. Значение * открывает строки
+-- всех арендаторов фоновым задачам, без параметра не видна ни одна строка
+CREATE OR REPLACE FUNCTION tenant_visible(tenant_id TEXT) RETURNS BOOLEAN
+    LANGUAGE SQL
+    STABLE AS
+$$
+SELECT tenant_id = current_setting('app.tenant_id', TRUE) OR current_setting('app.tenant_id', TRUE) = '*'
+$$;
+
+-- Политики защиты строк - вторая линия защиты после фильтров запросов. Владелец таблиц им тоже подчиняется,
+-- суперпользователь и роли с BYPASSRLS - нет
+ALTER TABLE subscriptions
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions USING (tenant_visible(tenant_id));
+
+ALTER TABLE subscription_plans
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_plans
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY subscription_plans_tenant_isolation ON subscription_plans USING (tenant_visible(tenant_id));
+
+ALTER TABLE subscription_pauses
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_pauses
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY subscription_pauses_tenant_isolation ON subscription_pauses USING (tenant_visible(tenant_id));
+
+ALTER TABLE subscription_audit_log
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_audit_log
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY subscription_audit_log_tenant_isolation ON subscription_audit_log USING (tenant_visible(tenant_id));
+
+ALTER TABLE subscription_outbox
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_outbox
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY subscription_outbox_tenant_isolation ON subscription_outbox USING (tenant_visible(tenant_id));
+
+ALTER TABLE webhook_endpoints
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_endpoints
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY webhook_endpoints_tenant_isolation ON webhook_endpoints USING (tenant_visible(tenant_id));
+
+ALTER TABLE webhook_deliveries
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY webhook_deliveries_tenant_isolation ON webhook_deliveries USING (tenant_visible(tenant_id));
+
+ALTER TABLE api_keys
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY api_keys_tenant_isolation ON api_keys USING (tenant_visible(tenant_id));
+
+ALTER TABLE idempotency_keys
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys
+    FORCE ROW LEVEL SECURITY;
+CREATE POLICY idempotency_keys_tenant_isolation ON idempotency_keys USING (tenant_visible(tenant_id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY IF EXISTS idempotency_keys_tenant_isolation ON idempotency_keys;
+ALTER TABLE idempotency_keys
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE idempotency_keys
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS api_keys_tenant_isolation ON api_keys;
+ALTER TABLE api_keys
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS webhook_deliveries_tenant_isolation ON webhook_deliveries;
+ALTER TABLE webhook_deliveries
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS webhook_endpoints_tenant_isolation ON webhook_endpoints;
+ALTER TABLE webhook_endpoints
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_endpoints
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS subscription_outbox_tenant_isolation ON subscription_outbox;
+ALTER TABLE subscription_outbox
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_outbox
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS subscription_audit_log_tenant_isolation ON subscription_audit_log;
+ALTER TABLE subscription_audit_log
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_audit_log
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS subscription_pauses_tenant_isolation ON subscription_pauses;
+ALTER TABLE subscription_pauses
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_pauses
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS subscription_plans_tenant_isolation ON subscription_plans;
+ALTER TABLE subscription_plans
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE subscription_plans
+    NO FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS subscriptions_tenant_isolation ON subscriptions;
+ALTER TABLE subscriptions
+    DISABLE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions
+    NO FORCE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS tenant_visible(TEXT);
+
+CREATE OR REPLACE FUNCTION notify_subscription_event() RETURNS TRIGGER
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    PERFORM pg_notify('subscription_events', json_build_object('id', NEW.id, 'user_id', NEW.user_id)::TEXT);
+    RETURN NULL;
+END
+$$;
+
+DROP INDEX IF EXISTS api_keys_tenant_id_idx;
+DROP INDEX IF EXISTS webhook_endpoints_tenant_id_idx;
+DROP INDEX IF EXISTS subscriptions_tenant_id_start_date_idx;
+
+-- ключи идемпотентности разных арендаторов могут совпадать
+DELETE
+FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT IF EXISTS idempotency_keys_pk;
+
+ALTER TABLE idempotency_keys
+    ADD CONSTRAINT idempotency_keys_pk PRIMARY KEY (key);
+
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_overlap_excl;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_overlap_excl EXCLUDE USING gist (
+        user_id WITH =,
+        normalize_service_name(service_name) WITH =,
+        subscription_period(start_date, end_date) WITH &&
+        ) WHERE (NOT overlap_allowed AND deleted_at IS NULL);
+
+ALTER TABLE idempotency_keys
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE webhook_deliveries
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE webhook_endpoints
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE subscription_outbox
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE subscription_audit_log
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE subscription_pauses
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS tenant_id;
+-- +goose StatementEnd
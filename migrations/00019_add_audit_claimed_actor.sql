@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscription_audit_log
+    ADD COLUMN IF NOT EXISTS claimed_actor TEXT;
+
+COMMENT ON COLUMN subscription_audit_log.actor IS 'Субъект аутентифицированного клиента, unauthenticated без аутентификации или system для фоновых задач';
+COMMENT ON COLUMN subscription_audit_log.claimed_actor IS 'Значение заголовка X-Actor. Задается клиентом и не проверяется';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscription_audit_log
+    DROP COLUMN IF EXISTS claimed_actor;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscription_outbox
+    ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN subscription_outbox.failed_at IS 'Время, когда попытки публикации исчерпаны. Такое событие больше не публикуется и не задерживает следующие события подписки';
+
+-- неопубликованные события выбираются первыми для каждой подписки
+DROP INDEX IF EXISTS subscription_outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS subscription_outbox_pending_idx ON subscription_outbox (subscription_id, id)
+    WHERE published_at IS NULL AND failed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS subscription_outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS subscription_outbox_pending_idx ON subscription_outbox (id) WHERE published_at IS NULL;
+
+ALTER TABLE subscription_outbox
+    DROP COLUMN IF EXISTS failed_at;
+-- +goose StatementEnd
@@ -12,6 +12,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -24,13 +25,534 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type DeletedFilter int32
+
+const (
+	DeletedFilter_DELETED_FILTER_UNSPECIFIED DeletedFilter = 0
+	// Только неудаленные подписки
+	DeletedFilter_DELETED_FILTER_EXCLUDE DeletedFilter = 1
+	// Удаленные и неудаленные подписки
+	DeletedFilter_DELETED_FILTER_INCLUDE DeletedFilter = 2
+	// Только удаленные подписки (корзина)
+	DeletedFilter_DELETED_FILTER_ONLY DeletedFilter = 3
+)
+
+// Enum value maps for DeletedFilter.
+var (
+	DeletedFilter_name = map[int32]string{
+		0: "DELETED_FILTER_UNSPECIFIED",
+		1: "DELETED_FILTER_EXCLUDE",
+		2: "DELETED_FILTER_INCLUDE",
+		3: "DELETED_FILTER_ONLY",
+	}
+	DeletedFilter_value = map[string]int32{
+		"DELETED_FILTER_UNSPECIFIED": 0,
+		"DELETED_FILTER_EXCLUDE":     1,
+		"DELETED_FILTER_INCLUDE":     2,
+		"DELETED_FILTER_ONLY":        3,
+	}
+)
+
+func (x DeletedFilter) Enum() *DeletedFilter {
+	p := new(DeletedFilter)
+	*p = x
+	return p
+}
+
+func (x DeletedFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeletedFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[0].Descriptor()
+}
+
+func (DeletedFilter) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[0]
+}
+
+func (x DeletedFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeletedFilter.Descriptor instead.
+func (DeletedFilter) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{0}
+}
+
+type ServiceNameMatch int32
+
+const (
+	ServiceNameMatch_SERVICE_NAME_MATCH_UNSPECIFIED ServiceNameMatch = 0
+	// Полное совпадение без учета регистра
+	ServiceNameMatch_SERVICE_NAME_MATCH_EXACT ServiceNameMatch = 1
+	// Наименование начинается с указанной строки, без учета регистра
+	ServiceNameMatch_SERVICE_NAME_MATCH_PREFIX ServiceNameMatch = 2
+	// Нечеткий поиск по триграммам (pg_trgm)
+	ServiceNameMatch_SERVICE_NAME_MATCH_SIMILAR ServiceNameMatch = 3
+)
+
+// Enum value maps for ServiceNameMatch.
+var (
+	ServiceNameMatch_name = map[int32]string{
+		0: "SERVICE_NAME_MATCH_UNSPECIFIED",
+		1: "SERVICE_NAME_MATCH_EXACT",
+		2: "SERVICE_NAME_MATCH_PREFIX",
+		3: "SERVICE_NAME_MATCH_SIMILAR",
+	}
+	ServiceNameMatch_value = map[string]int32{
+		"SERVICE_NAME_MATCH_UNSPECIFIED": 0,
+		"SERVICE_NAME_MATCH_EXACT":       1,
+		"SERVICE_NAME_MATCH_PREFIX":      2,
+		"SERVICE_NAME_MATCH_SIMILAR":     3,
+	}
+)
+
+func (x ServiceNameMatch) Enum() *ServiceNameMatch {
+	p := new(ServiceNameMatch)
+	*p = x
+	return p
+}
+
+func (x ServiceNameMatch) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ServiceNameMatch) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[1].Descriptor()
+}
+
+func (ServiceNameMatch) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[1]
+}
+
+func (x ServiceNameMatch) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ServiceNameMatch.Descriptor instead.
+func (ServiceNameMatch) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{1}
+}
+
+type Granularity int32
+
+const (
+	Granularity_GRANULARITY_UNSPECIFIED Granularity = 0
+	Granularity_GRANULARITY_MONTH       Granularity = 1
+	Granularity_GRANULARITY_QUARTER     Granularity = 2
+	Granularity_GRANULARITY_YEAR        Granularity = 3
+)
+
+// Enum value maps for Granularity.
+var (
+	Granularity_name = map[int32]string{
+		0: "GRANULARITY_UNSPECIFIED",
+		1: "GRANULARITY_MONTH",
+		2: "GRANULARITY_QUARTER",
+		3: "GRANULARITY_YEAR",
+	}
+	Granularity_value = map[string]int32{
+		"GRANULARITY_UNSPECIFIED": 0,
+		"GRANULARITY_MONTH":       1,
+		"GRANULARITY_QUARTER":     2,
+		"GRANULARITY_YEAR":        3,
+	}
+)
+
+func (x Granularity) Enum() *Granularity {
+	p := new(Granularity)
+	*p = x
+	return p
+}
+
+func (x Granularity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Granularity) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[2].Descriptor()
+}
+
+func (Granularity) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[2]
+}
+
+func (x Granularity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Granularity.Descriptor instead.
+func (Granularity) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{2}
+}
+
+type BreakdownDimension int32
+
+const (
+	BreakdownDimension_BREAKDOWN_DIMENSION_UNSPECIFIED  BreakdownDimension = 0
+	BreakdownDimension_BREAKDOWN_DIMENSION_SERVICE_NAME BreakdownDimension = 1
+	BreakdownDimension_BREAKDOWN_DIMENSION_USER_ID      BreakdownDimension = 2
+)
+
+// Enum value maps for BreakdownDimension.
+var (
+	BreakdownDimension_name = map[int32]string{
+		0: "BREAKDOWN_DIMENSION_UNSPECIFIED",
+		1: "BREAKDOWN_DIMENSION_SERVICE_NAME",
+		2: "BREAKDOWN_DIMENSION_USER_ID",
+	}
+	BreakdownDimension_value = map[string]int32{
+		"BREAKDOWN_DIMENSION_UNSPECIFIED":  0,
+		"BREAKDOWN_DIMENSION_SERVICE_NAME": 1,
+		"BREAKDOWN_DIMENSION_USER_ID":      2,
+	}
+)
+
+func (x BreakdownDimension) Enum() *BreakdownDimension {
+	p := new(BreakdownDimension)
+	*p = x
+	return p
+}
+
+func (x BreakdownDimension) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BreakdownDimension) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[3].Descriptor()
+}
+
+func (BreakdownDimension) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[3]
+}
+
+func (x BreakdownDimension) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BreakdownDimension.Descriptor instead.
+func (BreakdownDimension) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{3}
+}
+
+type BillingPeriod int32
+
+const (
+	BillingPeriod_BILLING_PERIOD_UNSPECIFIED BillingPeriod = 0
+	BillingPeriod_BILLING_PERIOD_WEEKLY      BillingPeriod = 1
+	BillingPeriod_BILLING_PERIOD_MONTHLY     BillingPeriod = 2
+	BillingPeriod_BILLING_PERIOD_QUARTERLY   BillingPeriod = 3
+	BillingPeriod_BILLING_PERIOD_YEARLY      BillingPeriod = 4
+	// Списание раз в billing_interval_months месяцев
+	BillingPeriod_BILLING_PERIOD_CUSTOM BillingPeriod = 5
+)
+
+// Enum value maps for BillingPeriod.
+var (
+	BillingPeriod_name = map[int32]string{
+		0: "BILLING_PERIOD_UNSPECIFIED",
+		1: "BILLING_PERIOD_WEEKLY",
+		2: "BILLING_PERIOD_MONTHLY",
+		3: "BILLING_PERIOD_QUARTERLY",
+		4: "BILLING_PERIOD_YEARLY",
+		5: "BILLING_PERIOD_CUSTOM",
+	}
+	BillingPeriod_value = map[string]int32{
+		"BILLING_PERIOD_UNSPECIFIED": 0,
+		"BILLING_PERIOD_WEEKLY":      1,
+		"BILLING_PERIOD_MONTHLY":     2,
+		"BILLING_PERIOD_QUARTERLY":   3,
+		"BILLING_PERIOD_YEARLY":      4,
+		"BILLING_PERIOD_CUSTOM":      5,
+	}
+)
+
+func (x BillingPeriod) Enum() *BillingPeriod {
+	p := new(BillingPeriod)
+	*p = x
+	return p
+}
+
+func (x BillingPeriod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BillingPeriod) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[4].Descriptor()
+}
+
+func (BillingPeriod) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[4]
+}
+
+func (x BillingPeriod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BillingPeriod.Descriptor instead.
+func (BillingPeriod) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{4}
+}
+
+// Статус жизненного цикла подписки: trial → active → paused → cancelled → expired
+type SubscriptionStatus int32
+
+const (
+	SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED SubscriptionStatus = 0
+	SubscriptionStatus_SUBSCRIPTION_STATUS_TRIAL       SubscriptionStatus = 1
+	SubscriptionStatus_SUBSCRIPTION_STATUS_ACTIVE      SubscriptionStatus = 2
+	SubscriptionStatus_SUBSCRIPTION_STATUS_PAUSED      SubscriptionStatus = 3
+	SubscriptionStatus_SUBSCRIPTION_STATUS_CANCELLED   SubscriptionStatus = 4
+	SubscriptionStatus_SUBSCRIPTION_STATUS_EXPIRED     SubscriptionStatus = 5
+)
+
+// Enum value maps for SubscriptionStatus.
+var (
+	SubscriptionStatus_name = map[int32]string{
+		0: "SUBSCRIPTION_STATUS_UNSPECIFIED",
+		1: "SUBSCRIPTION_STATUS_TRIAL",
+		2: "SUBSCRIPTION_STATUS_ACTIVE",
+		3: "SUBSCRIPTION_STATUS_PAUSED",
+		4: "SUBSCRIPTION_STATUS_CANCELLED",
+		5: "SUBSCRIPTION_STATUS_EXPIRED",
+	}
+	SubscriptionStatus_value = map[string]int32{
+		"SUBSCRIPTION_STATUS_UNSPECIFIED": 0,
+		"SUBSCRIPTION_STATUS_TRIAL":       1,
+		"SUBSCRIPTION_STATUS_ACTIVE":      2,
+		"SUBSCRIPTION_STATUS_PAUSED":      3,
+		"SUBSCRIPTION_STATUS_CANCELLED":   4,
+		"SUBSCRIPTION_STATUS_EXPIRED":     5,
+	}
+)
+
+func (x SubscriptionStatus) Enum() *SubscriptionStatus {
+	p := new(SubscriptionStatus)
+	*p = x
+	return p
+}
+
+func (x SubscriptionStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubscriptionStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[5].Descriptor()
+}
+
+func (SubscriptionStatus) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[5]
+}
+
+func (x SubscriptionStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubscriptionStatus.Descriptor instead.
+func (SubscriptionStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{5}
+}
+
+type PlanPhase int32
+
+const (
+	PlanPhase_PLAN_PHASE_UNSPECIFIED PlanPhase = 0
+	PlanPhase_PLAN_PHASE_TRIAL       PlanPhase = 1
+	PlanPhase_PLAN_PHASE_PROMO       PlanPhase = 2
+	PlanPhase_PLAN_PHASE_REGULAR     PlanPhase = 3
+)
+
+// Enum value maps for PlanPhase.
+var (
+	PlanPhase_name = map[int32]string{
+		0: "PLAN_PHASE_UNSPECIFIED",
+		1: "PLAN_PHASE_TRIAL",
+		2: "PLAN_PHASE_PROMO",
+		3: "PLAN_PHASE_REGULAR",
+	}
+	PlanPhase_value = map[string]int32{
+		"PLAN_PHASE_UNSPECIFIED": 0,
+		"PLAN_PHASE_TRIAL":       1,
+		"PLAN_PHASE_PROMO":       2,
+		"PLAN_PHASE_REGULAR":     3,
+	}
+)
+
+func (x PlanPhase) Enum() *PlanPhase {
+	p := new(PlanPhase)
+	*p = x
+	return p
+}
+
+func (x PlanPhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PlanPhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[6].Descriptor()
+}
+
+func (PlanPhase) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[6]
+}
+
+func (x PlanPhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PlanPhase.Descriptor instead.
+func (PlanPhase) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{6}
+}
+
+type HistoryAction int32
+
+const (
+	HistoryAction_HISTORY_ACTION_UNSPECIFIED HistoryAction = 0
+	HistoryAction_HISTORY_ACTION_CREATE      HistoryAction = 1
+	HistoryAction_HISTORY_ACTION_UPDATE      HistoryAction = 2
+	HistoryAction_HISTORY_ACTION_DELETE      HistoryAction = 3
+	HistoryAction_HISTORY_ACTION_UNDELETE    HistoryAction = 4
+	HistoryAction_HISTORY_ACTION_CHANGE_PLAN HistoryAction = 5
+	HistoryAction_HISTORY_ACTION_MERGE       HistoryAction = 6
+	HistoryAction_HISTORY_ACTION_CANCEL      HistoryAction = 7
+	HistoryAction_HISTORY_ACTION_PAUSE       HistoryAction = 8
+	HistoryAction_HISTORY_ACTION_RESUME      HistoryAction = 9
+	HistoryAction_HISTORY_ACTION_ACTIVATE    HistoryAction = 10
+	HistoryAction_HISTORY_ACTION_EXPIRE      HistoryAction = 11
+	HistoryAction_HISTORY_ACTION_PURGE       HistoryAction = 12
+)
+
+// Enum value maps for HistoryAction.
+var (
+	HistoryAction_name = map[int32]string{
+		0:  "HISTORY_ACTION_UNSPECIFIED",
+		1:  "HISTORY_ACTION_CREATE",
+		2:  "HISTORY_ACTION_UPDATE",
+		3:  "HISTORY_ACTION_DELETE",
+		4:  "HISTORY_ACTION_UNDELETE",
+		5:  "HISTORY_ACTION_CHANGE_PLAN",
+		6:  "HISTORY_ACTION_MERGE",
+		7:  "HISTORY_ACTION_CANCEL",
+		8:  "HISTORY_ACTION_PAUSE",
+		9:  "HISTORY_ACTION_RESUME",
+		10: "HISTORY_ACTION_ACTIVATE",
+		11: "HISTORY_ACTION_EXPIRE",
+		12: "HISTORY_ACTION_PURGE",
+	}
+	HistoryAction_value = map[string]int32{
+		"HISTORY_ACTION_UNSPECIFIED": 0,
+		"HISTORY_ACTION_CREATE":      1,
+		"HISTORY_ACTION_UPDATE":      2,
+		"HISTORY_ACTION_DELETE":      3,
+		"HISTORY_ACTION_UNDELETE":    4,
+		"HISTORY_ACTION_CHANGE_PLAN": 5,
+		"HISTORY_ACTION_MERGE":       6,
+		"HISTORY_ACTION_CANCEL":      7,
+		"HISTORY_ACTION_PAUSE":       8,
+		"HISTORY_ACTION_RESUME":      9,
+		"HISTORY_ACTION_ACTIVATE":    10,
+		"HISTORY_ACTION_EXPIRE":      11,
+		"HISTORY_ACTION_PURGE":       12,
+	}
+)
+
+func (x HistoryAction) Enum() *HistoryAction {
+	p := new(HistoryAction)
+	*p = x
+	return p
+}
+
+func (x HistoryAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HistoryAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[7].Descriptor()
+}
+
+func (HistoryAction) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[7]
+}
+
+func (x HistoryAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HistoryAction.Descriptor instead.
+func (HistoryAction) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{7}
+}
+
+type WebhookDeliveryStatus int32
+
+const (
+	WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_UNSPECIFIED WebhookDeliveryStatus = 0
+	// Доставка ждет очередной попытки
+	WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_PENDING   WebhookDeliveryStatus = 1
+	WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_SUCCEEDED WebhookDeliveryStatus = 2
+	// Все попытки исчерпаны, доставку можно повторить вручную
+	WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_FAILED WebhookDeliveryStatus = 3
+)
+
+// Enum value maps for WebhookDeliveryStatus.
+var (
+	WebhookDeliveryStatus_name = map[int32]string{
+		0: "WEBHOOK_DELIVERY_STATUS_UNSPECIFIED",
+		1: "WEBHOOK_DELIVERY_STATUS_PENDING",
+		2: "WEBHOOK_DELIVERY_STATUS_SUCCEEDED",
+		3: "WEBHOOK_DELIVERY_STATUS_FAILED",
+	}
+	WebhookDeliveryStatus_value = map[string]int32{
+		"WEBHOOK_DELIVERY_STATUS_UNSPECIFIED": 0,
+		"WEBHOOK_DELIVERY_STATUS_PENDING":     1,
+		"WEBHOOK_DELIVERY_STATUS_SUCCEEDED":   2,
+		"WEBHOOK_DELIVERY_STATUS_FAILED":      3,
+	}
+)
+
+func (x WebhookDeliveryStatus) Enum() *WebhookDeliveryStatus {
+	p := new(WebhookDeliveryStatus)
+	*p = x
+	return p
+}
+
+func (x WebhookDeliveryStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WebhookDeliveryStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_subscriptions_proto_enumTypes[8].Descriptor()
+}
+
+func (WebhookDeliveryStatus) Type() protoreflect.EnumType {
+	return &file_api_subscriptions_proto_enumTypes[8]
+}
+
+func (x WebhookDeliveryStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WebhookDeliveryStatus.Descriptor instead.
+func (WebhookDeliveryStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{8}
+}
+
 type AddSubscriptionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	Price         int32                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
-	StartDate     string                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	EndDate       *string                `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	UserId                string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName           string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price                 int32                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	StartDate             string                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate               *string                `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	Currency              *string                `protobuf:"bytes,6,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	BillingPeriod         *BillingPeriod         `protobuf:"varint,7,opt,name=billing_period,json=billingPeriod,proto3,enum=api.BillingPeriod,oneof" json:"billing_period,omitempty"`
+	BillingIntervalMonths *int32                 `protobuf:"varint,8,opt,name=billing_interval_months,json=billingIntervalMonths,proto3,oneof" json:"billing_interval_months,omitempty"`
+	RequestId             string                 `protobuf:"bytes,10,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	TrialMonths           *int32                 `protobuf:"varint,11,opt,name=trial_months,json=trialMonths,proto3,oneof" json:"trial_months,omitempty"`
+	// Льготные фазы следуют друг за другом после пробного периода, после них действует цена подписки
+	PromoPhases   []*PromoPhase `protobuf:"bytes,12,rep,name=promo_phases,json=promoPhases,proto3" json:"promo_phases,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -100,9 +622,52 @@ func (x *AddSubscriptionRequest) GetEndDate() string {
 	return ""
 }
 
+func (x *AddSubscriptionRequest) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *AddSubscriptionRequest) GetBillingPeriod() BillingPeriod {
+	if x != nil && x.BillingPeriod != nil {
+		return *x.BillingPeriod
+	}
+	return BillingPeriod_BILLING_PERIOD_UNSPECIFIED
+}
+
+func (x *AddSubscriptionRequest) GetBillingIntervalMonths() int32 {
+	if x != nil && x.BillingIntervalMonths != nil {
+		return *x.BillingIntervalMonths
+	}
+	return 0
+}
+
+func (x *AddSubscriptionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AddSubscriptionRequest) GetTrialMonths() int32 {
+	if x != nil && x.TrialMonths != nil {
+		return *x.TrialMonths
+	}
+	return 0
+}
+
+func (x *AddSubscriptionRequest) GetPromoPhases() []*PromoPhase {
+	if x != nil {
+		return x.PromoPhases
+	}
+	return nil
+}
+
 type AddSubscriptionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Overlaps      []*Subscription        `protobuf:"bytes,2,rep,name=overlaps,proto3" json:"overlaps,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -144,6 +709,13 @@ func (x *AddSubscriptionResponse) GetSubscription() *Subscription {
 	return nil
 }
 
+func (x *AddSubscriptionResponse) GetOverlaps() []*Subscription {
+	if x != nil {
+		return x.Overlaps
+	}
+	return nil
+}
+
 type GetSubscriptionRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
@@ -233,11 +805,23 @@ func (x *GetSubscriptionResponse) GetSubscription() *Subscription {
 }
 
 type GetSubscriptionsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Count         *int32                 `protobuf:"varint,1,opt,name=count,proto3,oneof" json:"count,omitempty"`
-	Page          *int32                 `protobuf:"varint,2,opt,name=page,proto3,oneof" json:"page,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Count            *int32                 `protobuf:"varint,1,opt,name=count,proto3,oneof" json:"count,omitempty"`
+	Page             *int32                 `protobuf:"varint,2,opt,name=page,proto3,oneof" json:"page,omitempty"`
+	UserId           *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName      *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	ServiceNameMatch *ServiceNameMatch      `protobuf:"varint,5,opt,name=service_name_match,json=serviceNameMatch,proto3,enum=api.ServiceNameMatch,oneof" json:"service_name_match,omitempty"`
+	ActiveAt         *string                `protobuf:"bytes,6,opt,name=active_at,json=activeAt,proto3,oneof" json:"active_at,omitempty"`
+	MinPrice         *int32                 `protobuf:"varint,7,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice         *int32                 `protobuf:"varint,8,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	OpenEndedOnly    *bool                  `protobuf:"varint,9,opt,name=open_ended_only,json=openEndedOnly,proto3,oneof" json:"open_ended_only,omitempty"`
+	OrderBy          *string                `protobuf:"bytes,10,opt,name=order_by,json=orderBy,proto3,oneof" json:"order_by,omitempty"`
+	PageSize         *int32                 `protobuf:"varint,11,opt,name=page_size,json=pageSize,proto3,oneof" json:"page_size,omitempty"`
+	PageToken        string                 `protobuf:"bytes,12,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	IncludeTotalSize bool                   `protobuf:"varint,13,opt,name=include_total_size,json=includeTotalSize,proto3" json:"include_total_size,omitempty"`
+	Deleted          *DeletedFilter         `protobuf:"varint,14,opt,name=deleted,proto3,enum=api.DeletedFilter,oneof" json:"deleted,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *GetSubscriptionsRequest) Reset() {
@@ -284,27 +868,115 @@ func (x *GetSubscriptionsRequest) GetPage() int32 {
 	return 0
 }
 
-type GetSubscriptionsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetSubscriptionsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
 }
 
-func (x *GetSubscriptionsResponse) Reset() {
-	*x = GetSubscriptionsResponse{}
-	mi := &file_api_subscriptions_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *GetSubscriptionsRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
 }
 
-func (x *GetSubscriptionsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetSubscriptionsRequest) GetServiceNameMatch() ServiceNameMatch {
+	if x != nil && x.ServiceNameMatch != nil {
+		return *x.ServiceNameMatch
+	}
+	return ServiceNameMatch_SERVICE_NAME_MATCH_UNSPECIFIED
 }
 
-func (*GetSubscriptionsResponse) ProtoMessage() {}
-
-func (x *GetSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+func (x *GetSubscriptionsRequest) GetActiveAt() string {
+	if x != nil && x.ActiveAt != nil {
+		return *x.ActiveAt
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsRequest) GetMinPrice() int32 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *GetSubscriptionsRequest) GetMaxPrice() int32 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *GetSubscriptionsRequest) GetOpenEndedOnly() bool {
+	if x != nil && x.OpenEndedOnly != nil {
+		return *x.OpenEndedOnly
+	}
+	return false
+}
+
+func (x *GetSubscriptionsRequest) GetOrderBy() string {
+	if x != nil && x.OrderBy != nil {
+		return *x.OrderBy
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsRequest) GetPageSize() int32 {
+	if x != nil && x.PageSize != nil {
+		return *x.PageSize
+	}
+	return 0
+}
+
+func (x *GetSubscriptionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsRequest) GetIncludeTotalSize() bool {
+	if x != nil {
+		return x.IncludeTotalSize
+	}
+	return false
+}
+
+func (x *GetSubscriptionsRequest) GetDeleted() DeletedFilter {
+	if x != nil && x.Deleted != nil {
+		return *x.Deleted
+	}
+	return DeletedFilter_DELETED_FILTER_UNSPECIFIED
+}
+
+type GetSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	// Токен следующей страницы, пустой на последней странице
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Общее количество подписок, подходящих под фильтр. Заполняется при include_total_size
+	TotalSize     *int64 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3,oneof" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionsResponse) Reset() {
+	*x = GetSubscriptionsResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionsResponse) ProtoMessage() {}
+
+func (x *GetSubscriptionsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_api_subscriptions_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -328,6 +1000,20 @@ func (x *GetSubscriptionsResponse) GetSubscriptions() []*Subscription {
 	return nil
 }
 
+func (x *GetSubscriptionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsResponse) GetTotalSize() int64 {
+	if x != nil && x.TotalSize != nil {
+		return *x.TotalSize
+	}
+	return 0
+}
+
 type UpdateSubscriptionRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
@@ -336,8 +1022,18 @@ type UpdateSubscriptionRequest struct {
 	Price          *int32                 `protobuf:"varint,4,opt,name=price,proto3,oneof" json:"price,omitempty"`
 	StartDate      *string                `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3,oneof" json:"start_date,omitempty"`
 	EndDate        *string                `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Валюта и периодичность не меняются после создания: новое значение отклоняется ошибкой FAILED_PRECONDITION
+	Currency              *string        `protobuf:"bytes,7,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	BillingPeriod         *BillingPeriod `protobuf:"varint,8,opt,name=billing_period,json=billingPeriod,proto3,enum=api.BillingPeriod,oneof" json:"billing_period,omitempty"`
+	BillingIntervalMonths *int32         `protobuf:"varint,9,opt,name=billing_interval_months,json=billingIntervalMonths,proto3,oneof" json:"billing_interval_months,omitempty"`
+	// Список изменяемых полей (AIP-134). Поле из маски, не переданное в запросе, очищается, если это допустимо
+	// (например, end_date). Без маски изменяются только переданные поля, "*" заменяет подписку целиком
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,10,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	RequestId  string                 `protobuf:"bytes,12,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Если ETag не совпадает с текущим, подписка не изменяется и возвращается ABORTED (HTTP 412)
+	Etag          string `protobuf:"bytes,13,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateSubscriptionRequest) Reset() {
@@ -412,9 +1108,52 @@ func (x *UpdateSubscriptionRequest) GetEndDate() string {
 	return ""
 }
 
+func (x *UpdateSubscriptionRequest) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetBillingPeriod() BillingPeriod {
+	if x != nil && x.BillingPeriod != nil {
+		return *x.BillingPeriod
+	}
+	return BillingPeriod_BILLING_PERIOD_UNSPECIFIED
+}
+
+func (x *UpdateSubscriptionRequest) GetBillingIntervalMonths() int32 {
+	if x != nil && x.BillingIntervalMonths != nil {
+		return *x.BillingIntervalMonths
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
 type UpdateSubscriptionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Overlaps      []*Subscription        `protobuf:"bytes,2,rep,name=overlaps,proto3" json:"overlaps,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -456,11 +1195,21 @@ func (x *UpdateSubscriptionResponse) GetSubscription() *Subscription {
 	return nil
 }
 
+func (x *UpdateSubscriptionResponse) GetOverlaps() []*Subscription {
+	if x != nil {
+		return x.Overlaps
+	}
+	return nil
+}
+
 type DeleteSubscriptionRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	RequestId      string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Если ETag не совпадает с текущим, подписка не удаляется и возвращается ABORTED (HTTP 412)
+	Etag          string `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteSubscriptionRequest) Reset() {
@@ -500,6 +1249,20 @@ func (x *DeleteSubscriptionRequest) GetSubscriptionId() string {
 	return ""
 }
 
+func (x *DeleteSubscriptionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *DeleteSubscriptionRequest) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
 type DeleteSubscriptionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -536,30 +1299,28 @@ func (*DeleteSubscriptionResponse) Descriptor() ([]byte, []int) {
 	return file_api_subscriptions_proto_rawDescGZIP(), []int{9}
 }
 
-type GetSumSubscriptionsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
-	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
-	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type UndeleteSubscriptionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	RequestId      string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *GetSumSubscriptionsRequest) Reset() {
-	*x = GetSumSubscriptionsRequest{}
+func (x *UndeleteSubscriptionRequest) Reset() {
+	*x = UndeleteSubscriptionRequest{}
 	mi := &file_api_subscriptions_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSumSubscriptionsRequest) String() string {
+func (x *UndeleteSubscriptionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSumSubscriptionsRequest) ProtoMessage() {}
+func (*UndeleteSubscriptionRequest) ProtoMessage() {}
 
-func (x *GetSumSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+func (x *UndeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_subscriptions_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -571,60 +1332,46 @@ func (x *GetSumSubscriptionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSumSubscriptionsRequest.ProtoReflect.Descriptor instead.
-func (*GetSumSubscriptionsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UndeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UndeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
 	return file_api_subscriptions_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetSumSubscriptionsRequest) GetStartDate() string {
+func (x *UndeleteSubscriptionRequest) GetSubscriptionId() string {
 	if x != nil {
-		return x.StartDate
+		return x.SubscriptionId
 	}
 	return ""
 }
 
-func (x *GetSumSubscriptionsRequest) GetEndDate() string {
+func (x *UndeleteSubscriptionRequest) GetRequestId() string {
 	if x != nil {
-		return x.EndDate
-	}
-	return ""
-}
-
-func (x *GetSumSubscriptionsRequest) GetUserId() string {
-	if x != nil && x.UserId != nil {
-		return *x.UserId
-	}
-	return ""
-}
-
-func (x *GetSumSubscriptionsRequest) GetServiceName() string {
-	if x != nil && x.ServiceName != nil {
-		return *x.ServiceName
+		return x.RequestId
 	}
 	return ""
 }
 
-type GetSumSubscriptionsResponse struct {
+type UndeleteSubscriptionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TotalSum      int32                  `protobuf:"varint,1,opt,name=total_sum,json=totalSum,proto3" json:"total_sum,omitempty"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSumSubscriptionsResponse) Reset() {
-	*x = GetSumSubscriptionsResponse{}
+func (x *UndeleteSubscriptionResponse) Reset() {
+	*x = UndeleteSubscriptionResponse{}
 	mi := &file_api_subscriptions_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSumSubscriptionsResponse) String() string {
+func (x *UndeleteSubscriptionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSumSubscriptionsResponse) ProtoMessage() {}
+func (*UndeleteSubscriptionResponse) ProtoMessage() {}
 
-func (x *GetSumSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+func (x *UndeleteSubscriptionResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_api_subscriptions_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -636,44 +1383,43 @@ func (x *GetSumSubscriptionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSumSubscriptionsResponse.ProtoReflect.Descriptor instead.
-func (*GetSumSubscriptionsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UndeleteSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*UndeleteSubscriptionResponse) Descriptor() ([]byte, []int) {
 	return file_api_subscriptions_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetSumSubscriptionsResponse) GetTotalSum() int32 {
+func (x *UndeleteSubscriptionResponse) GetSubscription() *Subscription {
 	if x != nil {
-		return x.TotalSum
+		return x.Subscription
 	}
-	return 0
+	return nil
 }
 
-type Subscription struct {
+type ChangeSubscriptionPlanRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
-	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ServiceName    string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
-	Price          int32                  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
-	StartDate      string                 `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	EndDate        *string                `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	EffectiveDate  string                 `protobuf:"bytes,2,opt,name=effective_date,json=effectiveDate,proto3" json:"effective_date,omitempty"`
+	Price          int32                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	ServiceName    *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	RequestId      string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *Subscription) Reset() {
-	*x = Subscription{}
+func (x *ChangeSubscriptionPlanRequest) Reset() {
+	*x = ChangeSubscriptionPlanRequest{}
 	mi := &file_api_subscriptions_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Subscription) String() string {
+func (x *ChangeSubscriptionPlanRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Subscription) ProtoMessage() {}
+func (*ChangeSubscriptionPlanRequest) ProtoMessage() {}
 
-func (x *Subscription) ProtoReflect() protoreflect.Message {
+func (x *ChangeSubscriptionPlanRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_subscriptions_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -685,109 +1431,5425 @@ func (x *Subscription) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
-func (*Subscription) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChangeSubscriptionPlanRequest.ProtoReflect.Descriptor instead.
+func (*ChangeSubscriptionPlanRequest) Descriptor() ([]byte, []int) {
 	return file_api_subscriptions_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *Subscription) GetSubscriptionId() string {
+func (x *ChangeSubscriptionPlanRequest) GetSubscriptionId() string {
 	if x != nil {
 		return x.SubscriptionId
 	}
 	return ""
 }
 
-func (x *Subscription) GetUserId() string {
+func (x *ChangeSubscriptionPlanRequest) GetEffectiveDate() string {
 	if x != nil {
-		return x.UserId
+		return x.EffectiveDate
 	}
 	return ""
 }
 
-func (x *Subscription) GetServiceName() string {
+func (x *ChangeSubscriptionPlanRequest) GetPrice() int32 {
 	if x != nil {
-		return x.ServiceName
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ChangeSubscriptionPlanRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
 	}
 	return ""
 }
 
-func (x *Subscription) GetPrice() int32 {
+func (x *ChangeSubscriptionPlanRequest) GetRequestId() string {
 	if x != nil {
-		return x.Price
+		return x.RequestId
 	}
-	return 0
+	return ""
 }
 
-func (x *Subscription) GetStartDate() string {
+type ChangeSubscriptionPlanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeSubscriptionPlanResponse) Reset() {
+	*x = ChangeSubscriptionPlanResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeSubscriptionPlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeSubscriptionPlanResponse) ProtoMessage() {}
+
+func (x *ChangeSubscriptionPlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[13]
 	if x != nil {
-		return x.StartDate
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Subscription) GetEndDate() string {
-	if x != nil && x.EndDate != nil {
-		return *x.EndDate
+// Deprecated: Use ChangeSubscriptionPlanResponse.ProtoReflect.Descriptor instead.
+func (*ChangeSubscriptionPlanResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ChangeSubscriptionPlanResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
 	}
-	return ""
+	return nil
 }
 
-var File_api_subscriptions_proto protoreflect.FileDescriptor
+type GetSubscriptionPlansRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
 
-const file_api_subscriptions_proto_rawDesc = "" +
-	"\n" +
-	"\x17api/subscriptions.proto\x12\x03api\x1a\x1cgoogle/api/annotations.proto\x1a\x1bbuf/validate/validate.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xf6\x03\n" +
-	"\x16AddSubscriptionRequest\x12D\n" +
-	"\auser_id\x18\x01 \x01(\tB+\x92A\x1d*\x1bID пользователя\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x06userId\x12[\n" +
-	"\fservice_name\x18\x02 \x01(\tB8\x92A+*)Наименование подписки\xbaH\a\xc8\x01\x01r\x02\x10\x03R\vserviceName\x12H\n" +
-	"\x05price\x18\x03 \x01(\x05B2\x92A%*#Стоимость подписки\xbaH\a\xc8\x01\x01\x1a\x02(\x00R\x05price\x12n\n" +
-	"\n" +
-	"start_date\x18\x04 \x01(\tBO\x92A(*&Дата старта подписки\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12r\n" +
-	"\bend_date\x18\x05 \x01(\tBR\x92A.*,Дата окончания подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x00R\aendDate\x88\x01\x01B\v\n" +
-	"\t_end_date\"P\n" +
-	"\x17AddSubscriptionResponse\x125\n" +
-	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"f\n" +
-	"\x16GetSubscriptionRequest\x12L\n" +
-	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\"P\n" +
-	"\x17GetSubscriptionResponse\x125\n" +
-	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"\xd2\x01\n" +
-	"\x17GetSubscriptionsRequest\x12b\n" +
-	"\x05count\x18\x01 \x01(\x05BG\x92A=*;Количество подписок на страницу\xbaH\x04\x1a\x02 \x00H\x00R\x05count\x88\x01\x01\x12@\n" +
-	"\x04page\x18\x02 \x01(\x05B'\x92A\x1d*\x1bНомер страницы\xbaH\x04\x1a\x02 \x00H\x01R\x04page\x88\x01\x01B\b\n" +
-	"\x06_countB\a\n" +
-	"\x05_page\"S\n" +
-	"\x18GetSubscriptionsResponse\x127\n" +
-	"\rsubscriptions\x18\x01 \x03(\v2\x11.api.SubscriptionR\rsubscriptions\"\x85\x05\n" +
-	"\x19UpdateSubscriptionRequest\x12L\n" +
-	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12F\n" +
-	"\auser_id\x18\x02 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
-	"\fservice_name\x18\x03 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12J\n" +
-	"\x05price\x18\x04 \x01(\x05B/\x92A%*#Стоимость подписки\xbaH\x04\x1a\x02(\x00H\x02R\x05price\x88\x01\x01\x12p\n" +
-	"\n" +
-	"start_date\x18\x05 \x01(\tBL\x92A(*&Дата старта подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x03R\tstartDate\x88\x01\x01\x12r\n" +
-	"\bend_date\x18\x06 \x01(\tBR\x92A.*,Дата окончания подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x04R\aendDate\x88\x01\x01B\n" +
-	"\n" +
-	"\b_user_idB\x0f\n" +
+func (x *GetSubscriptionPlansRequest) Reset() {
+	*x = GetSubscriptionPlansRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionPlansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionPlansRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionPlansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionPlansRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionPlansRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetSubscriptionPlansRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+type GetSubscriptionPlansResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plans         []*SubscriptionPlan    `protobuf:"bytes,1,rep,name=plans,proto3" json:"plans,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionPlansResponse) Reset() {
+	*x = GetSubscriptionPlansResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionPlansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionPlansResponse) ProtoMessage() {}
+
+func (x *GetSubscriptionPlansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionPlansResponse.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionPlansResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetSubscriptionPlansResponse) GetPlans() []*SubscriptionPlan {
+	if x != nil {
+		return x.Plans
+	}
+	return nil
+}
+
+type SubscriptionPlan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ValidFrom     string                 `protobuf:"bytes,1,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidTo       *string                `protobuf:"bytes,2,opt,name=valid_to,json=validTo,proto3,oneof" json:"valid_to,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price         int32                  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	Phase         PlanPhase              `protobuf:"varint,6,opt,name=phase,proto3,enum=api.PlanPhase" json:"phase,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionPlan) Reset() {
+	*x = SubscriptionPlan{}
+	mi := &file_api_subscriptions_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionPlan) ProtoMessage() {}
+
+func (x *SubscriptionPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionPlan.ProtoReflect.Descriptor instead.
+func (*SubscriptionPlan) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SubscriptionPlan) GetValidFrom() string {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return ""
+}
+
+func (x *SubscriptionPlan) GetValidTo() string {
+	if x != nil && x.ValidTo != nil {
+		return *x.ValidTo
+	}
+	return ""
+}
+
+func (x *SubscriptionPlan) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *SubscriptionPlan) GetPrice() int32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *SubscriptionPlan) GetPhase() PlanPhase {
+	if x != nil {
+		return x.Phase
+	}
+	return PlanPhase_PLAN_PHASE_UNSPECIFIED
+}
+
+type GetSumSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Currency      *string                `protobuf:"bytes,5,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSumSubscriptionsRequest) Reset() {
+	*x = GetSumSubscriptionsRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSumSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSumSubscriptionsRequest) ProtoMessage() {}
+
+func (x *GetSumSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSumSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*GetSumSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetSumSubscriptionsRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsRequest) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+type GetSumSubscriptionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Если сумма в целых единицах не помещается в int32, возвращается OUT_OF_RANGE: точная сумма есть в GetSumSubscriptionsV2
+	TotalSum      int32           `protobuf:"varint,1,opt,name=total_sum,json=totalSum,proto3" json:"total_sum,omitempty"`
+	Currency      string          `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Rates         []*ExchangeRate `protobuf:"bytes,3,rep,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSumSubscriptionsResponse) Reset() {
+	*x = GetSumSubscriptionsResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSumSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSumSubscriptionsResponse) ProtoMessage() {}
+
+func (x *GetSumSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSumSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*GetSumSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetSumSubscriptionsResponse) GetTotalSum() int32 {
+	if x != nil {
+		return x.TotalSum
+	}
+	return 0
+}
+
+func (x *GetSumSubscriptionsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsResponse) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type GetSpendTimeSeriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Currency      *string                `protobuf:"bytes,5,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	Granularity   Granularity            `protobuf:"varint,6,opt,name=granularity,proto3,enum=api.Granularity" json:"granularity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendTimeSeriesRequest) Reset() {
+	*x = GetSpendTimeSeriesRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendTimeSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendTimeSeriesRequest) ProtoMessage() {}
+
+func (x *GetSpendTimeSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendTimeSeriesRequest.ProtoReflect.Descriptor instead.
+func (*GetSpendTimeSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetSpendTimeSeriesRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesRequest) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesRequest) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_UNSPECIFIED
+}
+
+type GetSpendTimeSeriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*SpendBucket         `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	TotalSum      int64                  `protobuf:"varint,2,opt,name=total_sum,json=totalSum,proto3" json:"total_sum,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Rates         []*ExchangeRate        `protobuf:"bytes,4,rep,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendTimeSeriesResponse) Reset() {
+	*x = GetSpendTimeSeriesResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendTimeSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendTimeSeriesResponse) ProtoMessage() {}
+
+func (x *GetSpendTimeSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendTimeSeriesResponse.ProtoReflect.Descriptor instead.
+func (*GetSpendTimeSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetSpendTimeSeriesResponse) GetBuckets() []*SpendBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetSpendTimeSeriesResponse) GetTotalSum() int64 {
+	if x != nil {
+		return x.TotalSum
+	}
+	return 0
+}
+
+func (x *GetSpendTimeSeriesResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesResponse) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type SpendBucket struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	StartDate           string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate             string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Amount              int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	ActiveSubscriptions int32                  `protobuf:"varint,4,opt,name=active_subscriptions,json=activeSubscriptions,proto3" json:"active_subscriptions,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SpendBucket) Reset() {
+	*x = SpendBucket{}
+	mi := &file_api_subscriptions_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpendBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpendBucket) ProtoMessage() {}
+
+func (x *SpendBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpendBucket.ProtoReflect.Descriptor instead.
+func (*SpendBucket) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SpendBucket) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *SpendBucket) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *SpendBucket) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SpendBucket) GetActiveSubscriptions() int32 {
+	if x != nil {
+		return x.ActiveSubscriptions
+	}
+	return 0
+}
+
+type GetSpendBreakdownRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Currency      *string                `protobuf:"bytes,5,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	GroupBy       BreakdownDimension     `protobuf:"varint,6,opt,name=group_by,json=groupBy,proto3,enum=api.BreakdownDimension" json:"group_by,omitempty"`
+	Limit         *int32                 `protobuf:"varint,7,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendBreakdownRequest) Reset() {
+	*x = GetSpendBreakdownRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendBreakdownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendBreakdownRequest) ProtoMessage() {}
+
+func (x *GetSpendBreakdownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendBreakdownRequest.ProtoReflect.Descriptor instead.
+func (*GetSpendBreakdownRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetSpendBreakdownRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownRequest) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownRequest) GetGroupBy() BreakdownDimension {
+	if x != nil {
+		return x.GroupBy
+	}
+	return BreakdownDimension_BREAKDOWN_DIMENSION_UNSPECIFIED
+}
+
+func (x *GetSpendBreakdownRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type GetSpendBreakdownResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*SpendBreakdownRow   `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	TotalSum      int64                  `protobuf:"varint,2,opt,name=total_sum,json=totalSum,proto3" json:"total_sum,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Rates         []*ExchangeRate        `protobuf:"bytes,4,rep,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendBreakdownResponse) Reset() {
+	*x = GetSpendBreakdownResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendBreakdownResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendBreakdownResponse) ProtoMessage() {}
+
+func (x *GetSpendBreakdownResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendBreakdownResponse.ProtoReflect.Descriptor instead.
+func (*GetSpendBreakdownResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetSpendBreakdownResponse) GetRows() []*SpendBreakdownRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *GetSpendBreakdownResponse) GetTotalSum() int64 {
+	if x != nil {
+		return x.TotalSum
+	}
+	return 0
+}
+
+func (x *GetSpendBreakdownResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownResponse) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type SpendBreakdownRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Share         float64                `protobuf:"fixed64,3,opt,name=share,proto3" json:"share,omitempty"`
+	Subscriptions int32                  `protobuf:"varint,4,opt,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	Other         bool                   `protobuf:"varint,5,opt,name=other,proto3" json:"other,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpendBreakdownRow) Reset() {
+	*x = SpendBreakdownRow{}
+	mi := &file_api_subscriptions_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpendBreakdownRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpendBreakdownRow) ProtoMessage() {}
+
+func (x *SpendBreakdownRow) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpendBreakdownRow.ProtoReflect.Descriptor instead.
+func (*SpendBreakdownRow) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SpendBreakdownRow) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SpendBreakdownRow) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SpendBreakdownRow) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *SpendBreakdownRow) GetSubscriptions() int32 {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return 0
+}
+
+func (x *SpendBreakdownRow) GetOther() bool {
+	if x != nil {
+		return x.Other
+	}
+	return false
+}
+
+type ExchangeRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	ValidFrom     string                 `protobuf:"bytes,2,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	Rate          string                 `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeRate) Reset() {
+	*x = ExchangeRate{}
+	mi := &file_api_subscriptions_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeRate) ProtoMessage() {}
+
+func (x *ExchangeRate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeRate.ProtoReflect.Descriptor instead.
+func (*ExchangeRate) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ExchangeRate) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ExchangeRate) GetValidFrom() string {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return ""
+}
+
+func (x *ExchangeRate) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+type Subscription struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId        string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	UserId                string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName           string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price                 int32                  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	StartDate             string                 `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate               *string                `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	Currency              string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	BillingPeriod         BillingPeriod          `protobuf:"varint,8,opt,name=billing_period,json=billingPeriod,proto3,enum=api.BillingPeriod" json:"billing_period,omitempty"`
+	BillingIntervalMonths *int32                 `protobuf:"varint,9,opt,name=billing_interval_months,json=billingIntervalMonths,proto3,oneof" json:"billing_interval_months,omitempty"`
+	Status                SubscriptionStatus     `protobuf:"varint,11,opt,name=status,proto3,enum=api.SubscriptionStatus" json:"status,omitempty"`
+	TrialEndDate          *string                `protobuf:"bytes,12,opt,name=trial_end_date,json=trialEndDate,proto3,oneof" json:"trial_end_date,omitempty"`
+	DeletedAt             *string                `protobuf:"bytes,13,opt,name=deleted_at,json=deletedAt,proto3,oneof" json:"deleted_at,omitempty"`
+	Version               int64                  `protobuf:"varint,14,opt,name=version,proto3" json:"version,omitempty"`
+	Etag                  string                 `protobuf:"bytes,15,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_api_subscriptions_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Subscription) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *Subscription) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Subscription) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Subscription) GetPrice() int32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Subscription) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *Subscription) GetEndDate() string {
+	if x != nil && x.EndDate != nil {
+		return *x.EndDate
+	}
+	return ""
+}
+
+func (x *Subscription) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Subscription) GetBillingPeriod() BillingPeriod {
+	if x != nil {
+		return x.BillingPeriod
+	}
+	return BillingPeriod_BILLING_PERIOD_UNSPECIFIED
+}
+
+func (x *Subscription) GetBillingIntervalMonths() int32 {
+	if x != nil && x.BillingIntervalMonths != nil {
+		return *x.BillingIntervalMonths
+	}
+	return 0
+}
+
+func (x *Subscription) GetStatus() SubscriptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED
+}
+
+func (x *Subscription) GetTrialEndDate() string {
+	if x != nil && x.TrialEndDate != nil {
+		return *x.TrialEndDate
+	}
+	return ""
+}
+
+func (x *Subscription) GetDeletedAt() string {
+	if x != nil && x.DeletedAt != nil {
+		return *x.DeletedAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Subscription) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+// Денежная сумма API v2 в минимальных единицах валюты (копейках, центах; для валют без дробной части - в целых единицах)
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	AmountMinor   int64                  `protobuf:"varint,2,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_api_subscriptions_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Money) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+type ListSubscriptionOverlapsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionOverlapsRequest) Reset() {
+	*x = ListSubscriptionOverlapsRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionOverlapsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionOverlapsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionOverlapsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionOverlapsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionOverlapsRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListSubscriptionOverlapsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *ListSubscriptionOverlapsRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+type ListSubscriptionOverlapsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Overlaps      []*SubscriptionOverlap `protobuf:"bytes,1,rep,name=overlaps,proto3" json:"overlaps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionOverlapsResponse) Reset() {
+	*x = ListSubscriptionOverlapsResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionOverlapsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionOverlapsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionOverlapsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionOverlapsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionOverlapsResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListSubscriptionOverlapsResponse) GetOverlaps() []*SubscriptionOverlap {
+	if x != nil {
+		return x.Overlaps
+	}
+	return nil
+}
+
+// Пара подписок одного пользователя на один сервис с пересекающимися периодами
+type SubscriptionOverlap struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Overlapping   *Subscription          `protobuf:"bytes,2,opt,name=overlapping,proto3" json:"overlapping,omitempty"`
+	OverlapStart  string                 `protobuf:"bytes,3,opt,name=overlap_start,json=overlapStart,proto3" json:"overlap_start,omitempty"`
+	OverlapEnd    *string                `protobuf:"bytes,4,opt,name=overlap_end,json=overlapEnd,proto3,oneof" json:"overlap_end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionOverlap) Reset() {
+	*x = SubscriptionOverlap{}
+	mi := &file_api_subscriptions_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionOverlap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionOverlap) ProtoMessage() {}
+
+func (x *SubscriptionOverlap) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionOverlap.ProtoReflect.Descriptor instead.
+func (*SubscriptionOverlap) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SubscriptionOverlap) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *SubscriptionOverlap) GetOverlapping() *Subscription {
+	if x != nil {
+		return x.Overlapping
+	}
+	return nil
+}
+
+func (x *SubscriptionOverlap) GetOverlapStart() string {
+	if x != nil {
+		return x.OverlapStart
+	}
+	return ""
+}
+
+func (x *SubscriptionOverlap) GetOverlapEnd() string {
+	if x != nil && x.OverlapEnd != nil {
+		return *x.OverlapEnd
+	}
+	return ""
+}
+
+type MergeSubscriptionsRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId        string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	MergedSubscriptionIds []string               `protobuf:"bytes,2,rep,name=merged_subscription_ids,json=mergedSubscriptionIds,proto3" json:"merged_subscription_ids,omitempty"`
+	RequestId             string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *MergeSubscriptionsRequest) Reset() {
+	*x = MergeSubscriptionsRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeSubscriptionsRequest) ProtoMessage() {}
+
+func (x *MergeSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*MergeSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MergeSubscriptionsRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *MergeSubscriptionsRequest) GetMergedSubscriptionIds() []string {
+	if x != nil {
+		return x.MergedSubscriptionIds
+	}
+	return nil
+}
+
+func (x *MergeSubscriptionsRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type MergeSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeSubscriptionsResponse) Reset() {
+	*x = MergeSubscriptionsResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeSubscriptionsResponse) ProtoMessage() {}
+
+func (x *MergeSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*MergeSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *MergeSubscriptionsResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type CancelSubscriptionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	// По умолчанию - текущий месяц
+	EffectiveDate *string `protobuf:"bytes,2,opt,name=effective_date,json=effectiveDate,proto3,oneof" json:"effective_date,omitempty"`
+	RequestId     string  `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelSubscriptionRequest) Reset() {
+	*x = CancelSubscriptionRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelSubscriptionRequest) ProtoMessage() {}
+
+func (x *CancelSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CancelSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CancelSubscriptionRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *CancelSubscriptionRequest) GetEffectiveDate() string {
+	if x != nil && x.EffectiveDate != nil {
+		return *x.EffectiveDate
+	}
+	return ""
+}
+
+func (x *CancelSubscriptionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type CancelSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelSubscriptionResponse) Reset() {
+	*x = CancelSubscriptionResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelSubscriptionResponse) ProtoMessage() {}
+
+func (x *CancelSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CancelSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CancelSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type PauseSubscriptionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	// По умолчанию - текущий месяц
+	EffectiveDate *string `protobuf:"bytes,2,opt,name=effective_date,json=effectiveDate,proto3,oneof" json:"effective_date,omitempty"`
+	RequestId     string  `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseSubscriptionRequest) Reset() {
+	*x = PauseSubscriptionRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseSubscriptionRequest) ProtoMessage() {}
+
+func (x *PauseSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*PauseSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PauseSubscriptionRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *PauseSubscriptionRequest) GetEffectiveDate() string {
+	if x != nil && x.EffectiveDate != nil {
+		return *x.EffectiveDate
+	}
+	return ""
+}
+
+func (x *PauseSubscriptionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type PauseSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseSubscriptionResponse) Reset() {
+	*x = PauseSubscriptionResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseSubscriptionResponse) ProtoMessage() {}
+
+func (x *PauseSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*PauseSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *PauseSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type ResumeSubscriptionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	// По умолчанию - текущий месяц
+	EffectiveDate *string `protobuf:"bytes,2,opt,name=effective_date,json=effectiveDate,proto3,oneof" json:"effective_date,omitempty"`
+	RequestId     string  `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeSubscriptionRequest) Reset() {
+	*x = ResumeSubscriptionRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeSubscriptionRequest) ProtoMessage() {}
+
+func (x *ResumeSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*ResumeSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ResumeSubscriptionRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *ResumeSubscriptionRequest) GetEffectiveDate() string {
+	if x != nil && x.EffectiveDate != nil {
+		return *x.EffectiveDate
+	}
+	return ""
+}
+
+func (x *ResumeSubscriptionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type ResumeSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeSubscriptionResponse) Reset() {
+	*x = ResumeSubscriptionResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeSubscriptionResponse) ProtoMessage() {}
+
+func (x *ResumeSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*ResumeSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ResumeSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+// Льготная фаза: months месяцев по цене price в валюте подписки
+type PromoPhase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Months        int32                  `protobuf:"varint,1,opt,name=months,proto3" json:"months,omitempty"`
+	Price         int32                  `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromoPhase) Reset() {
+	*x = PromoPhase{}
+	mi := &file_api_subscriptions_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromoPhase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoPhase) ProtoMessage() {}
+
+func (x *PromoPhase) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoPhase.ProtoReflect.Descriptor instead.
+func (*PromoPhase) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PromoPhase) GetMonths() int32 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *PromoPhase) GetPrice() int32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+type ListEndingTrialsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// По умолчанию - 7 дней
+	WithinDays    *int32  `protobuf:"varint,1,opt,name=within_days,json=withinDays,proto3,oneof" json:"within_days,omitempty"`
+	UserId        *string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEndingTrialsRequest) Reset() {
+	*x = ListEndingTrialsRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndingTrialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndingTrialsRequest) ProtoMessage() {}
+
+func (x *ListEndingTrialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndingTrialsRequest.ProtoReflect.Descriptor instead.
+func (*ListEndingTrialsRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListEndingTrialsRequest) GetWithinDays() int32 {
+	if x != nil && x.WithinDays != nil {
+		return *x.WithinDays
+	}
+	return 0
+}
+
+func (x *ListEndingTrialsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+type ListEndingTrialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trials        []*EndingTrial         `protobuf:"bytes,1,rep,name=trials,proto3" json:"trials,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEndingTrialsResponse) Reset() {
+	*x = ListEndingTrialsResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEndingTrialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndingTrialsResponse) ProtoMessage() {}
+
+func (x *ListEndingTrialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndingTrialsResponse.ProtoReflect.Descriptor instead.
+func (*ListEndingTrialsResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListEndingTrialsResponse) GetTrials() []*EndingTrial {
+	if x != nil {
+		return x.Trials
+	}
+	return nil
+}
+
+type EndingTrial struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	TrialEndsOn   string                 `protobuf:"bytes,2,opt,name=trial_ends_on,json=trialEndsOn,proto3" json:"trial_ends_on,omitempty"`
+	DaysLeft      int32                  `protobuf:"varint,3,opt,name=days_left,json=daysLeft,proto3" json:"days_left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndingTrial) Reset() {
+	*x = EndingTrial{}
+	mi := &file_api_subscriptions_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndingTrial) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndingTrial) ProtoMessage() {}
+
+func (x *EndingTrial) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndingTrial.ProtoReflect.Descriptor instead.
+func (*EndingTrial) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *EndingTrial) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *EndingTrial) GetTrialEndsOn() string {
+	if x != nil {
+		return x.TrialEndsOn
+	}
+	return ""
+}
+
+func (x *EndingTrial) GetDaysLeft() int32 {
+	if x != nil {
+		return x.DaysLeft
+	}
+	return 0
+}
+
+type ListSubscriptionHistoryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionHistoryRequest) Reset() {
+	*x = ListSubscriptionHistoryRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionHistoryRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListSubscriptionHistoryRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+type ListSubscriptionHistoryResponse struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Entries       []*SubscriptionHistoryEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionHistoryResponse) Reset() {
+	*x = ListSubscriptionHistoryResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionHistoryResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListSubscriptionHistoryResponse) GetEntries() []*SubscriptionHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type SubscriptionHistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action        HistoryAction          `protobuf:"varint,2,opt,name=action,proto3,enum=api.HistoryAction" json:"action,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	RequestId     *string                `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3,oneof" json:"request_id,omitempty"`
+	ChangedAt     string                 `protobuf:"bytes,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	Changes       []*FieldChange         `protobuf:"bytes,6,rep,name=changes,proto3" json:"changes,omitempty"`
+	Before        *Subscription          `protobuf:"bytes,7,opt,name=before,proto3,oneof" json:"before,omitempty"`
+	After         *Subscription          `protobuf:"bytes,8,opt,name=after,proto3,oneof" json:"after,omitempty"`
+	ClaimedActor  *string                `protobuf:"bytes,9,opt,name=claimed_actor,json=claimedActor,proto3,oneof" json:"claimed_actor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionHistoryEntry) Reset() {
+	*x = SubscriptionHistoryEntry{}
+	mi := &file_api_subscriptions_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionHistoryEntry) ProtoMessage() {}
+
+func (x *SubscriptionHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionHistoryEntry.ProtoReflect.Descriptor instead.
+func (*SubscriptionHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SubscriptionHistoryEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SubscriptionHistoryEntry) GetAction() HistoryAction {
+	if x != nil {
+		return x.Action
+	}
+	return HistoryAction_HISTORY_ACTION_UNSPECIFIED
+}
+
+func (x *SubscriptionHistoryEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *SubscriptionHistoryEntry) GetRequestId() string {
+	if x != nil && x.RequestId != nil {
+		return *x.RequestId
+	}
+	return ""
+}
+
+func (x *SubscriptionHistoryEntry) GetChangedAt() string {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return ""
+}
+
+func (x *SubscriptionHistoryEntry) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *SubscriptionHistoryEntry) GetBefore() *Subscription {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *SubscriptionHistoryEntry) GetAfter() *Subscription {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *SubscriptionHistoryEntry) GetClaimedActor() string {
+	if x != nil && x.ClaimedActor != nil {
+		return *x.ClaimedActor
+	}
+	return ""
+}
+
+type FieldChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Before        string                 `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+	After         string                 `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_api_subscriptions_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *FieldChange) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldChange) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *FieldChange) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+type WatchSubscriptionsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId *string                `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	// Если событие уже удалено из outbox, возвращается OUT_OF_RANGE: клиент должен заново загрузить подписки
+	AfterEventId  *int64 `protobuf:"varint,2,opt,name=after_event_id,json=afterEventId,proto3,oneof" json:"after_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSubscriptionsRequest) Reset() {
+	*x = WatchSubscriptionsRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSubscriptionsRequest) ProtoMessage() {}
+
+func (x *WatchSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *WatchSubscriptionsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *WatchSubscriptionsRequest) GetAfterEventId() int64 {
+	if x != nil && x.AfterEventId != nil {
+		return *x.AfterEventId
+	}
+	return 0
+}
+
+type SubscriptionEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EventId        int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,3,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Action         HistoryAction          `protobuf:"varint,4,opt,name=action,proto3,enum=api.HistoryAction" json:"action,omitempty"`
+	Subscription   *Subscription          `protobuf:"bytes,5,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Changes        []*FieldChange         `protobuf:"bytes,6,rep,name=changes,proto3" json:"changes,omitempty"`
+	Actor          string                 `protobuf:"bytes,7,opt,name=actor,proto3" json:"actor,omitempty"`
+	RequestId      *string                `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3,oneof" json:"request_id,omitempty"`
+	OccurredAt     string                 `protobuf:"bytes,9,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SubscriptionEvent) Reset() {
+	*x = SubscriptionEvent{}
+	mi := &file_api_subscriptions_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionEvent) ProtoMessage() {}
+
+func (x *SubscriptionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionEvent.ProtoReflect.Descriptor instead.
+func (*SubscriptionEvent) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SubscriptionEvent) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *SubscriptionEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SubscriptionEvent) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *SubscriptionEvent) GetAction() HistoryAction {
+	if x != nil {
+		return x.Action
+	}
+	return HistoryAction_HISTORY_ACTION_UNSPECIFIED
+}
+
+func (x *SubscriptionEvent) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *SubscriptionEvent) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *SubscriptionEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *SubscriptionEvent) GetRequestId() string {
+	if x != nil && x.RequestId != nil {
+		return *x.RequestId
+	}
+	return ""
+}
+
+func (x *SubscriptionEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+type Webhook struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId               string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Url                     string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Description             string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	EventTypes              []string               `protobuf:"bytes,4,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	CreatedAt               string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SecretRotatedAt         string                 `protobuf:"bytes,6,opt,name=secret_rotated_at,json=secretRotatedAt,proto3" json:"secret_rotated_at,omitempty"`
+	PreviousSecretExpiresAt *string                `protobuf:"bytes,7,opt,name=previous_secret_expires_at,json=previousSecretExpiresAt,proto3,oneof" json:"previous_secret_expires_at,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_api_subscriptions_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Webhook) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Webhook) GetSecretRotatedAt() string {
+	if x != nil {
+		return x.SecretRotatedAt
+	}
+	return ""
+}
+
+func (x *Webhook) GetPreviousSecretExpiresAt() string {
+	if x != nil && x.PreviousSecretExpiresAt != nil {
+		return *x.PreviousSecretExpiresAt
+	}
+	return ""
+}
+
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+type CreateWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookResponse) Reset() {
+	*x = CreateWebhookResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookResponse) ProtoMessage() {}
+
+func (x *CreateWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookResponse.ProtoReflect.Descriptor instead.
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CreateWebhookResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *CreateWebhookResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{52}
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DeleteWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{55}
+}
+
+type RotateWebhookSecretRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId      string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	RevokePrevious bool                   `protobuf:"varint,2,opt,name=revoke_previous,json=revokePrevious,proto3" json:"revoke_previous,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RotateWebhookSecretRequest) Reset() {
+	*x = RotateWebhookSecretRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateWebhookSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateWebhookSecretRequest) ProtoMessage() {}
+
+func (x *RotateWebhookSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateWebhookSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateWebhookSecretRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *RotateWebhookSecretRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *RotateWebhookSecretRequest) GetRevokePrevious() bool {
+	if x != nil {
+		return x.RevokePrevious
+	}
+	return false
+}
+
+type RotateWebhookSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateWebhookSecretResponse) Reset() {
+	*x = RotateWebhookSecretResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateWebhookSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateWebhookSecretResponse) ProtoMessage() {}
+
+func (x *RotateWebhookSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateWebhookSecretResponse.ProtoReflect.Descriptor instead.
+func (*RotateWebhookSecretResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RotateWebhookSecretResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *RotateWebhookSecretResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Status        *WebhookDeliveryStatus `protobuf:"varint,2,opt,name=status,proto3,enum=api.WebhookDeliveryStatus,oneof" json:"status,omitempty"`
+	PageSize      *int32                 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3,oneof" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetStatus() WebhookDeliveryStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_UNSPECIFIED
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPageSize() int32 {
+	if x != nil && x.PageSize != nil {
+		return *x.PageSize
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type ReplayWebhookDeliveryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	DeliveryId    int64                  `protobuf:"varint,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhookDeliveryRequest) Reset() {
+	*x = ReplayWebhookDeliveryRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhookDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhookDeliveryRequest) ProtoMessage() {}
+
+func (x *ReplayWebhookDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhookDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*ReplayWebhookDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ReplayWebhookDeliveryRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *ReplayWebhookDeliveryRequest) GetDeliveryId() int64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+type ReplayWebhookDeliveryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivery      *WebhookDelivery       `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayWebhookDeliveryResponse) Reset() {
+	*x = ReplayWebhookDeliveryResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayWebhookDeliveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayWebhookDeliveryResponse) ProtoMessage() {}
+
+func (x *ReplayWebhookDeliveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayWebhookDeliveryResponse.ProtoReflect.Descriptor instead.
+func (*ReplayWebhookDeliveryResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ReplayWebhookDeliveryResponse) GetDelivery() *WebhookDelivery {
+	if x != nil {
+		return x.Delivery
+	}
+	return nil
+}
+
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DeliveryId     int64                  `protobuf:"varint,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	WebhookId      string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventId        int64                  `protobuf:"varint,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType      string                 `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Status         WebhookDeliveryStatus  `protobuf:"varint,5,opt,name=status,proto3,enum=api.WebhookDeliveryStatus" json:"status,omitempty"`
+	Attempts       int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt  *string                `protobuf:"bytes,7,opt,name=next_attempt_at,json=nextAttemptAt,proto3,oneof" json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *string                `protobuf:"bytes,8,opt,name=last_attempt_at,json=lastAttemptAt,proto3,oneof" json:"last_attempt_at,omitempty"`
+	ResponseStatus *int32                 `protobuf:"varint,9,opt,name=response_status,json=responseStatus,proto3,oneof" json:"response_status,omitempty"`
+	ResponseBody   *string                `protobuf:"bytes,10,opt,name=response_body,json=responseBody,proto3,oneof" json:"response_body,omitempty"`
+	LastError      *string                `protobuf:"bytes,11,opt,name=last_error,json=lastError,proto3,oneof" json:"last_error,omitempty"`
+	Payload        string                 `protobuf:"bytes,12,opt,name=payload,proto3" json:"payload,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeliveredAt    *string                `protobuf:"bytes,14,opt,name=delivered_at,json=deliveredAt,proto3,oneof" json:"delivered_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_api_subscriptions_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *WebhookDelivery) GetDeliveryId() int64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() WebhookDeliveryStatus {
+	if x != nil {
+		return x.Status
+	}
+	return WebhookDeliveryStatus_WEBHOOK_DELIVERY_STATUS_UNSPECIFIED
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() string {
+	if x != nil && x.NextAttemptAt != nil {
+		return *x.NextAttemptAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetLastAttemptAt() string {
+	if x != nil && x.LastAttemptAt != nil {
+		return *x.LastAttemptAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetResponseStatus() int32 {
+	if x != nil && x.ResponseStatus != nil {
+		return *x.ResponseStatus
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetResponseBody() string {
+	if x != nil && x.ResponseBody != nil {
+		return *x.ResponseBody
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil && x.LastError != nil {
+		return *x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() string {
+	if x != nil && x.DeliveredAt != nil {
+		return *x.DeliveredAt
+	}
+	return ""
+}
+
+type APIKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Prefix        string                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Scopes        []string               `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	UserId        *string                `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *string                `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3,oneof" json:"expires_at,omitempty"`
+	LastUsedAt    *string                `protobuf:"bytes,8,opt,name=last_used_at,json=lastUsedAt,proto3,oneof" json:"last_used_at,omitempty"`
+	RevokedAt     *string                `protobuf:"bytes,9,opt,name=revoked_at,json=revokedAt,proto3,oneof" json:"revoked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_api_subscriptions_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *APIKey) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *APIKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetExpiresAt() string {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetLastUsedAt() string {
+	if x != nil && x.LastUsedAt != nil {
+		return *x.LastUsedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetRevokedAt() string {
+	if x != nil && x.RevokedAt != nil {
+		return *x.RevokedAt
+	}
+	return ""
+}
+
+type CreateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ExpiresAt     *string                `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3,oneof" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresAt() string {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return ""
+}
+
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{66}
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_api_subscriptions_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *RevokeAPIKeyRequest) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+type RevokeAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyResponse) Reset() {
+	*x = RevokeAPIKeyResponse{}
+	mi := &file_api_subscriptions_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyResponse) ProtoMessage() {}
+
+func (x *RevokeAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *RevokeAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type AddSubscriptionV2Request struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Валюта цены становится валютой подписки
+	Price                 *Money         `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	StartDate             string         `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate               *string        `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	BillingPeriod         *BillingPeriod `protobuf:"varint,6,opt,name=billing_period,json=billingPeriod,proto3,enum=api.BillingPeriod,oneof" json:"billing_period,omitempty"`
+	BillingIntervalMonths *int32         `protobuf:"varint,7,opt,name=billing_interval_months,json=billingIntervalMonths,proto3,oneof" json:"billing_interval_months,omitempty"`
+	RequestId             string         `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	TrialMonths           *int32         `protobuf:"varint,9,opt,name=trial_months,json=trialMonths,proto3,oneof" json:"trial_months,omitempty"`
+	// Льготные фазы следуют друг за другом после пробного периода, после них действует цена подписки
+	PromoPhases   []*PromoPhaseV2 `protobuf:"bytes,10,rep,name=promo_phases,json=promoPhases,proto3" json:"promo_phases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSubscriptionV2Request) Reset() {
+	*x = AddSubscriptionV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSubscriptionV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSubscriptionV2Request) ProtoMessage() {}
+
+func (x *AddSubscriptionV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSubscriptionV2Request.ProtoReflect.Descriptor instead.
+func (*AddSubscriptionV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *AddSubscriptionV2Request) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AddSubscriptionV2Request) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *AddSubscriptionV2Request) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *AddSubscriptionV2Request) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *AddSubscriptionV2Request) GetEndDate() string {
+	if x != nil && x.EndDate != nil {
+		return *x.EndDate
+	}
+	return ""
+}
+
+func (x *AddSubscriptionV2Request) GetBillingPeriod() BillingPeriod {
+	if x != nil && x.BillingPeriod != nil {
+		return *x.BillingPeriod
+	}
+	return BillingPeriod_BILLING_PERIOD_UNSPECIFIED
+}
+
+func (x *AddSubscriptionV2Request) GetBillingIntervalMonths() int32 {
+	if x != nil && x.BillingIntervalMonths != nil {
+		return *x.BillingIntervalMonths
+	}
+	return 0
+}
+
+func (x *AddSubscriptionV2Request) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AddSubscriptionV2Request) GetTrialMonths() int32 {
+	if x != nil && x.TrialMonths != nil {
+		return *x.TrialMonths
+	}
+	return 0
+}
+
+func (x *AddSubscriptionV2Request) GetPromoPhases() []*PromoPhaseV2 {
+	if x != nil {
+		return x.PromoPhases
+	}
+	return nil
+}
+
+type AddSubscriptionV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *SubscriptionV2        `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Overlaps      []*SubscriptionV2      `protobuf:"bytes,2,rep,name=overlaps,proto3" json:"overlaps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSubscriptionV2Response) Reset() {
+	*x = AddSubscriptionV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSubscriptionV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSubscriptionV2Response) ProtoMessage() {}
+
+func (x *AddSubscriptionV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSubscriptionV2Response.ProtoReflect.Descriptor instead.
+func (*AddSubscriptionV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *AddSubscriptionV2Response) GetSubscription() *SubscriptionV2 {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *AddSubscriptionV2Response) GetOverlaps() []*SubscriptionV2 {
+	if x != nil {
+		return x.Overlaps
+	}
+	return nil
+}
+
+type GetSubscriptionV2Request struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionV2Request) Reset() {
+	*x = GetSubscriptionV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionV2Request) ProtoMessage() {}
+
+func (x *GetSubscriptionV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionV2Request.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetSubscriptionV2Request) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+type GetSubscriptionV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *SubscriptionV2        `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionV2Response) Reset() {
+	*x = GetSubscriptionV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionV2Response) ProtoMessage() {}
+
+func (x *GetSubscriptionV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionV2Response.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetSubscriptionV2Response) GetSubscription() *SubscriptionV2 {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type GetSubscriptionsV2Request struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Count            *int32                 `protobuf:"varint,1,opt,name=count,proto3,oneof" json:"count,omitempty"`
+	Page             *int32                 `protobuf:"varint,2,opt,name=page,proto3,oneof" json:"page,omitempty"`
+	UserId           *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName      *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	ServiceNameMatch *ServiceNameMatch      `protobuf:"varint,5,opt,name=service_name_match,json=serviceNameMatch,proto3,enum=api.ServiceNameMatch,oneof" json:"service_name_match,omitempty"`
+	ActiveAt         *string                `protobuf:"bytes,6,opt,name=active_at,json=activeAt,proto3,oneof" json:"active_at,omitempty"`
+	MinPrice         *Money                 `protobuf:"bytes,7,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice         *Money                 `protobuf:"bytes,8,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	OpenEndedOnly    *bool                  `protobuf:"varint,9,opt,name=open_ended_only,json=openEndedOnly,proto3,oneof" json:"open_ended_only,omitempty"`
+	OrderBy          *string                `protobuf:"bytes,10,opt,name=order_by,json=orderBy,proto3,oneof" json:"order_by,omitempty"`
+	PageSize         *int32                 `protobuf:"varint,11,opt,name=page_size,json=pageSize,proto3,oneof" json:"page_size,omitempty"`
+	PageToken        string                 `protobuf:"bytes,12,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	IncludeTotalSize bool                   `protobuf:"varint,13,opt,name=include_total_size,json=includeTotalSize,proto3" json:"include_total_size,omitempty"`
+	Deleted          *DeletedFilter         `protobuf:"varint,14,opt,name=deleted,proto3,enum=api.DeletedFilter,oneof" json:"deleted,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionsV2Request) Reset() {
+	*x = GetSubscriptionsV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionsV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionsV2Request) ProtoMessage() {}
+
+func (x *GetSubscriptionsV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionsV2Request.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionsV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *GetSubscriptionsV2Request) GetCount() int32 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+func (x *GetSubscriptionsV2Request) GetPage() int32 {
+	if x != nil && x.Page != nil {
+		return *x.Page
+	}
+	return 0
+}
+
+func (x *GetSubscriptionsV2Request) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsV2Request) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsV2Request) GetServiceNameMatch() ServiceNameMatch {
+	if x != nil && x.ServiceNameMatch != nil {
+		return *x.ServiceNameMatch
+	}
+	return ServiceNameMatch_SERVICE_NAME_MATCH_UNSPECIFIED
+}
+
+func (x *GetSubscriptionsV2Request) GetActiveAt() string {
+	if x != nil && x.ActiveAt != nil {
+		return *x.ActiveAt
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsV2Request) GetMinPrice() *Money {
+	if x != nil {
+		return x.MinPrice
+	}
+	return nil
+}
+
+func (x *GetSubscriptionsV2Request) GetMaxPrice() *Money {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return nil
+}
+
+func (x *GetSubscriptionsV2Request) GetOpenEndedOnly() bool {
+	if x != nil && x.OpenEndedOnly != nil {
+		return *x.OpenEndedOnly
+	}
+	return false
+}
+
+func (x *GetSubscriptionsV2Request) GetOrderBy() string {
+	if x != nil && x.OrderBy != nil {
+		return *x.OrderBy
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsV2Request) GetPageSize() int32 {
+	if x != nil && x.PageSize != nil {
+		return *x.PageSize
+	}
+	return 0
+}
+
+func (x *GetSubscriptionsV2Request) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsV2Request) GetIncludeTotalSize() bool {
+	if x != nil {
+		return x.IncludeTotalSize
+	}
+	return false
+}
+
+func (x *GetSubscriptionsV2Request) GetDeleted() DeletedFilter {
+	if x != nil && x.Deleted != nil {
+		return *x.Deleted
+	}
+	return DeletedFilter_DELETED_FILTER_UNSPECIFIED
+}
+
+type GetSubscriptionsV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*SubscriptionV2      `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	// Токен следующей страницы, пустой на последней странице
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Общее количество подписок, подходящих под фильтр. Заполняется при include_total_size
+	TotalSize     *int64 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3,oneof" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionsV2Response) Reset() {
+	*x = GetSubscriptionsV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionsV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionsV2Response) ProtoMessage() {}
+
+func (x *GetSubscriptionsV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionsV2Response.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionsV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *GetSubscriptionsV2Response) GetSubscriptions() []*SubscriptionV2 {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+func (x *GetSubscriptionsV2Response) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetSubscriptionsV2Response) GetTotalSize() int64 {
+	if x != nil && x.TotalSize != nil {
+		return *x.TotalSize
+	}
+	return 0
+}
+
+// Валюта и периодичность не меняются после создания, поэтому их нет среди изменяемых полей
+type UpdateSubscriptionV2Request struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	UserId         *string                `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName    *string                `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	// Валюта цены должна совпадать с валютой подписки
+	Price     *Money  `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	StartDate *string `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3,oneof" json:"start_date,omitempty"`
+	EndDate   *string `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	// Список изменяемых полей (AIP-134). Поле из маски, не переданное в запросе, очищается, если это допустимо
+	// (например, end_date). Без маски изменяются только переданные поля, "*" заменяет подписку целиком
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,7,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	RequestId  string                 `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Если ETag не совпадает с текущим, подписка не изменяется и возвращается ABORTED (HTTP 412)
+	Etag          string `protobuf:"bytes,9,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionV2Request) Reset() {
+	*x = UpdateSubscriptionV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionV2Request) ProtoMessage() {}
+
+func (x *UpdateSubscriptionV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionV2Request.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *UpdateSubscriptionV2Request) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionV2Request) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionV2Request) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionV2Request) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionV2Request) GetStartDate() string {
+	if x != nil && x.StartDate != nil {
+		return *x.StartDate
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionV2Request) GetEndDate() string {
+	if x != nil && x.EndDate != nil {
+		return *x.EndDate
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionV2Request) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionV2Request) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionV2Request) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type UpdateSubscriptionV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *SubscriptionV2        `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Overlaps      []*SubscriptionV2      `protobuf:"bytes,2,rep,name=overlaps,proto3" json:"overlaps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionV2Response) Reset() {
+	*x = UpdateSubscriptionV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionV2Response) ProtoMessage() {}
+
+func (x *UpdateSubscriptionV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionV2Response.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *UpdateSubscriptionV2Response) GetSubscription() *SubscriptionV2 {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionV2Response) GetOverlaps() []*SubscriptionV2 {
+	if x != nil {
+		return x.Overlaps
+	}
+	return nil
+}
+
+type ChangeSubscriptionPlanV2Request struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	EffectiveDate  string                 `protobuf:"bytes,2,opt,name=effective_date,json=effectiveDate,proto3" json:"effective_date,omitempty"`
+	// Валюта цены должна совпадать с валютой подписки
+	Price         *Money  `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	ServiceName   *string `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	RequestId     string  `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeSubscriptionPlanV2Request) Reset() {
+	*x = ChangeSubscriptionPlanV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeSubscriptionPlanV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeSubscriptionPlanV2Request) ProtoMessage() {}
+
+func (x *ChangeSubscriptionPlanV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeSubscriptionPlanV2Request.ProtoReflect.Descriptor instead.
+func (*ChangeSubscriptionPlanV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *ChangeSubscriptionPlanV2Request) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *ChangeSubscriptionPlanV2Request) GetEffectiveDate() string {
+	if x != nil {
+		return x.EffectiveDate
+	}
+	return ""
+}
+
+func (x *ChangeSubscriptionPlanV2Request) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ChangeSubscriptionPlanV2Request) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *ChangeSubscriptionPlanV2Request) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type ChangeSubscriptionPlanV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *SubscriptionV2        `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeSubscriptionPlanV2Response) Reset() {
+	*x = ChangeSubscriptionPlanV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeSubscriptionPlanV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeSubscriptionPlanV2Response) ProtoMessage() {}
+
+func (x *ChangeSubscriptionPlanV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeSubscriptionPlanV2Response.ProtoReflect.Descriptor instead.
+func (*ChangeSubscriptionPlanV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ChangeSubscriptionPlanV2Response) GetSubscription() *SubscriptionV2 {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type GetSubscriptionPlansV2Request struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionPlansV2Request) Reset() {
+	*x = GetSubscriptionPlansV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionPlansV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionPlansV2Request) ProtoMessage() {}
+
+func (x *GetSubscriptionPlansV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionPlansV2Request.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionPlansV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *GetSubscriptionPlansV2Request) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+type GetSubscriptionPlansV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plans         []*SubscriptionPlanV2  `protobuf:"bytes,1,rep,name=plans,proto3" json:"plans,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionPlansV2Response) Reset() {
+	*x = GetSubscriptionPlansV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionPlansV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionPlansV2Response) ProtoMessage() {}
+
+func (x *GetSubscriptionPlansV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionPlansV2Response.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionPlansV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *GetSubscriptionPlansV2Response) GetPlans() []*SubscriptionPlanV2 {
+	if x != nil {
+		return x.Plans
+	}
+	return nil
+}
+
+type GetSumSubscriptionsV2Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Currency      *string                `protobuf:"bytes,5,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSumSubscriptionsV2Request) Reset() {
+	*x = GetSumSubscriptionsV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSumSubscriptionsV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSumSubscriptionsV2Request) ProtoMessage() {}
+
+func (x *GetSumSubscriptionsV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSumSubscriptionsV2Request.ProtoReflect.Descriptor instead.
+func (*GetSumSubscriptionsV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *GetSumSubscriptionsV2Request) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsV2Request) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsV2Request) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsV2Request) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSumSubscriptionsV2Request) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+type GetSumSubscriptionsV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         *Money                 `protobuf:"bytes,1,opt,name=total,proto3" json:"total,omitempty"`
+	Rates         []*ExchangeRate        `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSumSubscriptionsV2Response) Reset() {
+	*x = GetSumSubscriptionsV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSumSubscriptionsV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSumSubscriptionsV2Response) ProtoMessage() {}
+
+func (x *GetSumSubscriptionsV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSumSubscriptionsV2Response.ProtoReflect.Descriptor instead.
+func (*GetSumSubscriptionsV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *GetSumSubscriptionsV2Response) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetSumSubscriptionsV2Response) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type GetSpendTimeSeriesV2Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Currency      *string                `protobuf:"bytes,5,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	Granularity   Granularity            `protobuf:"varint,6,opt,name=granularity,proto3,enum=api.Granularity" json:"granularity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendTimeSeriesV2Request) Reset() {
+	*x = GetSpendTimeSeriesV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendTimeSeriesV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendTimeSeriesV2Request) ProtoMessage() {}
+
+func (x *GetSpendTimeSeriesV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendTimeSeriesV2Request.ProtoReflect.Descriptor instead.
+func (*GetSpendTimeSeriesV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *GetSpendTimeSeriesV2Request) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesV2Request) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesV2Request) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesV2Request) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesV2Request) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *GetSpendTimeSeriesV2Request) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_UNSPECIFIED
+}
+
+type GetSpendTimeSeriesV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*SpendBucketV2       `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Total         *Money                 `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	Rates         []*ExchangeRate        `protobuf:"bytes,3,rep,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendTimeSeriesV2Response) Reset() {
+	*x = GetSpendTimeSeriesV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendTimeSeriesV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendTimeSeriesV2Response) ProtoMessage() {}
+
+func (x *GetSpendTimeSeriesV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendTimeSeriesV2Response.ProtoReflect.Descriptor instead.
+func (*GetSpendTimeSeriesV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *GetSpendTimeSeriesV2Response) GetBuckets() []*SpendBucketV2 {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetSpendTimeSeriesV2Response) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetSpendTimeSeriesV2Response) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type SpendBucketV2 struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	StartDate           string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate             string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Amount              *Money                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	ActiveSubscriptions int32                  `protobuf:"varint,4,opt,name=active_subscriptions,json=activeSubscriptions,proto3" json:"active_subscriptions,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SpendBucketV2) Reset() {
+	*x = SpendBucketV2{}
+	mi := &file_api_subscriptions_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpendBucketV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpendBucketV2) ProtoMessage() {}
+
+func (x *SpendBucketV2) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpendBucketV2.ProtoReflect.Descriptor instead.
+func (*SpendBucketV2) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *SpendBucketV2) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *SpendBucketV2) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *SpendBucketV2) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *SpendBucketV2) GetActiveSubscriptions() int32 {
+	if x != nil {
+		return x.ActiveSubscriptions
+	}
+	return 0
+}
+
+type GetSpendBreakdownV2Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     string                 `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId        *string                `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	ServiceName   *string                `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Currency      *string                `protobuf:"bytes,5,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	GroupBy       BreakdownDimension     `protobuf:"varint,6,opt,name=group_by,json=groupBy,proto3,enum=api.BreakdownDimension" json:"group_by,omitempty"`
+	Limit         *int32                 `protobuf:"varint,7,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendBreakdownV2Request) Reset() {
+	*x = GetSpendBreakdownV2Request{}
+	mi := &file_api_subscriptions_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendBreakdownV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendBreakdownV2Request) ProtoMessage() {}
+
+func (x *GetSpendBreakdownV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendBreakdownV2Request.ProtoReflect.Descriptor instead.
+func (*GetSpendBreakdownV2Request) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *GetSpendBreakdownV2Request) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownV2Request) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownV2Request) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownV2Request) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownV2Request) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *GetSpendBreakdownV2Request) GetGroupBy() BreakdownDimension {
+	if x != nil {
+		return x.GroupBy
+	}
+	return BreakdownDimension_BREAKDOWN_DIMENSION_UNSPECIFIED
+}
+
+func (x *GetSpendBreakdownV2Request) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type GetSpendBreakdownV2Response struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*SpendBreakdownRowV2 `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	Total         *Money                 `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	Rates         []*ExchangeRate        `protobuf:"bytes,3,rep,name=rates,proto3" json:"rates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpendBreakdownV2Response) Reset() {
+	*x = GetSpendBreakdownV2Response{}
+	mi := &file_api_subscriptions_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpendBreakdownV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpendBreakdownV2Response) ProtoMessage() {}
+
+func (x *GetSpendBreakdownV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpendBreakdownV2Response.ProtoReflect.Descriptor instead.
+func (*GetSpendBreakdownV2Response) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *GetSpendBreakdownV2Response) GetRows() []*SpendBreakdownRowV2 {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *GetSpendBreakdownV2Response) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetSpendBreakdownV2Response) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+type SpendBreakdownRowV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Share         float64                `protobuf:"fixed64,3,opt,name=share,proto3" json:"share,omitempty"`
+	Subscriptions int32                  `protobuf:"varint,4,opt,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	Other         bool                   `protobuf:"varint,5,opt,name=other,proto3" json:"other,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpendBreakdownRowV2) Reset() {
+	*x = SpendBreakdownRowV2{}
+	mi := &file_api_subscriptions_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpendBreakdownRowV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpendBreakdownRowV2) ProtoMessage() {}
+
+func (x *SpendBreakdownRowV2) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpendBreakdownRowV2.ProtoReflect.Descriptor instead.
+func (*SpendBreakdownRowV2) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *SpendBreakdownRowV2) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SpendBreakdownRowV2) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *SpendBreakdownRowV2) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *SpendBreakdownRowV2) GetSubscriptions() int32 {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return 0
+}
+
+func (x *SpendBreakdownRowV2) GetOther() bool {
+	if x != nil {
+		return x.Other
+	}
+	return false
+}
+
+type SubscriptionV2 struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName    string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Валюта цены - валюта подписки
+	Price                 *Money             `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	StartDate             string             `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate               *string            `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	BillingPeriod         BillingPeriod      `protobuf:"varint,7,opt,name=billing_period,json=billingPeriod,proto3,enum=api.BillingPeriod" json:"billing_period,omitempty"`
+	BillingIntervalMonths *int32             `protobuf:"varint,8,opt,name=billing_interval_months,json=billingIntervalMonths,proto3,oneof" json:"billing_interval_months,omitempty"`
+	Status                SubscriptionStatus `protobuf:"varint,9,opt,name=status,proto3,enum=api.SubscriptionStatus" json:"status,omitempty"`
+	TrialEndDate          *string            `protobuf:"bytes,10,opt,name=trial_end_date,json=trialEndDate,proto3,oneof" json:"trial_end_date,omitempty"`
+	DeletedAt             *string            `protobuf:"bytes,11,opt,name=deleted_at,json=deletedAt,proto3,oneof" json:"deleted_at,omitempty"`
+	Version               int64              `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	Etag                  string             `protobuf:"bytes,13,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *SubscriptionV2) Reset() {
+	*x = SubscriptionV2{}
+	mi := &file_api_subscriptions_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionV2) ProtoMessage() {}
+
+func (x *SubscriptionV2) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionV2.ProtoReflect.Descriptor instead.
+func (*SubscriptionV2) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *SubscriptionV2) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *SubscriptionV2) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubscriptionV2) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *SubscriptionV2) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *SubscriptionV2) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *SubscriptionV2) GetEndDate() string {
+	if x != nil && x.EndDate != nil {
+		return *x.EndDate
+	}
+	return ""
+}
+
+func (x *SubscriptionV2) GetBillingPeriod() BillingPeriod {
+	if x != nil {
+		return x.BillingPeriod
+	}
+	return BillingPeriod_BILLING_PERIOD_UNSPECIFIED
+}
+
+func (x *SubscriptionV2) GetBillingIntervalMonths() int32 {
+	if x != nil && x.BillingIntervalMonths != nil {
+		return *x.BillingIntervalMonths
+	}
+	return 0
+}
+
+func (x *SubscriptionV2) GetStatus() SubscriptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED
+}
+
+func (x *SubscriptionV2) GetTrialEndDate() string {
+	if x != nil && x.TrialEndDate != nil {
+		return *x.TrialEndDate
+	}
+	return ""
+}
+
+func (x *SubscriptionV2) GetDeletedAt() string {
+	if x != nil && x.DeletedAt != nil {
+		return *x.DeletedAt
+	}
+	return ""
+}
+
+func (x *SubscriptionV2) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SubscriptionV2) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type SubscriptionPlanV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ValidFrom     string                 `protobuf:"bytes,1,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidTo       *string                `protobuf:"bytes,2,opt,name=valid_to,json=validTo,proto3,oneof" json:"valid_to,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price         *Money                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Phase         PlanPhase              `protobuf:"varint,5,opt,name=phase,proto3,enum=api.PlanPhase" json:"phase,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionPlanV2) Reset() {
+	*x = SubscriptionPlanV2{}
+	mi := &file_api_subscriptions_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionPlanV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionPlanV2) ProtoMessage() {}
+
+func (x *SubscriptionPlanV2) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionPlanV2.ProtoReflect.Descriptor instead.
+func (*SubscriptionPlanV2) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *SubscriptionPlanV2) GetValidFrom() string {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return ""
+}
+
+func (x *SubscriptionPlanV2) GetValidTo() string {
+	if x != nil && x.ValidTo != nil {
+		return *x.ValidTo
+	}
+	return ""
+}
+
+func (x *SubscriptionPlanV2) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *SubscriptionPlanV2) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *SubscriptionPlanV2) GetPhase() PlanPhase {
+	if x != nil {
+		return x.Phase
+	}
+	return PlanPhase_PLAN_PHASE_UNSPECIFIED
+}
+
+// Льготная фаза: months месяцев по цене price. Валюта price должна совпадать с валютой подписки
+type PromoPhaseV2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Months        int32                  `protobuf:"varint,1,opt,name=months,proto3" json:"months,omitempty"`
+	Price         *Money                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromoPhaseV2) Reset() {
+	*x = PromoPhaseV2{}
+	mi := &file_api_subscriptions_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromoPhaseV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoPhaseV2) ProtoMessage() {}
+
+func (x *PromoPhaseV2) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscriptions_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoPhaseV2.ProtoReflect.Descriptor instead.
+func (*PromoPhaseV2) Descriptor() ([]byte, []int) {
+	return file_api_subscriptions_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *PromoPhaseV2) GetMonths() int32 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *PromoPhaseV2) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+var File_api_subscriptions_proto protoreflect.FileDescriptor
+
+const file_api_subscriptions_proto_rawDesc = "" +
+	"\n" +
+	"\x17api/subscriptions.proto\x12\x03api\x1a\x1cgoogle/api/annotations.proto\x1a google/protobuf/field_mask.proto\x1a\x1bbuf/validate/validate.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\x92\f\n" +
+	"\x16AddSubscriptionRequest\x12D\n" +
+	"\auser_id\x18\x01 \x01(\tB+\x92A\x1d*\x1bID пользователя\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x06userId\x12[\n" +
+	"\fservice_name\x18\x02 \x01(\tB8\x92A+*)Наименование подписки\xbaH\a\xc8\x01\x01r\x02\x10\x03R\vserviceName\x12H\n" +
+	"\x05price\x18\x03 \x01(\x05B2\x92A%*#Стоимость подписки\xbaH\a\xc8\x01\x01\x1a\x02(\x00R\x05price\x12n\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tBO\x92A(*&Дата старта подписки\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12r\n" +
+	"\bend_date\x18\x05 \x01(\tBR\x92A.*,Дата окончания подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x00R\aendDate\x88\x01\x01\x12\xa4\x01\n" +
+	"\bcurrency\x18\x06 \x01(\tB\x82\x01\x92An*lВалюта подписки (ISO 4217), по умолчанию базовая валюта сервиса\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x01R\bcurrency\x88\x01\x01\x12\xa8\x01\n" +
+	"\x0ebilling_period\x18\a \x01(\x0e2\x12.api.BillingPeriodBh\x92A]*[Периодичность списаний, по умолчанию BILLING_PERIOD_MONTHLY\xbaH\x05\x82\x01\x02\x10\x01H\x02R\rbillingPeriod\x88\x01\x01\x12\x9b\x01\n" +
+	"\x17billing_interval_months\x18\b \x01(\x05B^\x92AR*PИнтервал списаний в месяцах для BILLING_PERIOD_CUSTOM\xbaH\x06\x1a\x04\x18x \x00H\x03R\x15billingIntervalMonths\x88\x01\x01\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\n" +
+	" \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestId\x12\xb7\x01\n" +
+	"\ftrial_months\x18\v \x01(\x05B\x8e\x01\x92A\x81\x01*\x7fДлительность бесплатного пробного периода в месяцах, начиная с start_date\xbaH\x06\x1a\x04\x18\x18 \x00H\x04R\vtrialMonths\x88\x01\x01\x12}\n" +
+	"\fpromo_phases\x18\f \x03(\v2\x0f.api.PromoPhaseBI\x92A>*<Льготные фазы со сниженной ценой\xbaH\x05\x92\x01\x02\x10\fR\vpromoPhasesB\v\n" +
+	"\t_end_dateB\v\n" +
+	"\t_currencyB\x11\n" +
+	"\x0f_billing_periodB\x1a\n" +
+	"\x18_billing_interval_monthsB\x0f\n" +
+	"\r_trial_monthsJ\x04\b\t\x10\n" +
+	"R\vprice_money\"\x94\x02\n" +
+	"\x17AddSubscriptionResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\x12\xc1\x01\n" +
+	"\boverlaps\x18\x02 \x03(\v2\x11.api.SubscriptionB\x91\x01\x92A\x8d\x01*\x8a\x01Пересекающиеся подписки того же пользователя на тот же сервис (политика warn)R\boverlaps\"f\n" +
+	"\x16GetSubscriptionRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\"P\n" +
+	"\x17GetSubscriptionResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"\xb2\x13\n" +
+	"\x17GetSubscriptionsRequest\x12b\n" +
+	"\x05count\x18\x01 \x01(\x05BG\x92A=*;Количество подписок на страницу\xbaH\x04\x1a\x02 \x00H\x00R\x05count\x88\x01\x01\x12@\n" +
+	"\x04page\x18\x02 \x01(\x05B'\x92A\x1d*\x1bНомер страницы\xbaH\x04\x1a\x02 \x00H\x01R\x04page\x88\x01\x01\x12F\n" +
+	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x02R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x01H\x03R\vserviceName\x88\x01\x01\x12\xd3\x01\n" +
+	"\x12service_name_match\x18\x05 \x01(\x0e2\x15.api.ServiceNameMatchB\x88\x01\x92A}*{Способ сравнения наименования подписки, по умолчанию SERVICE_NAME_MATCH_EXACT\xbaH\x05\x82\x01\x02\x10\x01H\x04R\x10serviceNameMatch\x88\x01\x01\x12\x85\x01\n" +
+	"\tactive_at\x18\x06 \x01(\tBc\x92A?*=Месяц, в котором подписка активна\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x05R\bactiveAt\x88\x01\x01\x12\x8b\x01\n" +
+	"\tmin_price\x18\a \x01(\x05Bi\x92A_*]Минимальная стоимость подписки (в валюте подписки)\xbaH\x04\x1a\x02(\x00H\x06R\bminPrice\x88\x01\x01\x12\x8d\x01\n" +
+	"\tmax_price\x18\b \x01(\x05Bk\x92Aa*_Максимальная стоимость подписки (в валюте подписки)\xbaH\x04\x1a\x02(\x00H\aR\bmaxPrice\x88\x01\x01\x12d\n" +
+	"\x0fopen_ended_only\x18\t \x01(\bB7\x92A4*2Только бессрочные подпискиH\bR\ropenEndedOnly\x88\x01\x01\x12\xe6\x01\n" +
+	"\border_by\x18\n" +
+	" \x01(\tB\xc5\x01\x92A\x8b\x01*\x88\x01Сортировка: start_date, price или service_name с необязательным asc/desc, по умолчанию start_date\xbaH3r12/^(start_date|price|service_name)( (asc|desc))?$H\tR\aorderBy\x88\x01\x01\x12j\n" +
+	"\tpage_size\x18\v \x01(\x05BH\x92A;*9Размер страницы, по умолчанию 10\xbaH\a\x1a\x05\x18\xe8\a \x00H\n" +
+	"R\bpageSize\x88\x01\x01\x12x\n" +
+	"\n" +
+	"page_token\x18\f \x01(\tBY\x92AV*TТокен страницы из next_page_token предыдущего ответаR\tpageToken\x12\x9c\x01\n" +
+	"\x12include_total_size\x18\r \x01(\bBn\x92Ak*iВернуть общее количество подписок, подходящих под фильтрR\x10includeTotalSize\x12\xad\x01\n" +
+	"\adeleted\x18\x0e \x01(\x0e2\x12.api.DeletedFilterBz\x92Ao*mПоказывать ли удаленные подписки, по умолчанию DELETED_FILTER_EXCLUDE\xbaH\x05\x82\x01\x02\x10\x01H\vR\adeleted\x88\x01\x01:\xef\x02\xbaH\xeb\x02\x1a\x92\x01\n" +
+	"\vprice_range\x121min_price must be less than or equal to max_price\x1aP!has(this.min_price) || !has(this.max_price) || this.min_price <= this.max_price\x1ai\n" +
+	"\x14page_token_with_page\x12'page_token cannot be combined with page\x1a(!has(this.page) || this.page_token == ''\x1ai\n" +
+	"\x14page_size_with_count\x12'page_size cannot be combined with count\x1a(!has(this.page_size) || !has(this.count)B\b\n" +
+	"\x06_countB\a\n" +
+	"\x05_pageB\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\x15\n" +
+	"\x13_service_name_matchB\f\n" +
+	"\n" +
+	"_active_atB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_priceB\x12\n" +
+	"\x10_open_ended_onlyB\v\n" +
+	"\t_order_byB\f\n" +
+	"\n" +
+	"_page_sizeB\n" +
+	"\n" +
+	"\b_deletedJ\x04\b\x0f\x10\x10J\x04\b\x10\x10\x11R\x0fmin_price_moneyR\x0fmax_price_money\"\xae\x01\n" +
+	"\x18GetSubscriptionsResponse\x127\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x11.api.SubscriptionR\rsubscriptions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\"\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x03H\x00R\ttotalSize\x88\x01\x01B\r\n" +
+	"\v_total_size\"\xce\v\n" +
+	"\x19UpdateSubscriptionRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12F\n" +
+	"\auser_id\x18\x02 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x03 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12J\n" +
+	"\x05price\x18\x04 \x01(\x05B/\x92A%*#Стоимость подписки\xbaH\x04\x1a\x02(\x00H\x02R\x05price\x88\x01\x01\x12p\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\tBL\x92A(*&Дата старта подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x03R\tstartDate\x88\x01\x01\x12r\n" +
+	"\bend_date\x18\x06 \x01(\tBR\x92A.*,Дата окончания подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x04R\aendDate\x88\x01\x01\x12_\n" +
+	"\bcurrency\x18\a \x01(\tB>\x92A**(Валюта подписки (ISO 4217)\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x05R\bcurrency\x88\x01\x01\x12x\n" +
+	"\x0ebilling_period\x18\b \x01(\x0e2\x12.api.BillingPeriodB8\x92A-*+Периодичность списаний\xbaH\x05\x82\x01\x02\x10\x01H\x06R\rbillingPeriod\x88\x01\x01\x12\x9b\x01\n" +
+	"\x17billing_interval_months\x18\t \x01(\x05B^\x92AR*PИнтервал списаний в месяцах для BILLING_PERIOD_CUSTOM\xbaH\x06\x1a\x04\x18x \x00H\aR\x15billingIntervalMonths\x88\x01\x01\x12l\n" +
+	"\vupdate_mask\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.FieldMaskB/\x92A,**Маска изменяемых полейR\n" +
+	"updateMask\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\f \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestId\x12~\n" +
+	"\x04etag\x18\r \x01(\tBj\x92A`*^Ожидаемый ETag подписки, альтернатива заголовку If-Match\xbaH\x04r\x02\x18@R\x04etagB\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
 	"\r_service_nameB\b\n" +
 	"\x06_priceB\r\n" +
 	"\v_start_dateB\v\n" +
-	"\t_end_date\"S\n" +
+	"\t_end_dateB\v\n" +
+	"\t_currencyB\x11\n" +
+	"\x0f_billing_periodB\x1a\n" +
+	"\x18_billing_interval_monthsJ\x04\b\v\x10\fR\vprice_money\"\x97\x02\n" +
 	"\x1aUpdateSubscriptionResponse\x125\n" +
-	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"d\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\x12\xc1\x01\n" +
+	"\boverlaps\x18\x02 \x03(\v2\x11.api.SubscriptionB\x91\x01\x92A\x8d\x01*\x8a\x01Пересекающиеся подписки того же пользователя на тот же сервис (политика warn)R\boverlaps\"\xf7\x02\n" +
 	"\x19DeleteSubscriptionRequest\x12G\n" +
-	"\x0fsubscription_id\x18\x01 \x01(\tB\x1e\x92A\x15*\x13ID подписки\xbaH\x03\xc8\x01\x01R\x0esubscriptionId\"\x1c\n" +
-	"\x1aDeleteSubscriptionResponse\"\xc2\x03\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB\x1e\x92A\x15*\x13ID подписки\xbaH\x03\xc8\x01\x01R\x0esubscriptionId\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestId\x12~\n" +
+	"\x04etag\x18\x03 \x01(\tBj\x92A`*^Ожидаемый ETag подписки, альтернатива заголовку If-Match\xbaH\x04r\x02\x18@R\x04etag\"\x1c\n" +
+	"\x1aDeleteSubscriptionResponse\"\xfe\x01\n" +
+	"\x1bUndeleteSubscriptionRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestId\"U\n" +
+	"\x1cUndeleteSubscriptionResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"\xfe\x04\n" +
+	"\x1dChangeSubscriptionPlanRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12\x98\x01\n" +
+	"\x0eeffective_date\x18\x02 \x01(\tBq\x92AJ*HМесяц, с которого действует новый тариф\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\reffectiveDate\x12S\n" +
+	"\x05price\x18\x03 \x01(\x05B=\x92A0*.Новая стоимость подписки\xbaH\a\xc8\x01\x01\x1a\x02(\x00R\x05price\x12h\n" +
+	"\fservice_name\x18\x04 \x01(\tB@\x92A6*4Новое наименование подписки\xbaH\x04r\x02\x10\x03H\x00R\vserviceName\x88\x01\x01\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestIdB\x0f\n" +
+	"\r_service_nameJ\x04\b\x05\x10\x06R\vprice_money\"W\n" +
+	"\x1eChangeSubscriptionPlanResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"k\n" +
+	"\x1bGetSubscriptionPlansRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\"K\n" +
+	"\x1cGetSubscriptionPlansResponse\x12+\n" +
+	"\x05plans\x18\x01 \x03(\v2\x15.api.SubscriptionPlanR\x05plans\"\x93\x04\n" +
+	"\x10SubscriptionPlan\x12Y\n" +
+	"\n" +
+	"valid_from\x18\x01 \x01(\tB:\x92A7*5Месяц начала действия тарифаR\tvalidFrom\x12`\n" +
+	"\bvalid_to\x18\x02 \x01(\tB@\x92A=*;Последний месяц действия тарифаH\x00R\avalidTo\x88\x01\x01\x12Q\n" +
+	"\fservice_name\x18\x03 \x01(\tB.\x92A+*)Наименование подпискиR\vserviceName\x12>\n" +
+	"\x05price\x18\x04 \x01(\x05B(\x92A%*#Стоимость подпискиR\x05price\x12\x8e\x01\n" +
+	"\x05phase\x18\x06 \x01(\x0e2\x0e.api.PlanPhaseBh\x92Ae*cФаза тарифа: пробный период, льготная или обычная ценаR\x05phaseB\v\n" +
+	"\t_valid_toJ\x04\b\x05\x10\x06R\vprice_money\"\x98\x05\n" +
 	"\x1aGetSumSubscriptionsRequest\x12n\n" +
 	"\n" +
 	"start_date\x18\x01 \x01(\tBO\x92A(*&Дата старта подписки\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12p\n" +
 	"\bend_date\x18\x02 \x01(\tBU\x92A.*,Дата окончания подписки\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\aendDate\x12F\n" +
 	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
-	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01B\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12\xc6\x01\n" +
+	"\bcurrency\x18\x05 \x01(\tB\xa4\x01\x92A\x8f\x01*\x8c\x01Валюта, в которой считается сумма (ISO 4217), по умолчанию базовая валюта сервиса\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x02R\bcurrency\x88\x01\x01B\n" +
 	"\n" +
 	"\b_user_idB\x0f\n" +
-	"\r_service_name\"m\n" +
+	"\r_service_nameB\v\n" +
+	"\t_currency\"\xbe\x02\n" +
 	"\x1bGetSumSubscriptionsResponse\x12N\n" +
-	"\ttotal_sum\x18\x01 \x01(\x05B1\x92A.*,Итоговая сумма подписокR\btotalSum\"\xcb\x03\n" +
+	"\ttotal_sum\x18\x01 \x01(\x05B1\x92A.*,Итоговая сумма подписокR\btotalSum\x12I\n" +
+	"\bcurrency\x18\x02 \x01(\tB-\x92A**(Валюта итоговой суммыR\bcurrency\x12w\n" +
+	"\x05rates\x18\x03 \x03(\v2\x11.api.ExchangeRateBN\x92AK*IКурсы валют, использованные при расчётеR\x05ratesJ\x04\b\x04\x10\x05R\x05total\"\xa1\x06\n" +
+	"\x19GetSpendTimeSeriesRequest\x12l\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tBM\x92A&*$Дата начала периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12n\n" +
+	"\bend_date\x18\x02 \x01(\tBS\x92A,**Дата окончания периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\aendDate\x12F\n" +
+	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12\xc6\x01\n" +
+	"\bcurrency\x18\x05 \x01(\tB\xa4\x01\x92A\x8f\x01*\x8c\x01Валюта, в которой считается сумма (ISO 4217), по умолчанию базовая валюта сервиса\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x02R\bcurrency\x88\x01\x01\x12\x8b\x01\n" +
+	"\vgranularity\x18\x06 \x01(\x0e2\x10.api.GranularityBW\x92AL*JРазмер интервала, по умолчанию GRANULARITY_MONTH\xbaH\x05\x82\x01\x02\x10\x01R\vgranularityB\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\v\n" +
+	"\t_currency\"\x8a\x04\n" +
+	"\x1aGetSpendTimeSeriesResponse\x12\xb1\x01\n" +
+	"\abuckets\x18\x01 \x03(\v2\x10.api.SpendBucketB\x84\x01\x92A\x80\x01*~Интервалы в хронологическом порядке, включая интервалы без расходовR\abuckets\x12\x86\x01\n" +
+	"\ttotal_sum\x18\x02 \x01(\x03Bi\x92Af*dИтоговая сумма, совпадает с GetSumSubscriptions за тот же периодR\btotalSum\x126\n" +
+	"\bcurrency\x18\x03 \x01(\tB\x1a\x92A\x17*\x15Валюта суммR\bcurrency\x12w\n" +
+	"\x05rates\x18\x04 \x03(\v2\x11.api.ExchangeRateBN\x92AK*IКурсы валют, использованные при расчётеR\x05rates\"\xc1\x03\n" +
+	"\vSpendBucket\x12X\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tB9\x92A6*4Первый месяц интервала (MM-YYYY)R\tstartDate\x12Z\n" +
+	"\bend_date\x18\x02 \x01(\tB?\x92A<*:Последний месяц интервала (MM-YYYY)R\aendDate\x12N\n" +
+	"\x06amount\x18\x03 \x01(\x03B6\x92A3*1Сумма расходов за интервалR\x06amount\x12\xab\x01\n" +
+	"\x14active_subscriptions\x18\x04 \x01(\x05Bx\x92Au*sКоличество подписок, активных хотя бы в одном месяце интервалаR\x13activeSubscriptions\"\xac\a\n" +
+	"\x18GetSpendBreakdownRequest\x12l\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tBM\x92A&*$Дата начала периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12n\n" +
+	"\bend_date\x18\x02 \x01(\tBS\x92A,**Дата окончания периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\aendDate\x12F\n" +
+	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12\xc6\x01\n" +
+	"\bcurrency\x18\x05 \x01(\tB\xa4\x01\x92A\x8f\x01*\x8c\x01Валюта, в которой считается сумма (ISO 4217), по умолчанию базовая валюта сервиса\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x02R\bcurrency\x88\x01\x01\x12i\n" +
+	"\bgroup_by\x18\x06 \x01(\x0e2\x17.api.BreakdownDimensionB5\x92A'*%Признак группировки\xbaH\b\xc8\x01\x01\x82\x01\x02\x10\x01R\agroupBy\x12\xa2\x01\n" +
+	"\x05limit\x18\a \x01(\x05B\x86\x01\x92Ay*wКоличество крупнейших групп, остальные объединяются в строку other\xbaH\a\x1a\x05\x18\xe8\a \x00H\x03R\x05limit\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\v\n" +
+	"\t_currencyB\b\n" +
+	"\x06_limit\"\xdf\x03\n" +
+	"\x19GetSpendBreakdownResponse\x12\x87\x01\n" +
+	"\x04rows\x18\x01 \x03(\v2\x16.api.SpendBreakdownRowB[\x92AX*VГруппы по убыванию суммы, строка other - последняяR\x04rows\x12\x86\x01\n" +
+	"\ttotal_sum\x18\x02 \x01(\x03Bi\x92Af*dИтоговая сумма, совпадает с GetSumSubscriptions за тот же периодR\btotalSum\x126\n" +
+	"\bcurrency\x18\x03 \x01(\tB\x1a\x92A\x17*\x15Валюта суммR\bcurrency\x12w\n" +
+	"\x05rates\x18\x04 \x03(\v2\x11.api.ExchangeRateBN\x92AK*IКурсы валют, использованные при расчётеR\x05rates\"\x86\x04\n" +
+	"\x11SpendBreakdownRow\x12\x8b\x01\n" +
+	"\x03key\x18\x01 \x01(\tBy\x92Av*tНаименование подписки или ID пользователя, пустое для строки otherR\x03key\x12E\n" +
+	"\x06amount\x18\x02 \x01(\x03B-\x92A**(Сумма расходов группыR\x06amount\x12T\n" +
+	"\x05share\x18\x03 \x01(\x01B>\x92A;*9Доля от итоговой суммы (от 0 до 1)R\x05share\x12]\n" +
+	"\rsubscriptions\x18\x04 \x01(\x05B7\x92A4*2Количество подписок группыR\rsubscriptions\x12g\n" +
+	"\x05other\x18\x05 \x01(\bBQ\x92AN*LСтрока объединяет группы за пределами limitR\x05other\"\x94\x02\n" +
+	"\fExchangeRate\x128\n" +
+	"\bcurrency\x18\x01 \x01(\tB\x1c\x92A\x19*\x17Валюта (ISO 4217)R\bcurrency\x12b\n" +
+	"\n" +
+	"valid_from\x18\x02 \x01(\tBC\x92A@*>Дата начала действия курса (YYYY-MM-DD)R\tvalidFrom\x12f\n" +
+	"\x04rate\x18\x03 \x01(\tBR\x92AO*MСтоимость единицы валюты в базовой валютеR\x04rate\"\xf5\v\n" +
 	"\fSubscription\x12A\n" +
 	"\x0fsubscription_id\x18\x01 \x01(\tB\x18\x92A\x15*\x13ID подпискиR\x0esubscriptionId\x129\n" +
 	"\auser_id\x18\x02 \x01(\tB \x92A\x1d*\x1bID пользователяR\x06userId\x12Q\n" +
@@ -795,15 +6857,556 @@ const file_api_subscriptions_proto_rawDesc = "" +
 	"\x05price\x18\x04 \x01(\x05B(\x92A%*#Стоимость подпискиR\x05price\x12J\n" +
 	"\n" +
 	"start_date\x18\x05 \x01(\tB+\x92A(*&Дата старта подпискиR\tstartDate\x12Q\n" +
-	"\bend_date\x18\x06 \x01(\tB1\x92A.*,Дата окончания подпискиH\x00R\aendDate\x88\x01\x01B\v\n" +
-	"\t_end_date2\x8d\t\n" +
+	"\bend_date\x18\x06 \x01(\tB1\x92A.*,Дата окончания подпискиH\x00R\aendDate\x88\x01\x01\x12I\n" +
+	"\bcurrency\x18\a \x01(\tB-\x92A**(Валюта подписки (ISO 4217)R\bcurrency\x12k\n" +
+	"\x0ebilling_period\x18\b \x01(\x0e2\x12.api.BillingPeriodB0\x92A-*+Периодичность списанийR\rbillingPeriod\x12\x92\x01\n" +
+	"\x17billing_interval_months\x18\t \x01(\x05BU\x92AR*PИнтервал списаний в месяцах для BILLING_PERIOD_CUSTOMH\x01R\x15billingIntervalMonths\x88\x01\x01\x12S\n" +
+	"\x06status\x18\v \x01(\x0e2\x17.api.SubscriptionStatusB\"\x92A\x1f*\x1dСтатус подпискиR\x06status\x12m\n" +
+	"\x0etrial_end_date\x18\f \x01(\tBB\x92A?*=Последний месяц пробного периодаH\x02R\ftrialEndDate\x88\x01\x01\x12\xac\x01\n" +
+	"\n" +
+	"deleted_at\x18\r \x01(\tB\x87\x01\x92A\x83\x01*\x80\x01Время удаления подписки в формате RFC 3339, только для удаленных подписокH\x03R\tdeletedAt\x88\x01\x01\x12\x7f\n" +
+	"\aversion\x18\x0e \x01(\x03Be\x92Ab*`Версия подписки, увеличивается при каждом измененииR\aversion\x12\x95\x01\n" +
+	"\x04etag\x18\x0f \x01(\tB\x80\x01\x92A}*{ETag подписки для заголовка If-Match или поля etag при изменении и удаленииR\x04etagB\v\n" +
+	"\t_end_dateB\x1a\n" +
+	"\x18_billing_interval_monthsB\x11\n" +
+	"\x0f_trial_end_dateB\r\n" +
+	"\v_deleted_atJ\x04\b\n" +
+	"\x10\vR\vprice_money\"\xc8\x01\n" +
+	"\x05Money\x12L\n" +
+	"\bcurrency\x18\x01 \x01(\tB0\x92A\x19*\x17Валюта (ISO 4217)\xbaH\x11\xc8\x01\x01r\f2\n" +
+	"^[A-Z]{3}$R\bcurrency\x12q\n" +
+	"\famount_minor\x18\x02 \x01(\x03BN\x92AD*BСумма в минимальных единицах валюты\xbaH\x04\"\x02(\x00R\vamountMinor\"\xac\x02\n" +
+	"\x1fListSubscriptionOverlapsRequest\x12F\n" +
+	"\auser_id\x18\x01 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12\xa3\x01\n" +
+	"\fservice_name\x18\x02 \x01(\tB{\x92Aq*oНаименование подписки (без учета регистра и лишних пробелов)\xbaH\x04r\x02\x10\x01H\x01R\vserviceName\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_name\"X\n" +
+	" ListSubscriptionOverlapsResponse\x124\n" +
+	"\boverlaps\x18\x01 \x03(\v2\x18.api.SubscriptionOverlapR\boverlaps\"\x93\x03\n" +
+	"\x13SubscriptionOverlap\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\x123\n" +
+	"\voverlapping\x18\x02 \x01(\v2\x11.api.SubscriptionR\voverlapping\x12X\n" +
+	"\roverlap_start\x18\x03 \x01(\tB3\x92A0*.Первый месяц пересеченияR\foverlapStart\x12\xa5\x01\n" +
+	"\voverlap_end\x18\x04 \x01(\tB\x7f\x92A|*zПоследний месяц пересечения, не задан для бессрочного пересеченияH\x00R\n" +
+	"overlapEnd\x88\x01\x01B\x0e\n" +
+	"\f_overlap_end\"\xe4\x03\n" +
+	"\x19MergeSubscriptionsRequest\x12\x8a\x01\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tBa\x92AX*VID подписки, которая остается после объединения\xbaH\x03\xc8\x01\x01R\x0esubscriptionId\x12\xa6\x01\n" +
+	"\x17merged_subscription_ids\x18\x02 \x03(\tBn\x92AZ*XID подписок, которые удаляются после объединения\xbaH\x0e\x92\x01\v\b\x01\x18\x01\"\x05r\x03\xb0\x01\x01R\x15mergedSubscriptionIds\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestId\"S\n" +
+	"\x1aMergeSubscriptionsResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"\xa3\x03\n" +
+	"\x19CancelSubscriptionRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12\x91\x01\n" +
+	"\x0eeffective_date\x18\x02 \x01(\tBe\x92AA*?Последний месяц действия подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x00R\reffectiveDate\x88\x01\x01\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestIdB\x11\n" +
+	"\x0f_effective_date\"S\n" +
+	"\x1aCancelSubscriptionResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"\x93\x03\n" +
+	"\x18PauseSubscriptionRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12\x82\x01\n" +
+	"\x0eeffective_date\x18\x02 \x01(\tBV\x92A2*0Первый месяц приостановки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x00R\reffectiveDate\x88\x01\x01\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestIdB\x11\n" +
+	"\x0f_effective_date\"R\n" +
+	"\x19PauseSubscriptionResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"\x9f\x03\n" +
+	"\x19ResumeSubscriptionRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12\x8d\x01\n" +
+	"\x0eeffective_date\x18\x02 \x01(\tBa\x92A=*;Первый месяц после приостановки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x00R\reffectiveDate\x88\x01\x01\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestIdB\x11\n" +
+	"\x0f_effective_date\"S\n" +
+	"\x1aResumeSubscriptionResponse\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\"\x9f\x01\n" +
+	"\n" +
+	"PromoPhase\x12Y\n" +
+	"\x06months\x18\x01 \x01(\x05BA\x92A5*3Длительность фазы в месяцах\xbaH\x06\x1a\x04\x18x \x00R\x06months\x126\n" +
+	"\x05price\x18\x02 \x01(\x05B \x92A\x16*\x14Цена в фазе\xbaH\x04\x1a\x02(\x00R\x05price\"\xac\x02\n" +
+	"\x17ListEndingTrialsRequest\x12\xac\x01\n" +
+	"\vwithin_days\x18\x01 \x01(\x05B\x85\x01\x92Ax*vКоличество дней, в течение которых заканчивается пробный период\xbaH\a\x1a\x05\x18\xee\x02 \x00H\x00R\n" +
+	"withinDays\x88\x01\x01\x12F\n" +
+	"\auser_id\x18\x02 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x01R\x06userId\x88\x01\x01B\x0e\n" +
+	"\f_within_daysB\n" +
+	"\n" +
+	"\b_user_id\"D\n" +
+	"\x18ListEndingTrialsResponse\x12(\n" +
+	"\x06trials\x18\x01 \x03(\v2\x10.api.EndingTrialR\x06trials\"\xb0\x02\n" +
+	"\vEndingTrial\x125\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x11.api.SubscriptionR\fsubscription\x12q\n" +
+	"\rtrial_ends_on\x18\x02 \x01(\tBM\x92AJ*HПоследний день пробного периода (YYYY-MM-DD)R\vtrialEndsOn\x12w\n" +
+	"\tdays_left\x18\x03 \x01(\x05BZ\x92AW*UКоличество дней до окончания пробного периодаR\bdaysLeft\"n\n" +
+	"\x1eListSubscriptionHistoryRequest\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\"Z\n" +
+	"\x1fListSubscriptionHistoryResponse\x127\n" +
+	"\aentries\x18\x01 \x03(\v2\x1d.api.SubscriptionHistoryEntryR\aentries\"\xb0\t\n" +
+	"\x18SubscriptionHistoryEntry\x123\n" +
+	"\x02id\x18\x01 \x01(\x03B#\x92A *\x1eID записи журналаR\x02id\x12A\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x12.api.HistoryActionB\x15\x92A\x12*\x10ДействиеR\x06action\x12\xe7\x01\n" +
+	"\x05actor\x18\x03 \x01(\tB\xd0\x01\x92A\xcc\x01*\xc9\x01Автор изменения: субъект аутентифицированного клиента, unauthenticated без аутентификации или system для фоновых задачR\x05actor\x12\\\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tB8\x92A5*3ID запроса (заголовок X-Request-Id)H\x00R\trequestId\x88\x01\x01\x12L\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\tB-\x92A**(Время изменения (RFC 3339)R\tchangedAt\x12N\n" +
+	"\achanges\x18\x06 \x03(\v2\x10.api.FieldChangeB\"\x92A\x1f*\x1dИзмененные поляR\achanges\x12\x92\x01\n" +
+	"\x06before\x18\a \x01(\v2\x11.api.SubscriptionBb\x92A_*]Подписка до изменения, не заполняется при созданииH\x01R\x06before\x88\x01\x01\x12\xb3\x01\n" +
+	"\x05after\x18\b \x01(\v2\x11.api.SubscriptionB\x84\x01\x92A\x80\x01*~Подписка после изменения, не заполняется при окончательном удаленииH\x02R\x05after\x88\x01\x01\x12\xb4\x01\n" +
+	"\rclaimed_actor\x18\t \x01(\tB\x89\x01\x92A\x85\x01*\x82\x01Автор, которого клиент указал в заголовке X-Actor. Не проверяется серверомH\x03R\fclaimedActor\x88\x01\x01B\r\n" +
+	"\v_request_idB\t\n" +
+	"\a_beforeB\b\n" +
+	"\x06_afterB\x10\n" +
+	"\x0e_claimed_actor\"\x83\x02\n" +
+	"\vFieldChange\x124\n" +
+	"\x05field\x18\x01 \x01(\tB\x1e\x92A\x1b*\x19Поле подпискиR\x05field\x12\\\n" +
+	"\x06before\x18\x02 \x01(\tBD\x92AA*?Значение до изменения в формате JSONR\x06before\x12`\n" +
+	"\x05after\x18\x03 \x01(\tBJ\x92AG*EЗначение после изменения в формате JSONR\x05after\"\x90\x03\n" +
+	"\x19WatchSubscriptionsRequest\x12\xa8\x01\n" +
+	"\auser_id\x18\x01 \x01(\tB\x89\x01\x92A~*|ID пользователя, по умолчанию изменения подписок всех пользователей\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12\xa8\x01\n" +
+	"\x0eafter_event_id\x18\x02 \x01(\x03B}\x92As*qID последнего полученного события, чтобы получить пропущенные\xbaH\x04\"\x02 \x00H\x01R\fafterEventId\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\x11\n" +
+	"\x0f_after_event_id\"\xf4\x05\n" +
+	"\x11SubscriptionEvent\x121\n" +
+	"\bevent_id\x18\x01 \x01(\x03B\x16\x92A\x13*\x11ID событияR\aeventId\x12s\n" +
+	"\x04type\x18\x02 \x01(\tB_\x92A\\*ZТип события: SubscriptionCreated, SubscriptionUpdated или SubscriptionDeletedR\x04type\x12A\n" +
+	"\x0fsubscription_id\x18\x03 \x01(\tB\x18\x92A\x15*\x13ID подпискиR\x0esubscriptionId\x12A\n" +
+	"\x06action\x18\x04 \x01(\x0e2\x12.api.HistoryActionB\x15\x92A\x12*\x10ДействиеR\x06action\x12j\n" +
+	"\fsubscription\x18\x05 \x01(\v2\x11.api.SubscriptionB3\x92A0*.Подписка после измененияR\fsubscription\x12N\n" +
+	"\achanges\x18\x06 \x03(\v2\x10.api.FieldChangeB\"\x92A\x1f*\x1dИзмененные поляR\achanges\x128\n" +
+	"\x05actor\x18\a \x01(\tB\"\x92A\x1f*\x1dАвтор измененияR\x05actor\x12\\\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tB8\x92A5*3ID запроса (заголовок X-Request-Id)H\x00R\trequestId\x88\x01\x01\x12N\n" +
+	"\voccurred_at\x18\t \x01(\tB-\x92A**(Время изменения (RFC 3339)R\n" +
+	"occurredAtB\r\n" +
+	"\v_request_id\"\xf1\x05\n" +
+	"\aWebhook\x125\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tB\x16\x92A\x13*\x11ID вебхукаR\twebhookId\x12^\n" +
+	"\x03url\x18\x02 \x01(\tBL\x92AI*GАдрес, на который отправляются событияR\x03url\x127\n" +
+	"\vdescription\x18\x03 \x01(\tB\x15\x92A\x12*\x10ОписаниеR\vdescription\x12p\n" +
+	"\vevent_types\x18\x04 \x03(\tBO\x92AL*JТипы событий, пустой список - все событияR\n" +
+	"eventTypes\x12P\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tB1\x92A.*,Время регистрации (RFC 3339)R\tcreatedAt\x12s\n" +
+	"\x11secret_rotated_at\x18\x06 \x01(\tBG\x92AD*BВремя выдачи текущего секрета (RFC 3339)R\x0fsecretRotatedAt\x12\xbd\x01\n" +
+	"\x1aprevious_secret_expires_at\x18\a \x01(\tB{\x92Ax*vДо этого времени запросы подписываются и прежним секретом (RFC 3339)H\x00R\x17previousSecretExpiresAt\x88\x01\x01B\x1d\n" +
+	"\x1b_previous_secret_expires_at\"\xec\x02\n" +
+	"\x14CreateWebhookRequest\x12X\n" +
+	"\x03url\x18\x01 \x01(\tBF\x92A5*3Адрес получателя (http или https)\xbaH\v\xc8\x01\x01r\x06\x18\x80\x10\x88\x01\x01R\x03url\x12?\n" +
+	"\vdescription\x18\x02 \x01(\tB\x1d\x92A\x12*\x10Описание\xbaH\x05r\x03\x18\xff\x01R\vdescription\x12\xb8\x01\n" +
+	"\vevent_types\x18\x03 \x03(\tB\x96\x01\x92AH*FТипы событий, по умолчанию все события\xbaHH\x92\x01E\x18\x01\"Ar?R\x13SubscriptionCreatedR\x13SubscriptionUpdatedR\x13SubscriptionDeletedR\n" +
+	"eventTypes\"\xa5\x01\n" +
+	"\x15CreateWebhookResponse\x12&\n" +
+	"\awebhook\x18\x01 \x01(\v2\f.api.WebhookR\awebhook\x12d\n" +
+	"\x06secret\x18\x02 \x01(\tBL\x92AI*GСекрет для проверки подписи X-Webhook-SignatureR\x06secret\"\x15\n" +
+	"\x13ListWebhooksRequest\"@\n" +
+	"\x14ListWebhooksResponse\x12(\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\f.api.WebhookR\bwebhooks\"X\n" +
+	"\x14DeleteWebhookRequest\x12@\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tB!\x92A\x13*\x11ID вебхука\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\twebhookId\"\x17\n" +
+	"\x15DeleteWebhookResponse\"\x88\x02\n" +
+	"\x1aRotateWebhookSecretRequest\x12@\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tB!\x92A\x13*\x11ID вебхука\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\twebhookId\x12\xa7\x01\n" +
+	"\x0frevoke_previous\x18\x02 \x01(\bB~\x92A{*yОтозвать прежний секрет сразу, например, если он скомпрометированR\x0erevokePrevious\"\xb6\x01\n" +
+	"\x1bRotateWebhookSecretResponse\x12&\n" +
+	"\awebhook\x18\x01 \x01(\v2\f.api.WebhookR\awebhook\x12o\n" +
+	"\x06secret\x18\x02 \x01(\tBW\x92AT*RНовый секрет для проверки подписи X-Webhook-SignatureR\x06secret\"\x98\x03\n" +
+	"\x1cListWebhookDeliveriesRequest\x12@\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tB!\x92A\x13*\x11ID вебхука\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\twebhookId\x12\x94\x01\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.api.WebhookDeliveryStatusB[\x92AN*LСтатус доставок, по умолчанию все статусы\xbaH\a\x82\x01\x04\x10\x01 \x00H\x00R\x06status\x88\x01\x01\x12\x85\x01\n" +
+	"\tpage_size\x18\x03 \x01(\x05Bc\x92AV*TКоличество последних доставок, по умолчанию 50\xbaH\a\x1a\x05\x18\xf4\x03 \x00H\x01R\bpageSize\x88\x01\x01B\t\n" +
+	"\a_statusB\f\n" +
+	"\n" +
+	"_page_size\"U\n" +
+	"\x1dListWebhookDeliveriesResponse\x124\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x14.api.WebhookDeliveryR\n" +
+	"deliveries\"\xa2\x01\n" +
+	"\x1cReplayWebhookDeliveryRequest\x12@\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tB!\x92A\x13*\x11ID вебхука\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\twebhookId\x12@\n" +
+	"\vdelivery_id\x18\x02 \x01(\x03B\x1f\x92A\x15*\x13ID доставки\xbaH\x04\"\x02 \x00R\n" +
+	"deliveryId\"Q\n" +
+	"\x1dReplayWebhookDeliveryResponse\x120\n" +
+	"\bdelivery\x18\x01 \x01(\v2\x14.api.WebhookDeliveryR\bdelivery\"\xbe\v\n" +
+	"\x0fWebhookDelivery\x12d\n" +
+	"\vdelivery_id\x18\x01 \x01(\x03BC\x92A@*>ID доставки (заголовок X-Webhook-Delivery-Id)R\n" +
+	"deliveryId\x125\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tB\x16\x92A\x13*\x11ID вебхукаR\twebhookId\x12Q\n" +
+	"\bevent_id\x18\x03 \x01(\x03B6\x92A3*1ID события (заголовок X-Event-Id)R\aeventId\x129\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tB\x1a\x92A\x17*\x15Тип событияR\teventType\x12V\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1a.api.WebhookDeliveryStatusB\"\x92A\x1f*\x1dСтатус доставкиR\x06status\x12D\n" +
+	"\battempts\x18\x06 \x01(\x05B(\x92A%*#Количество попытокR\battempts\x12\x93\x01\n" +
+	"\x0fnext_attempt_at\x18\a \x01(\tBf\x92Ac*aВремя следующей попытки для доставки в очереди (RFC 3339)H\x00R\rnextAttemptAt\x88\x01\x01\x12i\n" +
+	"\x0flast_attempt_at\x18\b \x01(\tB<\x92A9*7Время последней попытки (RFC 3339)H\x01R\rlastAttemptAt\x88\x01\x01\x12x\n" +
+	"\x0fresponse_status\x18\t \x01(\x05BJ\x92AG*EHTTP-статус ответа на последнюю попыткуH\x02R\x0eresponseStatus\x88\x01\x01\x12x\n" +
+	"\rresponse_body\x18\n" +
+	" \x01(\tBN\x92AK*IНачало тела ответа на последнюю попыткуH\x03R\fresponseBody\x88\x01\x01\x12W\n" +
+	"\n" +
+	"last_error\x18\v \x01(\tB3\x92A0*.Ошибка последней попыткиH\x04R\tlastError\x88\x01\x01\x12^\n" +
+	"\apayload\x18\f \x01(\tBD\x92AA*?Тело запроса - событие в формате JSONR\apayload\x12`\n" +
+	"\n" +
+	"created_at\x18\r \x01(\tBA\x92A>*<Время постановки в очередь (RFC 3339)R\tcreatedAt\x12d\n" +
+	"\fdelivered_at\x18\x0e \x01(\tB<\x92A9*7Время успешной доставки (RFC 3339)H\x05R\vdeliveredAt\x88\x01\x01B\x12\n" +
+	"\x10_next_attempt_atB\x12\n" +
+	"\x10_last_attempt_atB\x12\n" +
+	"\x10_response_statusB\x10\n" +
+	"\x0e_response_bodyB\r\n" +
+	"\v_last_errorB\x0f\n" +
+	"\r_delivered_at\"\x80\b\n" +
+	"\x06APIKey\x120\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tB\x12\x92A\x0f*\rID ключаR\bapiKeyId\x12~\n" +
+	"\x04name\x18\x02 \x01(\tBj\x92Ag*eНазвание, например, имя задачи, которая использует ключR\x04name\x12j\n" +
+	"\x06prefix\x18\x03 \x01(\tBR\x92AO*MНачало ключа, по которому его можно узнатьR\x06prefix\x12:\n" +
+	"\x06scopes\x18\x04 \x03(\tB\"\x92A\x1f*\x1dОбласти доступаR\x06scopes\x12\xc9\x01\n" +
+	"\auser_id\x18\x05 \x01(\tB\xaa\x01\x92A\xa6\x01*\xa3\x01Пользователь, подписками которого ограничен ключ. Без него - подписки всех пользователейH\x00R\x06userId\x88\x01\x01\x12H\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tB)\x92A&*$Время выпуска (RFC 3339)R\tcreatedAt\x12b\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\tB>\x92A;*9Время окончания действия (RFC 3339)H\x01R\texpiresAt\x88\x01\x01\x12\x99\x01\n" +
+	"\flast_used_at\x18\b \x01(\tBr\x92Ao*mВремя последнего использования с точностью до минуты (RFC 3339)H\x02R\n" +
+	"lastUsedAt\x88\x01\x01\x12K\n" +
+	"\n" +
+	"revoked_at\x18\t \x01(\tB'\x92A$*\"Время отзыва (RFC 3339)H\x03R\trevokedAt\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\r\n" +
+	"\v_expires_atB\x0f\n" +
+	"\r_last_used_atB\r\n" +
+	"\v_revoked_at\"\xd1\x04\n" +
+	"\x13CreateAPIKeyRequest\x12\x89\x01\n" +
+	"\x04name\x18\x01 \x01(\tBu\x92Ag*eНазвание, например, имя задачи, которая использует ключ\xbaH\b\xc8\x01\x01r\x03\x18\xff\x01R\x04name\x12\x7f\n" +
+	"\x06scopes\x18\x02 \x03(\tBg\x92A\x1f*\x1dОбласти доступа\xbaHB\x92\x01?\b\x01\x18\x01\"9r7R\x12subscriptions:readR\x13subscriptions:writeR\freports:readR\x06scopes\x12v\n" +
+	"\auser_id\x18\x03 \x01(\tBX\x92AM*KОграничить ключ подписками пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12\x99\x01\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tBu\x92Ar*pВремя окончания действия (RFC 3339), по умолчанию ключ бессрочныйH\x01R\texpiresAt\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\r\n" +
+	"\v_expires_at\"\x8d\x01\n" +
+	"\x14CreateAPIKeyResponse\x12$\n" +
+	"\aapi_key\x18\x01 \x01(\v2\v.api.APIKeyR\x06apiKey\x12O\n" +
+	"\x03key\x18\x02 \x01(\tB=\x92A:*8Ключ для заголовка Authorization: BearerR\x03key\"\x14\n" +
+	"\x12ListAPIKeysRequest\"=\n" +
+	"\x13ListAPIKeysResponse\x12&\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\v.api.APIKeyR\aapiKeys\"R\n" +
+	"\x13RevokeAPIKeyRequest\x12;\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tB\x1d\x92A\x0f*\rID ключа\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\bapiKeyId\"<\n" +
+	"\x14RevokeAPIKeyResponse\x12$\n" +
+	"\aapi_key\x18\x01 \x01(\v2\v.api.APIKeyR\x06apiKey\"\xd7\n" +
+	"\n" +
+	"\x18AddSubscriptionV2Request\x12D\n" +
+	"\auser_id\x18\x01 \x01(\tB+\x92A\x1d*\x1bID пользователя\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x06userId\x12[\n" +
+	"\fservice_name\x18\x02 \x01(\tB8\x92A+*)Наименование подписки\xbaH\a\xc8\x01\x01r\x02\x10\x03R\vserviceName\x12P\n" +
+	"\x05price\x18\x03 \x01(\v2\n" +
+	".api.MoneyB.\x92A%*#Стоимость подписки\xbaH\x03\xc8\x01\x01R\x05price\x12n\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tBO\x92A(*&Дата старта подписки\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12r\n" +
+	"\bend_date\x18\x05 \x01(\tBR\x92A.*,Дата окончания подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x00R\aendDate\x88\x01\x01\x12\xa8\x01\n" +
+	"\x0ebilling_period\x18\x06 \x01(\x0e2\x12.api.BillingPeriodBh\x92A]*[Периодичность списаний, по умолчанию BILLING_PERIOD_MONTHLY\xbaH\x05\x82\x01\x02\x10\x01H\x01R\rbillingPeriod\x88\x01\x01\x12\x9b\x01\n" +
+	"\x17billing_interval_months\x18\a \x01(\x05B^\x92AR*PИнтервал списаний в месяцах для BILLING_PERIOD_CUSTOM\xbaH\x06\x1a\x04\x18x \x00H\x02R\x15billingIntervalMonths\x88\x01\x01\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestId\x12\xb7\x01\n" +
+	"\ftrial_months\x18\t \x01(\x05B\x8e\x01\x92A\x81\x01*\x7fДлительность бесплатного пробного периода в месяцах, начиная с start_date\xbaH\x06\x1a\x04\x18\x18 \x00H\x03R\vtrialMonths\x88\x01\x01\x12\x7f\n" +
+	"\fpromo_phases\x18\n" +
+	" \x03(\v2\x11.api.PromoPhaseV2BI\x92A>*<Льготные фазы со сниженной ценой\xbaH\x05\x92\x01\x02\x10\fR\vpromoPhasesB\v\n" +
+	"\t_end_dateB\x11\n" +
+	"\x0f_billing_periodB\x1a\n" +
+	"\x18_billing_interval_monthsB\x0f\n" +
+	"\r_trial_months\"\x9a\x02\n" +
+	"\x19AddSubscriptionV2Response\x127\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x13.api.SubscriptionV2R\fsubscription\x12\xc3\x01\n" +
+	"\boverlaps\x18\x02 \x03(\v2\x13.api.SubscriptionV2B\x91\x01\x92A\x8d\x01*\x8a\x01Пересекающиеся подписки того же пользователя на тот же сервис (политика warn)R\boverlaps\"h\n" +
+	"\x18GetSubscriptionV2Request\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\"T\n" +
+	"\x19GetSubscriptionV2Response\x127\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x13.api.SubscriptionV2R\fsubscription\"\xdb\x14\n" +
+	"\x19GetSubscriptionsV2Request\x12b\n" +
+	"\x05count\x18\x01 \x01(\x05BG\x92A=*;Количество подписок на страницу\xbaH\x04\x1a\x02 \x00H\x00R\x05count\x88\x01\x01\x12@\n" +
+	"\x04page\x18\x02 \x01(\x05B'\x92A\x1d*\x1bНомер страницы\xbaH\x04\x1a\x02 \x00H\x01R\x04page\x88\x01\x01\x12F\n" +
+	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x02R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x01H\x03R\vserviceName\x88\x01\x01\x12\xd3\x01\n" +
+	"\x12service_name_match\x18\x05 \x01(\x0e2\x15.api.ServiceNameMatchB\x88\x01\x92A}*{Способ сравнения наименования подписки, по умолчанию SERVICE_NAME_MATCH_EXACT\xbaH\x05\x82\x01\x02\x10\x01H\x04R\x10serviceNameMatch\x88\x01\x01\x12\x85\x01\n" +
+	"\tactive_at\x18\x06 \x01(\tBc\x92A?*=Месяц, в котором подписка активна\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x05R\bactiveAt\x88\x01\x01\x12\xbe\x01\n" +
+	"\tmin_price\x18\a \x01(\v2\n" +
+	".api.MoneyB\x94\x01\x92A\x90\x01*\x8d\x01Минимальная стоимость подписки, выбираются только подписки в валюте фильтраR\bminPrice\x12\xc0\x01\n" +
+	"\tmax_price\x18\b \x01(\v2\n" +
+	".api.MoneyB\x96\x01\x92A\x92\x01*\x8f\x01Максимальная стоимость подписки, выбираются только подписки в валюте фильтраR\bmaxPrice\x12d\n" +
+	"\x0fopen_ended_only\x18\t \x01(\bB7\x92A4*2Только бессрочные подпискиH\x06R\ropenEndedOnly\x88\x01\x01\x12\xe6\x01\n" +
+	"\border_by\x18\n" +
+	" \x01(\tB\xc5\x01\x92A\x8b\x01*\x88\x01Сортировка: start_date, price или service_name с необязательным asc/desc, по умолчанию start_date\xbaH3r12/^(start_date|price|service_name)( (asc|desc))?$H\aR\aorderBy\x88\x01\x01\x12j\n" +
+	"\tpage_size\x18\v \x01(\x05BH\x92A;*9Размер страницы, по умолчанию 10\xbaH\a\x1a\x05\x18\xe8\a \x00H\bR\bpageSize\x88\x01\x01\x12x\n" +
+	"\n" +
+	"page_token\x18\f \x01(\tBY\x92AV*TТокен страницы из next_page_token предыдущего ответаR\tpageToken\x12\x9c\x01\n" +
+	"\x12include_total_size\x18\r \x01(\bBn\x92Ak*iВернуть общее количество подписок, подходящих под фильтрR\x10includeTotalSize\x12\xad\x01\n" +
+	"\adeleted\x18\x0e \x01(\x0e2\x12.api.DeletedFilterBz\x92Ao*mПоказывать ли удаленные подписки, по умолчанию DELETED_FILTER_EXCLUDE\xbaH\x05\x82\x01\x02\x10\x01H\tR\adeleted\x88\x01\x01:\xfa\x03\xbaH\xf6\x03\x1a\x9d\x02\n" +
+	"\vprice_range\x12imin_price and max_price must have the same currency and min_price must be less than or equal to max_price\x1a\xa2\x01!has(this.min_price) || !has(this.max_price) || (this.min_price.currency == this.max_price.currency && this.min_price.amount_minor <= this.max_price.amount_minor)\x1ai\n" +
+	"\x14page_token_with_page\x12'page_token cannot be combined with page\x1a(!has(this.page) || this.page_token == ''\x1ai\n" +
+	"\x14page_size_with_count\x12'page_size cannot be combined with count\x1a(!has(this.page_size) || !has(this.count)B\b\n" +
+	"\x06_countB\a\n" +
+	"\x05_pageB\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\x15\n" +
+	"\x13_service_name_matchB\f\n" +
+	"\n" +
+	"_active_atB\x12\n" +
+	"\x10_open_ended_onlyB\v\n" +
+	"\t_order_byB\f\n" +
+	"\n" +
+	"_page_sizeB\n" +
+	"\n" +
+	"\b_deleted\"\xb2\x01\n" +
+	"\x1aGetSubscriptionsV2Response\x129\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x13.api.SubscriptionV2R\rsubscriptions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\"\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x03H\x00R\ttotalSize\x88\x01\x01B\r\n" +
+	"\v_total_size\"\xfe\a\n" +
+	"\x1bUpdateSubscriptionV2Request\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12F\n" +
+	"\auser_id\x18\x02 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x03 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12J\n" +
+	"\x05price\x18\x04 \x01(\v2\n" +
+	".api.MoneyB(\x92A%*#Стоимость подпискиR\x05price\x12p\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\tBL\x92A(*&Дата старта подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x02R\tstartDate\x88\x01\x01\x12r\n" +
+	"\bend_date\x18\x06 \x01(\tBR\x92A.*,Дата окончания подписки\xbaH\x1er\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$H\x03R\aendDate\x88\x01\x01\x12l\n" +
+	"\vupdate_mask\x18\a \x01(\v2\x1a.google.protobuf.FieldMaskB/\x92A,**Маска изменяемых полейR\n" +
+	"updateMask\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestId\x12~\n" +
+	"\x04etag\x18\t \x01(\tBj\x92A`*^Ожидаемый ETag подписки, альтернатива заголовку If-Match\xbaH\x04r\x02\x18@R\x04etagB\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\r\n" +
+	"\v_start_dateB\v\n" +
+	"\t_end_date\"\x9d\x02\n" +
+	"\x1cUpdateSubscriptionV2Response\x127\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x13.api.SubscriptionV2R\fsubscription\x12\xc3\x01\n" +
+	"\boverlaps\x18\x02 \x03(\v2\x13.api.SubscriptionV2B\x91\x01\x92A\x8d\x01*\x8a\x01Пересекающиеся подписки того же пользователя на тот же сервис (политика warn)R\boverlaps\"\xf5\x04\n" +
+	"\x1fChangeSubscriptionPlanV2Request\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\x12\x98\x01\n" +
+	"\x0eeffective_date\x18\x02 \x01(\tBq\x92AJ*HМесяц, с которого действует новый тариф\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\reffectiveDate\x12[\n" +
+	"\x05price\x18\x03 \x01(\v2\n" +
+	".api.MoneyB9\x92A0*.Новая стоимость подписки\xbaH\x03\xc8\x01\x01R\x05price\x12h\n" +
+	"\fservice_name\x18\x04 \x01(\tB@\x92A6*4Новое наименование подписки\xbaH\x04r\x02\x10\x03H\x00R\vserviceName\x88\x01\x01\x12\x90\x01\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tBq\x92Af*dКлюч идемпотентности, альтернатива заголовку Idempotency-Key\xbaH\x05r\x03\x18\xff\x01R\trequestIdB\x0f\n" +
+	"\r_service_name\"[\n" +
+	" ChangeSubscriptionPlanV2Response\x127\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x13.api.SubscriptionV2R\fsubscription\"m\n" +
+	"\x1dGetSubscriptionPlansV2Request\x12L\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB#\x92A\x15*\x13ID подписки\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\x0esubscriptionId\"O\n" +
+	"\x1eGetSubscriptionPlansV2Response\x12-\n" +
+	"\x05plans\x18\x01 \x03(\v2\x17.api.SubscriptionPlanV2R\x05plans\"\x9a\x05\n" +
+	"\x1cGetSumSubscriptionsV2Request\x12n\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tBO\x92A(*&Дата старта подписки\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12p\n" +
+	"\bend_date\x18\x02 \x01(\tBU\x92A.*,Дата окончания подписки\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\aendDate\x12F\n" +
+	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12\xc6\x01\n" +
+	"\bcurrency\x18\x05 \x01(\tB\xa4\x01\x92A\x8f\x01*\x8c\x01Валюта, в которой считается сумма (ISO 4217), по умолчанию базовая валюта сервиса\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x02R\bcurrency\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\v\n" +
+	"\t_currency\"\xed\x01\n" +
+	"\x1dGetSumSubscriptionsV2Response\x12S\n" +
+	"\x05total\x18\x01 \x01(\v2\n" +
+	".api.MoneyB1\x92A.*,Итоговая сумма подписокR\x05total\x12w\n" +
+	"\x05rates\x18\x02 \x03(\v2\x11.api.ExchangeRateBN\x92AK*IКурсы валют, использованные при расчётеR\x05rates\"\xa3\x06\n" +
+	"\x1bGetSpendTimeSeriesV2Request\x12l\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tBM\x92A&*$Дата начала периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12n\n" +
+	"\bend_date\x18\x02 \x01(\tBS\x92A,**Дата окончания периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\aendDate\x12F\n" +
+	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12\xc6\x01\n" +
+	"\bcurrency\x18\x05 \x01(\tB\xa4\x01\x92A\x8f\x01*\x8c\x01Валюта, в которой считается сумма (ISO 4217), по умолчанию базовая валюта сервиса\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x02R\bcurrency\x88\x01\x01\x12\x8b\x01\n" +
+	"\vgranularity\x18\x06 \x01(\x0e2\x10.api.GranularityBW\x92AL*JРазмер интервала, по умолчанию GRANULARITY_MONTH\xbaH\x05\x82\x01\x02\x10\x01R\vgranularityB\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\v\n" +
+	"\t_currency\"\xdd\x03\n" +
+	"\x1cGetSpendTimeSeriesV2Response\x12\xb3\x01\n" +
+	"\abuckets\x18\x01 \x03(\v2\x12.api.SpendBucketV2B\x84\x01\x92A\x80\x01*~Интервалы в хронологическом порядке, включая интервалы без расходовR\abuckets\x12\x8d\x01\n" +
+	"\x05total\x18\x02 \x01(\v2\n" +
+	".api.MoneyBk\x92Ah*fИтоговая сумма, совпадает с GetSumSubscriptionsV2 за тот же периодR\x05total\x12w\n" +
+	"\x05rates\x18\x03 \x03(\v2\x11.api.ExchangeRateBN\x92AK*IКурсы валют, использованные при расчётеR\x05rates\"\xcf\x03\n" +
+	"\rSpendBucketV2\x12X\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tB9\x92A6*4Первый месяц интервала (MM-YYYY)R\tstartDate\x12Z\n" +
+	"\bend_date\x18\x02 \x01(\tB?\x92A<*:Последний месяц интервала (MM-YYYY)R\aendDate\x12Z\n" +
+	"\x06amount\x18\x03 \x01(\v2\n" +
+	".api.MoneyB6\x92A3*1Сумма расходов за интервалR\x06amount\x12\xab\x01\n" +
+	"\x14active_subscriptions\x18\x04 \x01(\x05Bx\x92Au*sКоличество подписок, активных хотя бы в одном месяце интервалаR\x13activeSubscriptions\"\xae\a\n" +
+	"\x1aGetSpendBreakdownV2Request\x12l\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tBM\x92A&*$Дата начала периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\tstartDate\x12n\n" +
+	"\bend_date\x18\x02 \x01(\tBS\x92A,**Дата окончания периода\xbaH!\xc8\x01\x01r\x1c2\x1a^(1[0-2]|0[1-9])-[0-9]{4}$R\aendDate\x12F\n" +
+	"\auser_id\x18\x03 \x01(\tB(\x92A\x1d*\x1bID пользователя\xbaH\x05r\x03\xb0\x01\x01H\x00R\x06userId\x88\x01\x01\x12]\n" +
+	"\fservice_name\x18\x04 \x01(\tB5\x92A+*)Наименование подписки\xbaH\x04r\x02\x10\x03H\x01R\vserviceName\x88\x01\x01\x12\xc6\x01\n" +
+	"\bcurrency\x18\x05 \x01(\tB\xa4\x01\x92A\x8f\x01*\x8c\x01Валюта, в которой считается сумма (ISO 4217), по умолчанию базовая валюта сервиса\xbaH\x0er\f2\n" +
+	"^[A-Z]{3}$H\x02R\bcurrency\x88\x01\x01\x12i\n" +
+	"\bgroup_by\x18\x06 \x01(\x0e2\x17.api.BreakdownDimensionB5\x92A'*%Признак группировки\xbaH\b\xc8\x01\x01\x82\x01\x02\x10\x01R\agroupBy\x12\xa2\x01\n" +
+	"\x05limit\x18\a \x01(\x05B\x86\x01\x92Ay*wКоличество крупнейших групп, остальные объединяются в строку other\xbaH\a\x1a\x05\x18\xe8\a \x00H\x03R\x05limit\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\v\n" +
+	"\t_currencyB\b\n" +
+	"\x06_limit\"\xb2\x03\n" +
+	"\x1bGetSpendBreakdownV2Response\x12\x89\x01\n" +
+	"\x04rows\x18\x01 \x03(\v2\x18.api.SpendBreakdownRowV2B[\x92AX*VГруппы по убыванию суммы, строка other - последняяR\x04rows\x12\x8d\x01\n" +
+	"\x05total\x18\x02 \x01(\v2\n" +
+	".api.MoneyBk\x92Ah*fИтоговая сумма, совпадает с GetSumSubscriptionsV2 за тот же периодR\x05total\x12w\n" +
+	"\x05rates\x18\x03 \x03(\v2\x11.api.ExchangeRateBN\x92AK*IКурсы валют, использованные при расчётеR\x05rates\"\x94\x04\n" +
+	"\x13SpendBreakdownRowV2\x12\x8b\x01\n" +
+	"\x03key\x18\x01 \x01(\tBy\x92Av*tНаименование подписки или ID пользователя, пустое для строки otherR\x03key\x12Q\n" +
+	"\x06amount\x18\x02 \x01(\v2\n" +
+	".api.MoneyB-\x92A**(Сумма расходов группыR\x06amount\x12T\n" +
+	"\x05share\x18\x03 \x01(\x01B>\x92A;*9Доля от итоговой суммы (от 0 до 1)R\x05share\x12]\n" +
+	"\rsubscriptions\x18\x04 \x01(\x05B7\x92A4*2Количество подписок группыR\rsubscriptions\x12g\n" +
+	"\x05other\x18\x05 \x01(\bBQ\x92AN*LСтрока объединяет группы за пределами limitR\x05other\"\xa5\v\n" +
+	"\x0eSubscriptionV2\x12A\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tB\x18\x92A\x15*\x13ID подпискиR\x0esubscriptionId\x129\n" +
+	"\auser_id\x18\x02 \x01(\tB \x92A\x1d*\x1bID пользователяR\x06userId\x12Q\n" +
+	"\fservice_name\x18\x03 \x01(\tB.\x92A+*)Наименование подпискиR\vserviceName\x12J\n" +
+	"\x05price\x18\x04 \x01(\v2\n" +
+	".api.MoneyB(\x92A%*#Стоимость подпискиR\x05price\x12J\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\tB+\x92A(*&Дата старта подпискиR\tstartDate\x12Q\n" +
+	"\bend_date\x18\x06 \x01(\tB1\x92A.*,Дата окончания подпискиH\x00R\aendDate\x88\x01\x01\x12k\n" +
+	"\x0ebilling_period\x18\a \x01(\x0e2\x12.api.BillingPeriodB0\x92A-*+Периодичность списанийR\rbillingPeriod\x12\x92\x01\n" +
+	"\x17billing_interval_months\x18\b \x01(\x05BU\x92AR*PИнтервал списаний в месяцах для BILLING_PERIOD_CUSTOMH\x01R\x15billingIntervalMonths\x88\x01\x01\x12S\n" +
+	"\x06status\x18\t \x01(\x0e2\x17.api.SubscriptionStatusB\"\x92A\x1f*\x1dСтатус подпискиR\x06status\x12m\n" +
+	"\x0etrial_end_date\x18\n" +
+	" \x01(\tBB\x92A?*=Последний месяц пробного периодаH\x02R\ftrialEndDate\x88\x01\x01\x12\xac\x01\n" +
+	"\n" +
+	"deleted_at\x18\v \x01(\tB\x87\x01\x92A\x83\x01*\x80\x01Время удаления подписки в формате RFC 3339, только для удаленных подписокH\x03R\tdeletedAt\x88\x01\x01\x12\x7f\n" +
+	"\aversion\x18\f \x01(\x03Be\x92Ab*`Версия подписки, увеличивается при каждом измененииR\aversion\x12\x95\x01\n" +
+	"\x04etag\x18\r \x01(\tB\x80\x01\x92A}*{ETag подписки для заголовка If-Match или поля etag при изменении и удаленииR\x04etagB\v\n" +
+	"\t_end_dateB\x1a\n" +
+	"\x18_billing_interval_monthsB\x11\n" +
+	"\x0f_trial_end_dateB\r\n" +
+	"\v_deleted_at\"\x8e\x04\n" +
+	"\x12SubscriptionPlanV2\x12Y\n" +
+	"\n" +
+	"valid_from\x18\x01 \x01(\tB:\x92A7*5Месяц начала действия тарифаR\tvalidFrom\x12`\n" +
+	"\bvalid_to\x18\x02 \x01(\tB@\x92A=*;Последний месяц действия тарифаH\x00R\avalidTo\x88\x01\x01\x12Q\n" +
+	"\fservice_name\x18\x03 \x01(\tB.\x92A+*)Наименование подпискиR\vserviceName\x12J\n" +
+	"\x05price\x18\x04 \x01(\v2\n" +
+	".api.MoneyB(\x92A%*#Стоимость подпискиR\x05price\x12\x8e\x01\n" +
+	"\x05phase\x18\x05 \x01(\x0e2\x0e.api.PlanPhaseBh\x92Ae*cФаза тарифа: пробный период, льготная или обычная ценаR\x05phaseB\v\n" +
+	"\t_valid_to\"\xac\x01\n" +
+	"\fPromoPhaseV2\x12Y\n" +
+	"\x06months\x18\x01 \x01(\x05BA\x92A5*3Длительность фазы в месяцах\xbaH\x06\x1a\x04\x18x \x00R\x06months\x12A\n" +
+	"\x05price\x18\x02 \x01(\v2\n" +
+	".api.MoneyB\x1f\x92A\x16*\x14Цена в фазе\xbaH\x03\xc8\x01\x01R\x05price*\x80\x01\n" +
+	"\rDeletedFilter\x12\x1e\n" +
+	"\x1aDELETED_FILTER_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16DELETED_FILTER_EXCLUDE\x10\x01\x12\x1a\n" +
+	"\x16DELETED_FILTER_INCLUDE\x10\x02\x12\x17\n" +
+	"\x13DELETED_FILTER_ONLY\x10\x03*\x93\x01\n" +
+	"\x10ServiceNameMatch\x12\"\n" +
+	"\x1eSERVICE_NAME_MATCH_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18SERVICE_NAME_MATCH_EXACT\x10\x01\x12\x1d\n" +
+	"\x19SERVICE_NAME_MATCH_PREFIX\x10\x02\x12\x1e\n" +
+	"\x1aSERVICE_NAME_MATCH_SIMILAR\x10\x03*p\n" +
+	"\vGranularity\x12\x1b\n" +
+	"\x17GRANULARITY_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11GRANULARITY_MONTH\x10\x01\x12\x17\n" +
+	"\x13GRANULARITY_QUARTER\x10\x02\x12\x14\n" +
+	"\x10GRANULARITY_YEAR\x10\x03*\x80\x01\n" +
+	"\x12BreakdownDimension\x12#\n" +
+	"\x1fBREAKDOWN_DIMENSION_UNSPECIFIED\x10\x00\x12$\n" +
+	" BREAKDOWN_DIMENSION_SERVICE_NAME\x10\x01\x12\x1f\n" +
+	"\x1bBREAKDOWN_DIMENSION_USER_ID\x10\x02*\xba\x01\n" +
+	"\rBillingPeriod\x12\x1e\n" +
+	"\x1aBILLING_PERIOD_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15BILLING_PERIOD_WEEKLY\x10\x01\x12\x1a\n" +
+	"\x16BILLING_PERIOD_MONTHLY\x10\x02\x12\x1c\n" +
+	"\x18BILLING_PERIOD_QUARTERLY\x10\x03\x12\x19\n" +
+	"\x15BILLING_PERIOD_YEARLY\x10\x04\x12\x19\n" +
+	"\x15BILLING_PERIOD_CUSTOM\x10\x05*\xdc\x01\n" +
+	"\x12SubscriptionStatus\x12#\n" +
+	"\x1fSUBSCRIPTION_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19SUBSCRIPTION_STATUS_TRIAL\x10\x01\x12\x1e\n" +
+	"\x1aSUBSCRIPTION_STATUS_ACTIVE\x10\x02\x12\x1e\n" +
+	"\x1aSUBSCRIPTION_STATUS_PAUSED\x10\x03\x12!\n" +
+	"\x1dSUBSCRIPTION_STATUS_CANCELLED\x10\x04\x12\x1f\n" +
+	"\x1bSUBSCRIPTION_STATUS_EXPIRED\x10\x05*k\n" +
+	"\tPlanPhase\x12\x1a\n" +
+	"\x16PLAN_PHASE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10PLAN_PHASE_TRIAL\x10\x01\x12\x14\n" +
+	"\x10PLAN_PHASE_PROMO\x10\x02\x12\x16\n" +
+	"\x12PLAN_PHASE_REGULAR\x10\x03*\xf9\x02\n" +
+	"\rHistoryAction\x12\x1e\n" +
+	"\x1aHISTORY_ACTION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15HISTORY_ACTION_CREATE\x10\x01\x12\x19\n" +
+	"\x15HISTORY_ACTION_UPDATE\x10\x02\x12\x19\n" +
+	"\x15HISTORY_ACTION_DELETE\x10\x03\x12\x1b\n" +
+	"\x17HISTORY_ACTION_UNDELETE\x10\x04\x12\x1e\n" +
+	"\x1aHISTORY_ACTION_CHANGE_PLAN\x10\x05\x12\x18\n" +
+	"\x14HISTORY_ACTION_MERGE\x10\x06\x12\x19\n" +
+	"\x15HISTORY_ACTION_CANCEL\x10\a\x12\x18\n" +
+	"\x14HISTORY_ACTION_PAUSE\x10\b\x12\x19\n" +
+	"\x15HISTORY_ACTION_RESUME\x10\t\x12\x1b\n" +
+	"\x17HISTORY_ACTION_ACTIVATE\x10\n" +
+	"\x12\x19\n" +
+	"\x15HISTORY_ACTION_EXPIRE\x10\v\x12\x18\n" +
+	"\x14HISTORY_ACTION_PURGE\x10\f*\xb0\x01\n" +
+	"\x15WebhookDeliveryStatus\x12'\n" +
+	"#WEBHOOK_DELIVERY_STATUS_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fWEBHOOK_DELIVERY_STATUS_PENDING\x10\x01\x12%\n" +
+	"!WEBHOOK_DELIVERY_STATUS_SUCCEEDED\x10\x02\x12\"\n" +
+	"\x1eWEBHOOK_DELIVERY_STATUS_FAILED\x10\x032\x9bK\n" +
 	"\rSubscriptions\x12\x94\x01\n" +
 	"\x0fAddSubscription\x12\x1b.api.AddSubscriptionRequest\x1a\x1c.api.AddSubscriptionResponse\"F\x92A#\x12!Добавить подписку\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/v1/subscriptions\x12\xb0\x01\n" +
 	"\x0fGetSubscription\x12\x1b.api.GetSubscriptionRequest\x1a\x1c.api.GetSubscriptionResponse\"b\x92A0\x12.Получить подписку по её ID\x82\xd3\xe4\x93\x02)\x12'/api/v1/subscriptions/{subscription_id}\x12\x9b\x01\n" +
-	"\x10GetSubscriptions\x12\x1c.api.GetSubscriptionsRequest\x1a\x1d.api.GetSubscriptionsResponse\"J\x92A*\x12(Получить все подписки\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v1/subscriptions\x12\xaf\x01\n" +
-	"\x12UpdateSubscription\x12\x1e.api.UpdateSubscriptionRequest\x1a\x1f.api.UpdateSubscriptionResponse\"X\x92A#\x12!Обновить подписку\x82\xd3\xe4\x93\x02,:\x01*\x1a'/api/v1/subscriptions/{subscription_id}\x12\xaa\x01\n" +
-	"\x12DeleteSubscription\x12\x1e.api.DeleteSubscriptionRequest\x1a\x1f.api.DeleteSubscriptionResponse\"S\x92A!\x12\x1fУдалить подписку\x82\xd3\xe4\x93\x02)*'/api/v1/subscriptions/{subscription_id}\x12\xb4\x02\n" +
-	"\x13GetSumSubscriptions\x12\x1f.api.GetSumSubscriptionsRequest\x1a .api.GetSumSubscriptionsResponse\"\xd9\x01\x92A\xb4\x01\x12\xb1\x01Получить сумму подписок за выбранный период с фильтрацией по ID пользователя и названию подписки\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v1/subscriptions/sumB\xd5\x01\x92A\xa1\x01\x12\x9e\x01\n" +
+	"\x10GetSubscriptions\x12\x1c.api.GetSubscriptionsRequest\x1a\x1d.api.GetSubscriptionsResponse\"J\x92A*\x12(Получить все подписки\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v1/subscriptions\x12\xde\x01\n" +
+	"\x12UpdateSubscription\x12\x1e.api.UpdateSubscriptionRequest\x1a\x1f.api.UpdateSubscriptionResponse\"\x86\x01\x92A#\x12!Обновить подписку\x82\xd3\xe4\x93\x02Z:\x01*Z,:\x01*2'/api/v1/subscriptions/{subscription_id}\x1a'/api/v1/subscriptions/{subscription_id}\x12\x80\x03\n" +
+	"\x12DeleteSubscription\x12\x1e.api.DeleteSubscriptionRequest\x1a\x1f.api.DeleteSubscriptionResponse\"\xa8\x02\x92A\xf5\x01\x12\x1fУдалить подписку\x1a\xd1\x01Подписка помечается удаленной и может быть восстановлена до окончательного удаления по истечении срока хранения\x82\xd3\xe4\x93\x02)*'/api/v1/subscriptions/{subscription_id}\x12\xd9\x01\n" +
+	"\x14UndeleteSubscription\x12 .api.UndeleteSubscriptionRequest\x1a!.api.UndeleteSubscriptionResponse\"|\x92A>\x12<Восстановить удаленную подписку\x82\xd3\xe4\x93\x025:\x01*\"0/api/v1/subscriptions/{subscription_id}/undelete\x12\xff\x01\n" +
+	"\x16ChangeSubscriptionPlan\x12\".api.ChangeSubscriptionPlanRequest\x1a#.api.ChangeSubscriptionPlanResponse\"\x9b\x01\x92A`\x12^Изменить цену подписки начиная с указанного месяца\x82\xd3\xe4\x93\x022:\x01*\"-/api/v1/subscriptions/{subscription_id}/plans\x12\xd6\x01\n" +
+	"\x14GetSubscriptionPlans\x12 .api.GetSubscriptionPlansRequest\x1a!.api.GetSubscriptionPlansResponse\"y\x92AA\x12?Получить историю тарифов подписки\x82\xd3\xe4\x93\x02/\x12-/api/v1/subscriptions/{subscription_id}/plans\x12\xb4\x02\n" +
+	"\x13GetSumSubscriptions\x12\x1f.api.GetSumSubscriptionsRequest\x1a .api.GetSumSubscriptionsResponse\"\xd9\x01\x92A\xb4\x01\x12\xb1\x01Получить сумму подписок за выбранный период с фильтрацией по ID пользователя и названию подписки\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v1/subscriptions/sum\x12\xf8\x01\n" +
+	"\x12GetSpendTimeSeries\x12\x1e.api.GetSpendTimeSeriesRequest\x1a\x1f.api.GetSpendTimeSeriesResponse\"\xa0\x01\x92Aq\x12oПолучить расходы на подписки по месяцам, кварталам или годам\x82\xd3\xe4\x93\x02&\x12$/api/v1/subscriptions/sum/timeseries\x12\xff\x01\n" +
+	"\x11GetSpendBreakdown\x12\x1d.api.GetSpendBreakdownRequest\x1a\x1e.api.GetSpendBreakdownResponse\"\xaa\x01\x92A|\x12zПолучить расходы на подписки в разрезе сервисов или пользователей\x82\xd3\xe4\x93\x02%\x12#/api/v1/subscriptions/sum/breakdown\x12\x95\x02\n" +
+	"\x18ListSubscriptionOverlaps\x12$.api.ListSubscriptionOverlapsRequest\x1a%.api.ListSubscriptionOverlapsResponse\"\xab\x01\x92A\x81\x01\x12\x7fПолучить пересекающиеся подписки одного пользователя на один сервис\x82\xd3\xe4\x93\x02 \x12\x1e/api/v1/subscriptions/overlaps\x12\xe3\x01\n" +
+	"\x12MergeSubscriptions\x12\x1e.api.MergeSubscriptionsRequest\x1a\x1f.api.MergeSubscriptionsResponse\"\x8b\x01\x92AP\x12NОбъединить пересекающиеся подписки в одну\x82\xd3\xe4\x93\x022:\x01*\"-/api/v1/subscriptions/{subscription_id}/merge\x12\x82\x02\n" +
+	"\x10ListEndingTrials\x12\x1c.api.ListEndingTrialsRequest\x1a\x1d.api.ListEndingTrialsResponse\"\xb0\x01\x92A\x88\x01\x12\x85\x01Получить подписки, пробный период которых заканчивается в ближайшие дни\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/v1/subscriptions/trials\x12\xdc\x01\n" +
+	"\x12CancelSubscription\x12\x1e.api.CancelSubscriptionRequest\x1a\x1f.api.CancelSubscriptionResponse\"\x84\x01\x92AH\x12FОтменить подписку с указанного месяца\x82\xd3\xe4\x93\x023:\x01*\"./api/v1/subscriptions/{subscription_id}/cancel\x12\xe2\x01\n" +
+	"\x11PauseSubscription\x12\x1d.api.PauseSubscriptionRequest\x1a\x1e.api.PauseSubscriptionResponse\"\x8d\x01\x92AR\x12PПриостановить подписку с указанного месяца\x82\xd3\xe4\x93\x022:\x01*\"-/api/v1/subscriptions/{subscription_id}/pause\x12\x83\x02\n" +
+	"\x12ResumeSubscription\x12\x1e.api.ResumeSubscriptionRequest\x1a\x1f.api.ResumeSubscriptionResponse\"\xab\x01\x92Ao\x12mВозобновить приостановленную подписку с указанного месяца\x82\xd3\xe4\x93\x023:\x01*\"./api/v1/subscriptions/{subscription_id}/resume\x12\xe3\x01\n" +
+	"\x17ListSubscriptionHistory\x12#.api.ListSubscriptionHistoryRequest\x1a$.api.ListSubscriptionHistoryResponse\"}\x92AC\x12AПолучить журнал изменений подписки\x82\xd3\xe4\x93\x021\x12//api/v1/subscriptions/{subscription_id}/history\x12\xfc\x03\n" +
+	"\x12WatchSubscriptions\x12\x1e.api.WatchSubscriptionsRequest\x1a\x16.api.SubscriptionEvent\"\xab\x03\x92A\x84\x03\x12ZПолучать изменения подписок по мере их появления\x1a\xa5\x02HTTP Gateway отдает события как Server-Sent Events (text/event-stream): id события - event_id, тип - type. Возобновление с последнего полученного события - заголовок Last-Event-ID или параметр afterEventId\x82\xd3\xe4\x93\x02\x1d\x12\x1b/api/v1/subscriptions/watch0\x01\x12\xc4\x02\n" +
+	"\rCreateWebhook\x12\x19.api.CreateWebhookRequest\x1a\x1a.api.CreateWebhookResponse\"\xfb\x01\x92A\xdc\x01\x12-Зарегистрировать вебхук\x1a\xaa\x01Секрет для проверки подписи возвращается только в ответе на этот запрос и на ротацию секрета\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/api/v1/webhooks\x12\xa6\x01\n" +
+	"\fListWebhooks\x12\x18.api.ListWebhooksRequest\x1a\x19.api.ListWebhooksResponse\"a\x92AF\x12DПолучить зарегистрированные вебхуки\x82\xd3\xe4\x93\x02\x12\x12\x10/api/v1/webhooks\x12\xbf\x01\n" +
+	"\rDeleteWebhook\x12\x19.api.DeleteWebhookRequest\x1a\x1a.api.DeleteWebhookResponse\"w\x92AO\x12MУдалить вебхук вместе с журналом доставок\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v1/webhooks/{webhook_id}\x12\xcd\x02\n" +
+	"\x13RotateWebhookSecret\x12\x1f.api.RotateWebhookSecretRequest\x1a .api.RotateWebhookSecretResponse\"\xf2\x01\x92A\xb8\x01\x123Выдать вебхуку новый секрет\x1a\x80\x01Прежний секрет действует еще webhooks.secret_grace_period, если не передан revoke_previous\x82\xd3\xe4\x93\x020:\x01*\"+/api/v1/webhooks/{webhook_id}/rotate-secret\x12\xd2\x01\n" +
+	"\x15ListWebhookDeliveries\x12!.api.ListWebhookDeliveriesRequest\x1a\".api.ListWebhookDeliveriesResponse\"r\x92A?\x12=Получить журнал доставок вебхука\x82\xd3\xe4\x93\x02*\x12(/api/v1/webhooks/{webhook_id}/deliveries\x12\xef\x01\n" +
+	"\x15ReplayWebhookDelivery\x12!.api.ReplayWebhookDeliveryRequest\x1a\".api.ReplayWebhookDeliveryResponse\"\x8e\x01\x92AC\x12AПовторить доставку события вебхуку\x82\xd3\xe4\x93\x02B:\x01*\"=/api/v1/webhooks/{webhook_id}/deliveries/{delivery_id}/replay\x12\x94\x02\n" +
+	"\fCreateAPIKey\x12\x18.api.CreateAPIKeyRequest\x1a\x19.api.CreateAPIKeyResponse\"\xce\x01\x92A\xaf\x01\x12\x1fВыпустить API-ключ\x1a\x8b\x01Ключ возвращается только в ответе на этот запрос, сервис хранит лишь его хеш\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/api/v1/api-keys\x12\x93\x01\n" +
+	"\vListAPIKeys\x12\x17.api.ListAPIKeysRequest\x1a\x18.api.ListAPIKeysResponse\"Q\x92A6\x124Получить выпущенные API-ключи\x82\xd3\xe4\x93\x02\x12\x12\x10/api/v1/api-keys\x12\xb6\x02\n" +
+	"\fRevokeAPIKey\x12\x18.api.RevokeAPIKeyRequest\x1a\x19.api.RevokeAPIKeyResponse\"\xf0\x01\x92A\xbd\x01\x12\x1dОтозвать API-ключ\x1a\x9b\x01Отозванный ключ перестает приниматься сразу. Повторный отзыв не меняет время отзыва\x82\xd3\xe4\x93\x02):\x01*\"$/api/v1/api-keys/{api_key_id}/revoke\x12\xde\x01\n" +
+	"\x11AddSubscriptionV2\x12\x1d.api.AddSubscriptionV2Request\x1a\x1e.api.AddSubscriptionV2Response\"\x89\x01\x92Af\x12dДобавить подписку (цена в минимальных единицах валюты)\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/v2/subscriptions\x12\xfa\x01\n" +
+	"\x11GetSubscriptionV2\x12\x1d.api.GetSubscriptionV2Request\x1a\x1e.api.GetSubscriptionV2Response\"\xa5\x01\x92As\x12qПолучить подписку по её ID (цена в минимальных единицах валюты)\x82\xd3\xe4\x93\x02)\x12'/api/v2/subscriptions/{subscription_id}\x12\xe5\x01\n" +
+	"\x12GetSubscriptionsV2\x12\x1e.api.GetSubscriptionsV2Request\x1a\x1f.api.GetSubscriptionsV2Response\"\x8d\x01\x92Am\x12kПолучить все подписки (цены в минимальных единицах валюты)\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v2/subscriptions\x12\xa7\x02\n" +
+	"\x14UpdateSubscriptionV2\x12 .api.UpdateSubscriptionV2Request\x1a!.api.UpdateSubscriptionV2Response\"\xc9\x01\x92Af\x12dОбновить подписку (цена в минимальных единицах валюты)\x82\xd3\xe4\x93\x02Z:\x01*Z,:\x01*2'/api/v2/subscriptions/{subscription_id}\x1a'/api/v2/subscriptions/{subscription_id}\x12\xca\x02\n" +
+	"\x18ChangeSubscriptionPlanV2\x12$.api.ChangeSubscriptionPlanV2Request\x1a%.api.ChangeSubscriptionPlanV2Response\"\xe0\x01\x92A\xa4\x01\x12\xa1\x01Изменить цену подписки начиная с указанного месяца (цена в минимальных единицах валюты)\x82\xd3\xe4\x93\x022:\x01*\"-/api/v2/subscriptions/{subscription_id}/plans\x12\xa2\x02\n" +
+	"\x16GetSubscriptionPlansV2\x12\".api.GetSubscriptionPlansV2Request\x1a#.api.GetSubscriptionPlansV2Response\"\xbe\x01\x92A\x85\x01\x12\x82\x01Получить историю тарифов подписки (цены в минимальных единицах валюты)\x82\xd3\xe4\x93\x02/\x12-/api/v2/subscriptions/{subscription_id}/plans\x12\x92\x02\n" +
+	"\x15GetSumSubscriptionsV2\x12!.api.GetSumSubscriptionsV2Request\x1a\".api.GetSumSubscriptionsV2Response\"\xb1\x01\x92A\x8c\x01\x12\x89\x01Получить сумму подписок за выбранный период в минимальных единицах валюты\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v2/subscriptions/sum\x12\xb8\x02\n" +
+	"\x14GetSpendTimeSeriesV2\x12 .api.GetSpendTimeSeriesV2Request\x1a!.api.GetSpendTimeSeriesV2Response\"\xda\x01\x92A\xaa\x01\x12\xa7\x01Получить расходы на подписки по месяцам, кварталам или годам в минимальных единицах валюты\x82\xd3\xe4\x93\x02&\x12$/api/v2/subscriptions/sum/timeseries\x12\xbf\x02\n" +
+	"\x13GetSpendBreakdownV2\x12\x1f.api.GetSpendBreakdownV2Request\x1a .api.GetSpendBreakdownV2Response\"\xe4\x01\x92A\xb5\x01\x12\xb2\x01Получить расходы на подписки в разрезе сервисов или пользователей в минимальных единицах валюты\x82\xd3\xe4\x93\x02%\x12#/api/v2/subscriptions/sum/breakdownB\xd5\x01\x92A\xa1\x01\x12\x9e\x01\n" +
 	"\x1dСервис подписок\x12xREST-сервис для агрегации данных об онлайн-подписках пользователей2\x031.0Z.github.com/Geriler/effective-mobile/pb/api;apib\x06proto3"
 
 var (
@@ -818,44 +7421,281 @@ func file_api_subscriptions_proto_rawDescGZIP() []byte {
 	return file_api_subscriptions_proto_rawDescData
 }
 
-var file_api_subscriptions_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_api_subscriptions_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_api_subscriptions_proto_msgTypes = make([]protoimpl.MessageInfo, 93)
 var file_api_subscriptions_proto_goTypes = []any{
-	(*AddSubscriptionRequest)(nil),      // 0: api.AddSubscriptionRequest
-	(*AddSubscriptionResponse)(nil),     // 1: api.AddSubscriptionResponse
-	(*GetSubscriptionRequest)(nil),      // 2: api.GetSubscriptionRequest
-	(*GetSubscriptionResponse)(nil),     // 3: api.GetSubscriptionResponse
-	(*GetSubscriptionsRequest)(nil),     // 4: api.GetSubscriptionsRequest
-	(*GetSubscriptionsResponse)(nil),    // 5: api.GetSubscriptionsResponse
-	(*UpdateSubscriptionRequest)(nil),   // 6: api.UpdateSubscriptionRequest
-	(*UpdateSubscriptionResponse)(nil),  // 7: api.UpdateSubscriptionResponse
-	(*DeleteSubscriptionRequest)(nil),   // 8: api.DeleteSubscriptionRequest
-	(*DeleteSubscriptionResponse)(nil),  // 9: api.DeleteSubscriptionResponse
-	(*GetSumSubscriptionsRequest)(nil),  // 10: api.GetSumSubscriptionsRequest
-	(*GetSumSubscriptionsResponse)(nil), // 11: api.GetSumSubscriptionsResponse
-	(*Subscription)(nil),                // 12: api.Subscription
+	(DeletedFilter)(0),                       // 0: api.DeletedFilter
+	(ServiceNameMatch)(0),                    // 1: api.ServiceNameMatch
+	(Granularity)(0),                         // 2: api.Granularity
+	(BreakdownDimension)(0),                  // 3: api.BreakdownDimension
+	(BillingPeriod)(0),                       // 4: api.BillingPeriod
+	(SubscriptionStatus)(0),                  // 5: api.SubscriptionStatus
+	(PlanPhase)(0),                           // 6: api.PlanPhase
+	(HistoryAction)(0),                       // 7: api.HistoryAction
+	(WebhookDeliveryStatus)(0),               // 8: api.WebhookDeliveryStatus
+	(*AddSubscriptionRequest)(nil),           // 9: api.AddSubscriptionRequest
+	(*AddSubscriptionResponse)(nil),          // 10: api.AddSubscriptionResponse
+	(*GetSubscriptionRequest)(nil),           // 11: api.GetSubscriptionRequest
+	(*GetSubscriptionResponse)(nil),          // 12: api.GetSubscriptionResponse
+	(*GetSubscriptionsRequest)(nil),          // 13: api.GetSubscriptionsRequest
+	(*GetSubscriptionsResponse)(nil),         // 14: api.GetSubscriptionsResponse
+	(*UpdateSubscriptionRequest)(nil),        // 15: api.UpdateSubscriptionRequest
+	(*UpdateSubscriptionResponse)(nil),       // 16: api.UpdateSubscriptionResponse
+	(*DeleteSubscriptionRequest)(nil),        // 17: api.DeleteSubscriptionRequest
+	(*DeleteSubscriptionResponse)(nil),       // 18: api.DeleteSubscriptionResponse
+	(*UndeleteSubscriptionRequest)(nil),      // 19: api.UndeleteSubscriptionRequest
+	(*UndeleteSubscriptionResponse)(nil),     // 20: api.UndeleteSubscriptionResponse
+	(*ChangeSubscriptionPlanRequest)(nil),    // 21: api.ChangeSubscriptionPlanRequest
+	(*ChangeSubscriptionPlanResponse)(nil),   // 22: api.ChangeSubscriptionPlanResponse
+	(*GetSubscriptionPlansRequest)(nil),      // 23: api.GetSubscriptionPlansRequest
+	(*GetSubscriptionPlansResponse)(nil),     // 24: api.GetSubscriptionPlansResponse
+	(*SubscriptionPlan)(nil),                 // 25: api.SubscriptionPlan
+	(*GetSumSubscriptionsRequest)(nil),       // 26: api.GetSumSubscriptionsRequest
+	(*GetSumSubscriptionsResponse)(nil),      // 27: api.GetSumSubscriptionsResponse
+	(*GetSpendTimeSeriesRequest)(nil),        // 28: api.GetSpendTimeSeriesRequest
+	(*GetSpendTimeSeriesResponse)(nil),       // 29: api.GetSpendTimeSeriesResponse
+	(*SpendBucket)(nil),                      // 30: api.SpendBucket
+	(*GetSpendBreakdownRequest)(nil),         // 31: api.GetSpendBreakdownRequest
+	(*GetSpendBreakdownResponse)(nil),        // 32: api.GetSpendBreakdownResponse
+	(*SpendBreakdownRow)(nil),                // 33: api.SpendBreakdownRow
+	(*ExchangeRate)(nil),                     // 34: api.ExchangeRate
+	(*Subscription)(nil),                     // 35: api.Subscription
+	(*Money)(nil),                            // 36: api.Money
+	(*ListSubscriptionOverlapsRequest)(nil),  // 37: api.ListSubscriptionOverlapsRequest
+	(*ListSubscriptionOverlapsResponse)(nil), // 38: api.ListSubscriptionOverlapsResponse
+	(*SubscriptionOverlap)(nil),              // 39: api.SubscriptionOverlap
+	(*MergeSubscriptionsRequest)(nil),        // 40: api.MergeSubscriptionsRequest
+	(*MergeSubscriptionsResponse)(nil),       // 41: api.MergeSubscriptionsResponse
+	(*CancelSubscriptionRequest)(nil),        // 42: api.CancelSubscriptionRequest
+	(*CancelSubscriptionResponse)(nil),       // 43: api.CancelSubscriptionResponse
+	(*PauseSubscriptionRequest)(nil),         // 44: api.PauseSubscriptionRequest
+	(*PauseSubscriptionResponse)(nil),        // 45: api.PauseSubscriptionResponse
+	(*ResumeSubscriptionRequest)(nil),        // 46: api.ResumeSubscriptionRequest
+	(*ResumeSubscriptionResponse)(nil),       // 47: api.ResumeSubscriptionResponse
+	(*PromoPhase)(nil),                       // 48: api.PromoPhase
+	(*ListEndingTrialsRequest)(nil),          // 49: api.ListEndingTrialsRequest
+	(*ListEndingTrialsResponse)(nil),         // 50: api.ListEndingTrialsResponse
+	(*EndingTrial)(nil),                      // 51: api.EndingTrial
+	(*ListSubscriptionHistoryRequest)(nil),   // 52: api.ListSubscriptionHistoryRequest
+	(*ListSubscriptionHistoryResponse)(nil),  // 53: api.ListSubscriptionHistoryResponse
+	(*SubscriptionHistoryEntry)(nil),         // 54: api.SubscriptionHistoryEntry
+	(*FieldChange)(nil),                      // 55: api.FieldChange
+	(*WatchSubscriptionsRequest)(nil),        // 56: api.WatchSubscriptionsRequest
+	(*SubscriptionEvent)(nil),                // 57: api.SubscriptionEvent
+	(*Webhook)(nil),                          // 58: api.Webhook
+	(*CreateWebhookRequest)(nil),             // 59: api.CreateWebhookRequest
+	(*CreateWebhookResponse)(nil),            // 60: api.CreateWebhookResponse
+	(*ListWebhooksRequest)(nil),              // 61: api.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),             // 62: api.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),             // 63: api.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),            // 64: api.DeleteWebhookResponse
+	(*RotateWebhookSecretRequest)(nil),       // 65: api.RotateWebhookSecretRequest
+	(*RotateWebhookSecretResponse)(nil),      // 66: api.RotateWebhookSecretResponse
+	(*ListWebhookDeliveriesRequest)(nil),     // 67: api.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil),    // 68: api.ListWebhookDeliveriesResponse
+	(*ReplayWebhookDeliveryRequest)(nil),     // 69: api.ReplayWebhookDeliveryRequest
+	(*ReplayWebhookDeliveryResponse)(nil),    // 70: api.ReplayWebhookDeliveryResponse
+	(*WebhookDelivery)(nil),                  // 71: api.WebhookDelivery
+	(*APIKey)(nil),                           // 72: api.APIKey
+	(*CreateAPIKeyRequest)(nil),              // 73: api.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),             // 74: api.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),               // 75: api.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),              // 76: api.ListAPIKeysResponse
+	(*RevokeAPIKeyRequest)(nil),              // 77: api.RevokeAPIKeyRequest
+	(*RevokeAPIKeyResponse)(nil),             // 78: api.RevokeAPIKeyResponse
+	(*AddSubscriptionV2Request)(nil),         // 79: api.AddSubscriptionV2Request
+	(*AddSubscriptionV2Response)(nil),        // 80: api.AddSubscriptionV2Response
+	(*GetSubscriptionV2Request)(nil),         // 81: api.GetSubscriptionV2Request
+	(*GetSubscriptionV2Response)(nil),        // 82: api.GetSubscriptionV2Response
+	(*GetSubscriptionsV2Request)(nil),        // 83: api.GetSubscriptionsV2Request
+	(*GetSubscriptionsV2Response)(nil),       // 84: api.GetSubscriptionsV2Response
+	(*UpdateSubscriptionV2Request)(nil),      // 85: api.UpdateSubscriptionV2Request
+	(*UpdateSubscriptionV2Response)(nil),     // 86: api.UpdateSubscriptionV2Response
+	(*ChangeSubscriptionPlanV2Request)(nil),  // 87: api.ChangeSubscriptionPlanV2Request
+	(*ChangeSubscriptionPlanV2Response)(nil), // 88: api.ChangeSubscriptionPlanV2Response
+	(*GetSubscriptionPlansV2Request)(nil),    // 89: api.GetSubscriptionPlansV2Request
+	(*GetSubscriptionPlansV2Response)(nil),   // 90: api.GetSubscriptionPlansV2Response
+	(*GetSumSubscriptionsV2Request)(nil),     // 91: api.GetSumSubscriptionsV2Request
+	(*GetSumSubscriptionsV2Response)(nil),    // 92: api.GetSumSubscriptionsV2Response
+	(*GetSpendTimeSeriesV2Request)(nil),      // 93: api.GetSpendTimeSeriesV2Request
+	(*GetSpendTimeSeriesV2Response)(nil),     // 94: api.GetSpendTimeSeriesV2Response
+	(*SpendBucketV2)(nil),                    // 95: api.SpendBucketV2
+	(*GetSpendBreakdownV2Request)(nil),       // 96: api.GetSpendBreakdownV2Request
+	(*GetSpendBreakdownV2Response)(nil),      // 97: api.GetSpendBreakdownV2Response
+	(*SpendBreakdownRowV2)(nil),              // 98: api.SpendBreakdownRowV2
+	(*SubscriptionV2)(nil),                   // 99: api.SubscriptionV2
+	(*SubscriptionPlanV2)(nil),               // 100: api.SubscriptionPlanV2
+	(*PromoPhaseV2)(nil),                     // 101: api.PromoPhaseV2
+	(*fieldmaskpb.FieldMask)(nil),            // 102: google.protobuf.FieldMask
 }
 var file_api_subscriptions_proto_depIdxs = []int32{
-	12, // 0: api.AddSubscriptionResponse.subscription:type_name -> api.Subscription
-	12, // 1: api.GetSubscriptionResponse.subscription:type_name -> api.Subscription
-	12, // 2: api.GetSubscriptionsResponse.subscriptions:type_name -> api.Subscription
-	12, // 3: api.UpdateSubscriptionResponse.subscription:type_name -> api.Subscription
-	0,  // 4: api.Subscriptions.AddSubscription:input_type -> api.AddSubscriptionRequest
-	2,  // 5: api.Subscriptions.GetSubscription:input_type -> api.GetSubscriptionRequest
-	4,  // 6: api.Subscriptions.GetSubscriptions:input_type -> api.GetSubscriptionsRequest
-	6,  // 7: api.Subscriptions.UpdateSubscription:input_type -> api.UpdateSubscriptionRequest
-	8,  // 8: api.Subscriptions.DeleteSubscription:input_type -> api.DeleteSubscriptionRequest
-	10, // 9: api.Subscriptions.GetSumSubscriptions:input_type -> api.GetSumSubscriptionsRequest
-	1,  // 10: api.Subscriptions.AddSubscription:output_type -> api.AddSubscriptionResponse
-	3,  // 11: api.Subscriptions.GetSubscription:output_type -> api.GetSubscriptionResponse
-	5,  // 12: api.Subscriptions.GetSubscriptions:output_type -> api.GetSubscriptionsResponse
-	7,  // 13: api.Subscriptions.UpdateSubscription:output_type -> api.UpdateSubscriptionResponse
-	9,  // 14: api.Subscriptions.DeleteSubscription:output_type -> api.DeleteSubscriptionResponse
-	11, // 15: api.Subscriptions.GetSumSubscriptions:output_type -> api.GetSumSubscriptionsResponse
-	10, // [10:16] is the sub-list for method output_type
-	4,  // [4:10] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	4,   // 0: api.AddSubscriptionRequest.billing_period:type_name -> api.BillingPeriod
+	48,  // 1: api.AddSubscriptionRequest.promo_phases:type_name -> api.PromoPhase
+	35,  // 2: api.AddSubscriptionResponse.subscription:type_name -> api.Subscription
+	35,  // 3: api.AddSubscriptionResponse.overlaps:type_name -> api.Subscription
+	35,  // 4: api.GetSubscriptionResponse.subscription:type_name -> api.Subscription
+	1,   // 5: api.GetSubscriptionsRequest.service_name_match:type_name -> api.ServiceNameMatch
+	0,   // 6: api.GetSubscriptionsRequest.deleted:type_name -> api.DeletedFilter
+	35,  // 7: api.GetSubscriptionsResponse.subscriptions:type_name -> api.Subscription
+	4,   // 8: api.UpdateSubscriptionRequest.billing_period:type_name -> api.BillingPeriod
+	102, // 9: api.UpdateSubscriptionRequest.update_mask:type_name -> google.protobuf.FieldMask
+	35,  // 10: api.UpdateSubscriptionResponse.subscription:type_name -> api.Subscription
+	35,  // 11: api.UpdateSubscriptionResponse.overlaps:type_name -> api.Subscription
+	35,  // 12: api.UndeleteSubscriptionResponse.subscription:type_name -> api.Subscription
+	35,  // 13: api.ChangeSubscriptionPlanResponse.subscription:type_name -> api.Subscription
+	25,  // 14: api.GetSubscriptionPlansResponse.plans:type_name -> api.SubscriptionPlan
+	6,   // 15: api.SubscriptionPlan.phase:type_name -> api.PlanPhase
+	34,  // 16: api.GetSumSubscriptionsResponse.rates:type_name -> api.ExchangeRate
+	2,   // 17: api.GetSpendTimeSeriesRequest.granularity:type_name -> api.Granularity
+	30,  // 18: api.GetSpendTimeSeriesResponse.buckets:type_name -> api.SpendBucket
+	34,  // 19: api.GetSpendTimeSeriesResponse.rates:type_name -> api.ExchangeRate
+	3,   // 20: api.GetSpendBreakdownRequest.group_by:type_name -> api.BreakdownDimension
+	33,  // 21: api.GetSpendBreakdownResponse.rows:type_name -> api.SpendBreakdownRow
+	34,  // 22: api.GetSpendBreakdownResponse.rates:type_name -> api.ExchangeRate
+	4,   // 23: api.Subscription.billing_period:type_name -> api.BillingPeriod
+	5,   // 24: api.Subscription.status:type_name -> api.SubscriptionStatus
+	39,  // 25: api.ListSubscriptionOverlapsResponse.overlaps:type_name -> api.SubscriptionOverlap
+	35,  // 26: api.SubscriptionOverlap.subscription:type_name -> api.Subscription
+	35,  // 27: api.SubscriptionOverlap.overlapping:type_name -> api.Subscription
+	35,  // 28: api.MergeSubscriptionsResponse.subscription:type_name -> api.Subscription
+	35,  // 29: api.CancelSubscriptionResponse.subscription:type_name -> api.Subscription
+	35,  // 30: api.PauseSubscriptionResponse.subscription:type_name -> api.Subscription
+	35,  // 31: api.ResumeSubscriptionResponse.subscription:type_name -> api.Subscription
+	51,  // 32: api.ListEndingTrialsResponse.trials:type_name -> api.EndingTrial
+	35,  // 33: api.EndingTrial.subscription:type_name -> api.Subscription
+	54,  // 34: api.ListSubscriptionHistoryResponse.entries:type_name -> api.SubscriptionHistoryEntry
+	7,   // 35: api.SubscriptionHistoryEntry.action:type_name -> api.HistoryAction
+	55,  // 36: api.SubscriptionHistoryEntry.changes:type_name -> api.FieldChange
+	35,  // 37: api.SubscriptionHistoryEntry.before:type_name -> api.Subscription
+	35,  // 38: api.SubscriptionHistoryEntry.after:type_name -> api.Subscription
+	7,   // 39: api.SubscriptionEvent.action:type_name -> api.HistoryAction
+	35,  // 40: api.SubscriptionEvent.subscription:type_name -> api.Subscription
+	55,  // 41: api.SubscriptionEvent.changes:type_name -> api.FieldChange
+	58,  // 42: api.CreateWebhookResponse.webhook:type_name -> api.Webhook
+	58,  // 43: api.ListWebhooksResponse.webhooks:type_name -> api.Webhook
+	58,  // 44: api.RotateWebhookSecretResponse.webhook:type_name -> api.Webhook
+	8,   // 45: api.ListWebhookDeliveriesRequest.status:type_name -> api.WebhookDeliveryStatus
+	71,  // 46: api.ListWebhookDeliveriesResponse.deliveries:type_name -> api.WebhookDelivery
+	71,  // 47: api.ReplayWebhookDeliveryResponse.delivery:type_name -> api.WebhookDelivery
+	8,   // 48: api.WebhookDelivery.status:type_name -> api.WebhookDeliveryStatus
+	72,  // 49: api.CreateAPIKeyResponse.api_key:type_name -> api.APIKey
+	72,  // 50: api.ListAPIKeysResponse.api_keys:type_name -> api.APIKey
+	72,  // 51: api.RevokeAPIKeyResponse.api_key:type_name -> api.APIKey
+	36,  // 52: api.AddSubscriptionV2Request.price:type_name -> api.Money
+	4,   // 53: api.AddSubscriptionV2Request.billing_period:type_name -> api.BillingPeriod
+	101, // 54: api.AddSubscriptionV2Request.promo_phases:type_name -> api.PromoPhaseV2
+	99,  // 55: api.AddSubscriptionV2Response.subscription:type_name -> api.SubscriptionV2
+	99,  // 56: api.AddSubscriptionV2Response.overlaps:type_name -> api.SubscriptionV2
+	99,  // 57: api.GetSubscriptionV2Response.subscription:type_name -> api.SubscriptionV2
+	1,   // 58: api.GetSubscriptionsV2Request.service_name_match:type_name -> api.ServiceNameMatch
+	36,  // 59: api.GetSubscriptionsV2Request.min_price:type_name -> api.Money
+	36,  // 60: api.GetSubscriptionsV2Request.max_price:type_name -> api.Money
+	0,   // 61: api.GetSubscriptionsV2Request.deleted:type_name -> api.DeletedFilter
+	99,  // 62: api.GetSubscriptionsV2Response.subscriptions:type_name -> api.SubscriptionV2
+	36,  // 63: api.UpdateSubscriptionV2Request.price:type_name -> api.Money
+	102, // 64: api.UpdateSubscriptionV2Request.update_mask:type_name -> google.protobuf.FieldMask
+	99,  // 65: api.UpdateSubscriptionV2Response.subscription:type_name -> api.SubscriptionV2
+	99,  // 66: api.UpdateSubscriptionV2Response.overlaps:type_name -> api.SubscriptionV2
+	36,  // 67: api.ChangeSubscriptionPlanV2Request.price:type_name -> api.Money
+	99,  // 68: api.ChangeSubscriptionPlanV2Response.subscription:type_name -> api.SubscriptionV2
+	100, // 69: api.GetSubscriptionPlansV2Response.plans:type_name -> api.SubscriptionPlanV2
+	36,  // 70: api.GetSumSubscriptionsV2Response.total:type_name -> api.Money
+	34,  // 71: api.GetSumSubscriptionsV2Response.rates:type_name -> api.ExchangeRate
+	2,   // 72: api.GetSpendTimeSeriesV2Request.granularity:type_name -> api.Granularity
+	95,  // 73: api.GetSpendTimeSeriesV2Response.buckets:type_name -> api.SpendBucketV2
+	36,  // 74: api.GetSpendTimeSeriesV2Response.total:type_name -> api.Money
+	34,  // 75: api.GetSpendTimeSeriesV2Response.rates:type_name -> api.ExchangeRate
+	36,  // 76: api.SpendBucketV2.amount:type_name -> api.Money
+	3,   // 77: api.GetSpendBreakdownV2Request.group_by:type_name -> api.BreakdownDimension
+	98,  // 78: api.GetSpendBreakdownV2Response.rows:type_name -> api.SpendBreakdownRowV2
+	36,  // 79: api.GetSpendBreakdownV2Response.total:type_name -> api.Money
+	34,  // 80: api.GetSpendBreakdownV2Response.rates:type_name -> api.ExchangeRate
+	36,  // 81: api.SpendBreakdownRowV2.amount:type_name -> api.Money
+	36,  // 82: api.SubscriptionV2.price:type_name -> api.Money
+	4,   // 83: api.SubscriptionV2.billing_period:type_name -> api.BillingPeriod
+	5,   // 84: api.SubscriptionV2.status:type_name -> api.SubscriptionStatus
+	36,  // 85: api.SubscriptionPlanV2.price:type_name -> api.Money
+	6,   // 86: api.SubscriptionPlanV2.phase:type_name -> api.PlanPhase
+	36,  // 87: api.PromoPhaseV2.price:type_name -> api.Money
+	9,   // 88: api.Subscriptions.AddSubscription:input_type -> api.AddSubscriptionRequest
+	11,  // 89: api.Subscriptions.GetSubscription:input_type -> api.GetSubscriptionRequest
+	13,  // 90: api.Subscriptions.GetSubscriptions:input_type -> api.GetSubscriptionsRequest
+	15,  // 91: api.Subscriptions.UpdateSubscription:input_type -> api.UpdateSubscriptionRequest
+	17,  // 92: api.Subscriptions.DeleteSubscription:input_type -> api.DeleteSubscriptionRequest
+	19,  // 93: api.Subscriptions.UndeleteSubscription:input_type -> api.UndeleteSubscriptionRequest
+	21,  // 94: api.Subscriptions.ChangeSubscriptionPlan:input_type -> api.ChangeSubscriptionPlanRequest
+	23,  // 95: api.Subscriptions.GetSubscriptionPlans:input_type -> api.GetSubscriptionPlansRequest
+	26,  // 96: api.Subscriptions.GetSumSubscriptions:input_type -> api.GetSumSubscriptionsRequest
+	28,  // 97: api.Subscriptions.GetSpendTimeSeries:input_type -> api.GetSpendTimeSeriesRequest
+	31,  // 98: api.Subscriptions.GetSpendBreakdown:input_type -> api.GetSpendBreakdownRequest
+	37,  // 99: api.Subscriptions.ListSubscriptionOverlaps:input_type -> api.ListSubscriptionOverlapsRequest
+	40,  // 100: api.Subscriptions.MergeSubscriptions:input_type -> api.MergeSubscriptionsRequest
+	49,  // 101: api.Subscriptions.ListEndingTrials:input_type -> api.ListEndingTrialsRequest
+	42,  // 102: api.Subscriptions.CancelSubscription:input_type -> api.CancelSubscriptionRequest
+	44,  // 103: api.Subscriptions.PauseSubscription:input_type -> api.PauseSubscriptionRequest
+	46,  // 104: api.Subscriptions.ResumeSubscription:input_type -> api.ResumeSubscriptionRequest
+	52,  // 105: api.Subscriptions.ListSubscriptionHistory:input_type -> api.ListSubscriptionHistoryRequest
+	56,  // 106: api.Subscriptions.WatchSubscriptions:input_type -> api.WatchSubscriptionsRequest
+	59,  // 107: api.Subscriptions.CreateWebhook:input_type -> api.CreateWebhookRequest
+	61,  // 108: api.Subscriptions.ListWebhooks:input_type -> api.ListWebhooksRequest
+	63,  // 109: api.Subscriptions.DeleteWebhook:input_type -> api.DeleteWebhookRequest
+	65,  // 110: api.Subscriptions.RotateWebhookSecret:input_type -> api.RotateWebhookSecretRequest
+	67,  // 111: api.Subscriptions.ListWebhookDeliveries:input_type -> api.ListWebhookDeliveriesRequest
+	69,  // 112: api.Subscriptions.ReplayWebhookDelivery:input_type -> api.ReplayWebhookDeliveryRequest
+	73,  // 113: api.Subscriptions.CreateAPIKey:input_type -> api.CreateAPIKeyRequest
+	75,  // 114: api.Subscriptions.ListAPIKeys:input_type -> api.ListAPIKeysRequest
+	77,  // 115: api.Subscriptions.RevokeAPIKey:input_type -> api.RevokeAPIKeyRequest
+	79,  // 116: api.Subscriptions.AddSubscriptionV2:input_type -> api.AddSubscriptionV2Request
+	81,  // 117: api.Subscriptions.GetSubscriptionV2:input_type -> api.GetSubscriptionV2Request
+	83,  // 118: api.Subscriptions.GetSubscriptionsV2:input_type -> api.GetSubscriptionsV2Request
+	85,  // 119: api.Subscriptions.UpdateSubscriptionV2:input_type -> api.UpdateSubscriptionV2Request
+	87,  // 120: api.Subscriptions.ChangeSubscriptionPlanV2:input_type -> api.ChangeSubscriptionPlanV2Request
+	89,  // 121: api.Subscriptions.GetSubscriptionPlansV2:input_type -> api.GetSubscriptionPlansV2Request
+	91,  // 122: api.Subscriptions.GetSumSubscriptionsV2:input_type -> api.GetSumSubscriptionsV2Request
+	93,  // 123: api.Subscriptions.GetSpendTimeSeriesV2:input_type -> api.GetSpendTimeSeriesV2Request
+	96,  // 124: api.Subscriptions.GetSpendBreakdownV2:input_type -> api.GetSpendBreakdownV2Request
+	10,  // 125: api.Subscriptions.AddSubscription:output_type -> api.AddSubscriptionResponse
+	12,  // 126: api.Subscriptions.GetSubscription:output_type -> api.GetSubscriptionResponse
+	14,  // 127: api.Subscriptions.GetSubscriptions:output_type -> api.GetSubscriptionsResponse
+	16,  // 128: api.Subscriptions.UpdateSubscription:output_type -> api.UpdateSubscriptionResponse
+	18,  // 129: api.Subscriptions.DeleteSubscription:output_type -> api.DeleteSubscriptionResponse
+	20,  // 130: api.Subscriptions.UndeleteSubscription:output_type -> api.UndeleteSubscriptionResponse
+	22,  // 131: api.Subscriptions.ChangeSubscriptionPlan:output_type -> api.ChangeSubscriptionPlanResponse
+	24,  // 132: api.Subscriptions.GetSubscriptionPlans:output_type -> api.GetSubscriptionPlansResponse
+	27,  // 133: api.Subscriptions.GetSumSubscriptions:output_type -> api.GetSumSubscriptionsResponse
+	29,  // 134: api.Subscriptions.GetSpendTimeSeries:output_type -> api.GetSpendTimeSeriesResponse
+	32,  // 135: api.Subscriptions.GetSpendBreakdown:output_type -> api.GetSpendBreakdownResponse
+	38,  // 136: api.Subscriptions.ListSubscriptionOverlaps:output_type -> api.ListSubscriptionOverlapsResponse
+	41,  // 137: api.Subscriptions.MergeSubscriptions:output_type -> api.MergeSubscriptionsResponse
+	50,  // 138: api.Subscriptions.ListEndingTrials:output_type -> api.ListEndingTrialsResponse
+	43,  // 139: api.Subscriptions.CancelSubscription:output_type -> api.CancelSubscriptionResponse
+	45,  // 140: api.Subscriptions.PauseSubscription:output_type -> api.PauseSubscriptionResponse
+	47,  // 141: api.Subscriptions.ResumeSubscription:output_type -> api.ResumeSubscriptionResponse
+	53,  // 142: api.Subscriptions.ListSubscriptionHistory:output_type -> api.ListSubscriptionHistoryResponse
+	57,  // 143: api.Subscriptions.WatchSubscriptions:output_type -> api.SubscriptionEvent
+	60,  // 144: api.Subscriptions.CreateWebhook:output_type -> api.CreateWebhookResponse
+	62,  // 145: api.Subscriptions.ListWebhooks:output_type -> api.ListWebhooksResponse
+	64,  // 146: api.Subscriptions.DeleteWebhook:output_type -> api.DeleteWebhookResponse
+	66,  // 147: api.Subscriptions.RotateWebhookSecret:output_type -> api.RotateWebhookSecretResponse
+	68,  // 148: api.Subscriptions.ListWebhookDeliveries:output_type -> api.ListWebhookDeliveriesResponse
+	70,  // 149: api.Subscriptions.ReplayWebhookDelivery:output_type -> api.ReplayWebhookDeliveryResponse
+	74,  // 150: api.Subscriptions.CreateAPIKey:output_type -> api.CreateAPIKeyResponse
+	76,  // 151: api.Subscriptions.ListAPIKeys:output_type -> api.ListAPIKeysResponse
+	78,  // 152: api.Subscriptions.RevokeAPIKey:output_type -> api.RevokeAPIKeyResponse
+	80,  // 153: api.Subscriptions.AddSubscriptionV2:output_type -> api.AddSubscriptionV2Response
+	82,  // 154: api.Subscriptions.GetSubscriptionV2:output_type -> api.GetSubscriptionV2Response
+	84,  // 155: api.Subscriptions.GetSubscriptionsV2:output_type -> api.GetSubscriptionsV2Response
+	86,  // 156: api.Subscriptions.UpdateSubscriptionV2:output_type -> api.UpdateSubscriptionV2Response
+	88,  // 157: api.Subscriptions.ChangeSubscriptionPlanV2:output_type -> api.ChangeSubscriptionPlanV2Response
+	90,  // 158: api.Subscriptions.GetSubscriptionPlansV2:output_type -> api.GetSubscriptionPlansV2Response
+	92,  // 159: api.Subscriptions.GetSumSubscriptionsV2:output_type -> api.GetSumSubscriptionsV2Response
+	94,  // 160: api.Subscriptions.GetSpendTimeSeriesV2:output_type -> api.GetSpendTimeSeriesV2Response
+	97,  // 161: api.Subscriptions.GetSpendBreakdownV2:output_type -> api.GetSpendBreakdownV2Response
+	125, // [125:162] is the sub-list for method output_type
+	88,  // [88:125] is the sub-list for method input_type
+	88,  // [88:88] is the sub-list for extension type_name
+	88,  // [88:88] is the sub-list for extension extendee
+	0,   // [0:88] is the sub-list for field type_name
 }
 
 func init() { file_api_subscriptions_proto_init() }
@@ -865,21 +7705,51 @@ func file_api_subscriptions_proto_init() {
 	}
 	file_api_subscriptions_proto_msgTypes[0].OneofWrappers = []any{}
 	file_api_subscriptions_proto_msgTypes[4].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[5].OneofWrappers = []any{}
 	file_api_subscriptions_proto_msgTypes[6].OneofWrappers = []any{}
-	file_api_subscriptions_proto_msgTypes[10].OneofWrappers = []any{}
 	file_api_subscriptions_proto_msgTypes[12].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[16].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[17].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[19].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[22].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[26].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[28].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[30].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[33].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[35].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[37].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[40].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[45].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[47].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[48].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[49].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[58].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[62].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[63].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[64].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[70].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[74].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[75].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[76].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[78].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[82].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[84].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[87].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[90].OneofWrappers = []any{}
+	file_api_subscriptions_proto_msgTypes[91].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_subscriptions_proto_rawDesc), len(file_api_subscriptions_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      9,
+			NumMessages:   93,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_subscriptions_proto_goTypes,
 		DependencyIndexes: file_api_subscriptions_proto_depIdxs,
+		EnumInfos:         file_api_subscriptions_proto_enumTypes,
 		MessageInfos:      file_api_subscriptions_proto_msgTypes,
 	}.Build()
 	File_api_subscriptions_proto = out.File
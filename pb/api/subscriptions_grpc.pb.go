@@ -19,12 +19,43 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Subscriptions_AddSubscription_FullMethodName     = "/api.Subscriptions/AddSubscription"
-	Subscriptions_GetSubscription_FullMethodName     = "/api.Subscriptions/GetSubscription"
-	Subscriptions_GetSubscriptions_FullMethodName    = "/api.Subscriptions/GetSubscriptions"
-	Subscriptions_UpdateSubscription_FullMethodName  = "/api.Subscriptions/UpdateSubscription"
-	Subscriptions_DeleteSubscription_FullMethodName  = "/api.Subscriptions/DeleteSubscription"
-	Subscriptions_GetSumSubscriptions_FullMethodName = "/api.Subscriptions/GetSumSubscriptions"
+	Subscriptions_AddSubscription_FullMethodName          = "/api.Subscriptions/AddSubscription"
+	Subscriptions_GetSubscription_FullMethodName          = "/api.Subscriptions/GetSubscription"
+	Subscriptions_GetSubscriptions_FullMethodName         = "/api.Subscriptions/GetSubscriptions"
+	Subscriptions_UpdateSubscription_FullMethodName       = "/api.Subscriptions/UpdateSubscription"
+	Subscriptions_DeleteSubscription_FullMethodName       = "/api.Subscriptions/DeleteSubscription"
+	Subscriptions_UndeleteSubscription_FullMethodName     = "/api.Subscriptions/UndeleteSubscription"
+	Subscriptions_ChangeSubscriptionPlan_FullMethodName   = "/api.Subscriptions/ChangeSubscriptionPlan"
+	Subscriptions_GetSubscriptionPlans_FullMethodName     = "/api.Subscriptions/GetSubscriptionPlans"
+	Subscriptions_GetSumSubscriptions_FullMethodName      = "/api.Subscriptions/GetSumSubscriptions"
+	Subscriptions_GetSpendTimeSeries_FullMethodName       = "/api.Subscriptions/GetSpendTimeSeries"
+	Subscriptions_GetSpendBreakdown_FullMethodName        = "/api.Subscriptions/GetSpendBreakdown"
+	Subscriptions_ListSubscriptionOverlaps_FullMethodName = "/api.Subscriptions/ListSubscriptionOverlaps"
+	Subscriptions_MergeSubscriptions_FullMethodName       = "/api.Subscriptions/MergeSubscriptions"
+	Subscriptions_ListEndingTrials_FullMethodName         = "/api.Subscriptions/ListEndingTrials"
+	Subscriptions_CancelSubscription_FullMethodName       = "/api.Subscriptions/CancelSubscription"
+	Subscriptions_PauseSubscription_FullMethodName        = "/api.Subscriptions/PauseSubscription"
+	Subscriptions_ResumeSubscription_FullMethodName       = "/api.Subscriptions/ResumeSubscription"
+	Subscriptions_ListSubscriptionHistory_FullMethodName  = "/api.Subscriptions/ListSubscriptionHistory"
+	Subscriptions_WatchSubscriptions_FullMethodName       = "/api.Subscriptions/WatchSubscriptions"
+	Subscriptions_CreateWebhook_FullMethodName            = "/api.Subscriptions/CreateWebhook"
+	Subscriptions_ListWebhooks_FullMethodName             = "/api.Subscriptions/ListWebhooks"
+	Subscriptions_DeleteWebhook_FullMethodName            = "/api.Subscriptions/DeleteWebhook"
+	Subscriptions_RotateWebhookSecret_FullMethodName      = "/api.Subscriptions/RotateWebhookSecret"
+	Subscriptions_ListWebhookDeliveries_FullMethodName    = "/api.Subscriptions/ListWebhookDeliveries"
+	Subscriptions_ReplayWebhookDelivery_FullMethodName    = "/api.Subscriptions/ReplayWebhookDelivery"
+	Subscriptions_CreateAPIKey_FullMethodName             = "/api.Subscriptions/CreateAPIKey"
+	Subscriptions_ListAPIKeys_FullMethodName              = "/api.Subscriptions/ListAPIKeys"
+	Subscriptions_RevokeAPIKey_FullMethodName             = "/api.Subscriptions/RevokeAPIKey"
+	Subscriptions_AddSubscriptionV2_FullMethodName        = "/api.Subscriptions/AddSubscriptionV2"
+	Subscriptions_GetSubscriptionV2_FullMethodName        = "/api.Subscriptions/GetSubscriptionV2"
+	Subscriptions_GetSubscriptionsV2_FullMethodName       = "/api.Subscriptions/GetSubscriptionsV2"
+	Subscriptions_UpdateSubscriptionV2_FullMethodName     = "/api.Subscriptions/UpdateSubscriptionV2"
+	Subscriptions_ChangeSubscriptionPlanV2_FullMethodName = "/api.Subscriptions/ChangeSubscriptionPlanV2"
+	Subscriptions_GetSubscriptionPlansV2_FullMethodName   = "/api.Subscriptions/GetSubscriptionPlansV2"
+	Subscriptions_GetSumSubscriptionsV2_FullMethodName    = "/api.Subscriptions/GetSumSubscriptionsV2"
+	Subscriptions_GetSpendTimeSeriesV2_FullMethodName     = "/api.Subscriptions/GetSpendTimeSeriesV2"
+	Subscriptions_GetSpendBreakdownV2_FullMethodName      = "/api.Subscriptions/GetSpendBreakdownV2"
 )
 
 // SubscriptionsClient is the client API for Subscriptions service.
@@ -36,7 +67,39 @@ type SubscriptionsClient interface {
 	GetSubscriptions(ctx context.Context, in *GetSubscriptionsRequest, opts ...grpc.CallOption) (*GetSubscriptionsResponse, error)
 	UpdateSubscription(ctx context.Context, in *UpdateSubscriptionRequest, opts ...grpc.CallOption) (*UpdateSubscriptionResponse, error)
 	DeleteSubscription(ctx context.Context, in *DeleteSubscriptionRequest, opts ...grpc.CallOption) (*DeleteSubscriptionResponse, error)
+	UndeleteSubscription(ctx context.Context, in *UndeleteSubscriptionRequest, opts ...grpc.CallOption) (*UndeleteSubscriptionResponse, error)
+	ChangeSubscriptionPlan(ctx context.Context, in *ChangeSubscriptionPlanRequest, opts ...grpc.CallOption) (*ChangeSubscriptionPlanResponse, error)
+	GetSubscriptionPlans(ctx context.Context, in *GetSubscriptionPlansRequest, opts ...grpc.CallOption) (*GetSubscriptionPlansResponse, error)
 	GetSumSubscriptions(ctx context.Context, in *GetSumSubscriptionsRequest, opts ...grpc.CallOption) (*GetSumSubscriptionsResponse, error)
+	GetSpendTimeSeries(ctx context.Context, in *GetSpendTimeSeriesRequest, opts ...grpc.CallOption) (*GetSpendTimeSeriesResponse, error)
+	GetSpendBreakdown(ctx context.Context, in *GetSpendBreakdownRequest, opts ...grpc.CallOption) (*GetSpendBreakdownResponse, error)
+	ListSubscriptionOverlaps(ctx context.Context, in *ListSubscriptionOverlapsRequest, opts ...grpc.CallOption) (*ListSubscriptionOverlapsResponse, error)
+	MergeSubscriptions(ctx context.Context, in *MergeSubscriptionsRequest, opts ...grpc.CallOption) (*MergeSubscriptionsResponse, error)
+	ListEndingTrials(ctx context.Context, in *ListEndingTrialsRequest, opts ...grpc.CallOption) (*ListEndingTrialsResponse, error)
+	CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error)
+	PauseSubscription(ctx context.Context, in *PauseSubscriptionRequest, opts ...grpc.CallOption) (*PauseSubscriptionResponse, error)
+	ResumeSubscription(ctx context.Context, in *ResumeSubscriptionRequest, opts ...grpc.CallOption) (*ResumeSubscriptionResponse, error)
+	ListSubscriptionHistory(ctx context.Context, in *ListSubscriptionHistoryRequest, opts ...grpc.CallOption) (*ListSubscriptionHistoryResponse, error)
+	WatchSubscriptions(ctx context.Context, in *WatchSubscriptionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscriptionEvent], error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	RotateWebhookSecret(ctx context.Context, in *RotateWebhookSecretRequest, opts ...grpc.CallOption) (*RotateWebhookSecretResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	ReplayWebhookDelivery(ctx context.Context, in *ReplayWebhookDeliveryRequest, opts ...grpc.CallOption) (*ReplayWebhookDeliveryResponse, error)
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error)
+	// API v2: денежные суммы передаются только в Money - в минимальных единицах валюты и в int64
+	AddSubscriptionV2(ctx context.Context, in *AddSubscriptionV2Request, opts ...grpc.CallOption) (*AddSubscriptionV2Response, error)
+	GetSubscriptionV2(ctx context.Context, in *GetSubscriptionV2Request, opts ...grpc.CallOption) (*GetSubscriptionV2Response, error)
+	GetSubscriptionsV2(ctx context.Context, in *GetSubscriptionsV2Request, opts ...grpc.CallOption) (*GetSubscriptionsV2Response, error)
+	UpdateSubscriptionV2(ctx context.Context, in *UpdateSubscriptionV2Request, opts ...grpc.CallOption) (*UpdateSubscriptionV2Response, error)
+	ChangeSubscriptionPlanV2(ctx context.Context, in *ChangeSubscriptionPlanV2Request, opts ...grpc.CallOption) (*ChangeSubscriptionPlanV2Response, error)
+	GetSubscriptionPlansV2(ctx context.Context, in *GetSubscriptionPlansV2Request, opts ...grpc.CallOption) (*GetSubscriptionPlansV2Response, error)
+	GetSumSubscriptionsV2(ctx context.Context, in *GetSumSubscriptionsV2Request, opts ...grpc.CallOption) (*GetSumSubscriptionsV2Response, error)
+	GetSpendTimeSeriesV2(ctx context.Context, in *GetSpendTimeSeriesV2Request, opts ...grpc.CallOption) (*GetSpendTimeSeriesV2Response, error)
+	GetSpendBreakdownV2(ctx context.Context, in *GetSpendBreakdownV2Request, opts ...grpc.CallOption) (*GetSpendBreakdownV2Response, error)
 }
 
 type subscriptionsClient struct {
@@ -97,6 +160,36 @@ func (c *subscriptionsClient) DeleteSubscription(ctx context.Context, in *Delete
 	return out, nil
 }
 
+func (c *subscriptionsClient) UndeleteSubscription(ctx context.Context, in *UndeleteSubscriptionRequest, opts ...grpc.CallOption) (*UndeleteSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UndeleteSubscriptionResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_UndeleteSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ChangeSubscriptionPlan(ctx context.Context, in *ChangeSubscriptionPlanRequest, opts ...grpc.CallOption) (*ChangeSubscriptionPlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeSubscriptionPlanResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ChangeSubscriptionPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSubscriptionPlans(ctx context.Context, in *GetSubscriptionPlansRequest, opts ...grpc.CallOption) (*GetSubscriptionPlansResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSubscriptionPlansResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSubscriptionPlans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *subscriptionsClient) GetSumSubscriptions(ctx context.Context, in *GetSumSubscriptionsRequest, opts ...grpc.CallOption) (*GetSumSubscriptionsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetSumSubscriptionsResponse)
@@ -107,6 +200,295 @@ func (c *subscriptionsClient) GetSumSubscriptions(ctx context.Context, in *GetSu
 	return out, nil
 }
 
+func (c *subscriptionsClient) GetSpendTimeSeries(ctx context.Context, in *GetSpendTimeSeriesRequest, opts ...grpc.CallOption) (*GetSpendTimeSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSpendTimeSeriesResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSpendTimeSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSpendBreakdown(ctx context.Context, in *GetSpendBreakdownRequest, opts ...grpc.CallOption) (*GetSpendBreakdownResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSpendBreakdownResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSpendBreakdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ListSubscriptionOverlaps(ctx context.Context, in *ListSubscriptionOverlapsRequest, opts ...grpc.CallOption) (*ListSubscriptionOverlapsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubscriptionOverlapsResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ListSubscriptionOverlaps_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) MergeSubscriptions(ctx context.Context, in *MergeSubscriptionsRequest, opts ...grpc.CallOption) (*MergeSubscriptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_MergeSubscriptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ListEndingTrials(ctx context.Context, in *ListEndingTrialsRequest, opts ...grpc.CallOption) (*ListEndingTrialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEndingTrialsResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ListEndingTrials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelSubscriptionResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_CancelSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) PauseSubscription(ctx context.Context, in *PauseSubscriptionRequest, opts ...grpc.CallOption) (*PauseSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseSubscriptionResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_PauseSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ResumeSubscription(ctx context.Context, in *ResumeSubscriptionRequest, opts ...grpc.CallOption) (*ResumeSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeSubscriptionResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ResumeSubscription_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ListSubscriptionHistory(ctx context.Context, in *ListSubscriptionHistoryRequest, opts ...grpc.CallOption) (*ListSubscriptionHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubscriptionHistoryResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ListSubscriptionHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) WatchSubscriptions(ctx context.Context, in *WatchSubscriptionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscriptionEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Subscriptions_ServiceDesc.Streams[0], Subscriptions_WatchSubscriptions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchSubscriptionsRequest, SubscriptionEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Subscriptions_WatchSubscriptionsClient = grpc.ServerStreamingClient[SubscriptionEvent]
+
+func (c *subscriptionsClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWebhookResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) RotateWebhookSecret(ctx context.Context, in *RotateWebhookSecretRequest, opts ...grpc.CallOption) (*RotateWebhookSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateWebhookSecretResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_RotateWebhookSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ReplayWebhookDelivery(ctx context.Context, in *ReplayWebhookDeliveryRequest, opts ...grpc.CallOption) (*ReplayWebhookDeliveryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayWebhookDeliveryResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ReplayWebhookDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_CreateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_ListAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAPIKeyResponse)
+	err := c.cc.Invoke(ctx, Subscriptions_RevokeAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) AddSubscriptionV2(ctx context.Context, in *AddSubscriptionV2Request, opts ...grpc.CallOption) (*AddSubscriptionV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddSubscriptionV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_AddSubscriptionV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSubscriptionV2(ctx context.Context, in *GetSubscriptionV2Request, opts ...grpc.CallOption) (*GetSubscriptionV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSubscriptionV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSubscriptionV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSubscriptionsV2(ctx context.Context, in *GetSubscriptionsV2Request, opts ...grpc.CallOption) (*GetSubscriptionsV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSubscriptionsV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSubscriptionsV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) UpdateSubscriptionV2(ctx context.Context, in *UpdateSubscriptionV2Request, opts ...grpc.CallOption) (*UpdateSubscriptionV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateSubscriptionV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_UpdateSubscriptionV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) ChangeSubscriptionPlanV2(ctx context.Context, in *ChangeSubscriptionPlanV2Request, opts ...grpc.CallOption) (*ChangeSubscriptionPlanV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeSubscriptionPlanV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_ChangeSubscriptionPlanV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSubscriptionPlansV2(ctx context.Context, in *GetSubscriptionPlansV2Request, opts ...grpc.CallOption) (*GetSubscriptionPlansV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSubscriptionPlansV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSubscriptionPlansV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSumSubscriptionsV2(ctx context.Context, in *GetSumSubscriptionsV2Request, opts ...grpc.CallOption) (*GetSumSubscriptionsV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSumSubscriptionsV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSumSubscriptionsV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSpendTimeSeriesV2(ctx context.Context, in *GetSpendTimeSeriesV2Request, opts ...grpc.CallOption) (*GetSpendTimeSeriesV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSpendTimeSeriesV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSpendTimeSeriesV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsClient) GetSpendBreakdownV2(ctx context.Context, in *GetSpendBreakdownV2Request, opts ...grpc.CallOption) (*GetSpendBreakdownV2Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSpendBreakdownV2Response)
+	err := c.cc.Invoke(ctx, Subscriptions_GetSpendBreakdownV2_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubscriptionsServer is the server API for Subscriptions service.
 // All implementations must embed UnimplementedSubscriptionsServer
 // for forward compatibility.
@@ -116,7 +498,39 @@ type SubscriptionsServer interface {
 	GetSubscriptions(context.Context, *GetSubscriptionsRequest) (*GetSubscriptionsResponse, error)
 	UpdateSubscription(context.Context, *UpdateSubscriptionRequest) (*UpdateSubscriptionResponse, error)
 	DeleteSubscription(context.Context, *DeleteSubscriptionRequest) (*DeleteSubscriptionResponse, error)
+	UndeleteSubscription(context.Context, *UndeleteSubscriptionRequest) (*UndeleteSubscriptionResponse, error)
+	ChangeSubscriptionPlan(context.Context, *ChangeSubscriptionPlanRequest) (*ChangeSubscriptionPlanResponse, error)
+	GetSubscriptionPlans(context.Context, *GetSubscriptionPlansRequest) (*GetSubscriptionPlansResponse, error)
 	GetSumSubscriptions(context.Context, *GetSumSubscriptionsRequest) (*GetSumSubscriptionsResponse, error)
+	GetSpendTimeSeries(context.Context, *GetSpendTimeSeriesRequest) (*GetSpendTimeSeriesResponse, error)
+	GetSpendBreakdown(context.Context, *GetSpendBreakdownRequest) (*GetSpendBreakdownResponse, error)
+	ListSubscriptionOverlaps(context.Context, *ListSubscriptionOverlapsRequest) (*ListSubscriptionOverlapsResponse, error)
+	MergeSubscriptions(context.Context, *MergeSubscriptionsRequest) (*MergeSubscriptionsResponse, error)
+	ListEndingTrials(context.Context, *ListEndingTrialsRequest) (*ListEndingTrialsResponse, error)
+	CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error)
+	PauseSubscription(context.Context, *PauseSubscriptionRequest) (*PauseSubscriptionResponse, error)
+	ResumeSubscription(context.Context, *ResumeSubscriptionRequest) (*ResumeSubscriptionResponse, error)
+	ListSubscriptionHistory(context.Context, *ListSubscriptionHistoryRequest) (*ListSubscriptionHistoryResponse, error)
+	WatchSubscriptions(*WatchSubscriptionsRequest, grpc.ServerStreamingServer[SubscriptionEvent]) error
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	RotateWebhookSecret(context.Context, *RotateWebhookSecretRequest) (*RotateWebhookSecretResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	ReplayWebhookDelivery(context.Context, *ReplayWebhookDeliveryRequest) (*ReplayWebhookDeliveryResponse, error)
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+	// API v2: денежные суммы передаются только в Money - в минимальных единицах валюты и в int64
+	AddSubscriptionV2(context.Context, *AddSubscriptionV2Request) (*AddSubscriptionV2Response, error)
+	GetSubscriptionV2(context.Context, *GetSubscriptionV2Request) (*GetSubscriptionV2Response, error)
+	GetSubscriptionsV2(context.Context, *GetSubscriptionsV2Request) (*GetSubscriptionsV2Response, error)
+	UpdateSubscriptionV2(context.Context, *UpdateSubscriptionV2Request) (*UpdateSubscriptionV2Response, error)
+	ChangeSubscriptionPlanV2(context.Context, *ChangeSubscriptionPlanV2Request) (*ChangeSubscriptionPlanV2Response, error)
+	GetSubscriptionPlansV2(context.Context, *GetSubscriptionPlansV2Request) (*GetSubscriptionPlansV2Response, error)
+	GetSumSubscriptionsV2(context.Context, *GetSumSubscriptionsV2Request) (*GetSumSubscriptionsV2Response, error)
+	GetSpendTimeSeriesV2(context.Context, *GetSpendTimeSeriesV2Request) (*GetSpendTimeSeriesV2Response, error)
+	GetSpendBreakdownV2(context.Context, *GetSpendBreakdownV2Request) (*GetSpendBreakdownV2Response, error)
 	mustEmbedUnimplementedSubscriptionsServer()
 }
 
@@ -142,9 +556,102 @@ func (UnimplementedSubscriptionsServer) UpdateSubscription(context.Context, *Upd
 func (UnimplementedSubscriptionsServer) DeleteSubscription(context.Context, *DeleteSubscriptionRequest) (*DeleteSubscriptionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteSubscription not implemented")
 }
+func (UnimplementedSubscriptionsServer) UndeleteSubscription(context.Context, *UndeleteSubscriptionRequest) (*UndeleteSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeleteSubscription not implemented")
+}
+func (UnimplementedSubscriptionsServer) ChangeSubscriptionPlan(context.Context, *ChangeSubscriptionPlanRequest) (*ChangeSubscriptionPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeSubscriptionPlan not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSubscriptionPlans(context.Context, *GetSubscriptionPlansRequest) (*GetSubscriptionPlansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptionPlans not implemented")
+}
 func (UnimplementedSubscriptionsServer) GetSumSubscriptions(context.Context, *GetSumSubscriptionsRequest) (*GetSumSubscriptionsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSumSubscriptions not implemented")
 }
+func (UnimplementedSubscriptionsServer) GetSpendTimeSeries(context.Context, *GetSpendTimeSeriesRequest) (*GetSpendTimeSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpendTimeSeries not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSpendBreakdown(context.Context, *GetSpendBreakdownRequest) (*GetSpendBreakdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpendBreakdown not implemented")
+}
+func (UnimplementedSubscriptionsServer) ListSubscriptionOverlaps(context.Context, *ListSubscriptionOverlapsRequest) (*ListSubscriptionOverlapsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptionOverlaps not implemented")
+}
+func (UnimplementedSubscriptionsServer) MergeSubscriptions(context.Context, *MergeSubscriptionsRequest) (*MergeSubscriptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeSubscriptions not implemented")
+}
+func (UnimplementedSubscriptionsServer) ListEndingTrials(context.Context, *ListEndingTrialsRequest) (*ListEndingTrialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEndingTrials not implemented")
+}
+func (UnimplementedSubscriptionsServer) CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelSubscription not implemented")
+}
+func (UnimplementedSubscriptionsServer) PauseSubscription(context.Context, *PauseSubscriptionRequest) (*PauseSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSubscription not implemented")
+}
+func (UnimplementedSubscriptionsServer) ResumeSubscription(context.Context, *ResumeSubscriptionRequest) (*ResumeSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSubscription not implemented")
+}
+func (UnimplementedSubscriptionsServer) ListSubscriptionHistory(context.Context, *ListSubscriptionHistoryRequest) (*ListSubscriptionHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptionHistory not implemented")
+}
+func (UnimplementedSubscriptionsServer) WatchSubscriptions(*WatchSubscriptionsRequest, grpc.ServerStreamingServer[SubscriptionEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSubscriptions not implemented")
+}
+func (UnimplementedSubscriptionsServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedSubscriptionsServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedSubscriptionsServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedSubscriptionsServer) RotateWebhookSecret(context.Context, *RotateWebhookSecretRequest) (*RotateWebhookSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateWebhookSecret not implemented")
+}
+func (UnimplementedSubscriptionsServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedSubscriptionsServer) ReplayWebhookDelivery(context.Context, *ReplayWebhookDeliveryRequest) (*ReplayWebhookDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayWebhookDelivery not implemented")
+}
+func (UnimplementedSubscriptionsServer) CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (UnimplementedSubscriptionsServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedSubscriptionsServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedSubscriptionsServer) AddSubscriptionV2(context.Context, *AddSubscriptionV2Request) (*AddSubscriptionV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSubscriptionV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSubscriptionV2(context.Context, *GetSubscriptionV2Request) (*GetSubscriptionV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptionV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSubscriptionsV2(context.Context, *GetSubscriptionsV2Request) (*GetSubscriptionsV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptionsV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) UpdateSubscriptionV2(context.Context, *UpdateSubscriptionV2Request) (*UpdateSubscriptionV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSubscriptionV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) ChangeSubscriptionPlanV2(context.Context, *ChangeSubscriptionPlanV2Request) (*ChangeSubscriptionPlanV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeSubscriptionPlanV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSubscriptionPlansV2(context.Context, *GetSubscriptionPlansV2Request) (*GetSubscriptionPlansV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubscriptionPlansV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSumSubscriptionsV2(context.Context, *GetSumSubscriptionsV2Request) (*GetSumSubscriptionsV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSumSubscriptionsV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSpendTimeSeriesV2(context.Context, *GetSpendTimeSeriesV2Request) (*GetSpendTimeSeriesV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpendTimeSeriesV2 not implemented")
+}
+func (UnimplementedSubscriptionsServer) GetSpendBreakdownV2(context.Context, *GetSpendBreakdownV2Request) (*GetSpendBreakdownV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpendBreakdownV2 not implemented")
+}
 func (UnimplementedSubscriptionsServer) mustEmbedUnimplementedSubscriptionsServer() {}
 func (UnimplementedSubscriptionsServer) testEmbeddedByValue()                       {}
 
@@ -256,17 +763,71 @@ func _Subscriptions_DeleteSubscription_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Subscriptions_GetSumSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetSumSubscriptionsRequest)
+func _Subscriptions_UndeleteSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteSubscriptionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServer).GetSumSubscriptions(ctx, in)
+		return srv.(SubscriptionsServer).UndeleteSubscription(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Subscriptions_GetSumSubscriptions_FullMethodName,
+		FullMethod: Subscriptions_UndeleteSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).UndeleteSubscription(ctx, req.(*UndeleteSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ChangeSubscriptionPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeSubscriptionPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ChangeSubscriptionPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ChangeSubscriptionPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ChangeSubscriptionPlan(ctx, req.(*ChangeSubscriptionPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSubscriptionPlans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionPlansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSubscriptionPlans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSubscriptionPlans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSubscriptionPlans(ctx, req.(*GetSubscriptionPlansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSumSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSumSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSumSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSumSubscriptions_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(SubscriptionsServer).GetSumSubscriptions(ctx, req.(*GetSumSubscriptionsRequest))
@@ -274,6 +835,503 @@ func _Subscriptions_GetSumSubscriptions_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Subscriptions_GetSpendTimeSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpendTimeSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSpendTimeSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSpendTimeSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSpendTimeSeries(ctx, req.(*GetSpendTimeSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSpendBreakdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpendBreakdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSpendBreakdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSpendBreakdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSpendBreakdown(ctx, req.(*GetSpendBreakdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ListSubscriptionOverlaps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionOverlapsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ListSubscriptionOverlaps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ListSubscriptionOverlaps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ListSubscriptionOverlaps(ctx, req.(*ListSubscriptionOverlapsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_MergeSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).MergeSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_MergeSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).MergeSubscriptions(ctx, req.(*MergeSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ListEndingTrials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEndingTrialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ListEndingTrials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ListEndingTrials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ListEndingTrials(ctx, req.(*ListEndingTrialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_CancelSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).CancelSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_CancelSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).CancelSubscription(ctx, req.(*CancelSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_PauseSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).PauseSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_PauseSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).PauseSubscription(ctx, req.(*PauseSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ResumeSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ResumeSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ResumeSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ResumeSubscription(ctx, req.(*ResumeSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ListSubscriptionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ListSubscriptionHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ListSubscriptionHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ListSubscriptionHistory(ctx, req.(*ListSubscriptionHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_WatchSubscriptions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSubscriptionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubscriptionsServer).WatchSubscriptions(m, &grpc.GenericServerStream[WatchSubscriptionsRequest, SubscriptionEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Subscriptions_WatchSubscriptionsServer = grpc.ServerStreamingServer[SubscriptionEvent]
+
+func _Subscriptions_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_RotateWebhookSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateWebhookSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).RotateWebhookSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_RotateWebhookSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).RotateWebhookSecret(ctx, req.(*RotateWebhookSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ReplayWebhookDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayWebhookDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ReplayWebhookDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ReplayWebhookDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ReplayWebhookDelivery(ctx, req.(*ReplayWebhookDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_CreateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ListAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ListAPIKeys(ctx, req.(*ListAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_RevokeAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_AddSubscriptionV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSubscriptionV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).AddSubscriptionV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_AddSubscriptionV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).AddSubscriptionV2(ctx, req.(*AddSubscriptionV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSubscriptionV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSubscriptionV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSubscriptionV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSubscriptionV2(ctx, req.(*GetSubscriptionV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSubscriptionsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionsV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSubscriptionsV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSubscriptionsV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSubscriptionsV2(ctx, req.(*GetSubscriptionsV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_UpdateSubscriptionV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSubscriptionV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).UpdateSubscriptionV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_UpdateSubscriptionV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).UpdateSubscriptionV2(ctx, req.(*UpdateSubscriptionV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_ChangeSubscriptionPlanV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeSubscriptionPlanV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).ChangeSubscriptionPlanV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_ChangeSubscriptionPlanV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).ChangeSubscriptionPlanV2(ctx, req.(*ChangeSubscriptionPlanV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSubscriptionPlansV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionPlansV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSubscriptionPlansV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSubscriptionPlansV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSubscriptionPlansV2(ctx, req.(*GetSubscriptionPlansV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSumSubscriptionsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSumSubscriptionsV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSumSubscriptionsV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSumSubscriptionsV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSumSubscriptionsV2(ctx, req.(*GetSumSubscriptionsV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSpendTimeSeriesV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpendTimeSeriesV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSpendTimeSeriesV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSpendTimeSeriesV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSpendTimeSeriesV2(ctx, req.(*GetSpendTimeSeriesV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Subscriptions_GetSpendBreakdownV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSpendBreakdownV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServer).GetSpendBreakdownV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Subscriptions_GetSpendBreakdownV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServer).GetSpendBreakdownV2(ctx, req.(*GetSpendBreakdownV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Subscriptions_ServiceDesc is the grpc.ServiceDesc for Subscriptions service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -301,11 +1359,137 @@ var Subscriptions_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteSubscription",
 			Handler:    _Subscriptions_DeleteSubscription_Handler,
 		},
+		{
+			MethodName: "UndeleteSubscription",
+			Handler:    _Subscriptions_UndeleteSubscription_Handler,
+		},
+		{
+			MethodName: "ChangeSubscriptionPlan",
+			Handler:    _Subscriptions_ChangeSubscriptionPlan_Handler,
+		},
+		{
+			MethodName: "GetSubscriptionPlans",
+			Handler:    _Subscriptions_GetSubscriptionPlans_Handler,
+		},
 		{
 			MethodName: "GetSumSubscriptions",
 			Handler:    _Subscriptions_GetSumSubscriptions_Handler,
 		},
+		{
+			MethodName: "GetSpendTimeSeries",
+			Handler:    _Subscriptions_GetSpendTimeSeries_Handler,
+		},
+		{
+			MethodName: "GetSpendBreakdown",
+			Handler:    _Subscriptions_GetSpendBreakdown_Handler,
+		},
+		{
+			MethodName: "ListSubscriptionOverlaps",
+			Handler:    _Subscriptions_ListSubscriptionOverlaps_Handler,
+		},
+		{
+			MethodName: "MergeSubscriptions",
+			Handler:    _Subscriptions_MergeSubscriptions_Handler,
+		},
+		{
+			MethodName: "ListEndingTrials",
+			Handler:    _Subscriptions_ListEndingTrials_Handler,
+		},
+		{
+			MethodName: "CancelSubscription",
+			Handler:    _Subscriptions_CancelSubscription_Handler,
+		},
+		{
+			MethodName: "PauseSubscription",
+			Handler:    _Subscriptions_PauseSubscription_Handler,
+		},
+		{
+			MethodName: "ResumeSubscription",
+			Handler:    _Subscriptions_ResumeSubscription_Handler,
+		},
+		{
+			MethodName: "ListSubscriptionHistory",
+			Handler:    _Subscriptions_ListSubscriptionHistory_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _Subscriptions_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Subscriptions_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Subscriptions_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "RotateWebhookSecret",
+			Handler:    _Subscriptions_RotateWebhookSecret_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _Subscriptions_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ReplayWebhookDelivery",
+			Handler:    _Subscriptions_ReplayWebhookDelivery_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _Subscriptions_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _Subscriptions_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _Subscriptions_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "AddSubscriptionV2",
+			Handler:    _Subscriptions_AddSubscriptionV2_Handler,
+		},
+		{
+			MethodName: "GetSubscriptionV2",
+			Handler:    _Subscriptions_GetSubscriptionV2_Handler,
+		},
+		{
+			MethodName: "GetSubscriptionsV2",
+			Handler:    _Subscriptions_GetSubscriptionsV2_Handler,
+		},
+		{
+			MethodName: "UpdateSubscriptionV2",
+			Handler:    _Subscriptions_UpdateSubscriptionV2_Handler,
+		},
+		{
+			MethodName: "ChangeSubscriptionPlanV2",
+			Handler:    _Subscriptions_ChangeSubscriptionPlanV2_Handler,
+		},
+		{
+			MethodName: "GetSubscriptionPlansV2",
+			Handler:    _Subscriptions_GetSubscriptionPlansV2_Handler,
+		},
+		{
+			MethodName: "GetSumSubscriptionsV2",
+			Handler:    _Subscriptions_GetSumSubscriptionsV2_Handler,
+		},
+		{
+			MethodName: "GetSpendTimeSeriesV2",
+			Handler:    _Subscriptions_GetSpendTimeSeriesV2_Handler,
+		},
+		{
+			MethodName: "GetSpendBreakdownV2",
+			Handler:    _Subscriptions_GetSpendBreakdownV2_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSubscriptions",
+			Handler:       _Subscriptions_WatchSubscriptions_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/subscriptions.proto",
 }
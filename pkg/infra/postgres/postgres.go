@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,14 +15,48 @@ const (
 	defaultMaxConnIdleTime     = 30 * time.Minute
 	defaultHealthCheckInterval = time.Minute
 	defaultConnectTimeout      = 5 * time.Second
+	defaultResetTimeout        = 5 * time.Second
 )
 
-func Connect(ctx context.Context, databaseUrl string) (*pgxpool.Pool, error) {
+// Option настраивает пул соединений
+type Option func(*pgxpool.Config)
+
+// WithSessionSetting устанавливает параметр сеанса name в значение value(ctx) перед каждой выдачей соединения
+// из пула, где ctx - контекст запроса или транзакции, для которой берется соединение, и сбрасывает его в пустую
+// строку при возврате соединения в пул. Параметр действует на весь сеанс, а не на транзакцию (SET LOCAL), потому что
+// соединение выполняет и запросы вне явных транзакций. Сброс гарантирует, что соединение в пуле не хранит значение
+// предыдущего запроса. Это добавляет по одному запросу к базе на выдачу и на возврат соединения
+func WithSessionSetting(name string, value func(ctx context.Context) string) Option {
+	return func(cfg *pgxpool.Config) {
+		cfg.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+			if _, err := conn.Exec(ctx, "SELECT set_config($1, $2, false)", name, value(ctx)); err != nil {
+				// состояние сеанса неизвестно, поэтому соединение закрывается
+				return false, err
+			}
+
+			return true, nil
+		}
+		cfg.AfterRelease = func(conn *pgx.Conn) bool {
+			ctx, cancel := context.WithTimeout(context.Background(), defaultResetTimeout)
+			defer cancel()
+
+			// соединение, которое не удалось сбросить, закрывается
+			_, err := conn.Exec(ctx, "SELECT set_config($1, '', false)", name)
+			return err == nil
+		}
+	}
+}
+
+func Connect(ctx context.Context, databaseUrl string, opts ...Option) (*pgxpool.Pool, error) {
 	cfg, err := config(databaseUrl)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	connPool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, err
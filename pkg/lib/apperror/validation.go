@@ -0,0 +1,33 @@
+package apperror
+
+import "strings"
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError - ошибка валидации запроса с перечнем некорректных полей
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func NewValidationError(field, description string) *ValidationError {
+	return &ValidationError{
+		Violations: []FieldViolation{{Field: field, Description: description}},
+	}
+}
+
+func (e *ValidationError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("validation error:")
+	for _, violation := range e.Violations {
+		builder.WriteString(" ")
+		builder.WriteString(violation.Field)
+		builder.WriteString(": ")
+		builder.WriteString(violation.Description)
+		builder.WriteString(";")
+	}
+
+	return strings.TrimSuffix(builder.String(), ";")
+}
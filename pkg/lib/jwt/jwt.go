@@ -0,0 +1,145 @@
+// Package jwt проверяет JSON Web Token (RFC 7519) библиотекой golang-jwt ключами из набора JWKS (keyfunc)
+// и переводит утверждения проверенного токена в Claims
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v3"
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+// ErrKeysUnavailable - не удалось загрузить ключи проверки подписи, токен не проверен
+var ErrKeysUnavailable = errors.New("token signing keys are unavailable")
+
+// signingMethods - алгоритмы подписи, которые принимаются. none и алгоритмы, не указанные здесь, отклоняются
+var signingMethods = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// Claims - утверждения проверенного токена
+type Claims struct {
+	Issuer   string
+	Subject  string
+	Audience []string
+	// Scopes - области доступа из утверждения scope (строка через пробел) или scp (строка или список)
+	Scopes []string
+	// Raw - все утверждения токена. Числа представлены json.Number
+	Raw map[string]any
+}
+
+// String возвращает строковое утверждение name или пустую строку, если его нет
+func (c Claims) String(name string) string {
+	value, _ := c.Raw[name].(string)
+	return value
+}
+
+// Strings возвращает утверждение name - строку или список строк. Если утверждения нет или оно другого типа,
+// возвращается nil
+func (c Claims) Strings(name string) []string {
+	values, _ := stringsClaim(c.Raw, name, false)
+	return values
+}
+
+// HasScope сообщает, есть ли у токена область доступа scope
+func (c Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+// Verifier проверяет подпись и сроки действия токенов. Токен без срока действия (exp) не принимается
+type Verifier struct {
+	keys   keyfunc.Keyfunc
+	parser *jwtlib.Parser
+}
+
+// NewVerifier возвращает проверку токенов, подписанных ключами keys. Пустые issuer и audience не проверяются,
+// leeway - допустимое расхождение часов с издателем токенов
+func NewVerifier(keys keyfunc.Keyfunc, issuer, audience string, leeway time.Duration) *Verifier {
+	options := []jwtlib.ParserOption{
+		jwtlib.WithValidMethods(signingMethods),
+		jwtlib.WithExpirationRequired(),
+		jwtlib.WithLeeway(leeway),
+		jwtlib.WithJSONNumber(),
+	}
+	if issuer != "" {
+		options = append(options, jwtlib.WithIssuer(issuer))
+	}
+	if audience != "" {
+		options = append(options, jwtlib.WithAudience(audience))
+	}
+	return &Verifier{keys: keys, parser: jwtlib.NewParser(options...)}
+}
+
+// Verify проверяет токен в компактной форме и возвращает его утверждения
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parsed, err := v.parser.ParseWithClaims(token, jwtlib.MapClaims{}, v.keys.KeyfuncCtx(ctx))
+	if err != nil {
+		if errors.Is(err, jwtlib.ErrTokenUnverifiable) && !v.hasKeys(ctx) {
+			return Claims{}, fmt.Errorf("%w: %w", ErrKeysUnavailable, err)
+		}
+		return Claims{}, err
+	}
+
+	return claimsFromMap(parsed.Claims.(jwtlib.MapClaims))
+}
+
+// hasKeys сообщает, загружен ли хотя бы один ключ. Пустой набор означает, что JWKS еще не удалось получить
+func (v *Verifier) hasKeys(ctx context.Context) bool {
+	keys, err := v.keys.Storage().KeyReadAll(ctx)
+	return err == nil && len(keys) > 0
+}
+
+func claimsFromMap(raw jwtlib.MapClaims) (Claims, error) {
+	claims := Claims{Raw: raw}
+
+	var err error
+	if claims.Issuer, err = raw.GetIssuer(); err != nil {
+		return Claims{}, err
+	}
+	if claims.Subject, err = raw.GetSubject(); err != nil {
+		return Claims{}, err
+	}
+	if claims.Audience, err = raw.GetAudience(); err != nil {
+		return Claims{}, err
+	}
+
+	var ok bool
+	if claims.Scopes, ok = stringsClaim(raw, "scope", true); !ok {
+		return Claims{}, fmt.Errorf("%w: scope must be a string or a list of strings", jwtlib.ErrTokenInvalidClaims)
+	}
+	if claims.Scopes == nil {
+		if claims.Scopes, ok = stringsClaim(raw, "scp", true); !ok {
+			return Claims{}, fmt.Errorf("%w: scp must be a string or a list of strings", jwtlib.ErrTokenInvalidClaims)
+		}
+	}
+
+	return claims, nil
+}
+
+// stringsClaim разбирает утверждение-строку или список строк. Если split, строка делится по пробелам
+func stringsClaim(raw map[string]any, name string, split bool) ([]string, bool) {
+	switch value := raw[name].(type) {
+	case nil:
+		return nil, true
+	case string:
+		if split {
+			return strings.Fields(value), true
+		}
+		return []string{value}, true
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	}
+
+	return nil, false
+}
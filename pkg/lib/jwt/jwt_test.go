@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/jwkset"
+	"github.com/MicahParks/keyfunc/v3"
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestVerifier(t *testing.T) *Verifier {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "oct", "kid": "hs", "alg": "HS256", "k": base64.RawURLEncoding.EncodeToString(testSecret)},
+	}})
+	require.NoError(t, err)
+
+	keys, err := keyfunc.NewJWKSetJSON(data)
+	require.NoError(t, err)
+
+	return NewVerifier(keys, "https://auth.example.com", "subscriptions", time.Minute)
+}
+
+func sign(t *testing.T, claims jwtlib.MapClaims) string {
+	t.Helper()
+
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims)
+	token.Header["kid"] = "hs"
+	signed, err := token.SignedString(testSecret)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func validClaims() jwtlib.MapClaims {
+	return jwtlib.MapClaims{
+		"iss":    "https://auth.example.com",
+		"sub":    "60601fee-2bf1-4721-ae6f-7636e79a0cba",
+		"aud":    []string{"subscriptions"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"scope":  "subscriptions:read subscriptions:write",
+		"roles":  []string{"support", "finance"},
+		"tenant": "acme",
+	}
+}
+
+func TestVerifier_Claims(t *testing.T) {
+	verifier := newTestVerifier(t)
+
+	claims, err := verifier.Verify(context.Background(), sign(t, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth.example.com", claims.Issuer)
+	assert.Equal(t, "60601fee-2bf1-4721-ae6f-7636e79a0cba", claims.Subject)
+	assert.Equal(t, []string{"subscriptions"}, claims.Audience)
+	assert.Equal(t, []string{"subscriptions:read", "subscriptions:write"}, claims.Scopes)
+	assert.True(t, claims.HasScope("subscriptions:write"))
+	assert.Equal(t, []string{"support", "finance"}, claims.Strings("roles"))
+	assert.Equal(t, "acme", claims.String("tenant"))
+	assert.Empty(t, claims.String("missing"))
+
+	t.Run("Области доступа из scp", func(t *testing.T) {
+		raw := validClaims()
+		delete(raw, "scope")
+		raw["scp"] = []string{"subscriptions:admin"}
+
+		claims, err := verifier.Verify(context.Background(), sign(t, raw))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"subscriptions:admin"}, claims.Scopes)
+	})
+
+	t.Run("Роль строкой", func(t *testing.T) {
+		raw := validClaims()
+		raw["roles"] = "support"
+
+		claims, err := verifier.Verify(context.Background(), sign(t, raw))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"support"}, claims.Strings("roles"))
+	})
+
+	t.Run("Некорректная область доступа", func(t *testing.T) {
+		raw := validClaims()
+		raw["scope"] = 42
+
+		_, err := verifier.Verify(context.Background(), sign(t, raw))
+		assert.ErrorIs(t, err, jwtlib.ErrTokenInvalidClaims)
+	})
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	verifier := newTestVerifier(t)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err := verifier.Verify(context.Background(), sign(t, expired))
+	assert.ErrorIs(t, err, jwtlib.ErrTokenExpired)
+
+	withoutExpiry := validClaims()
+	delete(withoutExpiry, "exp")
+	_, err = verifier.Verify(context.Background(), sign(t, withoutExpiry))
+	assert.ErrorIs(t, err, jwtlib.ErrTokenRequiredClaimMissing)
+
+	otherAudience := validClaims()
+	otherAudience["aud"] = "billing"
+	_, err = verifier.Verify(context.Background(), sign(t, otherAudience))
+	assert.ErrorIs(t, err, jwtlib.ErrTokenInvalidAudience)
+
+	_, err = verifier.Verify(context.Background(), sign(t, validClaims())+"x")
+	assert.ErrorIs(t, err, jwtlib.ErrTokenSignatureInvalid)
+	assert.NotErrorIs(t, err, ErrKeysUnavailable)
+}
+
+func TestVerifier_KeysUnavailable(t *testing.T) {
+	// набор ключей по URL пуст, пока его не удалось загрузить
+	keys, err := keyfunc.New(keyfunc.Options{Storage: jwkset.NewMemoryStorage()})
+	require.NoError(t, err)
+	verifier := NewVerifier(keys, "", "", 0)
+
+	_, err = verifier.Verify(context.Background(), sign(t, validClaims()))
+	assert.ErrorIs(t, err, ErrKeysUnavailable)
+}
@@ -0,0 +1,30 @@
+package pagetoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cursor struct {
+	ID    string `json:"id"`
+	Price int32  `json:"price"`
+}
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+
+	token, err := signer.Encode(cursor{ID: "a", Price: 100})
+	assert.NoError(t, err)
+
+	var decoded cursor
+	assert.NoError(t, signer.Decode(token, &decoded))
+	assert.Equal(t, cursor{ID: "a", Price: 100}, decoded)
+
+	forged, err := NewSigner([]byte("other")).Encode(cursor{ID: "b"})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, signer.Decode(forged, &decoded), ErrInvalidToken)
+
+	assert.ErrorIs(t, signer.Decode("garbage", &decoded), ErrInvalidToken)
+	assert.ErrorIs(t, signer.Decode(token[1:], &decoded), ErrInvalidToken)
+}
@@ -0,0 +1,53 @@
+// Package principal хранит в контексте запроса аутентифицированного клиента
+package principal
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Principal - аутентифицированный клиент. Клиент без Admin видит и изменяет только подписки пользователя UserID,
+// а если UserID нулевой - подписки всех пользователей
+type Principal struct {
+	Subject string
+	UserID  uuid.UUID
+	// TenantID - арендатор клиента. Пустой у администратора, которому доступен арендатор из заголовка запроса
+	TenantID string
+	Scopes   []string
+	// Roles - роли клиента в политике доступа
+	Roles []string
+	Admin bool
+	// ScopeLimited - клиенту доступны только методы, для которых у него есть область доступа из Scopes
+	ScopeLimited bool
+}
+
+// HasScope сообщает, есть ли у клиента область доступа scope
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type contextKey struct{}
+
+// With возвращает контекст с аутентифицированным клиентом
+func With(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// From возвращает аутентифицированного клиента из контекста. ok ложно, если запрос не аутентифицирован:
+// аутентификация выключена или вызов сделан фоновой задачей
+func From(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(Principal)
+	return principal, ok
+}
+
+// ScopedUserID возвращает пользователя, данными которого ограничен клиент. Нулевой ID - ограничений нет
+func ScopedUserID(ctx context.Context) uuid.UUID {
+	principal, ok := From(ctx)
+	if !ok || principal.Admin {
+		return uuid.Nil
+	}
+
+	return principal.UserID
+}
@@ -0,0 +1,174 @@
+// Package rbac проверяет доступ к методам gRPC по ролям клиента. Политика задается в YAML: роли разрешают
+// вызов методов по шаблонам полного имени метода и ограничивают подписки, с которыми работает метод
+package rbac
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Resources - подписки каких пользователей доступны по правилу
+type Resources string
+
+const (
+	// ResourcesOwn - только подписки пользователя клиента
+	ResourcesOwn Resources = "own"
+	// ResourcesAll - подписки всех пользователей
+	ResourcesAll Resources = "all"
+)
+
+// Rule разрешает вызов методов, полное имя которых (/пакет.Сервис/Метод) подходит под один из шаблонов Methods.
+// В шаблонах * заменяет любую часть имени сервиса или метода (см. path.Match)
+type Rule struct {
+	Methods []string `yaml:"methods"`
+	// Resources - условие на подписки, по умолчанию own
+	Resources Resources `yaml:"resources"`
+}
+
+type Role struct {
+	Description string `yaml:"description"`
+	Rules       []Rule `yaml:"rules"`
+}
+
+// Policy - политика доступа. Что не разрешено ни одной ролью клиента, запрещено
+type Policy struct {
+	// DefaultRoles - роли клиентов, у которых в токене нет ни одной роли
+	DefaultRoles []string        `yaml:"default_roles"`
+	Roles        map[string]Role `yaml:"roles"`
+}
+
+// Decision - результат проверки доступа
+type Decision struct {
+	Allowed bool
+	// Resources - самое широкое условие среди правил, разрешивших вызов
+	Resources Resources
+}
+
+// Parse разбирает политику. Неизвестные поля считаются ошибкой, чтобы опечатка не ослабила политику незаметно
+func Parse(data []byte) (*Policy, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var policy Policy
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// ReadFile читает политику из YAML-файла
+func ReadFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Lint проверяет политику и возвращает найденные ошибки. methods - полные имена существующих методов:
+// шаблон, под который не подходит ни один из них, скорее всего содержит опечатку
+func (p *Policy) Lint(methods []string) []error {
+	var problems []error
+	if len(p.Roles) == 0 {
+		problems = append(problems, errors.New("roles: at least one role is required"))
+	}
+
+	for _, name := range p.DefaultRoles {
+		if _, ok := p.Roles[name]; !ok {
+			problems = append(problems, fmt.Errorf("default_roles: role %q is not defined", name))
+		}
+	}
+
+	names := make([]string, 0, len(p.Roles))
+	for name := range p.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		role := p.Roles[name]
+		if len(role.Rules) == 0 {
+			problems = append(problems, fmt.Errorf("roles.%s: role has no rules", name))
+		}
+
+		for i, rule := range role.Rules {
+			at := fmt.Sprintf("roles.%s.rules[%d]", name, i)
+			if len(rule.Methods) == 0 {
+				problems = append(problems, fmt.Errorf("%s: rule has no methods", at))
+			}
+			if rule.Resources != "" && rule.Resources != ResourcesOwn && rule.Resources != ResourcesAll {
+				problems = append(problems, fmt.Errorf("%s.resources: must be %s or %s, got %q", at, ResourcesOwn, ResourcesAll, rule.Resources))
+			}
+
+			for j, pattern := range rule.Methods {
+				if err := lintPattern(pattern, methods); err != nil {
+					problems = append(problems, fmt.Errorf("%s.methods[%d]: %w", at, j, err))
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+func lintPattern(pattern string, methods []string) error {
+	if !strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("pattern %q must be a full method name starting with /", pattern)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("pattern %q is invalid: %w", pattern, err)
+	}
+
+	matches := slices.ContainsFunc(methods, func(method string) bool {
+		ok, _ := path.Match(pattern, method)
+		return ok
+	})
+	if !matches {
+		return fmt.Errorf("pattern %q matches no method", pattern)
+	}
+
+	return nil
+}
+
+// Authorize проверяет, разрешен ли клиенту с ролями roles вызов метода method. Роли, которых нет в политике,
+// ничего не разрешают
+func (p *Policy) Authorize(roles []string, method string) Decision {
+	var decision Decision
+	for _, name := range roles {
+		role, ok := p.Roles[name]
+		if !ok {
+			continue
+		}
+
+		for _, rule := range role.Rules {
+			if !rule.matches(method) {
+				continue
+			}
+
+			decision.Allowed = true
+			if rule.Resources == ResourcesAll {
+				return Decision{Allowed: true, Resources: ResourcesAll}
+			}
+			decision.Resources = ResourcesOwn
+		}
+	}
+
+	return decision
+}
+
+func (r Rule) matches(method string) bool {
+	return slices.ContainsFunc(r.Methods, func(pattern string) bool {
+		ok, _ := path.Match(pattern, method)
+		return ok
+	})
+}
@@ -0,0 +1,95 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `
+default_roles: [user]
+roles:
+  user:
+    rules:
+      - methods: ["/api.Subscriptions/*"]
+  support:
+    rules:
+      - methods: [/api.Subscriptions/Get*, /api.Subscriptions/WatchSubscriptions]
+        resources: all
+  finance:
+    rules:
+      - methods: [/api.Subscriptions/GetSumSubscriptions]
+        resources: all
+`
+
+var testMethods = []string{
+	"/api.Subscriptions/GetSubscription",
+	"/api.Subscriptions/GetSumSubscriptions",
+	"/api.Subscriptions/DeleteSubscription",
+	"/api.Subscriptions/WatchSubscriptions",
+}
+
+func TestParse(t *testing.T) {
+	policy, err := Parse([]byte(testPolicy))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user"}, policy.DefaultRoles)
+	assert.Len(t, policy.Roles, 3)
+	assert.Empty(t, policy.Lint(testMethods))
+
+	_, err = Parse([]byte("roles:\n  user:\n    rule: []\n"))
+	assert.Error(t, err)
+}
+
+func TestPolicy_Lint(t *testing.T) {
+	policy, err := Parse([]byte(`
+default_roles: [guest]
+roles:
+  empty: {}
+  broken:
+    rules:
+      - resources: some
+      - methods: [api.Subscriptions/GetSubscription, "/api.Subscriptions/[", /api.Subscriptions/GetSubscriptionz]
+`))
+	assert.NoError(t, err)
+
+	var problems []string
+	for _, problem := range policy.Lint(testMethods) {
+		problems = append(problems, problem.Error())
+	}
+	assert.Equal(t, []string{
+		`default_roles: role "guest" is not defined`,
+		`roles.broken.rules[0]: rule has no methods`,
+		`roles.broken.rules[0].resources: must be own or all, got "some"`,
+		`roles.broken.rules[1].methods[0]: pattern "api.Subscriptions/GetSubscription" must be a full method name starting with /`,
+		`roles.broken.rules[1].methods[1]: pattern "/api.Subscriptions/[" is invalid: syntax error in pattern`,
+		`roles.broken.rules[1].methods[2]: pattern "/api.Subscriptions/GetSubscriptionz" matches no method`,
+		`roles.empty: role has no rules`,
+	}, problems)
+
+	assert.Len(t, (&Policy{}).Lint(testMethods), 1)
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	policy, err := Parse([]byte(testPolicy))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		roles    []string
+		method   string
+		expected Decision
+	}{
+		{name: "Свои подписки", roles: []string{"user"}, method: "/api.Subscriptions/DeleteSubscription", expected: Decision{Allowed: true, Resources: ResourcesOwn}},
+		{name: "Чтение всех подписок", roles: []string{"support"}, method: "/api.Subscriptions/GetSubscription", expected: Decision{Allowed: true, Resources: ResourcesAll}},
+		{name: "Метод не разрешен", roles: []string{"support"}, method: "/api.Subscriptions/DeleteSubscription", expected: Decision{}},
+		{name: "Самое широкое условие", roles: []string{"user", "finance"}, method: "/api.Subscriptions/GetSumSubscriptions", expected: Decision{Allowed: true, Resources: ResourcesAll}},
+		{name: "Неизвестная роль", roles: []string{"admin"}, method: "/api.Subscriptions/GetSubscription", expected: Decision{}},
+		{name: "Без ролей", method: "/api.Subscriptions/GetSubscription", expected: Decision{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, policy.Authorize(tc.roles, tc.method))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package requestmeta
+
+import "context"
+
+const (
+	// SystemActor - автор изменений, выполненных вне запроса клиента, например фоновыми задачами
+	SystemActor = "system"
+	// UnauthenticatedActor - автор изменений, выполненных клиентом без аутентификации
+	UnauthenticatedActor = "unauthenticated"
+)
+
+// Meta - сведения о запросе, которые нужны за пределами обработчика: ID запроса для сопоставления
+// с логами и автор изменений для журнала аудита
+type Meta struct {
+	RequestID string
+	// Actor - субъект аутентифицированного клиента, UnauthenticatedActor или SystemActor
+	Actor string
+	// ClaimedActor - автор, которого клиент назвал сам в заголовке X-Actor. Не проверяется, поэтому
+	// хранится отдельно от Actor и только для справки
+	ClaimedActor string
+}
+
+type contextKey struct{}
+
+// With возвращает контекст со сведениями о запросе
+func With(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, contextKey{}, meta)
+}
+
+// From возвращает сведения о запросе из контекста. Вне запроса автор - SystemActor, а ID запроса пустой
+func From(ctx context.Context) Meta {
+	meta, ok := ctx.Value(contextKey{}).(Meta)
+	if !ok || meta.Actor == "" {
+		meta.Actor = SystemActor
+	}
+
+	return meta
+}
@@ -0,0 +1,71 @@
+// Package tenant хранит в контексте арендатора, данными которого ограничен запрос
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+const (
+	// Default - арендатор запросов, для которых арендатор не указан, и данных, созданных до разделения по арендаторам
+	Default = "default"
+	// All - значение параметра сеанса Postgres, открывающее фоновым задачам данные всех арендаторов
+	All = "*"
+)
+
+var (
+	ErrInvalidID = errors.New("tenant id must be 1-63 lowercase latin letters, digits, '-' or '_' and start with a letter or digit")
+
+	idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+)
+
+type (
+	contextKey    struct{}
+	allContextKey struct{}
+)
+
+// Validate проверяет ID арендатора
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return ErrInvalidID
+	}
+
+	return nil
+}
+
+// With возвращает контекст запроса арендатора id
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// From возвращает арендатора запроса. Вне запроса, например при вызове фоновой задачей, - Default
+func From(ctx context.Context) string {
+	id, ok := ctx.Value(contextKey{}).(string)
+	if !ok || id == "" {
+		return Default
+	}
+
+	return id
+}
+
+// WithAll возвращает контекст фоновой задачи, которая обрабатывает данные всех арендаторов
+func WithAll(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allContextKey{}, true)
+}
+
+// IsAll сообщает, обрабатывает ли задача с контекстом ctx данные всех арендаторов
+func IsAll(ctx context.Context) bool {
+	all, _ := ctx.Value(allContextKey{}).(bool)
+	return all
+}
+
+// SessionSetting возвращает значение параметра сеанса Postgres app.tenant_id для запроса к базе с контекстом ctx:
+// по нему политики защиты строк отбирают строки арендатора
+func SessionSetting(ctx context.Context) string {
+	if IsAll(ctx) {
+		return All
+	}
+
+	return From(ctx)
+}
@@ -0,0 +1,86 @@
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header - заголовок с подписью тела запроса вебхука
+const Header = "X-Webhook-Signature"
+
+var (
+	ErrInvalidHeader    = errors.New("invalid webhook signature header")
+	ErrSignatureExpired = errors.New("webhook signature timestamp is outside of tolerance")
+	ErrNoValidSignature = errors.New("no valid webhook signature")
+)
+
+// Sign формирует значение заголовка Header в формате "t=<unix-время>,v1=<подпись>[,v1=<подпись>]".
+// Подпись - HMAC-SHA256 строки "<unix-время>.<тело>" в hex, по одной на каждый секрет: во время ротации
+// запрос подписывается и новым, и предыдущим секретом
+func Sign(body []byte, timestamp time.Time, secrets ...string) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	var header strings.Builder
+	header.WriteString("t=" + unix)
+	for _, secret := range secrets {
+		header.WriteString(",v1=" + hex.EncodeToString(sign(secret, unix, body)))
+	}
+
+	return header.String()
+}
+
+// Verify проверяет заголовок Header на стороне получателя: одна из подписей должна совпасть с подписью секретом
+// secret, а время подписи - отличаться от now не больше чем на tolerance. Ограничение по времени не дает повторить
+// перехваченный запрос позже
+func Verify(header string, body []byte, secret string, tolerance time.Duration, now time.Time) error {
+	var unix string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return ErrInvalidHeader
+		}
+
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				return ErrInvalidHeader
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidHeader
+	}
+
+	if now.Sub(time.Unix(seconds, 0)).Abs() > tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := sign(secret, unix, body)
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+
+	return ErrNoValidSignature
+}
+
+func sign(secret, unix string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
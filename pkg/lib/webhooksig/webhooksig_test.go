@@ -0,0 +1,28 @@
+package webhooksig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	now := time.Unix(1751371200, 0)
+
+	header := Sign(body, now, "new", "old")
+	assert.Regexp(t, `^t=1751371200,v1=[0-9a-f]{64},v1=[0-9a-f]{64}$`, header)
+
+	// во время ротации подходит и новый, и предыдущий секрет
+	assert.NoError(t, Verify(header, body, "new", time.Minute, now.Add(30*time.Second)))
+	assert.NoError(t, Verify(header, body, "old", time.Minute, now))
+
+	assert.ErrorIs(t, Verify(header, body, "other", time.Minute, now), ErrNoValidSignature)
+	assert.ErrorIs(t, Verify(header, []byte(`{"id":2}`), "new", time.Minute, now), ErrNoValidSignature)
+	assert.ErrorIs(t, Verify(header, body, "new", time.Minute, now.Add(2*time.Minute)), ErrSignatureExpired)
+
+	assert.ErrorIs(t, Verify("garbage", body, "new", time.Minute, now), ErrInvalidHeader)
+	assert.ErrorIs(t, Verify("t=1751371200", body, "new", time.Minute, now), ErrInvalidHeader)
+	assert.ErrorIs(t, Verify("t=1751371200,v1=zz", body, "new", time.Minute, now), ErrInvalidHeader)
+}